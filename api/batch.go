@@ -0,0 +1,335 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	// BatchFormatCSV the first line is the header, columns are mapped to arguments.
+	BatchFormatCSV = "csv"
+	// BatchFormatJSONLines each line is a JSON object of {"functionName": "", "arguments": []}.
+	BatchFormatJSONLines = "jsonl"
+
+	// BatchStatusRunning the batch job is running.
+	BatchStatusRunning = "running"
+	// BatchStatusCompleted all rows of the batch job are processed.
+	BatchStatusCompleted = "completed"
+
+	// batchSaveInterval to save the job report every some rows.
+	batchSaveInterval = 100
+)
+
+// BatchInvocation an invocation of the batch, a row of the file.
+type BatchInvocation struct {
+	Row          int      `json:"row"` // Start from 1, the header of csv is not counted.
+	FunctionName string   `json:"functionName"`
+	Arguments    []string `json:"arguments"`
+}
+
+// BatchRowResult result of an invocation.
+type BatchRowResult struct {
+	Row            int    `json:"row"`
+	TransactionID  string `json:"transactionID"`
+	ValidationCode string `json:"validationCode"` // Empty if not to wait for commit.
+	Error          string `json:"error"`
+	Skipped        bool   `json:"skipped"` // Done in the previous run.
+}
+
+// Done if the invocation has been done successfully. If the job waited for commit, the transaction must be valid.
+// Otherwise the transaction is only submitted to the orderer, it is done if it is endorsed and sent, though it might be invalid.
+func (result *BatchRowResult) Done(waitForCommit bool) bool {
+	if waitForCommit {
+		return result.TransactionID != "" && result.Error == "" && result.ValidationCode == pb.TxValidationCode_VALID.String()
+	}
+	return result.TransactionID != "" && result.Error == ""
+}
+
+// BatchOptions options of a batch job.
+type BatchOptions struct {
+	ChannelID     string   `json:"channelID"`
+	ChaincodeID   string   `json:"chaincodeID"`
+	Targets       []string `json:"targets"`
+	Concurrency   int      `json:"concurrency"`   // Default 1
+	Rate          float64  `json:"rate"`          // Invocations per second, 0 means no limit.
+	WaitForCommit bool     `json:"waitForCommit"` // Wait for the commit of each transaction, to have the validation code.
+}
+
+// BatchJob a batch invocation job and its report.
+type BatchJob struct {
+	ID        string            `json:"id"`
+	Options   BatchOptions      `json:"options"`
+	Status    string            `json:"status"`
+	Total     int               `json:"total"`
+	Processed int               `json:"processed"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Skipped   int               `json:"skipped"`
+	StartTime int64             `json:"startTime"`
+	EndTime   int64             `json:"endTime"`
+	Results   []*BatchRowResult `json:"results"`
+	lock      sync.RWMutex
+}
+
+// ParseBatchInvocations to parse the invocations from the file content.
+// For csv, the columns are names of the header mapped to arguments in order, all columns are used if it is empty.
+// The funcName is used if the function name of the invocation is empty.
+func ParseBatchInvocations(format string, content []byte, columns []string, funcName string) ([]*BatchInvocation, error) {
+	var invocations []*BatchInvocation
+	var err error
+	switch format {
+	case BatchFormatCSV:
+		invocations, err = parseBatchCSV(content, columns, funcName)
+	case BatchFormatJSONLines:
+		invocations, err = parseBatchJSONLines(content, funcName)
+	default:
+		return nil, errors.Errorf("%s is not a supported batch format", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, invocation := range invocations {
+		if invocation.FunctionName == "" {
+			return nil, errors.Errorf("function name of row %d is empty", invocation.Row)
+		}
+	}
+	return invocations, nil
+}
+
+func parseBatchCSV(content []byte, columns []string, funcName string) ([]*BatchInvocation, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when reading the csv header.")
+	}
+
+	indexes := []int{}
+	if len(columns) < 1 {
+		for idx := range header {
+			indexes = append(indexes, idx)
+		}
+	}
+	for _, column := range columns {
+		found := false
+		for idx, name := range header {
+			if strings.TrimSpace(name) == column {
+				indexes, found = append(indexes, idx), true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("column %s is not found in the csv header", column)
+		}
+	}
+
+	invocations := []*BatchInvocation{}
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.WithMessagef(err, "Error occurred when reading row %d of the csv.", row)
+		}
+		args := make([]string, len(indexes))
+		for idx, column := range indexes {
+			args[idx] = record[column]
+		}
+		invocations = append(invocations, &BatchInvocation{Row: row, FunctionName: funcName, Arguments: args})
+	}
+	return invocations, nil
+}
+
+func parseBatchJSONLines(content []byte, funcName string) ([]*BatchInvocation, error) {
+	invocations := []*BatchInvocation{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	row := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		row++
+		invocation := &BatchInvocation{}
+		if err := json.Unmarshal([]byte(line), invocation); err != nil {
+			return nil, errors.WithMessagef(err, "Error occurred when parsing row %d.", row)
+		}
+		invocation.Row = row
+		if invocation.FunctionName == "" {
+			invocation.FunctionName = funcName
+		}
+		if invocation.Arguments == nil {
+			invocation.Arguments = []string{}
+		}
+		invocations = append(invocations, invocation)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when reading the lines.")
+	}
+	return invocations, nil
+}
+
+// NewBatchJob to create a batch job. The rows done successfully in the previous job will be skipped.
+func NewBatchJob(id string, options BatchOptions, total int, previous *BatchJob) *BatchJob {
+	if options.Concurrency < 1 {
+		options.Concurrency = 1
+	}
+	job := &BatchJob{
+		ID:      id,
+		Options: options,
+		Status:  BatchStatusRunning,
+		Total:   total,
+		Results: make([]*BatchRowResult, total),
+	}
+	if previous != nil {
+		for _, result := range previous.Results {
+			if result != nil && result.Done(previous.Options.WaitForCommit) && result.Row >= 1 && result.Row <= total {
+				job.Results[result.Row-1] = &BatchRowResult{
+					Row:            result.Row,
+					TransactionID:  result.TransactionID,
+					ValidationCode: result.ValidationCode,
+					Skipped:        true,
+				}
+				job.Skipped++
+				job.Processed++
+			}
+		}
+	}
+	return job
+}
+
+// Snapshot to copy the job report, for output and saving while it is running.
+func (job *BatchJob) Snapshot() *BatchJob {
+	job.lock.RLock()
+	defer job.lock.RUnlock()
+	snapshot := &BatchJob{
+		ID:        job.ID,
+		Options:   job.Options,
+		Status:    job.Status,
+		Total:     job.Total,
+		Processed: job.Processed,
+		Succeeded: job.Succeeded,
+		Failed:    job.Failed,
+		Skipped:   job.Skipped,
+		StartTime: job.StartTime,
+		EndTime:   job.EndTime,
+		Results:   []*BatchRowResult{},
+	}
+	for _, result := range job.Results {
+		if result != nil {
+			r := *result
+			snapshot.Results = append(snapshot.Results, &r)
+		}
+	}
+	return snapshot
+}
+
+func (job *BatchJob) setResult(result *BatchRowResult) int {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	job.Results[result.Row-1] = result
+	job.Processed++
+	if result.Done(job.Options.WaitForCommit) {
+		job.Succeeded++
+	} else {
+		job.Failed++
+	}
+	return job.Processed
+}
+
+// Run to execute all invocations not done yet, the save function is called periodically and at the end.
+// The done function, if not nil, is called after each invocation with the time it began, e.g. to audit it.
+func (job *BatchJob) Run(conn *NetworkConnection, invocations []*BatchInvocation, save func(*BatchJob),
+	done func(invocation *BatchInvocation, result *BatchRowResult, begin time.Time)) {
+	job.lock.Lock()
+	job.StartTime = time.Now().UnixNano() / 1000000
+	job.lock.Unlock()
+
+	var ticker *time.Ticker
+	if job.Options.Rate > 0 {
+		ticker = time.NewTicker(time.Duration(float64(time.Second) / job.Options.Rate))
+		defer ticker.Stop()
+	}
+
+	tasks := make(chan *BatchInvocation)
+	var wg sync.WaitGroup
+	for i := 0; i < job.Options.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for invocation := range tasks {
+				begin := time.Now()
+				result := job.invoke(conn, invocation)
+				if done != nil {
+					done(invocation, result, begin)
+				}
+				if processed := job.setResult(result); processed%batchSaveInterval == 0 && save != nil {
+					save(job)
+				}
+			}
+		}()
+	}
+
+	for _, invocation := range invocations {
+		if invocation.Row < 1 || invocation.Row > job.Total || job.Results[invocation.Row-1] != nil {
+			continue
+		}
+		if ticker != nil {
+			<-ticker.C
+		}
+		tasks <- invocation
+	}
+	close(tasks)
+	wg.Wait()
+
+	job.lock.Lock()
+	job.Status = BatchStatusCompleted
+	job.EndTime = time.Now().UnixNano() / 1000000
+	job.lock.Unlock()
+	if save != nil {
+		save(job)
+	}
+	logger.Infof("Batch job %s completed: %d succeeded, %d failed, %d skipped.", job.ID, job.Succeeded, job.Failed, job.Skipped)
+}
+
+func (job *BatchJob) invoke(conn *NetworkConnection, invocation *BatchInvocation) *BatchRowResult {
+	// To keep the connection active for a long running job.
+	conn.ActiveTime = time.Now()
+
+	result := &BatchRowResult{Row: invocation.Row}
+	options := job.Options
+	if options.WaitForCommit {
+		// The response is with the error if the transaction is invalidated when committing.
+		response, err := ExecuteChaincode(conn, options.ChannelID, options.ChaincodeID, ChaincodeOperTypeExecute,
+			options.Targets, invocation.FunctionName, invocation.Arguments)
+		if response != nil {
+			result.TransactionID = string(response.TransactionID)
+			result.ValidationCode = response.TxValidationCode.String()
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		return result
+	}
+
+	response, err := SubmitChaincode(conn, options.ChannelID, options.ChaincodeID,
+		options.Targets, invocation.FunctionName, invocation.Arguments)
+	if response != nil {
+		result.TransactionID = string(response.TransactionID)
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestParseBatchInvocations(t *testing.T) {
+	csvContent := []byte("id, brand, price\nv001, b001, 100\nv002, \"b,002\", 200\n")
+	invocations, err := ParseBatchInvocations(BatchFormatCSV, csvContent, []string{"brand", "id"}, "createVehicle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invocations) != 2 || invocations[1].Row != 2 || invocations[1].Arguments[0] != "b,002" || invocations[1].Arguments[1] != "v002" {
+		t.Fatalf("wrong csv invocations %v", invocations)
+	}
+	if _, err := ParseBatchInvocations(BatchFormatCSV, csvContent, []string{"color"}, "createVehicle"); err == nil {
+		t.Error("column color should not be found")
+	}
+
+	jsonContent := []byte(`{"functionName": "createVehicle", "arguments": ["v001", "b001"]}
+
+{"arguments": ["v002"]}
+`)
+	invocations, err = ParseBatchInvocations(BatchFormatJSONLines, jsonContent, nil, "findVehicle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invocations) != 2 || invocations[0].FunctionName != "createVehicle" || invocations[1].FunctionName != "findVehicle" || invocations[1].Row != 2 {
+		t.Fatalf("wrong json lines invocations %v", invocations)
+	}
+	if _, err := ParseBatchInvocations(BatchFormatJSONLines, jsonContent, nil, ""); err == nil {
+		t.Error("the function name of row 2 should be required")
+	}
+}
+
+func TestNewBatchJobResume(t *testing.T) {
+	previous := &BatchJob{Results: []*BatchRowResult{
+		{Row: 1, TransactionID: "tx1"},
+		{Row: 2, TransactionID: "tx2", Error: "failed"},
+		{Row: 4, TransactionID: "tx4", ValidationCode: "VALID"},
+	}}
+	job := NewBatchJob("job", BatchOptions{}, 4, previous)
+	if job.Skipped != 2 || job.Processed != 2 || job.Options.Concurrency != 1 {
+		t.Fatalf("wrong resumed job %+v", job)
+	}
+	if job.Results[0] == nil || !job.Results[0].Skipped || job.Results[1] != nil || job.Results[3].ValidationCode != "VALID" {
+		t.Errorf("wrong resumed results %v", job.Results)
+	}
+	if len(job.Snapshot().Results) != 2 {
+		t.Error("only processed results should be in the snapshot")
+	}
+
+	committed := &BatchJob{Options: BatchOptions{WaitForCommit: true}, Results: []*BatchRowResult{
+		{Row: 1, TransactionID: "tx1", ValidationCode: "VALID"},
+		{Row: 2, TransactionID: "tx2", ValidationCode: "MVCC_READ_CONFLICT", Error: "invalidated"},
+		{Row: 3, TransactionID: "tx3"},
+	}}
+	job = NewBatchJob("job", BatchOptions{WaitForCommit: true}, 3, committed)
+	if job.Skipped != 1 || job.Results[0] == nil || job.Results[1] != nil || job.Results[2] != nil {
+		t.Errorf("only valid transactions should be skipped when waiting for commit, %v", job.Results)
+	}
+}
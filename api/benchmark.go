@@ -0,0 +1,428 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// BenchmarkStatusRunning the benchmark is running.
+	BenchmarkStatusRunning = "running"
+	// BenchmarkStatusCompleted the benchmark ran for the whole duration.
+	BenchmarkStatusCompleted = "completed"
+	// BenchmarkStatusStopped the benchmark is stopped before the end of the duration.
+	BenchmarkStatusStopped = "stopped"
+
+	// BenchmarkCodeEndorsementFailure the transaction failed before being endorsed.
+	BenchmarkCodeEndorsementFailure = "ENDORSEMENT_FAILURE"
+	// BenchmarkCodeCommitFailure the transaction failed after being endorsed, without a validation code.
+	BenchmarkCodeCommitFailure = "COMMIT_FAILURE"
+
+	// BenchmarkMaxDuration the longest duration of a benchmark, in seconds.
+	BenchmarkMaxDuration = 3600
+	// BenchmarkMaxConcurrency the most concurrent invocations of a benchmark.
+	BenchmarkMaxConcurrency = 500
+	// benchmarkMaxErrors the most distinct error messages kept in the report.
+	benchmarkMaxErrors = 20
+)
+
+// BenchmarkOptions options of a benchmark.
+// Arguments are templates, the placeholders are replaced for each invocation:
+// {{seq}} sequence number from 1, {{uuid}} a new uuid, {{timestamp}} Unix time in milliseconds,
+// {{random}} or {{random:min-max}} a random integer.
+type BenchmarkOptions struct {
+	ChannelID    string   `json:"channelID"`
+	ChaincodeID  string   `json:"chaincodeID"`
+	FunctionName string   `json:"functionName"`
+	Arguments    []string `json:"arguments"`
+	Targets      []string `json:"targets"`
+	TPS          float64  `json:"TPS"`         // Target transactions per second, 0 means as fast as the concurrency allows.
+	Concurrency  int      `json:"concurrency"` // Most concurrent invocations, default 1.
+	Duration     int      `json:"duration"`    // In seconds.
+}
+
+// LatencyStats statistics of latencies, in milliseconds.
+type LatencyStats struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Mean  float64 `json:"mean"`
+	P50   float64 `json:"p50"`
+	P95   float64 `json:"p95"`
+	P99   float64 `json:"p99"`
+}
+
+// BenchmarkReport report of a benchmark, it is also the live progress while running.
+type BenchmarkReport struct {
+	ID              string           `json:"id"`
+	Options         BenchmarkOptions `json:"options"`
+	Status          string           `json:"status"`
+	StartTime       int64            `json:"startTime"`
+	EndTime         int64            `json:"endTime"`
+	Elapsed         float64          `json:"elapsed"` // In seconds.
+	Submitted       int              `json:"submitted"`
+	Succeeded       int              `json:"succeeded"`
+	Failed          int              `json:"failed"`
+	Throughput      float64          `json:"throughput"` // Succeeded transactions per second.
+	Endorse         LatencyStats     `json:"endorse"`
+	Commit          LatencyStats     `json:"commit"`
+	Total           LatencyStats     `json:"total"`
+	ValidationCodes map[string]int   `json:"validationCodes"`
+	Errors          map[string]int   `json:"errors"`
+}
+
+// Benchmark a running benchmark.
+type Benchmark struct {
+	ID        string
+	Options   BenchmarkOptions
+	status    string
+	startTime time.Time
+	endTime   time.Time
+	submitted int
+	endorse   []float64
+	commit    []float64
+	total     []float64
+	codes     map[string]int
+	errors    map[string]int
+	lock      sync.RWMutex
+}
+
+// NewBenchmark to create a benchmark after checking the options.
+func NewBenchmark(options BenchmarkOptions) (*Benchmark, error) {
+	if options.ChannelID == "" || options.ChaincodeID == "" || options.FunctionName == "" {
+		return nil, errors.New("channel, chaincode and function name are required")
+	}
+	if options.Duration < 1 || options.Duration > BenchmarkMaxDuration {
+		return nil, errors.Errorf("duration should be between 1 and %d seconds", BenchmarkMaxDuration)
+	}
+	if options.Concurrency < 1 {
+		options.Concurrency = 1
+	}
+	if options.Concurrency > BenchmarkMaxConcurrency {
+		return nil, errors.Errorf("concurrency should not be more than %d", BenchmarkMaxConcurrency)
+	}
+	if options.TPS < 0 {
+		return nil, errors.New("TPS should not be negative")
+	}
+	return &Benchmark{
+		ID:      uuid.NewV1().String(),
+		Options: options,
+		status:  BenchmarkStatusRunning,
+		codes:   map[string]int{},
+		errors:  map[string]int{},
+	}, nil
+}
+
+var argumentPlaceholder = regexp.MustCompile(`\{\{(\w+)(?::(-?\d+)-(-?\d+))?\}\}`)
+
+// GenerateArguments to generate arguments from the templates for the invocation of the sequence number.
+func GenerateArguments(templates []string, seq int64) []string {
+	args := make([]string, len(templates))
+	for idx, template := range templates {
+		args[idx] = argumentPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+			matches := argumentPlaceholder.FindStringSubmatch(placeholder)
+			switch matches[1] {
+			case "seq":
+				return strconv.FormatInt(seq, 10)
+			case "uuid":
+				return uuid.NewV4().String()
+			case "timestamp":
+				return strconv.FormatInt(time.Now().UnixNano()/1000000, 10)
+			case "random":
+				min, max := int64(0), int64(math.MaxInt32)
+				if matches[2] != "" {
+					min, _ = strconv.ParseInt(matches[2], 10, 64)
+					max, _ = strconv.ParseInt(matches[3], 10, 64)
+				}
+				if max <= min {
+					return strconv.FormatInt(min, 10)
+				}
+				return strconv.FormatInt(min+rand.Int63n(max-min+1), 10)
+			}
+			return placeholder
+		})
+	}
+	return args
+}
+
+// Run to drive the invocations until the end of the duration, or the stop channel is closed.
+func (bm *Benchmark) Run(conn *NetworkConnection, stop <-chan struct{}) *BenchmarkReport {
+	bm.lock.Lock()
+	bm.startTime = time.Now()
+	bm.lock.Unlock()
+	logger.Infof("Benchmark %s starts: %+v", bm.ID, bm.Options)
+
+	timer := time.NewTimer(time.Duration(bm.Options.Duration) * time.Second)
+	defer timer.Stop()
+
+	var ticker *time.Ticker
+	if bm.Options.TPS > 0 {
+		ticker = time.NewTicker(time.Duration(float64(time.Second) / bm.Options.TPS))
+		defer ticker.Stop()
+	}
+
+	status := BenchmarkStatusCompleted
+	// Tokens of concurrency.
+	tokens := make(chan struct{}, bm.Options.Concurrency)
+	var wg sync.WaitGroup
+	var seq int64
+loop:
+	for {
+		if ticker != nil {
+			select {
+			case <-ticker.C:
+			case <-timer.C:
+				break loop
+			case <-stop:
+				status = BenchmarkStatusStopped
+				break loop
+			}
+		}
+		select {
+		case tokens <- struct{}{}:
+		case <-timer.C:
+			break loop
+		case <-stop:
+			status = BenchmarkStatusStopped
+			break loop
+		}
+
+		seq++
+		bm.lock.Lock()
+		bm.submitted++
+		bm.lock.Unlock()
+		// To keep the connection active for a long running benchmark.
+		conn.ActiveTime = time.Now()
+
+		wg.Add(1)
+		go func(seq int64) {
+			defer func() {
+				<-tokens
+				wg.Done()
+			}()
+			bm.invoke(conn, GenerateArguments(bm.Options.Arguments, seq))
+		}(seq)
+	}
+	wg.Wait()
+
+	bm.lock.Lock()
+	bm.status = status
+	bm.endTime = time.Now()
+	bm.lock.Unlock()
+
+	report := bm.Report()
+	logger.Infof("Benchmark %s %s: %d succeeded, %d failed, throughput %.2f.", bm.ID, status, report.Succeeded, report.Failed, report.Throughput)
+	return report
+}
+
+// timingHandler to record the time when the endorsements are validated, before the commit.
+// The handler might still be running after a timeout, so the time is guarded by a lock.
+type timingHandler struct {
+	endorsed time.Time
+	next     invoke.Handler
+	sync.Mutex
+}
+
+func (h *timingHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	h.Lock()
+	h.endorsed = time.Now()
+	h.Unlock()
+	h.next.Handle(requestContext, clientContext)
+}
+
+func (h *timingHandler) endorsedTime() time.Time {
+	h.Lock()
+	defer h.Unlock()
+	return h.endorsed
+}
+
+func (bm *Benchmark) invoke(conn *NetworkConnection, args []string) {
+	options := bm.Options
+	begin := time.Now()
+	channelClient, request, reqOpts, err := prepareChaincodeRequest(conn, options.ChannelID, options.ChaincodeID, options.Targets, options.FunctionName, args)
+	if err != nil {
+		bm.record(begin, nil, BenchmarkCodeEndorsementFailure, err)
+		return
+	}
+
+	timing := &timingHandler{next: invoke.NewCommitHandler()}
+	handler := invoke.NewProposalProcessorHandler(
+		invoke.NewEndorsementHandler(
+			invoke.NewEndorsementValidationHandler(
+				invoke.NewSignatureValidationHandler(timing),
+			),
+		),
+	)
+	_, err = channelClient.InvokeHandler(handler, request, reqOpts...)
+	endorsed := timing.endorsedTime()
+	if endorsed.IsZero() {
+		bm.record(begin, nil, BenchmarkCodeEndorsementFailure, err)
+		return
+	}
+
+	code := peer.TxValidationCode_VALID.String()
+	if err != nil {
+		code = BenchmarkCodeCommitFailure
+		if s, ok := status.FromError(err); ok && s.Group == status.EventServerStatus {
+			code = peer.TxValidationCode(s.Code).String()
+		}
+	}
+	bm.record(begin, &endorsed, code, err)
+}
+
+func (bm *Benchmark) record(begin time.Time, endorsed *time.Time, code string, err error) {
+	end := time.Now()
+	bm.lock.Lock()
+	defer bm.lock.Unlock()
+
+	bm.codes[code]++
+	if err != nil {
+		msg := err.Error()
+		if _, ok := bm.errors[msg]; ok || len(bm.errors) < benchmarkMaxErrors {
+			bm.errors[msg]++
+		}
+	}
+	if endorsed != nil {
+		bm.endorse = append(bm.endorse, toMillisecond(endorsed.Sub(begin)))
+	}
+	if err == nil {
+		bm.commit = append(bm.commit, toMillisecond(end.Sub(*endorsed)))
+		bm.total = append(bm.total, toMillisecond(end.Sub(begin)))
+	}
+}
+
+func toMillisecond(d time.Duration) float64 {
+	return float64(d.Nanoseconds()) / 1000000
+}
+
+// Report to get the report of the benchmark, it can be called while running.
+func (bm *Benchmark) Report() *BenchmarkReport {
+	bm.lock.RLock()
+	defer bm.lock.RUnlock()
+
+	report := &BenchmarkReport{
+		ID:              bm.ID,
+		Options:         bm.Options,
+		Status:          bm.status,
+		Submitted:       bm.submitted,
+		Succeeded:       len(bm.total),
+		Endorse:         CalculateLatencyStats(bm.endorse),
+		Commit:          CalculateLatencyStats(bm.commit),
+		Total:           CalculateLatencyStats(bm.total),
+		ValidationCodes: map[string]int{},
+		Errors:          map[string]int{},
+	}
+	for code, count := range bm.codes {
+		report.ValidationCodes[code] = count
+		if code != peer.TxValidationCode_VALID.String() {
+			report.Failed += count
+		}
+	}
+	for msg, count := range bm.errors {
+		report.Errors[msg] = count
+	}
+
+	if bm.startTime.IsZero() {
+		return report
+	}
+	end := bm.endTime
+	if end.IsZero() {
+		end = time.Now()
+	} else {
+		report.EndTime = end.UnixNano() / 1000000
+	}
+	report.StartTime = bm.startTime.UnixNano() / 1000000
+	report.Elapsed = end.Sub(bm.startTime).Seconds()
+	if report.Elapsed > 0 {
+		report.Throughput = float64(report.Succeeded) / report.Elapsed
+	}
+	return report
+}
+
+// CalculateLatencyStats to calculate the statistics of the latencies.
+func CalculateLatencyStats(latencies []float64) LatencyStats {
+	stats := LatencyStats{Count: len(latencies)}
+	if len(latencies) < 1 {
+		return stats
+	}
+	sorted := append([]float64{}, latencies...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, latency := range sorted {
+		sum += latency
+	}
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+	stats.Mean = sum / float64(len(sorted))
+	stats.P50 = percentile(sorted, 50)
+	stats.P95 = percentile(sorted, 95)
+	stats.P99 = percentile(sorted, 99)
+	return stats
+}
+
+// percentile by the nearest rank method, the values must be sorted.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// BenchmarkComparison a metric of several benchmark reports.
+type BenchmarkComparison struct {
+	Metric string    `json:"metric"`
+	Values []float64 `json:"values"` // In the same order as the reports.
+	Deltas []string  `json:"deltas"` // Change against the first report.
+}
+
+// CompareBenchmarks to compare the main metrics of the reports, against the first one.
+func CompareBenchmarks(reports []*BenchmarkReport) []*BenchmarkComparison {
+	metrics := []struct {
+		name  string
+		value func(*BenchmarkReport) float64
+	}{
+		{"throughput", func(r *BenchmarkReport) float64 { return r.Throughput }},
+		{"succeeded", func(r *BenchmarkReport) float64 { return float64(r.Succeeded) }},
+		{"failed", func(r *BenchmarkReport) float64 { return float64(r.Failed) }},
+		{"endorse.p50", func(r *BenchmarkReport) float64 { return r.Endorse.P50 }},
+		{"endorse.p95", func(r *BenchmarkReport) float64 { return r.Endorse.P95 }},
+		{"endorse.p99", func(r *BenchmarkReport) float64 { return r.Endorse.P99 }},
+		{"commit.p50", func(r *BenchmarkReport) float64 { return r.Commit.P50 }},
+		{"commit.p95", func(r *BenchmarkReport) float64 { return r.Commit.P95 }},
+		{"commit.p99", func(r *BenchmarkReport) float64 { return r.Commit.P99 }},
+		{"total.p50", func(r *BenchmarkReport) float64 { return r.Total.P50 }},
+		{"total.p95", func(r *BenchmarkReport) float64 { return r.Total.P95 }},
+		{"total.p99", func(r *BenchmarkReport) float64 { return r.Total.P99 }},
+	}
+
+	comparisons := []*BenchmarkComparison{}
+	for _, metric := range metrics {
+		comparison := &BenchmarkComparison{Metric: metric.name, Values: []float64{}, Deltas: []string{}}
+		for _, report := range reports {
+			value := metric.value(report)
+			comparison.Values = append(comparison.Values, value)
+			base := comparison.Values[0]
+			if base == 0 {
+				comparison.Deltas = append(comparison.Deltas, "")
+			} else {
+				comparison.Deltas = append(comparison.Deltas, fmt.Sprintf("%+.1f%%", (value-base)/base*100))
+			}
+		}
+		comparisons = append(comparisons, comparison)
+	}
+	return comparisons
+}
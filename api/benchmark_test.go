@@ -0,0 +1,53 @@
+package api
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestGenerateArguments(t *testing.T) {
+	args := GenerateArguments([]string{"v{{seq}}", "{{random:10-20}}", "{{uuid}}", "{{unknown}}", "plain"}, 7)
+	if args[0] != "v7" || args[3] != "{{unknown}}" || args[4] != "plain" || len(args[2]) != 36 {
+		t.Fatalf("wrong arguments %v", args)
+	}
+	random, err := strconv.Atoi(args[1])
+	if err != nil || random < 10 || random > 20 {
+		t.Errorf("random %s is out of range", args[1])
+	}
+}
+
+func TestCalculateLatencyStats(t *testing.T) {
+	latencies := []float64{}
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, float64(i))
+	}
+	stats := CalculateLatencyStats(latencies)
+	if stats.Count != 100 || stats.Min != 1 || stats.Max != 100 || stats.Mean != 50.5 ||
+		stats.P50 != 50 || stats.P95 != 95 || stats.P99 != 99 {
+		t.Errorf("wrong stats %+v", stats)
+	}
+	if latencies[0] != 100 {
+		t.Error("latencies should not be sorted in place")
+	}
+	if empty := CalculateLatencyStats(nil); empty.Count != 0 || empty.P99 != 0 {
+		t.Errorf("wrong empty stats %+v", empty)
+	}
+}
+
+func TestBenchmarkOptionsAndCompare(t *testing.T) {
+	if _, err := NewBenchmark(BenchmarkOptions{ChannelID: "mychannel", ChaincodeID: "vehiclesharing", FunctionName: "createVehicle"}); err == nil {
+		t.Error("duration should be required")
+	}
+	bm, err := NewBenchmark(BenchmarkOptions{ChannelID: "mychannel", ChaincodeID: "vehiclesharing", FunctionName: "createVehicle", Duration: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bm.Options.Concurrency != 1 || bm.Report().Status != BenchmarkStatusRunning {
+		t.Errorf("wrong benchmark %+v", bm.Report())
+	}
+
+	comparisons := CompareBenchmarks([]*BenchmarkReport{{Throughput: 100}, {Throughput: 120}, {Throughput: 50}})
+	if comparisons[0].Metric != "throughput" || comparisons[0].Deltas[1] != "+20.0%" || comparisons[0].Deltas[2] != "-50.0%" {
+		t.Errorf("wrong comparison %+v", comparisons[0])
+	}
+}
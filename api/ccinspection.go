@@ -0,0 +1,481 @@
+package api
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/IBM/fablet/util"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+)
+
+// Formats of chaincode packages, besides util.PackageFormat_TAR and util.PackageFormat_TARGZ of the source.
+const (
+	ChaincodePackageFormatCDS       = "cds"       // ChaincodeDeploymentSpec, by "peer chaincode package".
+	ChaincodePackageFormatSignedCDS = "signedcds" // Signed by owners, by "peer chaincode package -s".
+	ChaincodePackageFormatLifecycle = "lifecycle" // Of Fabric 2.x, by "peer lifecycle chaincode package".
+)
+
+// maxInspectedFileSize files larger than it are listed without reading, e.g. for the entry point.
+const maxInspectedFileSize = 1024 * 1024
+
+// The file names and modes of code packages which the peer accepts, see ValidateCodePackage of Fabric platforms.
+var (
+	codePackageNames     = regexp.MustCompile(`^(/)?(src|META-INF)/.*`)
+	javaCodePackageNames = regexp.MustCompile(`^(/)?src/((src|META-INF)/.*|(build\.gradle|settings\.gradle|pom\.xml))`)
+	javaClassNames       = regexp.MustCompile(`.*\.class$`)
+	goMainFunc           = regexp.MustCompile(`(?m)^func main\(\)`)
+	goMainPackage        = regexp.MustCompile(`(?m)^package main\b`)
+	goModule             = regexp.MustCompile(`(?m)^module\s+"?([^\s"]+)"?`)
+)
+
+// ChaincodePackageInspection the files, language, entry point and ID of a chaincode package, with mistakes which would fail the installation.
+type ChaincodePackageInspection struct {
+	Format  string `json:"format"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	Type    string `json:"type"`
+	Label   string `json:"label,omitempty"` // Of Fabric 2.x packages.
+	// EntryPoint the file of the main function for Go and Java, or the start script of package.json for Node.
+	EntryPoint string                  `json:"entryPoint"`
+	Files      []*ChaincodePackageFile `json:"files"`
+	// PackageID the ID which the peer reports of the installed package, the hex hash of the code and of the name and version for Fabric 1.4,
+	// or <label>:<hash of the package> for Fabric 2.x. It is empty if the code package can't be generated.
+	PackageID string `json:"packageID"`
+	CodeHash  string `json:"codeHash"`
+	// Errors mistakes which fail the installation or instantiation.
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+	entries  map[string][]byte
+}
+
+// ChaincodePackageFile a file of the chaincode package.
+type ChaincodePackageFile struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	Mode string `json:"mode"` // In octal, e.g. 100644.
+	mode int64
+}
+
+// lifecycleMetadata metadata.json of Fabric 2.x packages.
+type lifecycleMetadata struct {
+	Path  string `json:"path"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+}
+
+// InspectChaincodePackage to inspect the package of the chaincode before the installation, the package can be the tar or tar.gz source as
+// it is installed, a CDS, or a Fabric 2.x package. The format is detected by the content, the given format is checked against it.
+// The code package is generated as it is installed, so that the ID is the same as the peer reports.
+func InspectChaincodePackage(cc *Chaincode, packageFormat string) (*ChaincodePackageInspection, error) {
+	inspection := &ChaincodePackageInspection{
+		Name:     cc.Name,
+		Version:  cc.Version,
+		Path:     cc.Path,
+		Type:     cc.Type,
+		Files:    []*ChaincodePackageFile{},
+		Errors:   []string{},
+		Warnings: []string{},
+		entries:  map[string][]byte{},
+	}
+	if len(cc.Package) == 0 {
+		return nil, errors.New("the chaincode package is empty")
+	}
+
+	var err error
+	switch {
+	case isGzip(cc.Package):
+		err = inspection.inspectGzip(cc, packageFormat)
+	case isTar(cc.Package):
+		err = inspection.inspectSource(cc, util.PackageFormat_TAR, packageFormat)
+	default:
+		err = inspection.inspectCDS(cc.Package)
+	}
+	if err != nil {
+		return nil, err
+	}
+	inspection.checkSource()
+	return inspection, nil
+}
+
+func isGzip(data []byte) bool {
+	return len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+func isTar(data []byte) bool {
+	return len(data) > 262 && string(data[257:262]) == "ustar"
+}
+
+// inspectGzip to inspect the tar.gz source, or the Fabric 2.x package which has metadata.json and code.tar.gz only.
+func (inspection *ChaincodePackageInspection) inspectGzip(cc *Chaincode, packageFormat string) error {
+	files, entries, err := readPackageEntries(cc.Package, true)
+	if err != nil {
+		return errors.WithMessage(err, "Error occurred when reading the tar.gz chaincode package.")
+	}
+	_, hasMetadata := entries["metadata.json"]
+	_, hasCode := entries["code.tar.gz"]
+	if len(files) != 2 || !hasMetadata || !hasCode {
+		return inspection.inspectSource(cc, util.PackageFormat_TARGZ, packageFormat)
+	}
+
+	inspection.Format = ChaincodePackageFormatLifecycle
+	metadata := &lifecycleMetadata{}
+	if err := json.Unmarshal(entries["metadata.json"], metadata); err != nil {
+		return errors.WithMessage(err, "Error occurred when parsing metadata.json of the Fabric 2.x package.")
+	}
+	inspection.Path, inspection.Type, inspection.Label = metadata.Path, strings.ToLower(metadata.Type), metadata.Label
+	inspection.Name, inspection.Version = "", ""
+	sum := sha256.Sum256(cc.Package)
+	inspection.PackageID = fmt.Sprintf("%s:%s", metadata.Label, hex.EncodeToString(sum[:]))
+	codeSum := sha256.Sum256(entries["code.tar.gz"])
+	inspection.CodeHash = hex.EncodeToString(codeSum[:])
+	if inspection.Files, inspection.entries, err = readPackageEntries(entries["code.tar.gz"], true); err != nil {
+		return errors.WithMessage(err, "Error occurred when reading code.tar.gz of the Fabric 2.x package.")
+	}
+	inspection.Warnings = append(inspection.Warnings, "Fabric 2.x packages are installed by \"peer lifecycle chaincode install\", Fablet installs source packages of Fabric 1.4")
+	return nil
+}
+
+// inspectSource to inspect the tar or tar.gz source, the code package is generated from it as it is installed.
+func (inspection *ChaincodePackageInspection) inspectSource(cc *Chaincode, format string, packageFormat string) error {
+	inspection.Format = format
+	files, entries, err := readPackageEntries(cc.Package, format == util.PackageFormat_TARGZ)
+	if err != nil {
+		return errors.WithMessagef(err, "Error occurred when reading the %s chaincode package.", format)
+	}
+	inspection.Files, inspection.entries = files, entries
+	if packageFormat != "" && packageFormat != format {
+		inspection.Errors = append(inspection.Errors, fmt.Sprintf("the package is %s, but the package format is %s", format, packageFormat))
+	}
+	for _, file := range files {
+		// The package is uncompressed to a folder when it is installed.
+		if name := path.Clean(file.Name); path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			inspection.Errors = append(inspection.Errors, fmt.Sprintf("the file %s is out of the package folder", file.Name))
+		}
+	}
+	inspection.detectType()
+	if len(inspection.Errors) > 0 || inspection.Type == "" {
+		return nil
+	}
+
+	tmpFolder, err := ioutil.TempDir("", "fablet")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpFolder)
+	unpacked := *cc
+	unpacked.Type = inspection.Type
+	if err := UnpackChaincode(&unpacked, format, tmpFolder); err != nil {
+		inspection.Errors = append(inspection.Errors, fmt.Sprintf("the package can't be uncompressed: %s", err.Error()))
+		return nil
+	}
+	ccPkg, err := newCCPackage(&unpacked)
+	if err != nil {
+		inspection.Errors = append(inspection.Errors, err.Error())
+		return nil
+	}
+	inspection.setPackageID(ccPkg.Code, nil)
+	inspection.validateCodePackage(ccPkg.Code)
+	return nil
+}
+
+// inspectCDS to inspect the ChaincodeDeploymentSpec, or the one signed by owners. Both are tried as the peer does, the signed one wins.
+func (inspection *ChaincodePackageInspection) inspectCDS(data []byte) error {
+	cds, scds := &pb.ChaincodeDeploymentSpec{}, extractSignedCDS(data)
+	inspection.Format = ChaincodePackageFormatSignedCDS
+	if scds == nil || proto.Unmarshal(scds.ChaincodeDeploymentSpec, cds) != nil || cds.GetChaincodeSpec().GetChaincodeId().GetName() == "" {
+		scds = nil
+		cds = &pb.ChaincodeDeploymentSpec{}
+		inspection.Format = ChaincodePackageFormatCDS
+		if err := proto.Unmarshal(data, cds); err != nil || cds.GetChaincodeSpec().GetChaincodeId().GetName() == "" {
+			return errors.New("the chaincode package is not tar, tar.gz, CDS or a Fabric 2.x package")
+		}
+	}
+
+	ccID := cds.GetChaincodeSpec().GetChaincodeId()
+	inspection.Name, inspection.Version, inspection.Path = ccID.GetName(), ccID.GetVersion(), ccID.GetPath()
+	inspection.Type = strings.ToLower(cds.GetChaincodeSpec().GetType().String())
+	var err error
+	if inspection.Files, inspection.entries, err = readPackageEntries(cds.CodePackage, true); err != nil {
+		return errors.WithMessage(err, "Error occurred when reading the code package of the CDS.")
+	}
+	if scds != nil && scds.InstantiationPolicy == nil {
+		inspection.Errors = append(inspection.Errors, "the instantiation policy of the signed CDS is empty")
+	}
+	inspection.setPackageID(cds.CodePackage, scds)
+	inspection.validateCodePackage(cds.CodePackage)
+	inspection.Warnings = append(inspection.Warnings, "CDS packages are installed by \"peer chaincode install\", Fablet installs tar or tar.gz source packages")
+	return nil
+}
+
+// extractSignedCDS to get the signed CDS of the envelope, nil if it is not.
+func extractSignedCDS(data []byte) *pb.SignedChaincodeDeploymentSpec {
+	env := &common.Envelope{}
+	payload := &common.Payload{}
+	scds := &pb.SignedChaincodeDeploymentSpec{}
+	if proto.Unmarshal(data, env) != nil || proto.Unmarshal(env.Payload, payload) != nil || payload.Header == nil ||
+		proto.Unmarshal(payload.Header.ChannelHeader, &common.ChannelHeader{}) != nil || proto.Unmarshal(payload.Data, scds) != nil {
+		return nil
+	}
+	return scds
+}
+
+// setPackageID to compute the ID as the CDS package, or the signed CDS package, of Fabric 1.4 peers.
+func (inspection *ChaincodePackageInspection) setPackageID(code []byte, scds *pb.SignedChaincodeDeploymentSpec) {
+	codeHash := sha256.Sum256(code)
+	metadataHash := sha256.Sum256([]byte(inspection.Name + inspection.Version))
+	hash := sha256.New()
+	hash.Write(codeHash[:])
+	hash.Write(metadataHash[:])
+	if scds != nil {
+		signatureHash := sha256.New()
+		signatureHash.Write(scds.InstantiationPolicy)
+		for _, endorsement := range scds.OwnerEndorsements {
+			signatureHash.Write(endorsement.Endorser)
+		}
+		hash.Write(signatureHash.Sum(nil))
+	}
+	inspection.CodeHash = hex.EncodeToString(codeHash[:])
+	inspection.PackageID = hex.EncodeToString(hash.Sum(nil))
+}
+
+// validateCodePackage to check file names and modes of the code package as the peer does.
+func (inspection *ChaincodePackageInspection) validateCodePackage(code []byte) {
+	files, _, err := readPackageEntries(code, true)
+	if err != nil {
+		inspection.Errors = append(inspection.Errors, fmt.Sprintf("the code package is invalid: %s", err.Error()))
+		return
+	}
+	illegalModes := []string{}
+	for _, file := range files {
+		if inspection.Type == ChaincodeType_JAVA {
+			if !javaCodePackageNames.MatchString(file.Name) || javaClassNames.MatchString(file.Name) {
+				inspection.Errors = append(inspection.Errors, fmt.Sprintf("the file %s is rejected by the peer, Java chaincode has files of build.gradle, settings.gradle, pom.xml, src/ and META-INF/ without classes", file.Name))
+			}
+		} else if !codePackageNames.MatchString(file.Name) {
+			inspection.Errors = append(inspection.Errors, fmt.Sprintf("the file %s is rejected by the peer, which accepts files of src/ and META-INF/", file.Name))
+		}
+		if file.mode&^0100666 != 0 {
+			illegalModes = append(illegalModes, fmt.Sprintf("%s (%s)", file.Name, file.Mode))
+		}
+	}
+	if len(illegalModes) > 0 {
+		inspection.Errors = append(inspection.Errors, fmt.Sprintf("files of modes rejected by the peer, which accepts rw-rw-rw- at most: %s", strings.Join(illegalModes, ", ")))
+	}
+}
+
+// detectType to detect the language by the files, it is checked against the given type.
+func (inspection *ChaincodePackageInspection) detectType() {
+	detected := ""
+	switch {
+	case inspection.hasFile("src/package.json"):
+		detected = ChaincodeType_NODE
+	case inspection.hasFile("src/build.gradle") || inspection.hasFile("src/pom.xml"):
+		detected = ChaincodeType_JAVA
+	default:
+		for name := range inspection.entries {
+			if strings.HasSuffix(name, ".go") {
+				detected = ChaincodeType_GOLANG
+				break
+			}
+		}
+	}
+
+	switch {
+	case inspection.Type == "" && detected == "":
+		inspection.Errors = append(inspection.Errors, "the language of the chaincode is not detected, there is no Go file, src/package.json, src/build.gradle or src/pom.xml")
+	case inspection.Type == "":
+		inspection.Type = detected
+	case detected != "" && detected != inspection.Type:
+		inspection.Errors = append(inspection.Errors, fmt.Sprintf("the package looks like %s chaincode, but the type is %s", detected, inspection.Type))
+	}
+}
+
+// checkSource to find the entry point, and check the Go import path, or package.json and build.gradle.
+func (inspection *ChaincodePackageInspection) checkSource() {
+	switch inspection.Type {
+	case ChaincodeType_GOLANG:
+		inspection.checkGo()
+	case ChaincodeType_NODE:
+		inspection.checkNode()
+	case ChaincodeType_JAVA:
+		inspection.checkJava()
+	}
+}
+
+func (inspection *ChaincodePackageInspection) checkGo() {
+	if inspection.Path == "" {
+		inspection.Errors = append(inspection.Errors, "the import path of the Go chaincode is required")
+		return
+	}
+	dir := path.Join("src", inspection.goPackageDir())
+	goFiles := []string{}
+	for _, name := range inspection.fileNames() {
+		if path.Dir(name) == dir && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+			goFiles = append(goFiles, name)
+		}
+	}
+	if len(goFiles) == 0 {
+		message := fmt.Sprintf("there is no Go file of the import path %s, it should be in %s/", inspection.Path, dir)
+		if mains := inspection.goMainPackages(); len(mains) > 0 {
+			message += fmt.Sprintf(", main packages are %s", strings.Join(mains, ", "))
+		}
+		inspection.Errors = append(inspection.Errors, message)
+		return
+	}
+	for _, name := range goFiles {
+		if content := inspection.entries[name]; goMainPackage.Match(content) && goMainFunc.Match(content) {
+			inspection.EntryPoint = name
+			return
+		}
+	}
+	inspection.Errors = append(inspection.Errors, fmt.Sprintf("the Go package %s has no main function of package main", inspection.Path))
+}
+
+// goPackageDir the folder of the import path under src/, by the module of src/go.mod of Fabric 2.x packages.
+func (inspection *ChaincodePackageInspection) goPackageDir() string {
+	goMod, ok := inspection.entries["src/go.mod"]
+	if !ok {
+		return inspection.Path
+	}
+	module := ""
+	if matches := goModule.FindSubmatch(goMod); matches != nil {
+		module = string(matches[1])
+	}
+	if inspection.Path == module {
+		return "."
+	}
+	if module != "" && strings.HasPrefix(inspection.Path, module+"/") {
+		return strings.TrimPrefix(inspection.Path, module+"/")
+	}
+	// It might be a relative folder of the package.
+	return strings.TrimPrefix(inspection.Path, "./")
+}
+
+// goMainPackages to find import paths of main packages, for the hint of a wrong path.
+func (inspection *ChaincodePackageInspection) goMainPackages() []string {
+	mains := []string{}
+	for _, name := range inspection.fileNames() {
+		if strings.HasPrefix(name, "src/") && strings.HasSuffix(name, ".go") && goMainFunc.Match(inspection.entries[name]) {
+			mains = append(mains, strings.TrimPrefix(path.Dir(name), "src/"))
+		}
+	}
+	return mains
+}
+
+func (inspection *ChaincodePackageInspection) checkNode() {
+	content, ok := inspection.entries["src/package.json"]
+	if !ok {
+		message := "package.json is not found, it should be src/package.json of the package"
+		for _, name := range inspection.fileNames() {
+			if path.Base(name) == "package.json" && !strings.Contains(name, "node_modules/") {
+				message = fmt.Sprintf("package.json is %s, it should be src/package.json of the package", name)
+				break
+			}
+		}
+		inspection.Errors = append(inspection.Errors, message)
+		return
+	}
+	packageJSON := &struct {
+		Main    string            `json:"main"`
+		Scripts map[string]string `json:"scripts"`
+	}{}
+	if err := json.Unmarshal(content, packageJSON); err != nil {
+		inspection.Errors = append(inspection.Errors, fmt.Sprintf("package.json is invalid: %s", err.Error()))
+		return
+	}
+	// The peer starts the chaincode by "npm start".
+	if start := packageJSON.Scripts["start"]; start != "" {
+		inspection.EntryPoint = start
+	} else {
+		inspection.EntryPoint = packageJSON.Main
+		inspection.Errors = append(inspection.Errors, "package.json has no start script, which the peer runs by \"npm start\"")
+	}
+	if inspection.hasFile("src/node_modules/") {
+		inspection.Warnings = append(inspection.Warnings, "node_modules is in the package, the peer installs dependencies by \"npm install\"")
+	}
+}
+
+func (inspection *ChaincodePackageInspection) checkJava() {
+	if !inspection.hasFile("src/build.gradle") && !inspection.hasFile("src/pom.xml") {
+		inspection.Errors = append(inspection.Errors, "build.gradle or pom.xml is not found, it should be src/build.gradle or src/pom.xml of the package")
+		return
+	}
+	for _, name := range inspection.fileNames() {
+		if strings.HasSuffix(name, ".java") && bytes.Contains(inspection.entries[name], []byte("public static void main(")) {
+			inspection.EntryPoint = name
+			return
+		}
+	}
+	inspection.Warnings = append(inspection.Warnings, "no Java class of the main method is found")
+}
+
+// hasFile to check the file, or the folder if the name ends with /.
+func (inspection *ChaincodePackageInspection) hasFile(name string) bool {
+	for _, file := range inspection.Files {
+		if file.Name == name || (strings.HasSuffix(name, "/") && strings.HasPrefix(file.Name, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (inspection *ChaincodePackageInspection) fileNames() []string {
+	names := []string{}
+	for _, file := range inspection.Files {
+		names = append(names, file.Name)
+	}
+	return names
+}
+
+// readPackageEntries to list files of the tar, with contents of files which are not too large.
+func readPackageEntries(data []byte, gzipped bool) ([]*ChaincodePackageFile, map[string][]byte, error) {
+	var reader io.Reader = bytes.NewReader(data)
+	if gzipped {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		reader = gzipReader
+	}
+	files := []*ChaincodePackageFile{}
+	entries := map[string][]byte{}
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if header.FileInfo().IsDir() {
+			continue
+		}
+		files = append(files, &ChaincodePackageFile{Name: header.Name, Size: header.Size, Mode: fmt.Sprintf("%o", header.Mode), mode: header.Mode})
+		if header.Size <= maxInspectedFileSize {
+			content, err := ioutil.ReadAll(tarReader)
+			if err != nil {
+				return nil, nil, err
+			}
+			entries[header.Name] = content
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, entries, nil
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+)
+
+type testPackageFile struct {
+	name    string
+	content string
+	mode    int64
+}
+
+// newTestPackage to create the tar or tar.gz of files, with folders of them.
+func newTestPackage(t *testing.T, gzipped bool, files ...testPackageFile) []byte {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(&buf)
+	if gzipped {
+		tarWriter = tar.NewWriter(gzipWriter)
+	}
+	dirs := map[string]bool{}
+	for _, file := range files {
+		for dir, parents := path.Dir(file.name), []string{}; ; dir = path.Dir(dir) {
+			if dir == "." || dirs[dir] {
+				for i := len(parents) - 1; i >= 0; i-- {
+					dirs[parents[i]] = true
+					if err := tarWriter.WriteHeader(&tar.Header{Name: parents[i] + "/", Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+						t.Fatal(err)
+					}
+				}
+				break
+			}
+			parents = append(parents, dir)
+		}
+		mode := file.mode
+		if mode == 0 {
+			mode = 0644
+		}
+		if err := tarWriter.WriteHeader(&tar.Header{Name: file.name, Mode: mode, Size: int64(len(file.content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write([]byte(file.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tarWriter.Close()
+	if gzipped {
+		gzipWriter.Close()
+	}
+	return buf.Bytes()
+}
+
+const testGoChaincode = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("vs")
+}
+`
+
+// testCDSPackageID the ID of Fabric 1.4 peers, of the code hash, and of the name and version.
+func testCDSPackageID(t *testing.T, inspection *ChaincodePackageInspection, name string, version string, signature []byte) string {
+	codeHash, err := hex.DecodeString(inspection.CodeHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadataHash := sha256.Sum256([]byte(name + version))
+	id := append(codeHash, metadataHash[:]...)
+	if signature != nil {
+		signatureHash := sha256.Sum256(signature)
+		id = append(id, signatureHash[:]...)
+	}
+	sum := sha256.Sum256(id)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestInspectChaincodePackage(t *testing.T) {
+	goPackage := newTestPackage(t, true, testPackageFile{name: "src/fablet/vs/main.go", content: testGoChaincode},
+		testPackageFile{name: "src/fablet/vs/lib/util.go", content: "package lib\n"})
+	cc := &Chaincode{Name: "vehiclesharing", Version: "1.0", Path: "fablet/vs", Type: ChaincodeType_GOLANG, Package: goPackage}
+	inspection, err := InspectChaincodePackage(cc, "tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inspection.Errors) > 0 || inspection.Format != "tar.gz" || inspection.EntryPoint != "src/fablet/vs/main.go" || len(inspection.Files) != 2 {
+		t.Errorf("expected the Go chaincode without errors, but got %+v", inspection)
+	}
+	if inspection.PackageID == "" || inspection.PackageID != testCDSPackageID(t, inspection, "vehiclesharing", "1.0", nil) {
+		t.Errorf("expected the ID of the CDS package, but got %s", inspection.PackageID)
+	}
+	// The same package, the same ID.
+	if again, _ := InspectChaincodePackage(cc, ""); again.PackageID != inspection.PackageID {
+		t.Errorf("expected the ID %s, but got %s", inspection.PackageID, again.PackageID)
+	}
+
+	// The import path is wrong, and the format.
+	cc.Path = "vs"
+	inspection, _ = InspectChaincodePackage(cc, "")
+	if len(inspection.Errors) != 2 || !strings.Contains(inspection.Errors[1], "main packages are fablet/vs") || inspection.PackageID != "" {
+		t.Errorf("expected errors of the import path, but got %v", inspection.Errors)
+	}
+	cc.Path = "fablet/vs"
+	inspection, _ = InspectChaincodePackage(cc, "tar")
+	if len(inspection.Errors) != 1 || inspection.Errors[0] != "the package is tar.gz, but the package format is tar" {
+		t.Errorf("expected error of the package format, but got %v", inspection.Errors)
+	}
+
+	// Node chaincode detected by package.json, with an executable file.
+	nodePackage := newTestPackage(t, false, testPackageFile{name: "src/package.json", content: `{"main": "index.js", "scripts": {"start": "node index.js"}}`},
+		testPackageFile{name: "src/index.js", content: "", mode: 0755})
+	inspection, _ = InspectChaincodePackage(&Chaincode{Name: "vs", Version: "1.0", Package: nodePackage}, "tar")
+	if inspection.Type != ChaincodeType_NODE || inspection.EntryPoint != "node index.js" || inspection.Format != "tar" {
+		t.Errorf("expected the Node chaincode, but got %+v", inspection)
+	}
+	if len(inspection.Errors) != 1 || !strings.Contains(inspection.Errors[0], "src/index.js (755)") {
+		t.Errorf("expected error of the file mode, but got %v", inspection.Errors)
+	}
+	// package.json is not in src/.
+	nodePackage = newTestPackage(t, false, testPackageFile{name: "vs/package.json", content: `{"main": "index.js"}`})
+	inspection, _ = InspectChaincodePackage(&Chaincode{Name: "vs", Version: "1.0", Type: ChaincodeType_NODE, Package: nodePackage}, "tar")
+	if len(inspection.Errors) != 2 || !strings.Contains(inspection.Errors[0], "rejected by the peer") ||
+		inspection.Errors[1] != "package.json is vs/package.json, it should be src/package.json of the package" {
+		t.Errorf("expected errors of package.json, but got %v", inspection.Errors)
+	}
+
+	// Java chaincode without the build file.
+	javaPackage := newTestPackage(t, false, testPackageFile{name: "src/src/main/java/Main.java", content: "public static void main(String[] args) {}"})
+	inspection, _ = InspectChaincodePackage(&Chaincode{Name: "vs", Version: "1.0", Type: ChaincodeType_JAVA, Package: javaPackage}, "tar")
+	if len(inspection.Errors) != 1 || !strings.Contains(inspection.Errors[0], "build.gradle or pom.xml is not found") {
+		t.Errorf("expected error of build.gradle, but got %v", inspection.Errors)
+	}
+	if _, err := InspectChaincodePackage(&Chaincode{Package: []byte("chaincode")}, ""); err == nil {
+		t.Error("expected error of the invalid package")
+	}
+}
+
+func TestInspectChaincodeCDS(t *testing.T) {
+	code := newTestPackage(t, true, testPackageFile{name: "src/fablet/vs/main.go", content: testGoChaincode})
+	cds, _ := proto.Marshal(&pb.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: "vehiclesharing", Version: "1.0", Path: "fablet/vs"}},
+		CodePackage: code,
+	})
+	inspection, err := InspectChaincodePackage(&Chaincode{Package: cds}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inspection.Format != ChaincodePackageFormatCDS || inspection.Name != "vehiclesharing" || inspection.Type != ChaincodeType_GOLANG ||
+		inspection.EntryPoint != "src/fablet/vs/main.go" || len(inspection.Errors) > 0 {
+		t.Errorf("expected the CDS of the Go chaincode, but got %+v", inspection)
+	}
+	if inspection.PackageID != testCDSPackageID(t, inspection, "vehiclesharing", "1.0", nil) {
+		t.Errorf("expected the ID of the CDS package, but got %s", inspection.PackageID)
+	}
+
+	// Signed by an owner.
+	scds, _ := proto.Marshal(&pb.SignedChaincodeDeploymentSpec{ChaincodeDeploymentSpec: cds, InstantiationPolicy: []byte("policy"),
+		OwnerEndorsements: []*pb.Endorsement{{Endorser: []byte("owner")}}})
+	channelHeader, _ := proto.Marshal(&common.ChannelHeader{Type: int32(common.HeaderType_CHAINCODE_PACKAGE)})
+	payload, _ := proto.Marshal(&common.Payload{Header: &common.Header{ChannelHeader: channelHeader}, Data: scds})
+	env, _ := proto.Marshal(&common.Envelope{Payload: payload})
+	inspection, err = InspectChaincodePackage(&Chaincode{Package: env}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inspection.Format != ChaincodePackageFormatSignedCDS || inspection.PackageID != testCDSPackageID(t, inspection, "vehiclesharing", "1.0", []byte("policyowner")) {
+		t.Errorf("expected the ID of the signed CDS package, but got %+v", inspection)
+	}
+
+	// The Fabric 2.x package.
+	lifecycle := newTestPackage(t, true, testPackageFile{name: "metadata.json", content: `{"path": "fablet/vs", "type": "golang", "label": "vs_1"}`},
+		testPackageFile{name: "code.tar.gz", content: string(code)})
+	inspection, err = InspectChaincodePackage(&Chaincode{Package: lifecycle}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(lifecycle)
+	if inspection.Format != ChaincodePackageFormatLifecycle || inspection.PackageID != "vs_1:"+hex.EncodeToString(sum[:]) ||
+		inspection.EntryPoint != "src/fablet/vs/main.go" || len(inspection.Errors) > 0 || len(inspection.Warnings) != 1 {
+		t.Errorf("expected the Fabric 2.x package, but got %+v", inspection)
+	}
+}
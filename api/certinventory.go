@@ -0,0 +1,292 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	protosmsp "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+)
+
+// Sources of certificates of the inventory.
+const (
+	CertSourcePeerTLSCA            = "peerTLSCA"
+	CertSourceOrdererTLSCA         = "ordererTLSCA"
+	CertSourceIdentity             = "identity"
+	CertSourceMSPRootCA            = "mspRootCA"
+	CertSourceMSPIntermediateCA    = "mspIntermediateCA"
+	CertSourceMSPTLSRootCA         = "mspTLSRootCA"
+	CertSourceMSPTLSIntermediateCA = "mspTLSIntermediateCA"
+	CertSourceEndorser             = "endorser"
+)
+
+// Statuses of certificates by the expiry.
+const (
+	CertStatusValid    = "valid"
+	CertStatusExpiring = "expiring"
+	CertStatusExpired  = "expired"
+)
+
+// DefaultCertExpiryWindows windows in days to flag certificates which are expiring.
+var DefaultCertExpiryWindows = []int{7, 30, 90}
+
+// DefaultCertEndorserBlocks the number of latest blocks per channel to find endorser certificates.
+const DefaultCertEndorserBlocks = 10
+
+// CertSource where the certificate is seen.
+type CertSource struct {
+	Type      string `json:"type"`
+	Name      string `json:"name"` // The peer, orderer, MSP ID or identity label.
+	ChannelID string `json:"channelID,omitempty"`
+}
+
+// CertInfo a certificate of the inventory, it is identified by the SHA-256 fingerprint.
+type CertInfo struct {
+	Fingerprint  string        `json:"fingerprint"`
+	Subject      string        `json:"subject"`
+	Issuer       string        `json:"issuer"`
+	SerialNumber string        `json:"serialNumber"`
+	SANs         []string      `json:"SANs"`
+	KeyType      string        `json:"keyType"`
+	IsCA         bool          `json:"isCA"`
+	NotBefore    time.Time     `json:"notBefore"`
+	NotAfter     time.Time     `json:"notAfter"`
+	DaysLeft     int           `json:"daysLeft"`
+	Status       string        `json:"status"`
+	ExpiryWindow int           `json:"expiryWindow"` // The smallest window it is expiring in, 0 if in none.
+	Sources      []*CertSource `json:"sources"`
+}
+
+// CertInventory certificates seen by the connection, in the order of the expiry.
+type CertInventory struct {
+	Certs       []*CertInfo `json:"certs"`
+	Windows     []int       `json:"windows"`
+	Errors      []string    `json:"errors"`
+	CollectTime time.Time   `json:"collectTime"`
+}
+
+// CertInventoryOption options to collect the certificate inventory.
+type CertInventoryOption struct {
+	Windows        []int
+	EndorserBlocks uint64
+}
+
+// CertInventoryOptionFunc to handle the inventory option.
+type CertInventoryOptionFunc func(opt *CertInventoryOption)
+
+// WithExpiryWindows windows in days to flag certificates which are expiring.
+func WithExpiryWindows(windows ...int) CertInventoryOptionFunc {
+	return func(opt *CertInventoryOption) {
+		opt.Windows = windows
+	}
+}
+
+// WithEndorserBlocks the number of latest blocks per channel to find endorser certificates, 0 not to find.
+func WithEndorserBlocks(blocks uint64) CertInventoryOptionFunc {
+	return func(opt *CertInventoryOption) {
+		opt.EndorserBlocks = blocks
+	}
+}
+
+// CollectCertInventory to collect certificates of peers, orderers, the identity, MSPs of channels and endorsers of latest blocks.
+// Failures of channels are in errors of the inventory, other certificates are still collected.
+func CollectCertInventory(conn *NetworkConnection, options ...CertInventoryOptionFunc) *CertInventory {
+	opt := &CertInventoryOption{Windows: DefaultCertExpiryWindows, EndorserBlocks: DefaultCertEndorserBlocks}
+	for _, option := range options {
+		option(opt)
+	}
+	inventory := NewCertInventory(time.Now(), opt.Windows)
+
+	for _, peer := range conn.Peers {
+		inventory.Add(peer.TLSCACert, &CertSource{Type: CertSourcePeerTLSCA, Name: peer.Name})
+	}
+	for _, orderer := range conn.Orderers {
+		inventory.Add(orderer.TLSCACert, &CertSource{Type: CertSourceOrdererTLSCA, Name: orderer.Name})
+	}
+	if cert, err := TLSCertByBytes(conn.Participant.Cert); err == nil {
+		inventory.Add(cert, &CertSource{Type: CertSourceIdentity, Name: conn.Participant.Label})
+	} else {
+		inventory.Errors = append(inventory.Errors, fmt.Sprintf("identity: %s", err.Error()))
+	}
+
+	channelIDs := []string{}
+	for channelID := range conn.Channels {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+	for _, channelID := range channelIDs {
+		if err := collectChannelMSPCerts(conn, channelID, inventory); err != nil {
+			inventory.Errors = append(inventory.Errors, fmt.Sprintf("MSPs of %s: %s", channelID, err.Error()))
+		}
+		if opt.EndorserBlocks > 0 {
+			if err := collectEndorserCerts(conn, channelID, opt.EndorserBlocks, inventory); err != nil {
+				inventory.Errors = append(inventory.Errors, fmt.Sprintf("endorsers of %s: %s", channelID, err.Error()))
+			}
+		}
+	}
+	inventory.Sort()
+	return inventory
+}
+
+// channelMSPConfigs to get Fabric MSP configs of the channel config.
+func channelMSPConfigs(conn *NetworkConnection, channelID string) ([]*protosmsp.FabricMSPConfig, error) {
+	ch, err := conn.SDK.ChannelContext(channelID, fabsdk.WithIdentity(conn.SignID))()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := ch.ChannelService().ChannelConfig()
+	if err != nil {
+		return nil, err
+	}
+	fabricMSPConfigs := []*protosmsp.FabricMSPConfig{}
+	for _, mspConfig := range cfg.MSPs() {
+		fabricMSPConfig := &protosmsp.FabricMSPConfig{}
+		if err := proto.Unmarshal(mspConfig.GetConfig(), fabricMSPConfig); err != nil {
+			return nil, err
+		}
+		fabricMSPConfigs = append(fabricMSPConfigs, fabricMSPConfig)
+	}
+	return fabricMSPConfigs, nil
+}
+
+func collectChannelMSPCerts(conn *NetworkConnection, channelID string, inventory *CertInventory) error {
+	fabricMSPConfigs, err := channelMSPConfigs(conn, channelID)
+	if err != nil {
+		return err
+	}
+	for _, fabricMSPConfig := range fabricMSPConfigs {
+		for sourceType, certs := range map[string][][]byte{
+			CertSourceMSPRootCA:            fabricMSPConfig.GetRootCerts(),
+			CertSourceMSPIntermediateCA:    fabricMSPConfig.GetIntermediateCerts(),
+			CertSourceMSPTLSRootCA:         fabricMSPConfig.GetTlsRootCerts(),
+			CertSourceMSPTLSIntermediateCA: fabricMSPConfig.GetTlsIntermediateCerts(),
+		} {
+			for _, certBytes := range certs {
+				if cert, err := TLSCertByBytes(certBytes); err == nil {
+					inventory.Add(cert, &CertSource{Type: sourceType, Name: fabricMSPConfig.GetName(), ChannelID: channelID})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func collectEndorserCerts(conn *NetworkConnection, channelID string, blocks uint64, inventory *CertInventory) error {
+	ledger, err := QueryLedger(conn, channelID, nil)
+	if err != nil {
+		return err
+	}
+	begin := uint64(0)
+	if ledger.Height > blocks {
+		begin = ledger.Height - blocks
+	}
+	latestBlocks, err := QueryBlock(conn, channelID, nil, begin, ledger.Height-begin)
+	if err != nil {
+		return err
+	}
+	for _, block := range latestBlocks {
+		for _, tx := range block.Transactions {
+			for _, action := range tx.Actions {
+				for _, endorser := range action.Endorsers {
+					inventory.Add(endorser.cert, &CertSource{Type: CertSourceEndorser, Name: endorser.MSPID, ChannelID: channelID})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// NewCertInventory to create an empty inventory, the expiry is calculated at the time.
+func NewCertInventory(now time.Time, windows []int) *CertInventory {
+	sortedWindows := append([]int{}, windows...)
+	sort.Ints(sortedWindows)
+	return &CertInventory{Certs: []*CertInfo{}, Windows: sortedWindows, Errors: []string{}, CollectTime: now}
+}
+
+// Add to add the certificate of the source, a certificate seen before has the source added. Nil is ignored.
+func (inventory *CertInventory) Add(cert *x509.Certificate, source *CertSource) {
+	if cert == nil {
+		return
+	}
+	sum := sha256.Sum256(cert.Raw)
+	fingerprint := hex.EncodeToString(sum[:])
+	for _, info := range inventory.Certs {
+		if info.Fingerprint != fingerprint {
+			continue
+		}
+		for _, existing := range info.Sources {
+			if *existing == *source {
+				return
+			}
+		}
+		info.Sources = append(info.Sources, source)
+		return
+	}
+
+	info := &CertInfo{
+		Fingerprint:  fingerprint,
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.Text(16),
+		SANs:         certSANs(cert),
+		KeyType:      certKeyType(cert),
+		IsCA:         cert.IsCA,
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		Sources:      []*CertSource{source},
+	}
+	left := cert.NotAfter.Sub(inventory.CollectTime)
+	info.DaysLeft = int(left.Hours() / 24)
+	info.Status = CertStatusValid
+	if left <= 0 {
+		info.Status = CertStatusExpired
+	} else {
+		for _, window := range inventory.Windows {
+			if left <= time.Duration(window)*24*time.Hour {
+				info.Status = CertStatusExpiring
+				info.ExpiryWindow = window
+				break
+			}
+		}
+	}
+	inventory.Certs = append(inventory.Certs, info)
+}
+
+// Sort to sort certificates by the expiry, the earliest first.
+func (inventory *CertInventory) Sort() {
+	sort.SliceStable(inventory.Certs, func(i, j int) bool {
+		return inventory.Certs[i].NotAfter.Before(inventory.Certs[j].NotAfter)
+	})
+}
+
+func certSANs(cert *x509.Certificate) []string {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
+
+func certKeyType(cert *x509.Certificate) string {
+	switch key := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		return "ECDSA " + key.Curve.Params().Name
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", key.N.BitLen())
+	case ed25519.PublicKey:
+		return "Ed25519"
+	}
+	return cert.PublicKeyAlgorithm.String()
+}
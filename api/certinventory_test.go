@@ -0,0 +1,73 @@
+package api
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+)
+
+func parseTestCert(t *testing.T, certPEM string) *x509.Certificate {
+	block, _ := pem.Decode([]byte(certPEM))
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestCertInventory(t *testing.T) {
+	now := time.Now()
+	expired := parseTestCert(t, newTestCertPEM(t, "expired", nil, now.AddDate(-1, 0, 0), now.AddDate(0, 0, -1)))
+	expiring := parseTestCert(t, newTestCertPEM(t, "expiring", []string{"peer0.example.com"}, now.AddDate(-1, 0, 0), now.AddDate(0, 0, 20)))
+	valid := parseTestCert(t, newTestCertPEM(t, "valid", nil, now.AddDate(-1, 0, 0), now.AddDate(1, 0, 0)))
+	identityPEM := newTestCertPEM(t, "admin", nil, now.AddDate(-1, 0, 0), now.AddDate(0, 0, 5))
+
+	conn := &NetworkConnection{
+		Participant: &Participant{Label: "admin", Cert: []byte(identityPEM)},
+		Peers: map[string]*Peer{
+			"peer0": {Name: "peer0", TLSCACert: expiring},
+			"peer1": {Name: "peer1", TLSCACert: expiring},
+			"peer2": {Name: "peer2"},
+		},
+		Orderers: map[string]*Orderer{
+			"orderer0": {Name: "orderer0", TLSCACert: expired},
+			"orderer1": {Name: "orderer1", TLSCACert: valid},
+		},
+	}
+	inventory := CollectCertInventory(conn, WithExpiryWindows(30, 7))
+	if len(inventory.Certs) != 4 || len(inventory.Errors) != 0 || inventory.Windows[0] != 7 {
+		t.Fatalf("expected 4 certificates, but got %+v", inventory)
+	}
+
+	// Sorted by the expiry, the same certificate of peers is once.
+	expectations := []struct {
+		subject string
+		status  string
+		window  int
+		sources int
+	}{
+		{"CN=expired", CertStatusExpired, 0, 1},
+		{"CN=admin", CertStatusExpiring, 7, 1},
+		{"CN=expiring", CertStatusExpiring, 30, 2},
+		{"CN=valid", CertStatusValid, 0, 1},
+	}
+	for idx, expected := range expectations {
+		info := inventory.Certs[idx]
+		if info.Subject != expected.subject || info.Status != expected.status || info.ExpiryWindow != expected.window || len(info.Sources) != expected.sources {
+			t.Errorf("expected %+v, but got %+v", expected, info)
+		}
+	}
+	if info := inventory.Certs[2]; info.KeyType != "ECDSA P-256" || len(info.SANs) != 1 || info.SANs[0] != "peer0.example.com" || info.DaysLeft != 19 {
+		t.Errorf("expected details of the certificate, but got %+v", info)
+	}
+	if source := inventory.Certs[1].Sources[0]; source.Type != CertSourceIdentity || source.Name != "admin" {
+		t.Errorf("expected the identity, but got %+v", source)
+	}
+
+	// The same source is not added twice.
+	inventory.Add(valid, &CertSource{Type: CertSourceOrdererTLSCA, Name: "orderer1"})
+	if len(inventory.Certs[3].Sources) != 1 {
+		t.Errorf("expected the source once, but got %+v", inventory.Certs[3].Sources)
+	}
+}
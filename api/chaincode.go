@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/IBM/fablet/util"
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -34,6 +36,8 @@ type Chaincode struct {
 	Constructor []string `json:"constructor"` // Arguments for instantiation
 	// For status. The chaincode might be instantiated (on channel) by not installed (on peer).
 	Installed bool `json:"installed"` // It might be false while channelID is not empty, since it is instantiated in channel but not installed in current peer.
+	// PackageID of the installed package reported by the peer, see ChaincodePackageInspection.
+	PackageID string `json:"packageID,omitempty"`
 }
 
 const (
@@ -46,13 +50,22 @@ func (cc *Chaincode) String() string {
 	return fmt.Sprintf("%s:%s", cc.Name, cc.Version)
 }
 
-// InstallChaincode to handle the detailed corresponding message, and multiple peers - some failed issue, the installation will be executed for multiple times.
-// TODO to use resmgmt.InstallCC
-func InstallChaincode(conn *NetworkConnection, cc *Chaincode, targets []string) (map[string]ExecutionResult, error) {
-	if len(targets) < 1 {
-		return nil, errors.New("no any targets to install chaincode")
+// UnpackChaincode to uncompress the tar or tar.gz package into the folder, and set the path of the chaincode to it.
+func UnpackChaincode(cc *Chaincode, packageFormat string, folder string) error {
+	if err := util.UnTar(cc.Package, packageFormat, folder); err != nil {
+		return err
 	}
+	if cc.Type == ChaincodeType_GOLANG {
+		cc.BasePath = folder
+	} else {
+		// for Node and Java type chaincode
+		cc.Path = folder
+	}
+	return nil
+}
 
+// newCCPackage to generate the code package from the unpacked chaincode, as it is installed.
+func newCCPackage(cc *Chaincode) (*resource.CCPackage, error) {
 	var ccPkg *resource.CCPackage
 	var err error
 	if cc.Type == ChaincodeType_GOLANG {
@@ -68,6 +81,20 @@ func InstallChaincode(conn *NetworkConnection, cc *Chaincode, targets []string)
 	if err != nil {
 		return nil, errors.WithMessagef(err, "Error occurred when generating chaincode package of \"%s\"", cc.String())
 	}
+	return ccPkg, nil
+}
+
+// InstallChaincode to handle the detailed corresponding message, and multiple peers - some failed issue, the installation will be executed for multiple times.
+// TODO to use resmgmt.InstallCC
+func InstallChaincode(conn *NetworkConnection, cc *Chaincode, targets []string) (map[string]ExecutionResult, error) {
+	if len(targets) < 1 {
+		return nil, errors.New("no any targets to install chaincode")
+	}
+
+	ccPkg, err := newCCPackage(cc)
+	if err != nil {
+		return nil, err
+	}
 	icr := resource.InstallChaincodeRequest{Name: cc.Name, Path: cc.Path, Version: cc.Version, Package: ccPkg}
 	ctx := conn.Client
 	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeoutType(fab.PeerResponse))
@@ -159,6 +186,7 @@ func InstantiateChaincode(conn *NetworkConnection, cc *Chaincode, target string,
 		return "", errors.WithMessagef(err, "Failed to instantiate the chaincode %s:%s on channel %s.", cc.Name, cc.Version, cc.ChannelID)
 	}
 	logger.Infof("Succeed instantiated the chaincode %s:%s on channel %s.", cc.Name, cc.Version, cc.ChannelID)
+	InvalidateChaincodeMetadata(conn, cc.ChannelID, cc.Name)
 
 	return insRes.TransactionID, nil
 }
@@ -200,6 +228,7 @@ func UpgradeChaincode(conn *NetworkConnection, cc *Chaincode, target string, ord
 		return "", errors.WithMessagef(err, "Failed to upgrade the chaincode %s:%s on channel %s.", cc.Name, cc.Version, cc.ChannelID)
 	}
 	logger.Infof("Succeed upgrade the chaincode %s:%s on channel %s.", cc.Name, cc.Version, cc.ChannelID)
+	InvalidateChaincodeMetadata(conn, cc.ChannelID, cc.Name)
 
 	return updRes.TransactionID, nil
 }
@@ -215,45 +244,109 @@ const (
 )
 
 // ExecuteChaincode to invoke a chaincode
-// TODO to determine the targets
+// If the targets are empty, the SDK selects them, PlanEndorsers selects them by a strategy.
+// If the transaction is invalidated when committing, the response is returned with the error, with the TxValidationCode.
 func ExecuteChaincode(conn *NetworkConnection, channelID string, chaincodeID string,
 	operType ChaincodeOperType, targets []string,
 	funcName string, args []string,
 	options ...channel.RequestOption) (*channel.Response, error) {
-	channelContext := conn.SDK.ChannelContext(channelID, fabsdk.WithIdentity(conn.SignID))
-	channelClient, err := channel.New(channelContext)
-
+	channelClient, request, reqOpts, err := prepareChaincodeRequest(conn, channelID, chaincodeID, targets, funcName, args, options...)
 	if err != nil {
-		return nil, errors.WithMessagef(err, "Error occurred when creating a new client for channel %s.", channelID)
+		return nil, err
 	}
 
-	argsByte := make([][]byte, len(args))
-	for idx, arg := range args {
-		argsByte[idx] = []byte(arg)
-	}
-
-	reqOpts := []channel.RequestOption{}
-	reqOpts = append(reqOpts, channel.WithTargetEndpoints(targets...))
-	reqOpts = append(reqOpts, channel.WithRetry(retry.DefaultChannelOpts))
-	reqOpts = append(reqOpts, options...)
-
 	oper := channelClient.Execute
 	if operType == ChaincodeOperTypeQuery {
 		oper = channelClient.Query
 	}
 
-	response, err := oper(
-		channel.Request{
-			ChaincodeID: chaincodeID,
-			Fcn:         funcName,
-			Args:        argsByte,
-		},
-		reqOpts...,
-	)
+	response, err := oper(request, reqOpts...)
 
 	if err != nil {
-		return nil, errors.WithMessagef(err, "Error occurred when executing the chaincode %s.", chaincodeID)
+		err = errors.WithMessagef(err, "Error occurred when executing the chaincode %s.", chaincodeID)
+		// The transaction is endorsed and sent, but invalidated when committing, e.g. MVCC_READ_CONFLICT.
+		if response.TransactionID != "" {
+			return &response, err
+		}
+		return nil, err
 	}
 
 	return &response, nil
 }
+
+// SubmitChaincode to invoke a chaincode and send the transaction to the orderer, without waiting for the commit.
+// The TxValidationCode of the response is not set, the transaction status should be tracked by the transaction ID.
+func SubmitChaincode(conn *NetworkConnection, channelID string, chaincodeID string, targets []string,
+	funcName string, args []string,
+	options ...channel.RequestOption) (*channel.Response, error) {
+	return submitChaincode(conn, channelID, chaincodeID, targets, funcName, args, nil, options...)
+}
+
+// submitChaincode beforeSend is called with the transaction ID before the transaction is sent, if it is not nil.
+func submitChaincode(conn *NetworkConnection, channelID string, chaincodeID string, targets []string,
+	funcName string, args []string, beforeSend func(txID string, eventService fab.EventService),
+	options ...channel.RequestOption) (*channel.Response, error) {
+	channelClient, request, reqOpts, err := prepareChaincodeRequest(conn, channelID, chaincodeID, targets, funcName, args, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := invoke.NewProposalProcessorHandler(
+		invoke.NewEndorsementHandler(
+			invoke.NewEndorsementValidationHandler(
+				invoke.NewSignatureValidationHandler(&submitTxHandler{beforeSend: beforeSend}),
+			),
+		),
+	)
+	response, err := channelClient.InvokeHandler(handler, request, reqOpts...)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Error occurred when submitting the chaincode %s.", chaincodeID)
+	}
+
+	return &response, nil
+}
+
+// submitTxHandler the same as invoke.CommitTxHandler, but not to wait for the transaction status event.
+type submitTxHandler struct {
+	beforeSend func(txID string, eventService fab.EventService)
+}
+
+func (h *submitTxHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	tx, err := clientContext.Transactor.CreateTransaction(fab.TransactionRequest{
+		Proposal:          requestContext.Response.Proposal,
+		ProposalResponses: requestContext.Response.Responses,
+	})
+	if err != nil {
+		requestContext.Error = errors.WithMessage(err, "CreateTransaction failed")
+		return
+	}
+	if h.beforeSend != nil {
+		h.beforeSend(string(requestContext.Response.TransactionID), clientContext.EventService)
+	}
+	if _, err = clientContext.Transactor.SendTransaction(tx); err != nil {
+		requestContext.Error = errors.WithMessage(err, "SendTransaction failed")
+	}
+}
+
+func prepareChaincodeRequest(conn *NetworkConnection, channelID string, chaincodeID string, targets []string,
+	funcName string, args []string,
+	options ...channel.RequestOption) (*channel.Client, channel.Request, []channel.RequestOption, error) {
+	channelContext := conn.SDK.ChannelContext(channelID, fabsdk.WithIdentity(conn.SignID))
+	channelClient, err := channel.New(channelContext)
+
+	if err != nil {
+		return nil, channel.Request{}, nil, errors.WithMessagef(err, "Error occurred when creating a new client for channel %s.", channelID)
+	}
+
+	reqOpts := []channel.RequestOption{}
+	reqOpts = append(reqOpts, channel.WithTargetEndpoints(targets...))
+	reqOpts = append(reqOpts, channel.WithRetry(retry.DefaultChannelOpts))
+	reqOpts = append(reqOpts, options...)
+
+	request := channel.Request{
+		ChaincodeID: chaincodeID,
+		Fcn:         funcName,
+		Args:        getBytes(args),
+	}
+	return channelClient, request, reqOpts, nil
+}
@@ -27,7 +27,7 @@ func getJoinedChannels(conn *NetworkConnection, endpointURL string) ([]string, e
 	return channelIDs, nil
 }
 
-// CreateChannel to create a channel
+// CreateChannel to create a channel, the channel ID of the tx is returned even if it fails to create it.
 func CreateChannel(conn *NetworkConnection, txContent []byte, orderer string) (string, error) {
 	cub, _ := resource.ExtractChannelConfig(txContent)
 	cu := &common.ConfigUpdate{}
@@ -37,13 +37,16 @@ func CreateChannel(conn *NetworkConnection, txContent []byte, orderer string) (s
 	}
 	channelID := cu.GetChannelId()
 	resMgmtClient, err := resmgmt.New(conn.ClientProvider)
+	if err != nil {
+		return channelID, err
+	}
 	req := resmgmt.SaveChannelRequest{
 		ChannelID:     channelID,
 		ChannelConfig: bytes.NewReader(txContent)}
 
 	_, err = resMgmtClient.SaveChannel(req, resmgmt.WithRetry(retry.DefaultResMgmtOpts), resmgmt.WithOrdererEndpoint(orderer))
 	if err != nil {
-		return "", err
+		return channelID, err
 	}
 	return channelID, nil
 }
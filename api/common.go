@@ -2,6 +2,7 @@ package api
 
 import (
 	"crypto/x509"
+	"sync"
 	"time"
 
 	"github.com/IBM/fablet/log"
@@ -116,6 +117,26 @@ type NetworkConnection struct {
 	ChannelChaincodes  map[string][]*Chaincode
 	ChannelOrderers    map[string][]*Orderer
 	ChannelAnchorPeers map[string][]string
+
+	// Endpoint configs of the discovered network, which the sdk is updated with. It is nil without discovery.
+	EndpointConfigs *EndpointConfigs
+
+	// Contract metadata of chaincodes written with the contract API, map[channelID]map[chaincodeID].
+	ChannelChaincodeMetadata map[string]map[string]*ContractMetadata
+	metadataLock             sync.RWMutex
+	// Expiry of chaincodes cached without metadata, map[channelID]map[chaincodeID].
+	noMetadataExpiry map[string]map[string]time.Time
+
+	// Endorser selections cached by channel, chaincode and strategy.
+	selections    map[string]*EndorserSelection
+	selectionLock sync.Mutex
+}
+
+// EndpointConfigs endpoint configs of the network, which override the configs of the connection profile.
+type EndpointConfigs struct {
+	OrdererConfigs *OrdererConfigCognRes
+	ChannelConfigs *ChannelConfigCongnRes
+	ChannelPeers   *ChannelPeerCongnRes
 }
 
 // NetworkOverview for whole network
@@ -50,11 +50,11 @@ func getOnlyFile(path string) string {
 func getConnectionSimple() (*NetworkConnection, error) {
 	return NewConnection(
 		&ConnectionProfile{connConfig, yamlConfigType},
-		&Participant{"TestAdmin", "", mspIDOrg1, testCert, testPrivKey, nil}, true)
+		&Participant{Label: "TestAdmin", MSPID: mspIDOrg1, Cert: testCert, PrivateKey: testPrivKey}, true)
 }
 
 func getConnectionPre() (*NetworkConnection, error) {
 	return NewConnection(
 		&ConnectionProfile{connConfigPre, yamlConfigType},
-		&Participant{"TestAdmin", "", mspIDOrg1, testCert, testPrivKey, nil}, true)
+		&Participant{Label: "TestAdmin", MSPID: mspIDOrg1, Cert: testCert, PrivateKey: testPrivKey}, true)
 }
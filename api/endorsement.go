@@ -0,0 +1,333 @@
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/pkg/errors"
+)
+
+const (
+	// EndorsementDiffStatus the response status differs.
+	EndorsementDiffStatus = "status"
+	// EndorsementDiffPayload the response payload differs.
+	EndorsementDiffPayload = "payload"
+	// EndorsementDiffRead the version of a read key differs, or the key is not read by all.
+	EndorsementDiffRead = "read"
+	// EndorsementDiffWrite the value of a written key differs, or the key is not written by all.
+	EndorsementDiffWrite = "write"
+
+	// endorsementAbsent the value for the peer which doesn't have the key.
+	endorsementAbsent = "<absent>"
+	// endorsementDeleted the value for a deleted key.
+	endorsementDeleted = "<deleted>"
+)
+
+// PeerEndorsement the endorsement from one peer.
+type PeerEndorsement struct {
+	Peer             string            `json:"peer"`
+	Status           int32             `json:"status"`
+	Message          string            `json:"message"`
+	Payload          string            `json:"payload"`
+	ProposalResponse *ProposalResponse `json:"proposalResponse"`
+	Error            string            `json:"error"`
+}
+
+// EndorsementDiff a difference between endorsements of the same proposal.
+type EndorsementDiff struct {
+	Kind      string            `json:"kind"`
+	NameSpace string            `json:"nameSpace"`
+	Key       string            `json:"key"`
+	Values    map[string]string `json:"values"` // map[peer]value
+}
+
+// EndorsementComparison comparison result of the endorsements from all peers.
+type EndorsementComparison struct {
+	TransactionID string             `json:"transactionID"`
+	Endorsements  []*PeerEndorsement `json:"endorsements"`
+	Diffs         []*EndorsementDiff `json:"diffs"`
+	Consistent    bool               `json:"consistent"`
+}
+
+// FindChaincodePeers to find all peers of the channel on which the instantiated version of the chaincode has been installed.
+// Peers which fail to be queried, or hold other versions of the chaincode only, are returned as endorsements with error.
+func FindChaincodePeers(conn *NetworkConnection, channelID string, chaincodeID string) ([]string, []*PeerEndorsement, error) {
+	channel, ok := conn.Channels[channelID]
+	if !ok {
+		return nil, nil, errors.Errorf("channel %s is not found", channelID)
+	}
+	version := instantiatedVersion(conn, channelID, chaincodeID)
+
+	var wg sync.WaitGroup
+	var locker sync.Mutex
+	targets := []string{}
+	failed := []*PeerEndorsement{}
+	for _, peerName := range channel.Peers.StringList() {
+		peer := conn.findPeer(peerName)
+		if peer == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(peer *Peer) {
+			defer wg.Done()
+			ccs, err := QueryInstalledChaincodes(conn, peer.URL)
+			if err != nil {
+				logger.Errorf("Failed to query installed chaincodes of %s: %s", peer.URL, err.Error())
+				locker.Lock()
+				failed = append(failed, &PeerEndorsement{Peer: peer.URL,
+					Error: errors.WithMessage(err, "Error occurred when querying installed chaincodes").Error()})
+				locker.Unlock()
+				return
+			}
+			installed := []string{}
+			for _, cc := range ccs {
+				if cc.Name != chaincodeID {
+					continue
+				}
+				if version == "" || cc.Version == version {
+					locker.Lock()
+					targets = append(targets, peer.URL)
+					locker.Unlock()
+					return
+				}
+				installed = append(installed, cc.Version)
+			}
+			if len(installed) > 0 {
+				locker.Lock()
+				failed = append(failed, &PeerEndorsement{Peer: peer.URL,
+					Error: fmt.Sprintf("version %s of chaincode %s is instantiated, but %s installed", version, chaincodeID, strings.Join(installed, ", "))})
+				locker.Unlock()
+			}
+		}(peer)
+	}
+	wg.Wait()
+
+	sort.Strings(targets)
+	sort.Slice(failed, func(i, j int) bool { return failed[i].Peer < failed[j].Peer })
+	return targets, failed, nil
+}
+
+// instantiatedVersion returns the version of the chaincode instantiated on the channel, or empty if it is unknown.
+func instantiatedVersion(conn *NetworkConnection, channelID string, chaincodeID string) string {
+	ccs, err := QueryInstantiatedChaincodes(conn, channelID)
+	if err != nil {
+		logger.Errorf("Failed to query instantiated chaincodes of channel %s: %s", channelID, err.Error())
+		ccs = conn.ChannelChaincodes[channelID]
+	}
+	for _, cc := range ccs {
+		if cc.Name == chaincodeID {
+			return cc.Version
+		}
+	}
+	return ""
+}
+
+// EndorseChaincode to send the same proposal to all the targets, or all peers holding the chaincode if targets are empty.
+// The transaction will not be submitted to the orderer, the endorsements are compared with each other instead.
+func EndorseChaincode(conn *NetworkConnection, channelID string, chaincodeID string, targets []string,
+	funcName string, args []string) (*EndorsementComparison, error) {
+	var err error
+	failed := []*PeerEndorsement{}
+	if len(targets) < 1 {
+		if targets, failed, err = FindChaincodePeers(conn, channelID, chaincodeID); err != nil {
+			return nil, err
+		}
+	}
+	if len(targets) < 1 && len(failed) < 1 {
+		return nil, errors.Errorf("no any peer holds the chaincode %s in channel %s", chaincodeID, channelID)
+	}
+
+	ctx := conn.Client
+	txh, err := txn.NewHeader(ctx, channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when creating the transaction header.")
+	}
+	proposal, err := txn.CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{
+		ChaincodeID: chaincodeID,
+		Fcn:         funcName,
+		Args:        getBytes(args),
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when creating the proposal.")
+	}
+
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeoutType(fab.PeerResponse))
+	defer cancel()
+
+	endorsements := make([]*PeerEndorsement, len(targets))
+	var wg sync.WaitGroup
+	for idx, target := range targets {
+		endorsements[idx] = &PeerEndorsement{Peer: target}
+		peerCfg, err := comm.NetworkPeerConfig(ctx.EndpointConfig(), target)
+		if err != nil {
+			endorsements[idx].Error = errors.WithMessagef(err, "Error occurred when finding target \"%s\"", target).Error()
+			continue
+		}
+		peer, err := ctx.InfraProvider().CreatePeerFromConfig(peerCfg)
+		if err != nil {
+			endorsements[idx].Error = errors.WithMessagef(err, "Error occurred when getting network peer from target \"%s\"", target).Error()
+			continue
+		}
+
+		wg.Add(1)
+		// Per peer, to have the response or error of each one.
+		go func(endorsement *PeerEndorsement, processor fab.ProposalProcessor) {
+			defer wg.Done()
+			responses, err := txn.SendProposal(reqCtx, proposal, []fab.ProposalProcessor{processor})
+			if err != nil {
+				endorsement.Error = err.Error()
+				return
+			}
+			response := responses[0]
+			endorsement.Status = response.GetResponse().GetStatus()
+			endorsement.Message = response.GetResponse().GetMessage()
+			endorsement.Payload = string(response.GetResponse().GetPayload())
+			endorsement.ProposalResponse = translateProposalResponsePayload(response.GetPayload())
+		}(endorsements[idx], peer)
+	}
+	wg.Wait()
+
+	return CompareEndorsements(string(txh.TransactionID()), append(endorsements, failed...)), nil
+}
+
+// CompareEndorsements to find all differences among the endorsements, the ones with error are ignored.
+func CompareEndorsements(txID string, endorsements []*PeerEndorsement) *EndorsementComparison {
+	comparison := &EndorsementComparison{TransactionID: txID, Endorsements: endorsements, Diffs: []*EndorsementDiff{}, Consistent: true}
+
+	responded := []*PeerEndorsement{}
+	for _, endorsement := range endorsements {
+		if endorsement.Error != "" {
+			comparison.Consistent = false
+			continue
+		}
+		responded = append(responded, endorsement)
+	}
+
+	status := map[string]string{}
+	payload := map[string]string{}
+	reads := map[string]map[string]string{}  // map[ns\x00key]map[peer]version
+	writes := map[string]map[string]string{} // map[ns\x00key]map[peer]value
+	for _, endorsement := range responded {
+		status[endorsement.Peer] = fmt.Sprintf("%d", endorsement.Status)
+		payload[endorsement.Peer] = endorsement.Payload
+		collectEndorsementRWSet(endorsement, reads, writes)
+	}
+
+	if differs(status, responded) {
+		comparison.Diffs = append(comparison.Diffs, &EndorsementDiff{Kind: EndorsementDiffStatus, Values: status})
+	}
+	if differs(payload, responded) {
+		comparison.Diffs = append(comparison.Diffs, &EndorsementDiff{Kind: EndorsementDiffPayload, Values: payload})
+	}
+	comparison.Diffs = append(comparison.Diffs, keyDiffs(EndorsementDiffRead, reads, responded)...)
+	comparison.Diffs = append(comparison.Diffs, keyDiffs(EndorsementDiffWrite, writes, responded)...)
+
+	if len(comparison.Diffs) > 0 {
+		comparison.Consistent = false
+	}
+	return comparison
+}
+
+func collectEndorsementRWSet(endorsement *PeerEndorsement, reads map[string]map[string]string, writes map[string]map[string]string) {
+	if endorsement.ProposalResponse == nil || endorsement.ProposalResponse.TXReadWriteSet == nil {
+		return
+	}
+	put := func(kvs map[string]map[string]string, ns string, key string, value string) {
+		k := ns + "\x00" + key
+		if _, ok := kvs[k]; !ok {
+			kvs[k] = map[string]string{}
+		}
+		kvs[k][endorsement.Peer] = value
+	}
+
+	for _, nsrwset := range endorsement.ProposalResponse.TXReadWriteSet.NSReadWriteSets {
+		for _, read := range nsrwset.KVReadSet {
+			put(reads, nsrwset.NameSpace, read.Key, fmt.Sprintf("%d:%d", read.VerBlockNum, read.VerTxNum))
+		}
+		for _, write := range nsrwset.KVWriteSet {
+			put(writes, nsrwset.NameSpace, write.Key, writeValue(write))
+		}
+		// Keys and values of private data are hashes.
+		for _, col := range nsrwset.HashReadWriteCols {
+			ns := nsrwset.NameSpace + "/" + col.CollectionName
+			for _, read := range col.KVReadSet {
+				put(reads, ns, hex.EncodeToString([]byte(read.Key)), fmt.Sprintf("%d:%d", read.VerBlockNum, read.VerTxNum))
+			}
+			for _, write := range col.KVWriteSet {
+				value := endorsementDeleted
+				if !write.IsDelete {
+					value = hex.EncodeToString([]byte(fmt.Sprintf("%v", write.Value)))
+				}
+				put(writes, ns, hex.EncodeToString([]byte(write.Key)), value)
+			}
+		}
+	}
+}
+
+func writeValue(write *KVWrite) string {
+	if write.IsDelete {
+		return endorsementDeleted
+	}
+	if str, ok := write.Value.(string); ok {
+		return str
+	}
+	return fmt.Sprintf("%v", write.Value)
+}
+
+// differs fills the absent peers, and returns if there is any different value.
+func differs(values map[string]string, endorsements []*PeerEndorsement) bool {
+	for _, endorsement := range endorsements {
+		if _, ok := values[endorsement.Peer]; !ok {
+			values[endorsement.Peer] = endorsementAbsent
+		}
+	}
+	first := true
+	var firstValue string
+	for _, value := range values {
+		if first {
+			first, firstValue = false, value
+		} else if value != firstValue {
+			return true
+		}
+	}
+	return false
+}
+
+func keyDiffs(kind string, kvs map[string]map[string]string, endorsements []*PeerEndorsement) []*EndorsementDiff {
+	keys := []string{}
+	for k := range kvs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	diffs := []*EndorsementDiff{}
+	for _, k := range keys {
+		if differs(kvs[k], endorsements) {
+			ns, key := splitNSKey(k)
+			diffs = append(diffs, &EndorsementDiff{Kind: kind, NameSpace: ns, Key: key, Values: kvs[k]})
+		}
+	}
+	return diffs
+}
+
+func splitNSKey(k string) (string, string) {
+	if idx := strings.Index(k, "\x00"); idx >= 0 {
+		return k[:idx], k[idx+1:]
+	}
+	return "", k
+}
+
+func getBytes(strs []string) [][]byte {
+	bytes := make([][]byte, len(strs))
+	for idx, str := range strs {
+		bytes[idx] = []byte(str)
+	}
+	return bytes
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"testing"
+)
+
+func newTestEndorsement(peer string, payload string, reads []*KVRead, writes []*KVWrite) *PeerEndorsement {
+	return &PeerEndorsement{
+		Peer:    peer,
+		Status:  200,
+		Payload: payload,
+		ProposalResponse: &ProposalResponse{
+			TXReadWriteSet: &TXReadWriteSet{
+				NSReadWriteSets: []*NSReadWriteSet{
+					{NameSpace: "vs", KVReadSet: reads, KVWriteSet: writes},
+				},
+			},
+		},
+	}
+}
+
+func TestCompareEndorsements(t *testing.T) {
+	same := CompareEndorsements("tx1", []*PeerEndorsement{
+		newTestEndorsement(target01, "ok", []*KVRead{{Key: "v001", VerBlockNum: 3}}, []*KVWrite{{Key: "v001", Value: "1"}}),
+		newTestEndorsement(target02, "ok", []*KVRead{{Key: "v001", VerBlockNum: 3}}, []*KVWrite{{Key: "v001", Value: "1"}}),
+	})
+	if !same.Consistent || len(same.Diffs) != 0 {
+		t.Fatalf("endorsements should be consistent: %v", same.Diffs)
+	}
+
+	// Such as wronglyTxRandValue of vehiclesharing, which writes a random value.
+	diverged := CompareEndorsements("tx2", []*PeerEndorsement{
+		newTestEndorsement(target01, "ok", []*KVRead{{Key: "v001", VerBlockNum: 3}}, []*KVWrite{{Key: "v001", Value: "1"}}),
+		newTestEndorsement(target02, "ok", []*KVRead{{Key: "v001", VerBlockNum: 4}}, []*KVWrite{{Key: "v001", Value: "2"}, {Key: "v002", IsDelete: true}}),
+		{Peer: target11, Error: "connection refused"},
+	})
+	if diverged.Consistent {
+		t.Fatal("endorsements should be diverged")
+	}
+	kinds := map[string]*EndorsementDiff{}
+	for _, diff := range diverged.Diffs {
+		kinds[diff.Kind+":"+diff.Key] = diff
+	}
+	if len(kinds) != 3 {
+		t.Fatalf("expected 3 diffs, got %d", len(diverged.Diffs))
+	}
+	if diff := kinds["read:v001"]; diff == nil || diff.NameSpace != "vs" || diff.Values[target02] != "4:0" {
+		t.Errorf("wrong read diff %v", diff)
+	}
+	if diff := kinds["write:v002"]; diff == nil || diff.Values[target01] != endorsementAbsent || diff.Values[target02] != endorsementDeleted {
+		t.Errorf("wrong write diff %v", diff)
+	}
+	if _, ok := kinds["write:v001"]; !ok {
+		t.Error("value difference of v001 should be found")
+	}
+}
@@ -0,0 +1,416 @@
+package api
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// CATimeout the timeout of requests to Fabric CAs.
+const CATimeout = time.Second * 30
+
+// CAConfig a Fabric CA of the connection profile, the registrar is not responded.
+type CAConfig struct {
+	Name       string   `json:"name"`
+	URL        string   `json:"URL"`
+	CAName     string   `json:"caName"`
+	MSPIDs     []string `json:"MSPIDs"` // Of organizations with the CA.
+	TLSCACerts [][]byte `json:"-"`
+	Registrar  struct {
+		EnrollID     string
+		EnrollSecret string
+	} `json:"-"`
+	HasRegistrar bool `json:"hasRegistrar"`
+}
+
+// CAEnrollment the enrolled certificate, with the private key generated for it.
+// PKCS11 is set instead of PrivateKey if the certificate is of the key in a PKCS#11 token.
+type CAEnrollment struct {
+	Cert       []byte        `json:"cert"`
+	PrivateKey []byte        `json:"-"`
+	PKCS11     *PKCS11Config `json:"-"`
+	CAChain    []byte        `json:"CAChain"`
+}
+
+// CAAttribute an attribute of an identity of the CA.
+type CAAttribute struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	ECert bool   `json:"ecert,omitempty"`
+}
+
+// CARegistration to register an identity, the CA generates the secret if it is empty.
+type CARegistration struct {
+	Name           string         `json:"id" openapi:"required"`
+	Type           string         `json:"type"` // e.g. client, peer, orderer, admin or user.
+	Secret         string         `json:"secret,omitempty"`
+	MaxEnrollments int            `json:"max_enrollments,omitempty"`
+	Affiliation    string         `json:"affiliation"`
+	Attributes     []*CAAttribute `json:"attrs,omitempty"`
+	CAName         string         `json:"caname,omitempty"`
+}
+
+// CARevocation to revoke all certificates of the identity, or the certificate of the serial and AKI.
+type CARevocation struct {
+	Name   string `json:"id,omitempty"`
+	Serial string `json:"serial,omitempty"`
+	AKI    string `json:"aki,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	CAName string `json:"caname,omitempty"`
+	GenCRL bool   `json:"gencrl,omitempty"`
+}
+
+// CARevokedCert a revoked certificate.
+type CARevokedCert struct {
+	Serial string `json:"serial"`
+	AKI    string `json:"AKI"`
+}
+
+// CARevocationResult the revoked certificates, with the CRL if it is requested.
+type CARevocationResult struct {
+	RevokedCerts []*CARevokedCert `json:"revokedCerts"`
+	CRL          []byte           `json:"CRL"`
+}
+
+// CAIdentity an identity registered in the CA.
+type CAIdentity struct {
+	ID             string         `json:"id"`
+	Type           string         `json:"type"`
+	Affiliation    string         `json:"affiliation"`
+	Attributes     []*CAAttribute `json:"attrs"`
+	MaxEnrollments int            `json:"max_enrollments"`
+}
+
+// CAError an error responded by the CA.
+type CAError struct {
+	StatusCode int
+	Code       int
+	Message    string
+}
+
+func (err *CAError) Error() string {
+	return fmt.Sprintf("the CA responded %d: code %d, %s", err.StatusCode, err.Code, err.Message)
+}
+
+// ProfileCAs to get Fabric CAs of the connection profile, sorted by name.
+func ProfileCAs(config []byte) ([]*CAConfig, error) {
+	profile := struct {
+		Organizations map[string]struct {
+			MSPID                  string   `yaml:"mspid"`
+			CertificateAuthorities []string `yaml:"certificateAuthorities"`
+		} `yaml:"organizations"`
+		CertificateAuthorities map[string]struct {
+			URL        string `yaml:"url"`
+			CAName     string `yaml:"caName"`
+			TLSCACerts struct {
+				Pem  interface{} `yaml:"pem"`
+				Path interface{} `yaml:"path"`
+			} `yaml:"tlsCACerts"`
+			Registrar struct {
+				EnrollID     string `yaml:"enrollId"`
+				EnrollSecret string `yaml:"enrollSecret"`
+			} `yaml:"registrar"`
+		} `yaml:"certificateAuthorities"`
+	}{}
+	if err := yaml.Unmarshal(config, &profile); err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing the connection profile.")
+	}
+
+	cas := []*CAConfig{}
+	for name, caCfg := range profile.CertificateAuthorities {
+		ca := &CAConfig{Name: name, URL: caCfg.URL, CAName: caCfg.CAName, MSPIDs: []string{}, TLSCACerts: [][]byte{}}
+		ca.Registrar.EnrollID = caCfg.Registrar.EnrollID
+		ca.Registrar.EnrollSecret = caCfg.Registrar.EnrollSecret
+		ca.HasRegistrar = ca.Registrar.EnrollID != ""
+		for _, pemContent := range stringOrList(caCfg.TLSCACerts.Pem) {
+			ca.TLSCACerts = append(ca.TLSCACerts, []byte(pemContent))
+		}
+		for _, path := range stringOrList(caCfg.TLSCACerts.Path) {
+			content, err := ioutil.ReadFile(os.ExpandEnv(path))
+			if err != nil {
+				return nil, errors.WithMessagef(err, "Error occurred when reading TLS CA certificates of %s.", name)
+			}
+			ca.TLSCACerts = append(ca.TLSCACerts, content)
+		}
+		for _, org := range profile.Organizations {
+			for _, orgCA := range org.CertificateAuthorities {
+				if orgCA == name {
+					ca.MSPIDs = append(ca.MSPIDs, org.MSPID)
+				}
+			}
+		}
+		sort.Strings(ca.MSPIDs)
+		cas = append(cas, ca)
+	}
+	sort.Slice(cas, func(i, j int) bool { return cas[i].Name < cas[j].Name })
+	return cas, nil
+}
+
+func stringOrList(value interface{}) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		items := []string{}
+		for _, item := range value {
+			items = append(items, fmt.Sprint(item))
+		}
+		return items
+	}
+	return nil
+}
+
+// CAClient a client of the REST API of a Fabric CA.
+type CAClient struct {
+	*CAConfig
+	HTTPClient *http.Client
+}
+
+// NewCAClient to create a client of the CA, the TLS CA certificates of the profile are trusted.
+func NewCAClient(ca *CAConfig) (*CAClient, error) {
+	if _, err := url.Parse(ca.URL); err != nil || ca.URL == "" {
+		return nil, errors.Errorf("the URL %s of CA %s is invalid", ca.URL, ca.Name)
+	}
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if len(ca.TLSCACerts) > 0 {
+		pool := x509.NewCertPool()
+		for _, cert := range ca.TLSCACerts {
+			if !pool.AppendCertsFromPEM(cert) {
+				return nil, errors.Errorf("TLS CA certificates of CA %s are invalid", ca.Name)
+			}
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	return &CAClient{CAConfig: ca, HTTPClient: &http.Client{Transport: transport, Timeout: CATimeout}}, nil
+}
+
+// Enroll to enroll the identity by the secret, with a new key. The CN is the enrollment ID.
+func (c *CAClient) Enroll(enrollID string, secret string, hosts ...string) (*CAEnrollment, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return c.enroll("enroll", enrollID, hosts, key, func(req *http.Request, body []byte) error {
+		req.SetBasicAuth(enrollID, secret)
+		return nil
+	})
+}
+
+// Reenroll to renew the certificate of the identity, with a new key.
+// The key of an identity in a PKCS#11 token is kept, since a new key can't be exported from the token.
+func (c *CAClient) Reenroll(identity *Participant) (*CAEnrollment, error) {
+	cert, err := TLSCertByBytes(identity.Cert)
+	if err != nil {
+		return nil, err
+	}
+	var key crypto.Signer
+	if identity.PKCS11 != nil {
+		if key, err = identity.signer(); err != nil {
+			return nil, err
+		}
+	} else if key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		return nil, err
+	}
+	enrollment, err := c.enroll("reenroll", cert.Subject.CommonName, cert.DNSNames, key, func(req *http.Request, body []byte) error {
+		return c.addToken(req, body, identity)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if identity.PKCS11 != nil {
+		enrollment.PrivateKey, enrollment.PKCS11 = nil, identity.PKCS11
+	}
+	return enrollment, nil
+}
+
+// enroll to send the CSR signed by the key, the private key of the enrollment is set if the key is a software one.
+func (c *CAClient) enroll(endpoint string, CN string, hosts []string, key crypto.Signer, auth func(req *http.Request, body []byte) error) (*CAEnrollment, error) {
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: CN},
+		DNSNames: hosts,
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"certificate_request": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})),
+		"hosts":               hosts,
+		"caname":              c.CAName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &struct {
+		Cert       string
+		ServerInfo struct {
+			CAChain string
+		}
+	}{}
+	if err := c.send(http.MethodPost, endpoint, body, auth, result); err != nil {
+		return nil, err
+	}
+	certPEM, err := base64.StdEncoding.DecodeString(result.Cert)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when decoding the enrolled certificate.")
+	}
+	caChain, _ := base64.StdEncoding.DecodeString(result.ServerInfo.CAChain)
+	enrollment := &CAEnrollment{Cert: certPEM, CAChain: caChain}
+	if prvKey, ok := key.(*ecdsa.PrivateKey); ok {
+		keyDER, err := x509.MarshalPKCS8PrivateKey(prvKey)
+		if err != nil {
+			return nil, err
+		}
+		enrollment.PrivateKey = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	}
+	return enrollment, nil
+}
+
+// EnrollRegistrar to enroll the registrar of the connection profile.
+func (c *CAClient) EnrollRegistrar() (*Participant, error) {
+	if !c.HasRegistrar {
+		return nil, errors.Errorf("CA %s has no registrar in the connection profile", c.Name)
+	}
+	enrollment, err := c.Enroll(c.Registrar.EnrollID, c.Registrar.EnrollSecret)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when enrolling the registrar.")
+	}
+	return &Participant{Label: c.Registrar.EnrollID, Cert: enrollment.Cert, PrivateKey: enrollment.PrivateKey}, nil
+}
+
+// Register to register the identity by the registrar, returns the secret.
+func (c *CAClient) Register(registrar *Participant, registration *CARegistration) (string, error) {
+	if registration.CAName == "" {
+		registration.CAName = c.CAName
+	}
+	body, err := json.Marshal(registration)
+	if err != nil {
+		return "", err
+	}
+	result := &struct {
+		Secret string `json:"secret"`
+	}{}
+	if err := c.send(http.MethodPost, "register", body, c.tokenAuth(registrar), result); err != nil {
+		return "", err
+	}
+	return result.Secret, nil
+}
+
+// Revoke to revoke certificates by the registrar.
+func (c *CAClient) Revoke(registrar *Participant, revocation *CARevocation) (*CARevocationResult, error) {
+	if revocation.CAName == "" {
+		revocation.CAName = c.CAName
+	}
+	body, err := json.Marshal(revocation)
+	if err != nil {
+		return nil, err
+	}
+	result := &CARevocationResult{}
+	if err := c.send(http.MethodPost, "revoke", body, c.tokenAuth(registrar), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Identities to list identities which the registrar is allowed to see.
+func (c *CAClient) Identities(registrar *Participant) ([]*CAIdentity, error) {
+	endpoint := "identities"
+	if c.CAName != "" {
+		endpoint += "?ca=" + url.QueryEscape(c.CAName)
+	}
+	result := &struct {
+		Identities []*CAIdentity `json:"identities"`
+	}{Identities: []*CAIdentity{}}
+	if err := c.send(http.MethodGet, endpoint, nil, c.tokenAuth(registrar), result); err != nil {
+		return nil, err
+	}
+	return result.Identities, nil
+}
+
+func (c *CAClient) tokenAuth(identity *Participant) func(req *http.Request, body []byte) error {
+	return func(req *http.Request, body []byte) error {
+		return c.addToken(req, body, identity)
+	}
+}
+
+// addToken to sign the request by the identity, as the token of Fabric CA 1.4.
+func (c *CAClient) addToken(req *http.Request, body []byte, identity *Participant) error {
+	signer, err := identity.signer()
+	if err != nil {
+		return err
+	}
+	pub, ok := signer.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return errors.New("only ECDSA keys are supported by the CA token")
+	}
+	b64Cert := base64.StdEncoding.EncodeToString(identity.Cert)
+	payload := req.Method + "." + base64.StdEncoding.EncodeToString([]byte(req.URL.RequestURI())) + "." +
+		base64.StdEncoding.EncodeToString(body) + "." + b64Cert
+	digest := sha256.Sum256([]byte(payload))
+	sig, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return err
+	}
+	// Fabric only accepts signatures with the low S.
+	if sig, err = lowSSignature(pub, sig); err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", b64Cert+"."+base64.StdEncoding.EncodeToString(sig))
+	return nil
+}
+
+func (c *CAClient) send(method string, endpoint string, body []byte, auth func(req *http.Request, body []byte) error, result interface{}) error {
+	req, err := http.NewRequest(method, strings.TrimSuffix(c.URL, "/")+"/api/v1/"+endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := auth(req, body); err != nil {
+		return err
+	}
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return errors.WithMessagef(err, "Error occurred when requesting CA %s.", c.Name)
+	}
+	defer res.Body.Close()
+
+	resBody := &struct {
+		Success bool            `json:"success"`
+		Result  json.RawMessage `json:"result"`
+		Errors  []struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(resBody); err != nil {
+		return &CAError{StatusCode: res.StatusCode, Message: err.Error()}
+	}
+	if !resBody.Success || res.StatusCode >= 300 {
+		caErr := &CAError{StatusCode: res.StatusCode, Message: "unknown error"}
+		if len(resBody.Errors) > 0 {
+			caErr.Code, caErr.Message = resBody.Errors[0].Code, resBody.Errors[0].Message
+		}
+		return caErr
+	}
+	return json.Unmarshal(resBody.Result, result)
+}
@@ -0,0 +1,294 @@
+package api
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeCA a Fabric CA of the REST API, with identities of their secrets.
+type fakeCA struct {
+	t          *testing.T
+	key        *ecdsa.PrivateKey
+	cert       *x509.Certificate
+	secrets    map[string]string
+	identities []*CAIdentity
+	serial     int64
+}
+
+func newFakeCA(t *testing.T) *fakeCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca.org1.example.com"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &fakeCA{t: t, key: key, cert: cert, secrets: map[string]string{"admin": "adminpw"},
+		identities: []*CAIdentity{{ID: "admin", Type: "client", Attributes: []*CAAttribute{}}}, serial: 1}
+}
+
+func (ca *fakeCA) respond(res http.ResponseWriter, status int, result interface{}, errMsg string) {
+	res.WriteHeader(status)
+	errs := []map[string]interface{}{}
+	if errMsg != "" {
+		errs = append(errs, map[string]interface{}{"code": 20, "message": errMsg})
+	}
+	json.NewEncoder(res).Encode(map[string]interface{}{"success": errMsg == "", "result": result, "errors": errs})
+}
+
+// verifyToken to verify the token is signed by an identity of the CA, returns the CN.
+func (ca *fakeCA) verifyToken(req *http.Request, body []byte) (string, error) {
+	parts := strings.Split(req.Header.Get("Authorization"), ".")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("the token is invalid")
+	}
+	certPEM, _ := base64.StdEncoding.DecodeString(parts[0])
+	sig, _ := base64.StdEncoding.DecodeString(parts[1])
+	cert, err := TLSCertByBytes(certPEM)
+	if err != nil {
+		return "", err
+	}
+	if err := cert.CheckSignatureFrom(ca.cert); err != nil {
+		return "", err
+	}
+	payload := req.Method + "." + base64.StdEncoding.EncodeToString([]byte(req.URL.RequestURI())) + "." +
+		base64.StdEncoding.EncodeToString(body) + "." + parts[0]
+	digest := sha256.Sum256([]byte(payload))
+	rs := struct{ R, S *big.Int }{}
+	if _, err := asn1.Unmarshal(sig, &rs); err != nil {
+		return "", err
+	}
+	if rs.S.Cmp(new(big.Int).Rsh(elliptic.P256().Params().N, 1)) > 0 {
+		return "", fmt.Errorf("the signature has a high S")
+	}
+	if !ecdsa.Verify(cert.PublicKey.(*ecdsa.PublicKey), digest[:], rs.R, rs.S) {
+		return "", fmt.Errorf("the signature is invalid")
+	}
+	return cert.Subject.CommonName, nil
+}
+
+func (ca *fakeCA) issue(res http.ResponseWriter, body []byte) {
+	enrollReq := &struct {
+		CertificateRequest string `json:"certificate_request"`
+		CAName             string `json:"caname"`
+	}{}
+	json.Unmarshal(body, enrollReq)
+	block, _ := pem.Decode([]byte(enrollReq.CertificateRequest))
+	if block == nil || enrollReq.CAName != "ca-org1" {
+		ca.respond(res, http.StatusBadRequest, nil, "the request is invalid")
+		return
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		ca.respond(res, http.StatusBadRequest, nil, err.Error())
+		return
+	}
+	ca.serial++
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(ca.serial),
+		Subject:      csr.Subject,
+		DNSNames:     csr.DNSNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+	}, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		ca.respond(res, http.StatusInternalServerError, nil, err.Error())
+		return
+	}
+	ca.respond(res, http.StatusCreated, map[string]interface{}{
+		"Cert": base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		"ServerInfo": map[string]interface{}{
+			"CAName":  "ca-org1",
+			"CAChain": base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw})),
+		},
+	}, "")
+}
+
+func (ca *fakeCA) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	if req.URL.Path == "/api/v1/enroll" {
+		id, secret, ok := req.BasicAuth()
+		if !ok || ca.secrets[id] != secret {
+			ca.respond(res, http.StatusUnauthorized, nil, "authentication failure")
+			return
+		}
+		ca.issue(res, body)
+		return
+	}
+
+	caller, err := ca.verifyToken(req, body)
+	if err != nil {
+		ca.respond(res, http.StatusUnauthorized, nil, err.Error())
+		return
+	}
+	switch req.URL.Path {
+	case "/api/v1/reenroll":
+		ca.issue(res, body)
+	case "/api/v1/register":
+		registration := &CARegistration{}
+		json.Unmarshal(body, registration)
+		if caller != "admin" || registration.CAName != "ca-org1" {
+			ca.respond(res, http.StatusUnauthorized, nil, "not allowed to register")
+			return
+		}
+		if registration.Secret == "" {
+			registration.Secret = "generated"
+		}
+		ca.secrets[registration.Name] = registration.Secret
+		ca.identities = append(ca.identities, &CAIdentity{ID: registration.Name, Type: registration.Type,
+			Affiliation: registration.Affiliation, Attributes: registration.Attributes})
+		ca.respond(res, http.StatusCreated, map[string]interface{}{"secret": registration.Secret}, "")
+	case "/api/v1/revoke":
+		revocation := &CARevocation{}
+		json.Unmarshal(body, revocation)
+		delete(ca.secrets, revocation.Name)
+		ca.respond(res, http.StatusOK, map[string]interface{}{
+			"RevokedCerts": []map[string]string{{"Serial": "2", "AKI": "aki"}}, "CRL": []byte("crl")}, "")
+	case "/api/v1/identities":
+		if req.Method != http.MethodGet || req.URL.Query().Get("ca") != "ca-org1" {
+			ca.respond(res, http.StatusBadRequest, nil, "the request is invalid")
+			return
+		}
+		ca.respond(res, http.StatusOK, map[string]interface{}{"identities": ca.identities, "caname": "ca-org1"}, "")
+	default:
+		ca.respond(res, http.StatusNotFound, nil, "not found")
+	}
+}
+
+func TestCAClient(t *testing.T) {
+	fake := newFakeCA(t)
+	srv := httptest.NewTLSServer(fake)
+	defer srv.Close()
+	tlsCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	profile := fmt.Sprintf(`organizations:
+  Org1:
+    mspid: Org1MSP
+    certificateAuthorities:
+      - ca.org1.example.com
+certificateAuthorities:
+  ca.org1.example.com:
+    url: %s
+    caName: ca-org1
+    tlsCACerts:
+      pem: |
+%s
+    registrar:
+      enrollId: admin
+      enrollSecret: adminpw
+`, srv.URL, indentPEM(string(tlsCert), "        "))
+	cas, err := ProfileCAs([]byte(profile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cas) != 1 || cas[0].CAName != "ca-org1" || len(cas[0].MSPIDs) != 1 || cas[0].MSPIDs[0] != "Org1MSP" || !cas[0].HasRegistrar {
+		t.Fatalf("expected the CA of the profile, but got %+v", cas)
+	}
+	client, err := NewCAClient(cas[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Enroll("admin", "wrong"); err == nil {
+		t.Error("expected error of the wrong secret")
+	} else if caErr, ok := err.(*CAError); !ok || caErr.StatusCode != http.StatusUnauthorized || caErr.Message != "authentication failure" {
+		t.Errorf("expected the error of the CA, but got %v", err)
+	}
+	registrar, err := client.EnrollRegistrar()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := client.Register(registrar, &CARegistration{Name: "user1", Type: "client", Affiliation: "org1.department1",
+		Attributes: []*CAAttribute{{Name: "role", Value: "auditor", ECert: true}}})
+	if err != nil || secret != "generated" {
+		t.Fatalf("expected the generated secret, but got %s, %v", secret, err)
+	}
+	enrollment, err := client.Enroll("user1", secret, "user1.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := TLSCertByBytes(enrollment.Cert)
+	if err != nil || cert.Subject.CommonName != "user1" || len(cert.DNSNames) != 1 || cert.CheckSignatureFrom(fake.cert) != nil {
+		t.Fatalf("expected the certificate of user1, but got %+v, %v", cert, err)
+	}
+	if _, err := TLSCertByBytes(enrollment.CAChain); err != nil {
+		t.Errorf("expected the CA chain, but got %v", err)
+	}
+	key, err := parsePrivateKey(enrollment.PrivateKey, "")
+	if err != nil || !key.Public().(*ecdsa.PublicKey).Equal(cert.PublicKey) {
+		t.Errorf("expected the key of the certificate, but got %v", err)
+	}
+
+	user1 := &Participant{Cert: enrollment.Cert, PrivateKey: enrollment.PrivateKey}
+	reenrollment, err := client.Reenroll(user1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renewed, err := TLSCertByBytes(reenrollment.Cert); err != nil || renewed.Subject.CommonName != "user1" || renewed.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+		t.Errorf("expected a new certificate of user1, but got %v", err)
+	}
+	// The key of the token is kept, it is only reachable by its signer.
+	defaultOpen := openPKCS11Signer
+	PKCS11Libraries = []string{"/usr/lib/softhsm/libsofthsm2.so"}
+	openPKCS11Signer = func(cfg *PKCS11Config) (crypto.Signer, error) {
+		return key, nil
+	}
+	defer func() {
+		openPKCS11Signer, PKCS11Libraries = defaultOpen, nil
+	}()
+	tokenUser1 := &Participant{Cert: enrollment.Cert,
+		PKCS11: &PKCS11Config{Library: "/usr/lib/softhsm/libsofthsm2.so", Slot: 3, PIN: "98765432", KeyLabel: "user1"}}
+	reenrollment, err = client.Reenroll(tokenUser1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renewed, err := TLSCertByBytes(reenrollment.Cert); err != nil || !key.Public().(*ecdsa.PublicKey).Equal(renewed.PublicKey) ||
+		reenrollment.PrivateKey != nil || reenrollment.PKCS11 != tokenUser1.PKCS11 {
+		t.Errorf("expected a new certificate of the key of the token, but got %+v, %v", reenrollment, err)
+	}
+
+	if _, err := client.Register(user1, &CARegistration{Name: "user2"}); err == nil {
+		t.Error("expected error of the identity which is not a registrar")
+	}
+
+	identities, err := client.Identities(registrar)
+	if err != nil || len(identities) != 2 || identities[1].ID != "user1" || identities[1].Attributes[0].Value != "auditor" {
+		t.Errorf("expected identities, but got %+v, %v", identities, err)
+	}
+	result, err := client.Revoke(registrar, &CARevocation{Name: "user1", Reason: "keycompromise", GenCRL: true})
+	if err != nil || len(result.RevokedCerts) != 1 || result.RevokedCerts[0].Serial != "2" || string(result.CRL) != "crl" {
+		t.Errorf("expected revoked certificates, but got %+v, %v", result, err)
+	}
+	if _, err := client.Enroll("user1", secret); err == nil {
+		t.Error("expected error of the revoked identity")
+	}
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/factory/defcore"
+	"github.com/pkg/errors"
+)
+
+// PKCS11Config the private key of the identity in a PKCS#11 token, e.g. an HSM. The key is found by the label, and never leaves the token.
+type PKCS11Config struct {
+	Library  string `json:"library"`
+	Slot     uint   `json:"slot"`
+	PIN      string `json:"PIN,omitempty"`
+	KeyLabel string `json:"keyLabel"`
+}
+
+// String to identify the key in the token, without the PIN.
+func (cfg *PKCS11Config) String() string {
+	return fmt.Sprintf("%s slot %d key %s", cfg.Library, cfg.Slot, cfg.KeyLabel)
+}
+
+// cacheKey to identify the opened key with the hash of the PIN, so that a key opened by a PIN isn't used by another PIN.
+func (cfg *PKCS11Config) cacheKey() string {
+	sum := sha256.Sum256([]byte(cfg.PIN))
+	return cfg.String() + " PIN " + hex.EncodeToString(sum[:])
+}
+
+// PKCS11Libraries the PKCS#11 libraries which identities can use, since a library is loaded into the process.
+// No library can be used if it is empty.
+var PKCS11Libraries []string
+
+// PKCS11LibraryError the PKCS#11 library is not one of PKCS11Libraries.
+type PKCS11LibraryError struct {
+	Library string
+}
+
+func (err *PKCS11LibraryError) Error() string {
+	return fmt.Sprintf("the PKCS#11 library %s is not allowed", err.Library)
+}
+
+// checkPKCS11Library to check the library is one of PKCS11Libraries, before it is loaded.
+func checkPKCS11Library(library string) error {
+	for _, allowed := range PKCS11Libraries {
+		if allowed != "" && filepath.Clean(allowed) == filepath.Clean(library) {
+			return nil
+		}
+	}
+	return &PKCS11LibraryError{Library: library}
+}
+
+// openPKCS11Signer to open the key of the token, it is set if Fablet is built with the tag pkcs11.
+var openPKCS11Signer = func(cfg *PKCS11Config) (crypto.Signer, error) {
+	return nil, errors.New("Fablet is built without PKCS#11 support, please build it with the tag pkcs11")
+}
+
+// hsmKey a private key of which the signing is done by the signer, the SKI is of the public key as the software BCCSP.
+type hsmKey struct {
+	signer crypto.Signer
+	ski    []byte
+	pub    *hsmPublicKey
+}
+
+func newHSMKey(signer crypto.Signer) (*hsmKey, error) {
+	pub, ok := signer.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("only ECDSA keys are supported, but got %T", signer.Public())
+	}
+	sum := sha256.Sum256(elliptic.Marshal(pub.Curve, pub.X, pub.Y))
+	return &hsmKey{signer: signer, ski: sum[:], pub: &hsmPublicKey{pub: pub, ski: sum[:]}}, nil
+}
+
+// hsmPublicKey the public key of the key in the token, which is got from the token when it is opened.
+type hsmPublicKey struct {
+	pub *ecdsa.PublicKey
+	ski []byte
+}
+
+// Bytes the public key in PKIX.
+func (k *hsmPublicKey) Bytes() ([]byte, error) {
+	return x509.MarshalPKIXPublicKey(k.pub)
+}
+
+// SKI of the public key.
+func (k *hsmPublicKey) SKI() []byte {
+	return k.ski
+}
+
+// Symmetric false.
+func (k *hsmPublicKey) Symmetric() bool {
+	return false
+}
+
+// Private false.
+func (k *hsmPublicKey) Private() bool {
+	return false
+}
+
+// PublicKey itself.
+func (k *hsmPublicKey) PublicKey() (core.Key, error) {
+	return k, nil
+}
+
+// Bytes the private key is not exportable.
+func (k *hsmKey) Bytes() ([]byte, error) {
+	return nil, errors.New("the private key in the token is not exportable")
+}
+
+// SKI of the public key.
+func (k *hsmKey) SKI() []byte {
+	return k.ski
+}
+
+// Symmetric false.
+func (k *hsmKey) Symmetric() bool {
+	return false
+}
+
+// Private true.
+func (k *hsmKey) Private() bool {
+	return true
+}
+
+// PublicKey the public key of the token.
+func (k *hsmKey) PublicKey() (core.Key, error) {
+	return k.pub, nil
+}
+
+// sign to sign the digest, the ECDSA signature is with the low S which Fabric requires.
+func (k *hsmKey) sign(digest []byte) ([]byte, error) {
+	sig, err := k.signer.Sign(rand.Reader, digest, crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	return lowSSignature(k.signer.Public().(*ecdsa.PublicKey), sig)
+}
+
+// lowSSignature to convert the DER ECDSA signature to the one with the low S.
+func lowSSignature(pub *ecdsa.PublicKey, sig []byte) ([]byte, error) {
+	rs := struct{ R, S *big.Int }{}
+	if _, err := asn1.Unmarshal(sig, &rs); err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when unmarshaling the ECDSA signature.")
+	}
+	halfOrder := new(big.Int).Rsh(pub.Params().N, 1)
+	if rs.S.Cmp(halfOrder) <= 0 {
+		return sig, nil
+	}
+	rs.S.Sub(pub.Params().N, rs.S)
+	return asn1.Marshal(rs)
+}
+
+// hsmCryptoSuite the crypto suite of the SDK, with the key of the token. Other keys are of the embedded suite.
+type hsmCryptoSuite struct {
+	core.CryptoSuite
+	key *hsmKey
+}
+
+// GetKey to get the key of the token by the SKI of the certificate.
+func (cs *hsmCryptoSuite) GetKey(ski []byte) (core.Key, error) {
+	if bytes.Equal(ski, cs.key.ski) {
+		return cs.key, nil
+	}
+	return cs.CryptoSuite.GetKey(ski)
+}
+
+// Sign to sign by the token if the key is of it.
+func (cs *hsmCryptoSuite) Sign(k core.Key, digest []byte, opts core.SignerOpts) ([]byte, error) {
+	if key, ok := k.(*hsmKey); ok {
+		return key.sign(digest)
+	}
+	return cs.CryptoSuite.Sign(k, digest, opts)
+}
+
+// hsmCorePkg the core providers of the SDK, with the crypto suite of the token.
+type hsmCorePkg struct {
+	*defcore.ProviderFactory
+	key *hsmKey
+}
+
+// CreateCryptoSuiteProvider to wrap the default crypto suite.
+func (pkg *hsmCorePkg) CreateCryptoSuiteProvider(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
+	cs, err := pkg.ProviderFactory.CreateCryptoSuiteProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	return &hsmCryptoSuite{CryptoSuite: cs, key: pkg.key}, nil
+}
+
+// hsmKeys keys of tokens which are opened, by the library, slot, label and the hash of the PIN.
+var hsmKeys = struct {
+	sync.Mutex
+	keys map[string]*hsmKey
+}{keys: make(map[string]*hsmKey)}
+
+// hsmKey to get the key of the token of the participant, it is opened once. Nil if the private key is not in a token.
+func (participant *Participant) hsmKey() (*hsmKey, error) {
+	if participant.PKCS11 == nil {
+		return nil, nil
+	}
+	if err := checkPKCS11Library(participant.PKCS11.Library); err != nil {
+		return nil, err
+	}
+	hsmKeys.Lock()
+	defer hsmKeys.Unlock()
+	id := participant.PKCS11.cacheKey()
+	if key, ok := hsmKeys.keys[id]; ok {
+		return key, nil
+	}
+	signer, err := openPKCS11Signer(participant.PKCS11)
+	if err != nil {
+		return nil, &IdentityError{Err: errors.WithMessagef(err, "Error occurred when opening the key of %s.", participant.PKCS11)}
+	}
+	key, err := newHSMKey(signer)
+	if err != nil {
+		return nil, &IdentityError{Err: err}
+	}
+	hsmKeys.keys[id] = key
+	return key, nil
+}
+
+// sdkOptions options of the SDK for the participant, with the crypto suite of the token if the private key is in one.
+func (participant *Participant) sdkOptions() ([]fabsdk.Option, error) {
+	key, err := participant.hsmKey()
+	if err != nil || key == nil {
+		return nil, err
+	}
+	return []fabsdk.Option{fabsdk.WithCorePkg(&hsmCorePkg{ProviderFactory: defcore.NewProviderFactory(), key: key})}, nil
+}
+
+// signer to get the signer of the participant, of the token or of the private key.
+func (participant *Participant) signer() (crypto.Signer, error) {
+	key, err := participant.hsmKey()
+	if err != nil {
+		return nil, err
+	}
+	if key != nil {
+		return key.signer, nil
+	}
+	return parsePrivateKey(participant.PrivateKey, participant.Passphrase)
+}
@@ -0,0 +1,168 @@
+// +build pkcs11
+
+package api
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	openPKCS11Signer = openP11Signer
+}
+
+// p11Modules modules which are loaded and initialized, by the library path.
+var p11Modules = struct {
+	sync.Mutex
+	modules map[string]*pkcs11.Ctx
+}{modules: make(map[string]*pkcs11.Ctx)}
+
+// Named curves of EC keys in tokens.
+var p11Curves = map[string]elliptic.Curve{
+	asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}.String(): elliptic.P256(),
+	asn1.ObjectIdentifier{1, 3, 132, 0, 34}.String():          elliptic.P384(),
+	asn1.ObjectIdentifier{1, 3, 132, 0, 35}.String():          elliptic.P521(),
+}
+
+// p11Signer an EC private key of a token, the session is used by one signing at a time.
+type p11Signer struct {
+	sync.Mutex
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	key     pkcs11.ObjectHandle
+	pub     *ecdsa.PublicKey
+}
+
+func p11Module(library string) (*pkcs11.Ctx, error) {
+	if err := checkPKCS11Library(library); err != nil {
+		return nil, err
+	}
+	p11Modules.Lock()
+	defer p11Modules.Unlock()
+	if ctx, ok := p11Modules.modules[library]; ok {
+		return ctx, nil
+	}
+	ctx := pkcs11.New(library)
+	if ctx == nil {
+		return nil, errors.Errorf("the PKCS#11 library %s cannot be loaded", library)
+	}
+	if err := ctx.Initialize(); err != nil && err != pkcs11.Error(pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		return nil, errors.WithMessage(err, "Error occurred when initializing the PKCS#11 library.")
+	}
+	p11Modules.modules[library] = ctx
+	return ctx, nil
+}
+
+// openP11Signer to log in the slot, and to find the private and public keys of the label.
+func openP11Signer(cfg *PKCS11Config) (crypto.Signer, error) {
+	ctx, err := p11Module(cfg.Library)
+	if err != nil {
+		return nil, err
+	}
+	session, err := ctx.OpenSession(cfg.Slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Error occurred when opening a session of slot %d.", cfg.Slot)
+	}
+	if err := ctx.Login(session, pkcs11.CKU_USER, cfg.PIN); err != nil && err != pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+		ctx.CloseSession(session)
+		return nil, errors.WithMessage(err, "Error occurred when logging in the token.")
+	}
+
+	signer := &p11Signer{ctx: ctx, session: session}
+	if signer.key, err = p11Object(ctx, session, pkcs11.CKO_PRIVATE_KEY, cfg.KeyLabel); err != nil {
+		ctx.CloseSession(session)
+		return nil, err
+	}
+	pubKey, err := p11Object(ctx, session, pkcs11.CKO_PUBLIC_KEY, cfg.KeyLabel)
+	if err != nil {
+		ctx.CloseSession(session)
+		return nil, err
+	}
+	if signer.pub, err = p11PublicKey(ctx, session, pubKey); err != nil {
+		ctx.CloseSession(session)
+		return nil, err
+	}
+	return signer, nil
+}
+
+// p11Object to find the only object of the class and label.
+func p11Object(ctx *pkcs11.Ctx, session pkcs11.SessionHandle, class uint, label string) (pkcs11.ObjectHandle, error) {
+	if err := ctx.FindObjectsInit(session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}); err != nil {
+		return 0, err
+	}
+	objects, _, err := ctx.FindObjects(session, 2)
+	ctx.FindObjectsFinal(session)
+	if err != nil {
+		return 0, err
+	}
+	kind := "private"
+	if class == pkcs11.CKO_PUBLIC_KEY {
+		kind = "public"
+	}
+	if len(objects) != 1 {
+		return 0, errors.Errorf("expected one %s key of label %s in the token, but got %d", kind, label, len(objects))
+	}
+	return objects[0], nil
+}
+
+// p11PublicKey to get the EC public key by the curve and the point.
+func p11PublicKey(ctx *pkcs11.Ctx, session pkcs11.SessionHandle, object pkcs11.ObjectHandle) (*ecdsa.PublicKey, error) {
+	attrs, err := ctx.GetAttributeValue(session, object, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when getting the EC public key, only EC keys are supported.")
+	}
+	oid := asn1.ObjectIdentifier{}
+	if _, err := asn1.Unmarshal(attrs[0].Value, &oid); err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when unmarshaling the curve.")
+	}
+	curve, ok := p11Curves[oid.String()]
+	if !ok {
+		return nil, errors.Errorf("the curve %s is not supported", oid)
+	}
+	// The point is in a DER octet string by the spec, some tokens give it as it is.
+	point := attrs[1].Value
+	var octets []byte
+	if rest, err := asn1.Unmarshal(point, &octets); err == nil && len(rest) == 0 {
+		point = octets
+	}
+	x, y := elliptic.Unmarshal(curve, point)
+	if x == nil {
+		return nil, errors.New("the EC point of the public key is invalid")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// Public the public key of the private key.
+func (signer *p11Signer) Public() crypto.PublicKey {
+	return signer.pub
+}
+
+// Sign to sign the digest in the token, the signature is DER encoded as of crypto/ecdsa.
+func (signer *p11Signer) Sign(_ io.Reader, digest []byte, _ crypto.SignerOpts) ([]byte, error) {
+	signer.Lock()
+	defer signer.Unlock()
+	if err := signer.ctx.SignInit(signer.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, signer.key); err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when initializing the signing of the token.")
+	}
+	sig, err := signer.ctx.Sign(signer.session, digest)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when signing by the token.")
+	}
+	// The signature of CKM_ECDSA is r and s of the same length.
+	half := len(sig) / 2
+	return asn1.Marshal(struct{ R, S *big.Int }{new(big.Int).SetBytes(sig[:half]), new(big.Int).SetBytes(sig[half:])})
+}
@@ -0,0 +1,102 @@
+// +build pkcs11
+
+package api
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"math/big"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+)
+
+// TestP11Signer with SoftHSM, e.g.:
+// softhsm2-util --init-token --free --label fablet --pin 98765432 --so-pin 1234
+// FABLET_PKCS11_LIB=/usr/lib/softhsm/libsofthsm2.so FABLET_PKCS11_SLOT=<slot> FABLET_PKCS11_PIN=98765432 go test -tags pkcs11 ./api -run TestP11Signer
+func TestP11Signer(t *testing.T) {
+	library := os.Getenv("FABLET_PKCS11_LIB")
+	if library == "" {
+		t.Skip("FABLET_PKCS11_LIB of SoftHSM is not set")
+	}
+	slot, err := strconv.ParseUint(os.Getenv("FABLET_PKCS11_SLOT"), 10, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &PKCS11Config{Library: library, Slot: uint(slot), PIN: os.Getenv("FABLET_PKCS11_PIN"), KeyLabel: "fablet-test-" + strconv.FormatInt(int64(os.Getpid()), 10)}
+
+	PKCS11Libraries = []string{library}
+	defer func() {
+		PKCS11Libraries = nil
+	}()
+
+	// A P-256 key pair of the label in the token.
+	ctx, err := p11Module(library)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := ctx.OpenSession(cfg.Slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.CloseSession(session)
+	if err := ctx.Login(session, pkcs11.CKU_USER, cfg.PIN); err != nil && err != pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+		t.Fatal(err)
+	}
+	p256, _ := asn1.Marshal(asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7})
+	pubKey, privKey, err := ctx.GenerateKeyPair(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+			pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, p256),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, cfg.KeyLabel),
+		},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, cfg.KeyLabel),
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.DestroyObject(session, pubKey)
+	defer ctx.DestroyObject(session, privKey)
+
+	signer, err := openP11Signer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := newHSMKey(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte("fablet"))
+	sig, err := key.sign(digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := struct{ R, S *big.Int }{}
+	if _, err := asn1.Unmarshal(sig, &rs); err != nil {
+		t.Fatal(err)
+	}
+	pub := signer.Public().(*ecdsa.PublicKey)
+	if !ecdsa.Verify(pub, digest[:], rs.R, rs.S) || rs.S.Cmp(new(big.Int).Rsh(pub.Params().N, 1)) > 0 {
+		t.Errorf("expected a valid signature with the low S, but got %x", sig)
+	}
+	if _, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256); err != nil {
+		t.Error(err)
+	}
+
+	cfg.KeyLabel = "nobody"
+	if _, err := openP11Signer(cfg); err == nil {
+		t.Error("expected error of the label which is not in the token")
+	}
+}
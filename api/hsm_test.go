@@ -0,0 +1,169 @@
+package api
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+)
+
+const hsmTestProfile = `version: 1.0.0
+client:
+  organization: Org1
+  credentialStore:
+    path: %[1]s/state
+    cryptoStore:
+      path: %[1]s/msp
+organizations:
+  Org1:
+    mspid: Org1MSP
+    cryptoPath: msp
+`
+
+func newTestKeyCertPEM(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "hsmuser"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestHSMSigningIdentity(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The token is a software key, which is only reachable by its signer.
+	defaultOpen := openPKCS11Signer
+	PKCS11Libraries = []string{"/usr/lib/softhsm/libsofthsm2.so"}
+	openPKCS11Signer = func(cfg *PKCS11Config) (crypto.Signer, error) {
+		return key, nil
+	}
+	defer func() {
+		openPKCS11Signer, PKCS11Libraries = defaultOpen, nil
+	}()
+
+	participant := &Participant{Label: "hsmuser", MSPID: "Org1MSP", Cert: newTestKeyCertPEM(t, key),
+		PKCS11: &PKCS11Config{Library: "/usr/lib/softhsm/libsofthsm2.so", Slot: 1, PIN: "98765432", KeyLabel: "hsmuser"}}
+	sdkOptions, err := participant.sdkOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpFolder, err := ioutil.TempDir("", "fablet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpFolder)
+	sdk, err := fabsdk.New(config.FromRaw([]byte(fmt.Sprintf(hsmTestProfile, tmpFolder)), ProfileTypeYAML), sdkOptions...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdk.Close()
+	signID, err := CreateSigningIdentity(sdk, participant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := signID.PrivateKey().Bytes(); err == nil {
+		t.Error("expected the private key is not exportable")
+	}
+
+	ctx, err := sdk.Context(fabsdk.WithIdentity(signID))()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		msg := []byte{byte(i)}
+		sig, err := ctx.SigningManager().Sign(msg, signID.PrivateKey())
+		if err != nil {
+			t.Fatal(err)
+		}
+		rs := struct{ R, S *big.Int }{}
+		if _, err := asn1.Unmarshal(sig, &rs); err != nil {
+			t.Fatal(err)
+		}
+		digest := sha256.Sum256(msg)
+		if !ecdsa.Verify(&key.PublicKey, digest[:], rs.R, rs.S) || rs.S.Cmp(new(big.Int).Rsh(key.Params().N, 1)) > 0 {
+			t.Fatalf("expected a valid signature with the low S, but got %x", sig)
+		}
+	}
+
+	// The certificate of another key.
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	participant.Cert = newTestKeyCertPEM(t, other)
+	if _, err := CreateSigningIdentity(sdk, participant); err == nil {
+		t.Error("expected error of the certificate which doesn't match the key")
+	} else if _, ok := err.(*IdentityError); !ok {
+		t.Errorf("expected an identity error, but got %v", err)
+	}
+}
+
+func TestHSMKeyByPIN(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opens := 0
+	defaultOpen := openPKCS11Signer
+	PKCS11Libraries = []string{"/usr/lib/softhsm/libsofthsm2.so"}
+	openPKCS11Signer = func(cfg *PKCS11Config) (crypto.Signer, error) {
+		opens++
+		if cfg.PIN != "98765432" {
+			return nil, fmt.Errorf("wrong PIN")
+		}
+		return key, nil
+	}
+	defer func() {
+		openPKCS11Signer, PKCS11Libraries = defaultOpen, nil
+	}()
+
+	cfg := PKCS11Config{Library: "/usr/lib/softhsm/libsofthsm2.so", Slot: 2, PIN: "98765432", KeyLabel: "bypin"}
+	if _, err := (&Participant{PKCS11: &cfg}).hsmKey(); err != nil {
+		t.Fatal(err)
+	}
+	// Only allowed libraries are loaded.
+	other := cfg
+	other.Library = "/tmp/libevil.so"
+	if _, err := (&Participant{PKCS11: &other}).hsmKey(); err == nil || opens != 1 {
+		t.Errorf("expected error of the library which is not allowed, but got %v", err)
+	} else if _, ok := err.(*PKCS11LibraryError); !ok {
+		t.Errorf("expected a library error, but got %v", err)
+	}
+	// The key opened by the PIN is not used by a wrong PIN.
+	wrong := cfg
+	wrong.PIN = "00000000"
+	if _, err := (&Participant{PKCS11: &wrong}).hsmKey(); err == nil {
+		t.Error("expected error of the wrong PIN")
+	}
+	opened, err := (&Participant{PKCS11: &cfg}).hsmKey()
+	if err != nil || opens != 2 {
+		t.Errorf("expected the key opened once by the PIN, but got %d opens %v", opens, err)
+	}
+	if pub, err := opened.PublicKey(); err != nil || !bytes.Equal(pub.SKI(), opened.SKI()) {
+		t.Errorf("expected the public key of the token, but got %v %v", pub, err)
+	}
+	profile := &ConnectionProfile{Config: []byte(hsmTestProfile), ConfigType: ProfileTypeYAML}
+	if CalConnIdentifier(profile, &Participant{PKCS11: &cfg}, false) == CalConnIdentifier(profile, &Participant{PKCS11: &wrong}, false) {
+		t.Error("expected connections of different PINs are not the same")
+	}
+}
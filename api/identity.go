@@ -0,0 +1,280 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	protosmsp "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/pkg/errors"
+)
+
+// Roles of identities by NodeOUs, the member if it has none of them.
+const (
+	IdentityRoleAdmin   = "admin"
+	IdentityRoleClient  = "client"
+	IdentityRolePeer    = "peer"
+	IdentityRoleOrderer = "orderer"
+	IdentityRoleMember  = "member"
+)
+
+// Operations of Fablet which are predicted for the identity.
+const (
+	IdentityOpQuery         = "query" // To discover, query chaincodes and ledgers.
+	IdentityOpExecute       = "execute"
+	IdentityOpInstall       = "install"
+	IdentityOpInstantiate   = "instantiate"
+	IdentityOpJoinChannel   = "joinChannel"
+	IdentityOpCreateChannel = "createChannel"
+)
+
+// IdentityInspection the decoded certificate of the identity, with checks against MSPs of channels and predicted operations.
+type IdentityInspection struct {
+	MSPID        string    `json:"MSPID"`
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serialNumber"`
+	Fingerprint  string    `json:"fingerprint"`
+	OUs          []string  `json:"OUs"`
+	KeyType      string    `json:"keyType"`
+	NotBefore    time.Time `json:"notBefore"`
+	NotAfter     time.Time `json:"notAfter"`
+	Expired      bool      `json:"expired"`
+	// Role by NodeOUs of the MSP in channels, the one of the highest precedence if channels differ, see the role of each channel.
+	// It is by the OU names which Fabric uses by default if no channel is checked.
+	Role         string                  `json:"role"`
+	KeyMatch     bool                    `json:"keyMatch"`
+	KeyError     string                  `json:"keyError,omitempty"`
+	Channels     []*IdentityChannelCheck `json:"channels"`
+	Capabilities []*IdentityCapability   `json:"capabilities"`
+	Errors       []string                `json:"errors"`
+	cert         *x509.Certificate
+}
+
+// IdentityChannelCheck the identity against the MSP of the MSP ID in the channel config.
+type IdentityChannelCheck struct {
+	ChannelID    string `json:"channelID"`
+	MSPFound     bool   `json:"MSPFound"`
+	ChainsToRoot bool   `json:"chainsToRoot"`
+	ChainError   string `json:"chainError,omitempty"`
+	NodeOUs      bool   `json:"nodeOUs"`
+	Role         string `json:"role"`
+	Admin        bool   `json:"admin"` // In admin certificates of the MSP, or of the admin role.
+}
+
+// IdentityCapability an operation which the identity is predicted to be allowed, or not with the reason.
+type IdentityCapability struct {
+	Operation string `json:"operation"`
+	Allowed   bool   `json:"allowed"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// InspectIdentity to decode the certificate of the participant, and to check whether the key matches it.
+// Operations are predicted without channels, see CheckIdentityChannels.
+func InspectIdentity(participant *Participant) (*IdentityInspection, error) {
+	cert, err := TLSCertByBytes(participant.Cert)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing the certificate of the identity.")
+	}
+	sum := sha256.Sum256(cert.Raw)
+	inspection := &IdentityInspection{
+		MSPID:        participant.MSPID,
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.Text(16),
+		Fingerprint:  hex.EncodeToString(sum[:]),
+		OUs:          append([]string{}, cert.Subject.OrganizationalUnit...),
+		KeyType:      certKeyType(cert),
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		Expired:      time.Now().After(cert.NotAfter) || time.Now().Before(cert.NotBefore),
+		Role:         IdentityRoleMember,
+		Channels:     []*IdentityChannelCheck{},
+		Errors:       []string{},
+		cert:         cert,
+	}
+	for _, role := range []string{IdentityRoleAdmin, IdentityRoleClient, IdentityRolePeer, IdentityRoleOrderer} {
+		if inspection.hasOU(role) {
+			inspection.Role = role
+			break
+		}
+	}
+
+	if err := identityKeyMatches(participant, cert); err != nil {
+		inspection.KeyError = err.Error()
+	} else {
+		inspection.KeyMatch = true
+	}
+	inspection.predictCapabilities()
+	return inspection, nil
+}
+
+func identityKeyMatches(participant *Participant, cert *x509.Certificate) error {
+	signer, err := participant.signer()
+	if err != nil {
+		return err
+	}
+	return keyMatchesCert(signer, cert)
+}
+
+func (inspection *IdentityInspection) hasOU(ou string) bool {
+	for _, certOU := range inspection.OUs {
+		if strings.EqualFold(certOU, ou) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckIdentityChannels to check the identity against the MSP of the MSP ID in configs of channels of the connection,
+// operations are predicted again. Failures of channels are in errors of the inspection.
+func CheckIdentityChannels(conn *NetworkConnection, inspection *IdentityInspection) {
+	channelIDs := []string{}
+	for channelID := range conn.Channels {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+	for _, channelID := range channelIDs {
+		fabricMSPConfigs, err := channelMSPConfigs(conn, channelID)
+		if err != nil {
+			inspection.Errors = append(inspection.Errors, fmt.Sprintf("MSPs of %s: %s", channelID, err.Error()))
+			continue
+		}
+		check := &IdentityChannelCheck{ChannelID: channelID, Role: IdentityRoleMember}
+		for _, fabricMSPConfig := range fabricMSPConfigs {
+			if fabricMSPConfig.GetName() == inspection.MSPID {
+				inspection.checkMSP(fabricMSPConfig, check)
+				break
+			}
+		}
+		inspection.Channels = append(inspection.Channels, check)
+	}
+	inspection.predictCapabilities()
+}
+
+// checkMSP to check the certificate chains to roots of the MSP, and to get the role by NodeOUs and admin certificates.
+func (inspection *IdentityInspection) checkMSP(fabricMSPConfig *protosmsp.FabricMSPConfig, check *IdentityChannelCheck) {
+	check.MSPFound = true
+	roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+	for _, certBytes := range fabricMSPConfig.GetRootCerts() {
+		if cert, err := TLSCertByBytes(certBytes); err == nil {
+			roots.AddCert(cert)
+		}
+	}
+	for _, certBytes := range fabricMSPConfig.GetIntermediateCerts() {
+		if cert, err := TLSCertByBytes(certBytes); err == nil {
+			intermediates.AddCert(cert)
+		}
+	}
+	// The expiry is given by the inspection, the chain is checked within the validity of the certificate.
+	if _, err := inspection.cert.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates,
+		CurrentTime: inspection.cert.NotBefore.Add(time.Second), KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}); err != nil {
+		check.ChainError = err.Error()
+	} else {
+		check.ChainsToRoot = true
+	}
+
+	if nodeOUs := fabricMSPConfig.GetFabricNodeOus(); nodeOUs.GetEnable() {
+		check.NodeOUs = true
+		// The first role of the OUs, the admin one wins over the client one of the same certificate.
+		for _, roleOU := range []struct {
+			role         string
+			ouIdentifier *protosmsp.FabricOUIdentifier
+		}{
+			{IdentityRoleAdmin, nodeOUs.GetAdminOuIdentifier()},
+			{IdentityRoleClient, nodeOUs.GetClientOuIdentifier()},
+			{IdentityRolePeer, nodeOUs.GetPeerOuIdentifier()},
+			{IdentityRoleOrderer, nodeOUs.GetOrdererOuIdentifier()},
+		} {
+			if ou := roleOU.ouIdentifier.GetOrganizationalUnitIdentifier(); ou != "" && inspection.hasOU(ou) {
+				check.Role = roleOU.role
+				break
+			}
+		}
+	}
+	check.Admin = check.Role == IdentityRoleAdmin
+	for _, admin := range fabricMSPConfig.GetAdmins() {
+		if adminCert, err := TLSCertByBytes(admin); err == nil && bytes.Equal(adminCert.Raw, inspection.cert.Raw) {
+			check.Admin = true
+		}
+	}
+}
+
+// mergeRoles to get the role of the highest precedence of the roles of channels, admin, client, peer, orderer and then member.
+func mergeRoles(roles map[string]bool) string {
+	for _, role := range []string{IdentityRoleAdmin, IdentityRoleClient, IdentityRolePeer, IdentityRoleOrderer} {
+		if roles[role] {
+			return role
+		}
+	}
+	return IdentityRoleMember
+}
+
+// predictCapabilities to predict operations of Fablet by the validity, the key, the MSP of channels and the role.
+func (inspection *IdentityInspection) predictCapabilities() {
+	base := ""
+	switch {
+	case inspection.Expired:
+		base = "the certificate is expired or not yet valid"
+	case !inspection.KeyMatch:
+		base = "the private key doesn't match the certificate"
+	}
+
+	member, admin, writer := "", false, ""
+	if len(inspection.Channels) > 0 {
+		member = fmt.Sprintf("MSP %s is not in any channel", inspection.MSPID)
+		// Roles of the channels where the identity is a member, it may differ since each channel has its MSP config.
+		roles, isWriter := map[string]bool{}, false
+		for _, check := range inspection.Channels {
+			if !check.MSPFound {
+				continue
+			}
+			if !check.ChainsToRoot {
+				member = fmt.Sprintf("the certificate doesn't chain to roots of MSP %s", inspection.MSPID)
+				continue
+			}
+			member = ""
+			admin = admin || check.Admin
+			roles[check.Role] = true
+			isWriter = isWriter || !check.NodeOUs || check.Role == IdentityRoleAdmin || check.Role == IdentityRoleClient
+		}
+		if member == "" {
+			inspection.Role = mergeRoles(roles)
+			if !isWriter {
+				writer = fmt.Sprintf("the role %s is not a writer of default policies in any channel, which are of admins and clients", inspection.Role)
+			}
+		}
+	} else {
+		role := inspection.Role
+		admin = role == IdentityRoleAdmin
+		if role != IdentityRoleMember && role != IdentityRoleAdmin && role != IdentityRoleClient {
+			writer = fmt.Sprintf("the role %s is not a writer of default policies, which are of admins and clients", role)
+		}
+	}
+
+	allowed := func(operation string, reasons ...string) *IdentityCapability {
+		for _, reason := range reasons {
+			if reason != "" {
+				return &IdentityCapability{Operation: operation, Reason: reason}
+			}
+		}
+		return &IdentityCapability{Operation: operation, Allowed: true}
+	}
+	notAdmin := ""
+	if !admin {
+		notAdmin = fmt.Sprintf("the identity is not an admin of MSP %s", inspection.MSPID)
+	}
+	inspection.Capabilities = []*IdentityCapability{
+		allowed(IdentityOpQuery, base, member),
+		allowed(IdentityOpExecute, base, member, writer),
+		allowed(IdentityOpInstall, base, member, notAdmin),
+		allowed(IdentityOpInstantiate, base, member, notAdmin),
+		allowed(IdentityOpJoinChannel, base, member, notAdmin),
+		allowed(IdentityOpCreateChannel, base, member, notAdmin),
+	}
+}
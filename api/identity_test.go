@@ -0,0 +1,136 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	protosmsp "github.com/hyperledger/fabric-protos-go/msp"
+)
+
+// newTestIdentity to create an identity of the OU issued by the CA, with the PKCS8 key.
+func newTestIdentity(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, ou string) *Participant {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "user1", OrganizationalUnit: []string{ou}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+	}, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, _ := x509.MarshalPKCS8PrivateKey(key)
+	return &Participant{MSPID: "Org1MSP",
+		Cert:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		PrivateKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})}
+}
+
+func capabilitiesOf(inspection *IdentityInspection) map[string]bool {
+	allowed := map[string]bool{}
+	for _, capability := range inspection.Capabilities {
+		allowed[capability.Operation] = capability.Allowed
+	}
+	return allowed
+}
+
+func TestInspectIdentity(t *testing.T) {
+	fake := newFakeCA(t)
+	client := newTestIdentity(t, fake.cert, fake.key, "client")
+	inspection, err := InspectIdentity(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inspection.Subject != "CN=user1,OU=client" || inspection.Role != IdentityRoleClient || !inspection.KeyMatch || inspection.Expired {
+		t.Errorf("expected the client identity, but got %+v", inspection)
+	}
+	if allowed := capabilitiesOf(inspection); !allowed[IdentityOpQuery] || !allowed[IdentityOpExecute] || allowed[IdentityOpInstall] {
+		t.Errorf("expected the client to query and execute only, but got %v", allowed)
+	}
+
+	// The key of another identity.
+	other := newTestIdentity(t, fake.cert, fake.key, "admin")
+	client.PrivateKey = other.PrivateKey
+	inspection, _ = InspectIdentity(client)
+	if inspection.KeyMatch || inspection.Capabilities[0].Allowed || inspection.Capabilities[0].Reason != "the private key doesn't match the certificate" {
+		t.Errorf("expected the key doesn't match, but got %+v", inspection)
+	}
+	if _, err := InspectIdentity(&Participant{Cert: []byte("cert")}); err == nil {
+		t.Error("expected error of the invalid certificate")
+	}
+
+	// Against the MSP of a channel, with NodeOUs.
+	nodeOUs := &protosmsp.FabricMSPConfig{
+		Name:      "Org1MSP",
+		RootCerts: [][]byte{pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: fake.cert.Raw})},
+		FabricNodeOus: &protosmsp.FabricNodeOUs{Enable: true,
+			ClientOuIdentifier: &protosmsp.FabricOUIdentifier{OrganizationalUnitIdentifier: "client"},
+			PeerOuIdentifier:   &protosmsp.FabricOUIdentifier{OrganizationalUnitIdentifier: "peer"},
+			AdminOuIdentifier:  &protosmsp.FabricOUIdentifier{OrganizationalUnitIdentifier: "admin"},
+		},
+	}
+	inspection, _ = InspectIdentity(other)
+	check := &IdentityChannelCheck{ChannelID: "mychannel", Role: IdentityRoleMember}
+	inspection.checkMSP(nodeOUs, check)
+	inspection.Channels = append(inspection.Channels, check)
+	inspection.predictCapabilities()
+	if !check.ChainsToRoot || !check.NodeOUs || check.Role != IdentityRoleAdmin || !check.Admin {
+		t.Errorf("expected the admin of the MSP, but got %+v", check)
+	}
+	for operation, allowed := range capabilitiesOf(inspection) {
+		if !allowed {
+			t.Errorf("expected the admin is allowed to %s", operation)
+		}
+	}
+
+	// A peer of another CA, and an admin certificate without NodeOUs.
+	anotherCA := newFakeCA(t)
+	peer := newTestIdentity(t, anotherCA.cert, anotherCA.key, "peer")
+	inspection, _ = InspectIdentity(peer)
+	check = &IdentityChannelCheck{ChannelID: "mychannel", Role: IdentityRoleMember}
+	inspection.checkMSP(nodeOUs, check)
+	inspection.Channels = append(inspection.Channels, check)
+	inspection.predictCapabilities()
+	if check.ChainsToRoot || check.ChainError == "" || check.Role != IdentityRolePeer || capabilitiesOf(inspection)[IdentityOpQuery] {
+		t.Errorf("expected the peer doesn't chain to roots, but got %+v %v", check, capabilitiesOf(inspection))
+	}
+
+	noNodeOUs := &protosmsp.FabricMSPConfig{Name: "Org1MSP", RootCerts: [][]byte{pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: anotherCA.cert.Raw})}, Admins: [][]byte{peer.Cert}}
+	inspection, _ = InspectIdentity(peer)
+	check = &IdentityChannelCheck{ChannelID: "mychannel", Role: IdentityRoleMember}
+	inspection.checkMSP(noNodeOUs, check)
+	inspection.Channels = append(inspection.Channels, check)
+	inspection.predictCapabilities()
+	if !check.ChainsToRoot || check.NodeOUs || !check.Admin || inspection.Role != IdentityRoleMember || !capabilitiesOf(inspection)[IdentityOpInstall] {
+		t.Errorf("expected the admin certificate of the MSP, but got %+v %+v", check, inspection)
+	}
+
+	// Roles of channels are merged, the client of a channel with NodeOUs is not overridden by a later channel without them.
+	clientFakeCA := newTestIdentity(t, fake.cert, fake.key, "client")
+	inspection, _ = InspectIdentity(clientFakeCA)
+	for channelID, cfg := range map[string]*protosmsp.FabricMSPConfig{"channela": nodeOUs, "channelb": {Name: "Org1MSP", RootCerts: nodeOUs.RootCerts}} {
+		check = &IdentityChannelCheck{ChannelID: channelID, Role: IdentityRoleMember}
+		inspection.checkMSP(cfg, check)
+		inspection.Channels = append(inspection.Channels, check)
+	}
+	inspection.predictCapabilities()
+	if inspection.Role != IdentityRoleClient || !capabilitiesOf(inspection)[IdentityOpExecute] || capabilitiesOf(inspection)[IdentityOpInstall] {
+		t.Errorf("expected the client of channels, but got %+v %v", inspection, capabilitiesOf(inspection))
+	}
+
+	// The MSP is not of the channel.
+	inspection.Channels = []*IdentityChannelCheck{{ChannelID: "otherchannel", Role: IdentityRoleMember}}
+	inspection.predictCapabilities()
+	if capability := inspection.Capabilities[0]; capability.Allowed || capability.Reason != "MSP Org1MSP is not in any channel" {
+		t.Errorf("expected the MSP is not in channels, but got %+v", capability)
+	}
+}
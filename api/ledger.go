@@ -30,6 +30,7 @@ type Endorser struct {
 	MSPID      string `json:"MSPID"`
 	Sign       string `json:"sign"`
 	Issuer     string `json:"issuer"`
+	cert       *x509.Certificate
 }
 
 // Action action of a transaction
@@ -43,7 +44,9 @@ type Action struct {
 
 // Transaction transaction of a block
 type Transaction struct {
-	Actions []*Action `json:"actions"`
+	TxID           string    `json:"txID"`
+	ValidationCode string    `json:"validationCode"` // From the transactions filter of the block metadata.
+	Actions        []*Action `json:"actions"`
 }
 
 // Block block of a ledger
@@ -66,10 +69,11 @@ type Ledger struct {
 
 // ChaincodeData a simplied chaincode data from lscc
 type ChaincodeData struct {
-	Name       string   `json:"name"`
-	Version    string   `json:"version"`
-	Principals []string `json:"principals"`
-	Rule       string   `json:"rule"`
+	Name       string      `json:"name"`
+	Version    string      `json:"version"`
+	Principals []string    `json:"principals"`
+	Rule       string      `json:"rule"`
+	Policy     *PolicyNode `json:"policy"`
 }
 
 // KVRead key value pair.
@@ -93,11 +97,21 @@ type HashReadWriteCol struct {
 	KVWriteSet     []*KVWrite `json:"kvWriteSet"`
 }
 
+// RangeQuery range query info, for the phantom read validation.
+type RangeQuery struct {
+	StartKey     string    `json:"startKey"`
+	EndKey       string    `json:"endKey"`
+	ItrExhausted bool      `json:"itrExhausted"`
+	KVReadSet    []*KVRead `json:"kvReadSet"`    // Raw reads, empty if the reads are summarized as merkle hashes.
+	MerkleHashed bool      `json:"merkleHashed"` // Too many reads so only the merkle hashes are recorded.
+}
+
 // NSReadWriteSet namespace rw set.
 type NSReadWriteSet struct {
 	NameSpace         string              `json:"nameSpace"`
 	KVReadSet         []*KVRead           `json:"kvReadSet"`
 	KVWriteSet        []*KVWrite          `json:"kvWriteSet"`
+	RangeQueries      []*RangeQuery       `json:"rangeQueries"`
 	HashReadWriteCols []*HashReadWriteCol `json:"hashReadWriteCols"`
 }
 
@@ -198,6 +212,12 @@ func getCert(bytes []byte) *x509.Certificate {
 }
 
 func translateProposalResponse(capl *peer.ChaincodeActionPayload) *ProposalResponse {
+	return translateProposalResponsePayload(capl.GetAction().GetProposalResponsePayload())
+}
+
+// translateProposalResponsePayload to translate the bytes of peer.ProposalResponsePayload,
+// which is either in a transaction of block, or responsed by the endorser directly.
+func translateProposalResponsePayload(payload []byte) *ProposalResponse {
 	_txrwset := &TXReadWriteSet{NSReadWriteSets: []*NSReadWriteSet{}}
 	_pr := &ProposalResponse{TXReadWriteSet: _txrwset}
 
@@ -206,7 +226,7 @@ func translateProposalResponse(capl *peer.ChaincodeActionPayload) *ProposalRespo
 	txrwst := &rwset.TxReadWriteSet{}
 	kvst := &kvrwset.KVRWSet{}
 
-	if proto.Unmarshal(capl.GetAction().GetProposalResponsePayload(), prpl) != nil ||
+	if proto.Unmarshal(payload, prpl) != nil ||
 		proto.Unmarshal(prpl.GetExtension(), ccac) != nil ||
 		proto.Unmarshal(ccac.GetResults(), txrwst) != nil {
 		return _pr
@@ -230,6 +250,7 @@ func translateProposalResponse(capl *peer.ChaincodeActionPayload) *ProposalRespo
 			NameSpace:         nsrwst.GetNamespace(),
 			KVReadSet:         []*KVRead{},
 			KVWriteSet:        []*KVWrite{},
+			RangeQueries:      []*RangeQuery{},
 			HashReadWriteCols: []*HashReadWriteCol{},
 		}
 		_txrwset.NSReadWriteSets = append(_txrwset.NSReadWriteSets, _nsrwst)
@@ -289,6 +310,23 @@ func translateProposalResponse(capl *peer.ChaincodeActionPayload) *ProposalRespo
 				}
 				_nsrwst.KVWriteSet = append(_nsrwst.KVWriteSet, _kvw)
 			}
+			for _, rqi := range kvst.GetRangeQueriesInfo() {
+				_rq := &RangeQuery{
+					StartKey:     rqi.GetStartKey(),
+					EndKey:       rqi.GetEndKey(),
+					ItrExhausted: rqi.GetItrExhausted(),
+					KVReadSet:    []*KVRead{},
+					MerkleHashed: rqi.GetReadsMerkleHashes() != nil,
+				}
+				for _, read := range rqi.GetRawReads().GetKvReads() {
+					_rq.KVReadSet = append(_rq.KVReadSet, &KVRead{
+						Key:         read.GetKey(),
+						VerBlockNum: read.GetVersion().GetBlockNum(),
+						VerTxNum:    read.GetVersion().GetTxNum(),
+					})
+				}
+				_nsrwst.RangeQueries = append(_nsrwst.RangeQueries, _rq)
+			}
 		}
 	}
 
@@ -318,6 +356,9 @@ func translateChaincodeData(payload []byte) *ChaincodeData {
 				_ccd.Principals = append(_ccd.Principals, buffer.String())
 			}
 			_ccd.Rule = spe.GetRule().String()
+			if policy, err := PolicyFromEnvelope(spe); err == nil {
+				_ccd.Policy = policy
+			}
 		}
 
 	}
@@ -334,6 +375,7 @@ func translateBlock(block *common.Block) *Block {
 	}
 
 	transactions := []*Transaction{}
+	txFilter := getTxFilter(block)
 
 	env := &common.Envelope{}
 	pl := &common.Payload{} //common.Header, byte of peer.Transaction
@@ -344,7 +386,7 @@ func translateBlock(block *common.Block) *Block {
 	edr := &protosmsp.SerializedIdentity{} //Not SigningIdentityInfo{}
 	input := &peer.ChaincodeInvocationSpec{}
 
-	for _, d := range block.GetData().GetData() {
+	for idx, d := range block.GetData().GetData() {
 		// No error handling
 		// TODO
 		proto.Unmarshal(d, env)
@@ -373,6 +415,7 @@ func translateBlock(block *common.Block) *Block {
 					endorser.IsCA = cert.IsCA
 					endorser.Subject = cert.Subject.String()
 					endorser.Issuer = cert.Issuer.String()
+					endorser.cert = cert
 				}
 				endorsers = append(endorsers, endorser)
 			}
@@ -388,7 +431,11 @@ func translateBlock(block *common.Block) *Block {
 			actions = append(actions, action)
 		}
 
-		transaction := &Transaction{Actions: actions}
+		transaction := &Transaction{
+			TxID:           ch.GetTxId(),
+			ValidationCode: txFilter.validationCode(idx),
+			Actions:        actions,
+		}
 		transactions = append(transactions, transaction)
 	}
 
@@ -404,6 +451,24 @@ func translateBlock(block *common.Block) *Block {
 	return blk
 }
 
+// txFilter validation codes of the transactions in a block.
+type txFilter []byte
+
+func getTxFilter(block *common.Block) txFilter {
+	metadata := block.GetMetadata().GetMetadata()
+	if len(metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		return nil
+	}
+	return txFilter(metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+}
+
+func (filter txFilter) validationCode(idx int) string {
+	if idx >= len(filter) {
+		return ""
+	}
+	return peer.TxValidationCode(filter[idx]).String()
+}
+
 // QueryBlock to query blocks of the given numbers.
 // TODO the targets can be empty
 func QueryBlock(conn *NetworkConnection, channelID string, targets []string, begin uint64, len uint64) ([]*Block, error) {
@@ -433,6 +498,22 @@ func QueryBlock(conn *NetworkConnection, channelID string, targets []string, beg
 	return blocks, nil
 }
 
+// QueryBlockByNumber to query the block of the given number, the error of the peer is returned as it is.
+func QueryBlockByNumber(conn *NetworkConnection, channelID string, targets []string, number uint64) (*Block, error) {
+	// TODO to use a common getChannelContext
+	channelContext := conn.SDK.ChannelContext(channelID, fabsdk.WithIdentity(conn.SignID))
+	ldgClient, err := ledger.New(channelContext)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := ldgClient.QueryBlock(number, ledger.WithTargetEndpoints(targets...))
+	if err != nil {
+		return nil, err
+	}
+	return translateBlock(block), nil
+}
+
 // QueryBlockByHash to query blocks of the given hash.
 func QueryBlockByHash(conn *NetworkConnection, channelID string, targets []string, blockHash string) (*Block, error) {
 	// TODO to use a common getChannelContext
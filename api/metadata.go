@@ -0,0 +1,460 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/pkg/errors"
+)
+
+// MetadataFunction the system function exposed by chaincodes written with the Fabric contract API.
+const MetadataFunction = "org.hyperledger.fabric:GetMetadata"
+
+// NoMetadataTTL how long a chaincode without metadata is cached as such, after that the metadata is queried again.
+var NoMetadataTTL = 10 * time.Minute
+
+// systemContractNamespace the namespace of the system contract of the contract API, which is not in the metadata.
+const systemContractNamespace = "org.hyperledger.fabric"
+
+// ContractMetadata metadata returned by org.hyperledger.fabric:GetMetadata
+type ContractMetadata struct {
+	Info       map[string]interface{} `json:"info,omitempty"`
+	Contracts  map[string]*Contract   `json:"contracts"`
+	Components ContractComponents     `json:"components"`
+}
+
+// ContractComponents reusable schemas referred by $ref.
+type ContractComponents struct {
+	Schemas map[string]ContractSchema `json:"schemas,omitempty"`
+}
+
+// Contract a contract within the chaincode.
+type Contract struct {
+	Name         string                 `json:"name"`
+	Info         map[string]interface{} `json:"info,omitempty"`
+	Default      bool                   `json:"default"`
+	Transactions []*ContractTransaction `json:"transactions"`
+}
+
+// ContractTransaction a transaction function of a contract.
+type ContractTransaction struct {
+	Name       string               `json:"name"`
+	Tag        []string             `json:"tag,omitempty"`
+	Parameters []*ContractParameter `json:"parameters,omitempty"`
+	Returns    ContractSchema       `json:"returns,omitempty"`
+}
+
+// ContractParameter parameter of a transaction function.
+type ContractParameter struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Schema      ContractSchema `json:"schema"`
+}
+
+// ContractSchema a JSON schema, only the subset used by the contract API is evaluated.
+type ContractSchema map[string]interface{}
+
+// UnmarshalJSON the node contract API describes returns as [{"name": "success", "schema": {...}}], while go as a schema directly.
+func (schema *ContractSchema) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		returns := []*ContractParameter{}
+		if err := json.Unmarshal(data, &returns); err != nil {
+			return err
+		}
+		if len(returns) > 0 {
+			*schema = returns[0].Schema
+		}
+		return nil
+	}
+	tmp := map[string]interface{}{}
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	*schema = tmp
+	return nil
+}
+
+// ArgumentError error of a single argument.
+type ArgumentError struct {
+	Index   int    `json:"index"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// ArgumentValidationError all errors found when validating the arguments against the metadata.
+type ArgumentValidationError struct {
+	Function string           `json:"function"`
+	Errors   []*ArgumentError `json:"errors"`
+}
+
+func (ave *ArgumentValidationError) Error() string {
+	msgs := []string{}
+	for _, argErr := range ave.Errors {
+		if argErr.Index < 0 {
+			msgs = append(msgs, argErr.Message)
+		} else {
+			msgs = append(msgs, fmt.Sprintf("argument %d (%s): %s", argErr.Index, argErr.Name, argErr.Message))
+		}
+	}
+	return fmt.Sprintf("Invalid arguments of %s: %s", ave.Function, strings.Join(msgs, "; "))
+}
+
+// ParseContractMetadata to parse the payload of GetMetadata.
+func ParseContractMetadata(payload []byte) (*ContractMetadata, error) {
+	metadata := &ContractMetadata{}
+	if err := json.Unmarshal(payload, metadata); err != nil {
+		return nil, errors.WithMessage(err, "Failed to parse the contract metadata.")
+	}
+	if len(metadata.Contracts) < 1 {
+		return nil, errors.New("no any contract found in the metadata")
+	}
+	for name, contract := range metadata.Contracts {
+		if contract.Name == "" {
+			contract.Name = name
+		}
+	}
+	return metadata, nil
+}
+
+// QueryChaincodeMetadata to query the contract metadata from the chaincode, and then cache it in the connection.
+// If the chaincode answers with an error, or with an invalid metadata, it is cached without metadata for NoMetadataTTL,
+// as it might not be written with the contract API. Other errors, e.g. of the transport, are not cached.
+func QueryChaincodeMetadata(conn *NetworkConnection, channelID string, chaincodeID string) (*ContractMetadata, error) {
+	response, err := ExecuteChaincode(conn, channelID, chaincodeID, ChaincodeOperTypeQuery, nil, MetadataFunction, []string{})
+	if err != nil {
+		if isChaincodeResponseError(err) {
+			conn.storeChaincodeMetadata(channelID, chaincodeID, nil)
+		}
+		return nil, err
+	}
+	metadata, err := ParseContractMetadata(response.Payload)
+	if err != nil {
+		conn.storeChaincodeMetadata(channelID, chaincodeID, nil)
+		return nil, err
+	}
+	conn.storeChaincodeMetadata(channelID, chaincodeID, metadata)
+	return metadata, nil
+}
+
+// InvalidateChaincodeMetadata to remove the cached metadata, e.g. when the chaincode is upgraded.
+func InvalidateChaincodeMetadata(conn *NetworkConnection, channelID string, chaincodeID string) {
+	conn.metadataLock.Lock()
+	defer conn.metadataLock.Unlock()
+	delete(conn.ChannelChaincodeMetadata[channelID], chaincodeID)
+	delete(conn.noMetadataExpiry[channelID], chaincodeID)
+}
+
+// isChaincodeResponseError returns whether the error is the response of the chaincode itself, e.g. the function is unknown,
+// rather than an error of the transport or the endorser.
+func isChaincodeResponseError(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	if s.Group == status.ChaincodeStatus {
+		return true
+	}
+	if s.Group != status.ClientStatus || s.Code != status.MultipleErrors.ToInt32() || len(s.Details) == 0 {
+		return false
+	}
+	for _, detail := range s.Details {
+		detailErr, ok := detail.(error)
+		if !ok || !isChaincodeResponseError(detailErr) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetChaincodeMetadata to get the cached metadata, query it if not found or refresh is required.
+func GetChaincodeMetadata(conn *NetworkConnection, channelID string, chaincodeID string, refresh bool) (*ContractMetadata, error) {
+	if !refresh {
+		if metadata, _ := conn.findChaincodeMetadata(channelID, chaincodeID); metadata != nil {
+			return metadata, nil
+		}
+	}
+	return QueryChaincodeMetadata(conn, channelID, chaincodeID)
+}
+
+// ValidateChaincodeArguments to validate the arguments by the metadata, which is queried at the first time of the chaincode.
+// It returns nil if there is no metadata, since the chaincode might not be written with the contract API.
+func ValidateChaincodeArguments(conn *NetworkConnection, channelID string, chaincodeID string, funcName string, args []string) error {
+	if isSystemContractFunction(funcName) {
+		return nil
+	}
+	metadata, queried := conn.findChaincodeMetadata(channelID, chaincodeID)
+	if !queried {
+		if _, err := QueryChaincodeMetadata(conn, channelID, chaincodeID); err != nil {
+			logger.Debugf("No contract metadata of chaincode %s on channel %s: %s", chaincodeID, channelID, err.Error())
+		}
+		metadata, _ = conn.findChaincodeMetadata(channelID, chaincodeID)
+	}
+	if metadata == nil {
+		return nil
+	}
+	return metadata.ValidateArguments(funcName, args)
+}
+
+// findChaincodeMetadata returns whether the metadata has been queried, the metadata is nil if the chaincode has none.
+// A chaincode without metadata is regarded as not queried after NoMetadataTTL.
+func (conn *NetworkConnection) findChaincodeMetadata(channelID string, chaincodeID string) (*ContractMetadata, bool) {
+	conn.metadataLock.RLock()
+	defer conn.metadataLock.RUnlock()
+	metadata, ok := conn.ChannelChaincodeMetadata[channelID][chaincodeID]
+	if ok && metadata == nil && time.Now().After(conn.noMetadataExpiry[channelID][chaincodeID]) {
+		return nil, false
+	}
+	return metadata, ok
+}
+
+func (conn *NetworkConnection) storeChaincodeMetadata(channelID string, chaincodeID string, metadata *ContractMetadata) {
+	conn.metadataLock.Lock()
+	defer conn.metadataLock.Unlock()
+	if conn.ChannelChaincodeMetadata == nil {
+		conn.ChannelChaincodeMetadata = make(map[string]map[string]*ContractMetadata)
+	}
+	if _, ok := conn.ChannelChaincodeMetadata[channelID]; !ok {
+		conn.ChannelChaincodeMetadata[channelID] = make(map[string]*ContractMetadata)
+	}
+	conn.ChannelChaincodeMetadata[channelID][chaincodeID] = metadata
+
+	if conn.noMetadataExpiry == nil {
+		conn.noMetadataExpiry = make(map[string]map[string]time.Time)
+	}
+	if _, ok := conn.noMetadataExpiry[channelID]; !ok {
+		conn.noMetadataExpiry[channelID] = make(map[string]time.Time)
+	}
+	if metadata == nil {
+		conn.noMetadataExpiry[channelID][chaincodeID] = time.Now().Add(NoMetadataTTL)
+	} else {
+		delete(conn.noMetadataExpiry[channelID], chaincodeID)
+	}
+}
+
+// isSystemContractFunction functions of the system contract, e.g. org.hyperledger.fabric:GetMetadata, are of every contract chaincode.
+func isSystemContractFunction(funcName string) bool {
+	return strings.HasPrefix(funcName, systemContractNamespace+":")
+}
+
+// FindTransaction to find the transaction by function name, which might be <contract>:<transaction> or only <transaction> of the default contract.
+func (metadata *ContractMetadata) FindTransaction(funcName string) (*Contract, *ContractTransaction) {
+	contractName, txName := "", funcName
+	if idx := strings.LastIndex(funcName, ":"); idx >= 0 {
+		contractName, txName = funcName[:idx], funcName[idx+1:]
+	}
+
+	contracts := []*Contract{}
+	if contractName != "" {
+		if contract, ok := metadata.Contracts[contractName]; ok {
+			contracts = append(contracts, contract)
+		}
+	} else {
+		for _, contract := range metadata.Contracts {
+			if contract.Default || len(metadata.Contracts) == 1 {
+				contracts = []*Contract{contract}
+				break
+			}
+			contracts = append(contracts, contract)
+		}
+	}
+
+	for _, contract := range contracts {
+		for _, tx := range contract.Transactions {
+			if tx.Name == txName {
+				return contract, tx
+			}
+		}
+	}
+	return nil, nil
+}
+
+// ValidateArguments to validate the arguments against the transaction parameters.
+func (metadata *ContractMetadata) ValidateArguments(funcName string, args []string) error {
+	if isSystemContractFunction(funcName) {
+		return nil
+	}
+	valErr := &ArgumentValidationError{Function: funcName, Errors: []*ArgumentError{}}
+
+	_, tx := metadata.FindTransaction(funcName)
+	if tx == nil {
+		valErr.Errors = append(valErr.Errors, &ArgumentError{Index: -1, Message: "function not found in the contract metadata"})
+		return valErr
+	}
+
+	if len(args) != len(tx.Parameters) {
+		valErr.Errors = append(valErr.Errors, &ArgumentError{Index: -1,
+			Message: fmt.Sprintf("expected %d arguments but got %d", len(tx.Parameters), len(args))})
+		return valErr
+	}
+
+	for idx, param := range tx.Parameters {
+		if msg := metadata.validateArgument(param.Schema, args[idx]); msg != "" {
+			valErr.Errors = append(valErr.Errors, &ArgumentError{Index: idx, Name: param.Name, Message: msg})
+		}
+	}
+
+	if len(valErr.Errors) > 0 {
+		return valErr
+	}
+	return nil
+}
+
+// The contract API takes string parameters as they are, and others as JSON.
+func (metadata *ContractMetadata) validateArgument(schema ContractSchema, arg string) string {
+	schema = metadata.resolveSchema(schema)
+	if schemaType, _ := schema["type"].(string); schemaType == "string" || schemaType == "" {
+		return metadata.validateValue(schema, arg, "")
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(strings.NewReader(arg))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Sprintf("not a valid %v value", schema["type"])
+	}
+	return metadata.validateValue(schema, value, "")
+}
+
+func (metadata *ContractMetadata) resolveSchema(schema ContractSchema) ContractSchema {
+	// Avoid endless reference.
+	for depth := 0; depth < 16; depth++ {
+		ref, ok := schema["$ref"].(string)
+		if !ok {
+			return schema
+		}
+		name := ref[strings.LastIndex(ref, "/")+1:]
+		refSchema, ok := metadata.Components.Schemas[name]
+		if !ok {
+			return ContractSchema{}
+		}
+		schema = refSchema
+	}
+	return schema
+}
+
+func toContractSchema(v interface{}) ContractSchema {
+	if m, ok := v.(map[string]interface{}); ok {
+		return ContractSchema(m)
+	}
+	if m, ok := v.(ContractSchema); ok {
+		return m
+	}
+	return ContractSchema{}
+}
+
+func schemaNumber(schema ContractSchema, key string) (float64, bool) {
+	n, ok := schema[key].(float64)
+	return n, ok
+}
+
+// validateValue returns the message of the first violation, or empty if valid.
+func (metadata *ContractMetadata) validateValue(schema ContractSchema, value interface{}, path string) string {
+	schema = metadata.resolveSchema(schema)
+	prefix := ""
+	if path != "" {
+		prefix = path + ": "
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if fmt.Sprintf("%v", e) == fmt.Sprintf("%v", value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("%s%v is not one of %v", prefix, value, enum)
+		}
+	}
+
+	schemaType, _ := schema["type"].(string)
+	switch schemaType {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return prefix + "must be a string"
+		}
+		if min, ok := schemaNumber(schema, "minLength"); ok && float64(len(str)) < min {
+			return fmt.Sprintf("%smust be at least %v characters", prefix, min)
+		}
+		if max, ok := schemaNumber(schema, "maxLength"); ok && float64(len(str)) > max {
+			return fmt.Sprintf("%smust be at most %v characters", prefix, max)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(str) {
+				return fmt.Sprintf("%smust match pattern %s", prefix, pattern)
+			}
+		}
+	case "number", "integer":
+		num, ok := value.(json.Number)
+		if !ok {
+			return fmt.Sprintf("%smust be a %s", prefix, schemaType)
+		}
+		if schemaType == "integer" {
+			if _, err := num.Int64(); err != nil {
+				return prefix + "must be an integer"
+			}
+		}
+		f, err := num.Float64()
+		if err != nil {
+			return fmt.Sprintf("%smust be a %s", prefix, schemaType)
+		}
+		if min, ok := schemaNumber(schema, "minimum"); ok && f < min {
+			return fmt.Sprintf("%smust be >= %v", prefix, min)
+		}
+		if max, ok := schemaNumber(schema, "maximum"); ok && f > max {
+			return fmt.Sprintf("%smust be <= %v", prefix, max)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return prefix + "must be a boolean"
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return prefix + "must be an array"
+		}
+		if itemSchema, ok := schema["items"]; ok {
+			for idx, item := range items {
+				if msg := metadata.validateValue(toContractSchema(itemSchema), item, fmt.Sprintf("%s[%d]", path, idx)); msg != "" {
+					return msg
+				}
+			}
+		}
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return prefix + "must be an object"
+		}
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, r := range required {
+				if _, exist := obj[fmt.Sprintf("%v", r)]; !exist {
+					return fmt.Sprintf("%smissing required property %v", prefix, r)
+				}
+			}
+		}
+		if props, ok := schema["properties"].(map[string]interface{}); ok {
+			for name, propSchema := range props {
+				propValue, exist := obj[name]
+				if !exist {
+					continue
+				}
+				subPath := name
+				if path != "" {
+					subPath = path + "." + name
+				}
+				if msg := metadata.validateValue(toContractSchema(propSchema), propValue, subPath); msg != "" {
+					return msg
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/pkg/errors"
+	grpcCodes "google.golang.org/grpc/codes"
+)
+
+var testContractMetadata = []byte(`{
+	"info": {"title": "vehicle", "version": "1.0"},
+	"contracts": {
+		"VehicleContract": {
+			"name": "VehicleContract",
+			"default": true,
+			"transactions": [
+				{
+					"name": "CreateVehicle",
+					"tag": ["submit"],
+					"parameters": [
+						{"name": "id", "schema": {"type": "string", "minLength": 1}},
+						{"name": "price", "schema": {"type": "integer", "minimum": 0}},
+						{"name": "vehicle", "schema": {"$ref": "#/components/schemas/Vehicle"}}
+					]
+				},
+				{
+					"name": "FindVehicle",
+					"parameters": [{"name": "id", "schema": {"type": "string"}}],
+					"returns": [{"name": "success", "schema": {"$ref": "#/components/schemas/Vehicle"}}]
+				}
+			]
+		},
+		"LeaseContract": {
+			"transactions": [
+				{"name": "CreateLease", "parameters": [{"name": "shared", "schema": {"type": "boolean"}}]}
+			]
+		}
+	},
+	"components": {
+		"schemas": {
+			"Vehicle": {
+				"type": "object",
+				"required": ["brand"],
+				"properties": {
+					"brand": {"type": "string"},
+					"tags": {"type": "array", "items": {"type": "string"}}
+				}
+			}
+		}
+	}
+}`)
+
+func TestContractMetadataValidation(t *testing.T) {
+	metadata, err := ParseContractMetadata(testContractMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.Contracts["LeaseContract"].Name != "LeaseContract" {
+		t.Fatal("contract name should be filled by its key")
+	}
+	_, tx := metadata.FindTransaction("FindVehicle")
+	if tx == nil || tx.Returns["$ref"] != "#/components/schemas/Vehicle" {
+		t.Fatal("node style returns should be parsed as schema")
+	}
+
+	valid := [][]string{
+		{"v001", "100", `{"brand": "b001"}`},
+		{"v002", "0", `{"brand": "b002", "tags": ["red"]}`},
+	}
+	for _, args := range valid {
+		if err := metadata.ValidateArguments("CreateVehicle", args); err != nil {
+			t.Errorf("%v should be valid: %s", args, err.Error())
+		}
+	}
+	if err := metadata.ValidateArguments("LeaseContract:CreateLease", []string{"true"}); err != nil {
+		t.Error(err)
+	}
+	if err := metadata.ValidateArguments(MetadataFunction, []string{}); err != nil {
+		t.Errorf("the system contract should be valid: %s", err.Error())
+	}
+
+	invalid := map[string][]string{
+		"CreateVehicle":             {"v001", "100"},
+		"NotExist":                  {},
+		"CreateLease":               {"true"},
+		"LeaseContract:CreateLease": {"yes"},
+	}
+	for funcName, args := range invalid {
+		if err := metadata.ValidateArguments(funcName, args); err == nil {
+			t.Errorf("%s %v should be invalid", funcName, args)
+		}
+	}
+
+	err = metadata.ValidateArguments("CreateVehicle", []string{"", "1.5", `{"tags": [1]}`})
+	valErr, ok := err.(*ArgumentValidationError)
+	if !ok {
+		t.Fatalf("expected ArgumentValidationError, got %v", err)
+	}
+	if len(valErr.Errors) != 3 {
+		t.Fatalf("expected 3 argument errors, got %s", valErr.Error())
+	}
+	t.Log(valErr.Error())
+}
+
+func TestChaincodeMetadataCache(t *testing.T) {
+	unknownFunc := status.New(status.ChaincodeStatus, 500, "Invalid invoke function name.", nil)
+	unreachable := status.New(status.GRPCTransportStatus, int32(grpcCodes.Unavailable), "connection refused", nil)
+	if !isChaincodeResponseError(errors.WithMessage(unknownFunc, "Error occurred when executing the chaincode vehiclesharing.")) {
+		t.Error("the chaincode response should be cached without metadata")
+	}
+	if !isChaincodeResponseError(multi.New(unknownFunc, unknownFunc)) {
+		t.Error("chaincode responses of all peers should be cached without metadata")
+	}
+	if isChaincodeResponseError(unreachable) || isChaincodeResponseError(multi.New(unknownFunc, unreachable)) {
+		t.Error("transport errors should not be cached")
+	}
+
+	conn := &NetworkConnection{}
+	conn.storeChaincodeMetadata("mychannel", "vehiclesharing", nil)
+	if _, queried := conn.findChaincodeMetadata("mychannel", "vehiclesharing"); !queried {
+		t.Error("the chaincode without metadata should be cached")
+	}
+	conn.noMetadataExpiry["mychannel"]["vehiclesharing"] = time.Now().Add(-time.Second)
+	if _, queried := conn.findChaincodeMetadata("mychannel", "vehiclesharing"); queried {
+		t.Error("the chaincode without metadata should be queried again after the TTL")
+	}
+
+	conn.storeChaincodeMetadata("mychannel", "vehiclesharing", &ContractMetadata{})
+	InvalidateChaincodeMetadata(conn, "mychannel", "vehiclesharing")
+	if _, queried := conn.findChaincodeMetadata("mychannel", "vehiclesharing"); queried {
+		t.Error("the metadata should be queried again after invalidation")
+	}
+}
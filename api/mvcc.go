@@ -0,0 +1,257 @@
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MVCCMaxBlocks the most blocks scanned in an analysis.
+	MVCCMaxBlocks = 5000
+	// mvccQueryBlocks blocks queried each time.
+	mvccQueryBlocks = 512
+)
+
+// MVCCConflictKey a key causes the conflict of a transaction.
+type MVCCConflictKey struct {
+	NameSpace   string `json:"nameSpace"` // <chaincode>/<collection> for private data, and the key is hex of its hash.
+	Key         string `json:"key"`
+	ReadVersion string `json:"readVersion"` // <block>:<tx> read by the invalid transaction, empty for phantom reads.
+	WriterTxID  string `json:"writerTxID"`  // The earlier valid transaction wrote the key.
+	WriterBlock uint64 `json:"writerBlock"`
+	WriterTxNum uint64 `json:"writerTxNum"`
+}
+
+// MVCCConflict a transaction invalidated for MVCC read conflict or phantom read conflict.
+type MVCCConflict struct {
+	TxID           string             `json:"txID"`
+	BlockNumber    uint64             `json:"blockNumber"`
+	TxNum          uint64             `json:"txNum"`
+	ValidationCode string             `json:"validationCode"`
+	ChaincodeName  string             `json:"chaincodeName"`
+	Arguments      []string           `json:"arguments"`
+	Keys           []*MVCCConflictKey `json:"keys"` // Might be empty if the writers are before the scanned range.
+}
+
+// HotKey a key ranked by count of conflicts.
+type HotKey struct {
+	NameSpace string `json:"nameSpace"`
+	Key       string `json:"key"`
+	Conflicts int    `json:"conflicts"`
+	Writes    int    `json:"writes"` // Valid writes in the scanned range.
+}
+
+// MVCCAnalysis result of the MVCC conflict analysis.
+type MVCCAnalysis struct {
+	ChannelID       string          `json:"channelID"`
+	BeginBlock      uint64          `json:"beginBlock"`
+	EndBlock        uint64          `json:"endBlock"`
+	Transactions    int             `json:"transactions"`
+	ValidationCodes map[string]int  `json:"validationCodes"`
+	Conflicts       []*MVCCConflict `json:"conflicts"`
+	HotKeys         []*HotKey       `json:"hotKeys"`
+}
+
+type mvccWriter struct {
+	txID     string
+	blockNum uint64
+	txNum    uint64
+	isDelete bool
+}
+
+// mvccState the latest valid writer of every key, map[ns\x00key].
+type mvccState map[string]*mvccWriter
+
+// AnalyzeMVCC to scan blocks from begin to end (inclusive), and find transactions invalidated for MVCC conflicts.
+func AnalyzeMVCC(conn *NetworkConnection, channelID string, targets []string, begin uint64, end uint64) (*MVCCAnalysis, error) {
+	if end < begin {
+		return nil, errors.Errorf("end block %d is less than begin block %d", end, begin)
+	}
+	if end-begin+1 > MVCCMaxBlocks {
+		return nil, errors.Errorf("at most %d blocks can be analyzed once", MVCCMaxBlocks)
+	}
+
+	blocks := []*Block{}
+	for n := begin; n <= end; n += mvccQueryBlocks {
+		count := end - n + 1
+		if count > mvccQueryBlocks {
+			count = mvccQueryBlocks
+		}
+		bs, err := QueryBlock(conn, channelID, targets, n, count)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "Error occurred when querying blocks from %d.", n)
+		}
+		blocks = append(blocks, bs...)
+	}
+
+	analysis := AnalyzeBlocksMVCC(blocks)
+	analysis.ChannelID = channelID
+	analysis.BeginBlock, analysis.EndBlock = begin, end
+	return analysis, nil
+}
+
+// AnalyzeBlocksMVCC to find MVCC conflicts from the blocks, which should be in order.
+// Keys written before the first block are unknown, so the writers of their conflicts cannot be named.
+func AnalyzeBlocksMVCC(blocks []*Block) *MVCCAnalysis {
+	analysis := &MVCCAnalysis{ValidationCodes: map[string]int{}, Conflicts: []*MVCCConflict{}, HotKeys: []*HotKey{}}
+	state := mvccState{}
+	hotKeys := map[string]*HotKey{}
+	hotKey := func(k string) *HotKey {
+		if _, ok := hotKeys[k]; !ok {
+			ns, key := splitNSKey(k)
+			hotKeys[k] = &HotKey{NameSpace: ns, Key: key}
+		}
+		return hotKeys[k]
+	}
+
+	for _, block := range blocks {
+		for txNum, tx := range block.Transactions {
+			analysis.Transactions++
+			analysis.ValidationCodes[tx.ValidationCode]++
+
+			switch tx.ValidationCode {
+			case peer.TxValidationCode_VALID.String():
+				for _, k := range state.write(tx, block.Number, uint64(txNum)) {
+					hotKey(k).Writes++
+				}
+			case peer.TxValidationCode_MVCC_READ_CONFLICT.String(), peer.TxValidationCode_PHANTOM_READ_CONFLICT.String():
+				conflict := &MVCCConflict{
+					TxID:           tx.TxID,
+					BlockNumber:    block.Number,
+					TxNum:          uint64(txNum),
+					ValidationCode: tx.ValidationCode,
+					Keys:           state.conflictKeys(tx),
+				}
+				if len(tx.Actions) > 0 {
+					conflict.ChaincodeName = tx.Actions[0].ChaincodeName
+					conflict.Arguments = tx.Actions[0].Arguments
+				}
+				for _, key := range conflict.Keys {
+					hotKey(key.NameSpace + "\x00" + key.Key).Conflicts++
+				}
+				analysis.Conflicts = append(analysis.Conflicts, conflict)
+			}
+		}
+	}
+
+	for _, hk := range hotKeys {
+		if hk.Conflicts > 0 {
+			analysis.HotKeys = append(analysis.HotKeys, hk)
+		}
+	}
+	sort.Slice(analysis.HotKeys, func(i, j int) bool {
+		hi, hj := analysis.HotKeys[i], analysis.HotKeys[j]
+		if hi.Conflicts != hj.Conflicts {
+			return hi.Conflicts > hj.Conflicts
+		}
+		if hi.Writes != hj.Writes {
+			return hi.Writes > hj.Writes
+		}
+		return hi.NameSpace+"\x00"+hi.Key < hj.NameSpace+"\x00"+hj.Key
+	})
+	return analysis
+}
+
+// txNSRWSets to get all rw sets of the transaction, private data are in namespace <chaincode>/<collection> with hex keys.
+func txNSRWSets(tx *Transaction) []*NSReadWriteSet {
+	nsrwsets := []*NSReadWriteSet{}
+	for _, action := range tx.Actions {
+		if action.ProposalResponse == nil || action.ProposalResponse.TXReadWriteSet == nil {
+			continue
+		}
+		for _, nsrwset := range action.ProposalResponse.TXReadWriteSet.NSReadWriteSets {
+			nsrwsets = append(nsrwsets, nsrwset)
+			for _, col := range nsrwset.HashReadWriteCols {
+				colset := &NSReadWriteSet{NameSpace: nsrwset.NameSpace + "/" + col.CollectionName}
+				for _, read := range col.KVReadSet {
+					colset.KVReadSet = append(colset.KVReadSet, &KVRead{Key: hex.EncodeToString([]byte(read.Key)), VerBlockNum: read.VerBlockNum, VerTxNum: read.VerTxNum})
+				}
+				for _, write := range col.KVWriteSet {
+					colset.KVWriteSet = append(colset.KVWriteSet, &KVWrite{Key: hex.EncodeToString([]byte(write.Key)), IsDelete: write.IsDelete})
+				}
+				nsrwsets = append(nsrwsets, colset)
+			}
+		}
+	}
+	return nsrwsets
+}
+
+// write to update the state by the valid transaction, and return the keys written.
+func (state mvccState) write(tx *Transaction, blockNum uint64, txNum uint64) []string {
+	keys := []string{}
+	for _, nsrwset := range txNSRWSets(tx) {
+		for _, write := range nsrwset.KVWriteSet {
+			k := nsrwset.NameSpace + "\x00" + write.Key
+			state[k] = &mvccWriter{txID: tx.TxID, blockNum: blockNum, txNum: txNum, isDelete: write.IsDelete}
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// conflictKeys to find the keys read by the transaction but changed by earlier valid transactions.
+func (state mvccState) conflictKeys(tx *Transaction) []*MVCCConflictKey {
+	keys := []*MVCCConflictKey{}
+	found := map[string]bool{}
+	add := func(ns string, key string, readVersion string, writer *mvccWriter) {
+		if found[ns+"\x00"+key] {
+			return
+		}
+		found[ns+"\x00"+key] = true
+		keys = append(keys, &MVCCConflictKey{NameSpace: ns, Key: key, ReadVersion: readVersion,
+			WriterTxID: writer.txID, WriterBlock: writer.blockNum, WriterTxNum: writer.txNum})
+	}
+
+	for _, nsrwset := range txNSRWSets(tx) {
+		for _, read := range nsrwset.KVReadSet {
+			if writer, ok := state[nsrwset.NameSpace+"\x00"+read.Key]; ok && writer.conflicts(read) {
+				add(nsrwset.NameSpace, read.Key, fmt.Sprintf("%d:%d", read.VerBlockNum, read.VerTxNum), writer)
+			}
+		}
+
+		// Phantom reads, the results of the range query changed.
+		for _, rq := range nsrwset.RangeQueries {
+			reads := map[string]*KVRead{}
+			for _, read := range rq.KVReadSet {
+				reads[read.Key] = read
+			}
+			// The iteration stops at the last read key if it is not exhausted.
+			endKey, inclusive := rq.EndKey, false
+			if !rq.ItrExhausted && len(rq.KVReadSet) > 0 {
+				endKey, inclusive = rq.KVReadSet[len(rq.KVReadSet)-1].Key, true
+			}
+			for k, writer := range state {
+				ns, key := splitNSKey(k)
+				if ns != nsrwset.NameSpace || key < rq.StartKey ||
+					(endKey != "" && (key > endKey || (key == endKey && !inclusive))) {
+					continue
+				}
+				if read, ok := reads[key]; ok {
+					if writer.conflicts(read) {
+						add(ns, key, fmt.Sprintf("%d:%d", read.VerBlockNum, read.VerTxNum), writer)
+					}
+				} else if !writer.isDelete && !rq.MerkleHashed {
+					add(ns, key, "", writer)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].NameSpace+"\x00"+keys[i].Key < keys[j].NameSpace+"\x00"+keys[j].Key
+	})
+	return keys
+}
+
+// conflicts if the version read is not the one written by the writer.
+func (writer *mvccWriter) conflicts(read *KVRead) bool {
+	if writer.isDelete {
+		// A deleted key has no version, it is read as 0:0.
+		return read.VerBlockNum != 0 || read.VerTxNum != 0
+	}
+	return read.VerBlockNum != writer.blockNum || read.VerTxNum != writer.txNum
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"testing"
+)
+
+func testMVCCTx(txID string, code string, reads []*KVRead, writes []*KVWrite, rqs []*RangeQuery) *Transaction {
+	return &Transaction{TxID: txID, ValidationCode: code, Actions: []*Action{{
+		ChaincodeName: "vehiclesharing",
+		ProposalResponse: &ProposalResponse{TXReadWriteSet: &TXReadWriteSet{NSReadWriteSets: []*NSReadWriteSet{{
+			NameSpace: "vehiclesharing", KVReadSet: reads, KVWriteSet: writes, RangeQueries: rqs,
+		}}}},
+	}}}
+}
+
+func TestAnalyzeBlocksMVCC(t *testing.T) {
+	blocks := []*Block{
+		{Number: 10, Transactions: []*Transaction{
+			testMVCCTx("tx1", "VALID", nil, []*KVWrite{{Key: "v1"}, {Key: "v2"}}, nil),
+		}},
+		{Number: 11, Transactions: []*Transaction{
+			testMVCCTx("tx2", "VALID", []*KVRead{{Key: "v1", VerBlockNum: 10}}, []*KVWrite{{Key: "v1"}}, nil),
+			// Read v1 of 10:0, but it is written by tx2 at 11:0.
+			testMVCCTx("tx3", "MVCC_READ_CONFLICT", []*KVRead{{Key: "v1", VerBlockNum: 10}, {Key: "v2", VerBlockNum: 10}}, []*KVWrite{{Key: "v1"}}, nil),
+			testMVCCTx("tx4", "MVCC_READ_CONFLICT", []*KVRead{{Key: "v1", VerBlockNum: 10}}, []*KVWrite{{Key: "v1"}}, nil),
+			testMVCCTx("tx5", "VALID", nil, []*KVWrite{{Key: "v3"}}, nil),
+			// v3 is created after the range query.
+			testMVCCTx("tx6", "PHANTOM_READ_CONFLICT", nil, nil, []*RangeQuery{{StartKey: "v", EndKey: "w", ItrExhausted: true,
+				KVReadSet: []*KVRead{{Key: "v1", VerBlockNum: 11}, {Key: "v2", VerBlockNum: 10}}}}),
+			// Invalid transactions don't change the state.
+			testMVCCTx("tx7", "VALID", []*KVRead{{Key: "v1", VerBlockNum: 11}}, nil, nil),
+		}},
+	}
+
+	analysis := AnalyzeBlocksMVCC(blocks)
+	if analysis.Transactions != 7 || analysis.ValidationCodes["MVCC_READ_CONFLICT"] != 2 || len(analysis.Conflicts) != 3 {
+		t.Fatalf("wrong analysis %+v", analysis)
+	}
+
+	tx3 := analysis.Conflicts[0]
+	if tx3.TxID != "tx3" || tx3.TxNum != 1 || len(tx3.Keys) != 1 || tx3.Keys[0].Key != "v1" ||
+		tx3.Keys[0].WriterTxID != "tx2" || tx3.Keys[0].ReadVersion != "10:0" {
+		t.Errorf("wrong conflict %+v %+v", tx3, tx3.Keys)
+	}
+	tx6 := analysis.Conflicts[2]
+	if tx6.ValidationCode != "PHANTOM_READ_CONFLICT" || len(tx6.Keys) != 1 || tx6.Keys[0].Key != "v3" || tx6.Keys[0].WriterTxID != "tx5" {
+		t.Errorf("wrong phantom conflict %+v", tx6.Keys)
+	}
+
+	if len(analysis.HotKeys) != 2 || analysis.HotKeys[0].Key != "v1" || analysis.HotKeys[0].Conflicts != 2 || analysis.HotKeys[0].Writes != 2 {
+		t.Errorf("wrong hot keys %+v", analysis.HotKeys)
+	}
+}
@@ -34,7 +34,14 @@ func NewConnection(connProfile *ConnectionProfile, participant *Participant, use
 	if len(connProfile.Config) < 1 {
 		return nil, errors.New("the connection profile is empty")
 	}
-	sdk, err := fabsdk.New(config.FromRaw(connProfile.Config, connProfile.ConfigType))
+	if connProfile.ConfigType == "" {
+		connProfile.ConfigType = DetectProfileType(connProfile.Config)
+	}
+	sdkOptions, err := participant.sdkOptions()
+	if err != nil {
+		return nil, err
+	}
+	sdk, err := fabsdk.New(config.FromRaw(connProfile.Config, connProfile.ConfigType), sdkOptions...)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +95,11 @@ func CalConnIdentifier(connProfile *ConnectionProfile, participant *Participant,
 	hash.Write([]byte(connProfile.ConfigType))
 	hash.Write(participant.Cert)
 	hash.Write(participant.PrivateKey)
+	hash.Write([]byte(participant.Passphrase))
+	if participant.PKCS11 != nil {
+		// With the PIN, a connection of the token is not reused by a wrong PIN.
+		hash.Write([]byte(participant.PKCS11.cacheKey()))
+	}
 	hash.Write([]byte(participant.MSPID))
 	dis := []byte{1}
 	if !useDiscovery {
@@ -108,6 +120,7 @@ func (conn *NetworkConnection) initBaseNetwork() {
 	conn.ChannelChaincodes = make(map[string][]*Chaincode)
 	conn.ChannelOrderers = make(map[string][]*Orderer)
 	conn.ChannelAnchorPeers = make(map[string][]string)
+	conn.ChannelChaincodeMetadata = make(map[string]map[string]*ContractMetadata)
 
 	// Add all peers
 	for peerName, peerCfg := range conn.ConfiguredPeers() {
@@ -524,16 +537,14 @@ func (conn *NetworkConnection) Show() string {
 
 // UpdateWithDiscovery update the network connection based on the discovery result, if useDiscovery option is true.
 func (conn *NetworkConnection) updateConnection() error {
-	occr := &OrdererConfigCognRes{OrdererConfigs: make(map[string]*fab.OrdererConfig)}
-	cccr := &ChannelConfigCongnRes{ChannelConfigs: make(map[string]*fab.ChannelEndpointConfig)}
-	cpcr := &ChannelPeerCongnRes{ChannelPeersList: make(map[string][]fab.ChannelPeer)}
-
-	conn.updateOrdererConfig(occr)
-	conn.updateChannelConfig(cccr)
-	conn.updateChannelPeers(cpcr)
+	endpointConfigs := conn.newEndpointConfigs()
 
+	sdkOptions, err := conn.Participant.sdkOptions()
+	if err != nil {
+		return err
+	}
 	sdk, err := fabsdk.New(config.FromRaw(conn.ConnectionProfile.Config, conn.ConnectionProfile.ConfigType),
-		fabsdk.WithEndpointConfig(cpcr, cccr, occr))
+		append(sdkOptions, fabsdk.WithEndpointConfig(endpointConfigs.ChannelPeers, endpointConfigs.ChannelConfigs, endpointConfigs.OrdererConfigs))...)
 
 	if err != nil {
 		return err
@@ -556,9 +567,23 @@ func (conn *NetworkConnection) updateConnection() error {
 	conn.SDK = sdk
 	conn.ClientProvider = ctxProvider
 	conn.Client = ctx
+	conn.EndpointConfigs = endpointConfigs
 	return nil
 }
 
+// newEndpointConfigs to generate endpoint configs of the network, with the discovered peers, orderers and channels.
+func (conn *NetworkConnection) newEndpointConfigs() *EndpointConfigs {
+	endpointConfigs := &EndpointConfigs{
+		OrdererConfigs: &OrdererConfigCognRes{OrdererConfigs: make(map[string]*fab.OrdererConfig)},
+		ChannelConfigs: &ChannelConfigCongnRes{ChannelConfigs: make(map[string]*fab.ChannelEndpointConfig)},
+		ChannelPeers:   &ChannelPeerCongnRes{ChannelPeersList: make(map[string][]fab.ChannelPeer)},
+	}
+	conn.updateOrdererConfig(endpointConfigs.OrdererConfigs)
+	conn.updateChannelConfig(endpointConfigs.ChannelConfigs)
+	conn.updateChannelPeers(endpointConfigs.ChannelPeers)
+	return endpointConfigs
+}
+
 func (conn *NetworkConnection) updateChannelLedgers() {
 	for channelID := range conn.Channels {
 		// Auto target
@@ -11,7 +11,7 @@ import (
 func TestNetwork(t *testing.T) {
 	conn, err := NewConnection(
 		&ConnectionProfile{connConfig, yamlConfigType},
-		&Participant{"TestAdmin", "", mspIDOrg1, testCert, testPrivKey, nil},
+		&Participant{Label: "TestAdmin", MSPID: mspIDOrg1, Cert: testCert, PrivateKey: testPrivKey},
 		true,
 	)
 	if err != nil {
@@ -25,7 +25,7 @@ func TestNetwork(t *testing.T) {
 func TestQueryInstalledChaincodes(t *testing.T) {
 	conn, err := NewConnection(
 		&ConnectionProfile{connConfig, yamlConfigType},
-		&Participant{"TestAdmin", "", mspIDOrg1, testCert, testPrivKey, nil},
+		&Participant{Label: "TestAdmin", MSPID: mspIDOrg1, Cert: testCert, PrivateKey: testPrivKey},
 		true,
 	)
 	if err != nil {
@@ -1,11 +1,14 @@
 package api
 
 import (
+	"bytes"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
@@ -18,7 +21,20 @@ type Participant struct {
 	MSPID      string
 	Cert       []byte
 	PrivateKey []byte
+	// Passphrase of PrivateKey if it is encrypted.
+	Passphrase string
 	SignID     msp.SigningIdentity
+	// PKCS11 the private key in a PKCS#11 token instead of PrivateKey.
+	PKCS11 *PKCS11Config
+}
+
+// IdentityError the identity of the participant is invalid, e.g. the MSPID is unknown, or the cert and key don't match.
+type IdentityError struct {
+	Err error
+}
+
+func (err *IdentityError) Error() string {
+	return err.Err.Error()
 }
 
 // Find the org name definied in the connection profile.
@@ -57,19 +73,59 @@ func CreateSigningIdentity(sdk *fabsdk.FabricSDK, participant *Participant) (msp
 	if participant.OrgName == "" {
 		participant.OrgName = findOrgNameByMSPID(participant.MSPID, orgs)
 		if participant.OrgName == "" {
-			return nil, errors.Errorf("MSPID %s not found in the connection profile.", participant.MSPID)
+			return nil, &IdentityError{Err: errors.Errorf("MSPID %s not found in the connection profile.", participant.MSPID)}
 		}
 	}
 
-	mgr, err := mspImpl.NewIdentityManager(participant.OrgName, userStore, cryptoSuite, endpointConfig)
+	// The key of the token is found by the SKI of the certificate, instead of imported.
+	options := []msp.SigningIdentityOption{msp.WithCert(participant.Cert)}
+	key, err := participant.hsmKey()
 	if err != nil {
 		return nil, err
 	}
+	if key != nil {
+		cryptoSuite = &hsmCryptoSuite{CryptoSuite: cryptoSuite, key: key}
+		pubKey, err := cryptoutil.GetPublicKeyFromCert(participant.Cert, cryptoSuite)
+		if err != nil {
+			return nil, &IdentityError{Err: err}
+		}
+		if !bytes.Equal(pubKey.SKI(), key.ski) {
+			return nil, &IdentityError{Err: errors.Errorf("the certificate doesn't match the key of %s", participant.PKCS11)}
+		}
+	} else {
+		privateKey, err := participant.normalizedKey()
+		if err != nil {
+			return nil, &IdentityError{Err: err}
+		}
+		options = append(options, msp.WithPrivateKey(privateKey))
+	}
 
-	id, err := mgr.CreateSigningIdentity(msp.WithCert(participant.Cert), msp.WithPrivateKey(participant.PrivateKey))
+	mgr, err := mspImpl.NewIdentityManager(participant.OrgName, userStore, cryptoSuite, endpointConfig)
 	if err != nil {
 		return nil, err
 	}
 
+	id, err := mgr.CreateSigningIdentity(options...)
+	if err != nil {
+		return nil, &IdentityError{Err: err}
+	}
+
 	return id, nil
 }
+
+// normalizedKey to get the private key as the unencrypted PKCS#8 which the SDK imports, and check it matches the certificate,
+// the errors are clearer than of the SDK.
+func (participant *Participant) normalizedKey() ([]byte, error) {
+	signer, err := parsePrivateKey(participant.PrivateKey, participant.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := TLSCertByBytes(participant.Cert)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing the certificate of the identity.")
+	}
+	if err := keyMatchesCert(signer, cert); err != nil {
+		return nil, err
+	}
+	return marshalFabricKey(signer)
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"encoding/hex"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
 )
 
@@ -20,7 +22,8 @@ func QueryInstalledChaincodes(conn *NetworkConnection, endpointURL string, optio
 			Name:      installedCC.GetName(),
 			Version:   installedCC.GetVersion(),
 			Path:      installedCC.GetPath(),
-			Installed: true})
+			Installed: true,
+			PackageID: hex.EncodeToString(installedCC.GetId())})
 	}
 	return ccs, nil
 }
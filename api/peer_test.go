@@ -9,7 +9,7 @@ import (
 func TestPeer(t *testing.T) {
 	conn, err := NewConnection(
 		&ConnectionProfile{connConfig, yamlConfigType},
-		&Participant{"TestAdmin", "", mspIDOrg1, testCert, testPrivKey, nil},
+		&Participant{Label: "TestAdmin", MSPID: mspIDOrg1, Cert: testCert, PrivateKey: testPrivKey},
 		true,
 	)
 	if err != nil {
@@ -0,0 +1,455 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PolicyNodeAnd all sub policies are required.
+	PolicyNodeAnd = "AND"
+	// PolicyNodeOr any sub policy is required.
+	PolicyNodeOr = "OR"
+	// PolicyNodeOutOf N of the sub policies are required.
+	PolicyNodeOutOf = "OUTOF"
+	// PolicyNodePrincipal signed by an identity of the MSP with the role.
+	PolicyNodePrincipal = "PRINCIPAL"
+)
+
+// Roles of the principal, see msp.MSPRole.
+const (
+	PolicyRoleMember  = "member"
+	PolicyRoleAdmin   = "admin"
+	PolicyRoleClient  = "client"
+	PolicyRolePeer    = "peer"
+	PolicyRoleOrderer = "orderer"
+)
+
+var policyRoles = []string{PolicyRoleMember, PolicyRoleAdmin, PolicyRoleClient, PolicyRolePeer, PolicyRoleOrderer}
+
+// policyOperatorNames the canonical names of operators, which cauthdsl accepts besides the lower and upper case.
+var policyOperatorNames = map[string]string{PolicyNodeAnd: "And", PolicyNodeOr: "Or", PolicyNodeOutOf: "OutOf"}
+
+// policyMSPID MSP IDs which cauthdsl accepts.
+var policyMSPID = regexp.MustCompile(`^[[:alnum:].-]+$`)
+
+// PolicySyntaxError syntax error of the policy expression, the position starts from 1.
+type PolicySyntaxError struct {
+	Position int    `json:"position"`
+	Message  string `json:"message"`
+}
+
+func (pse *PolicySyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", pse.Position, pse.Message)
+}
+
+// PolicyNode a node of the policy tree.
+type PolicyNode struct {
+	Type     string        `json:"type"`
+	N        int           `json:"n,omitempty"`
+	MSPID    string        `json:"MSPID,omitempty"`
+	Role     string        `json:"role,omitempty"`
+	Children []*PolicyNode `json:"children,omitempty"`
+	Position int           `json:"position,omitempty"`
+}
+
+// PolicyIdentity an identity which might sign for the policy, such as an endorsing peer.
+type PolicyIdentity struct {
+	Name  string `json:"name"`
+	MSPID string `json:"MSPID"`
+	Role  string `json:"role"`
+}
+
+// PolicyEvaluation result of evaluating a policy with a set of identities.
+type PolicyEvaluation struct {
+	Satisfied bool     `json:"satisfied"`
+	Used      []string `json:"used"`   // Names of the identities used to satisfy the policy.
+	Unused    []string `json:"unused"` // Names of the identities not required.
+}
+
+// ParsePolicy to parse the expression such as "AND('Org1MSP.peer', OutOf(1, 'Org2MSP.member', 'Org3MSP.admin'))".
+func ParsePolicy(policy string) (*PolicyNode, error) {
+	parser := &policyParser{input: []rune(policy)}
+	node, err := parser.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	parser.skipSpaces()
+	if parser.pos < len(parser.input) {
+		return nil, parser.errorf("unexpected %q after the end of the policy", string(parser.input[parser.pos]))
+	}
+	return node, nil
+}
+
+type policyParser struct {
+	input []rune
+	pos   int
+}
+
+func (parser *policyParser) errorf(format string, args ...interface{}) *PolicySyntaxError {
+	return &PolicySyntaxError{Position: parser.pos + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+func (parser *policyParser) skipSpaces() {
+	for parser.pos < len(parser.input) && unicode.IsSpace(parser.input[parser.pos]) {
+		parser.pos++
+	}
+}
+
+func (parser *policyParser) expect(r rune) error {
+	parser.skipSpaces()
+	if parser.pos >= len(parser.input) {
+		return parser.errorf("expected %q but reached the end", string(r))
+	}
+	if parser.input[parser.pos] != r {
+		return parser.errorf("expected %q but got %q", string(r), string(parser.input[parser.pos]))
+	}
+	parser.pos++
+	return nil
+}
+
+func (parser *policyParser) parseExpr() (*PolicyNode, error) {
+	parser.skipSpaces()
+	if parser.pos >= len(parser.input) {
+		return nil, parser.errorf("expected a policy but reached the end")
+	}
+
+	start := parser.pos
+	r := parser.input[parser.pos]
+	if r == '\'' || r == '"' {
+		return parser.parsePrincipal()
+	}
+	if !unicode.IsLetter(r) {
+		return nil, parser.errorf("unexpected %q, expected AND, OR, OutOf or a quoted principal", string(r))
+	}
+
+	for parser.pos < len(parser.input) && unicode.IsLetter(parser.input[parser.pos]) {
+		parser.pos++
+	}
+	name := string(parser.input[start:parser.pos])
+	node := &PolicyNode{Position: start + 1}
+	// The same as cauthdsl, operators are of the canonical, lower or upper case only.
+	switch upper := strings.ToUpper(name); {
+	case name != upper && name != strings.ToLower(name) && name != policyOperatorNames[upper]:
+		parser.pos = start
+		return nil, parser.errorf("unknown operator %q, expected AND, OR or OutOf", name)
+	case upper == PolicyNodeAnd:
+		node.Type = PolicyNodeAnd
+	case upper == PolicyNodeOr:
+		node.Type = PolicyNodeOr
+	case upper == PolicyNodeOutOf:
+		node.Type = PolicyNodeOutOf
+	default:
+		parser.pos = start
+		return nil, parser.errorf("unknown operator %q, expected AND, OR or OutOf", name)
+	}
+
+	if err := parser.expect('('); err != nil {
+		return nil, err
+	}
+
+	if node.Type == PolicyNodeOutOf {
+		n, err := parser.parseInt()
+		if err != nil {
+			return nil, err
+		}
+		node.N = n
+		if err := parser.expect(','); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		child, err := parser.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+
+		parser.skipSpaces()
+		if parser.pos < len(parser.input) && parser.input[parser.pos] == ',' {
+			parser.pos++
+			continue
+		}
+		if err := parser.expect(')'); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	switch node.Type {
+	case PolicyNodeAnd:
+		node.N = len(node.Children)
+	case PolicyNodeOr:
+		node.N = 1
+	case PolicyNodeOutOf:
+		if node.N > len(node.Children) {
+			return nil, &PolicySyntaxError{Position: node.Position,
+				Message: fmt.Sprintf("OutOf requires %d but there are only %d sub policies", node.N, len(node.Children))}
+		}
+	}
+	return node, nil
+}
+
+func (parser *policyParser) parseInt() (int, error) {
+	parser.skipSpaces()
+	start := parser.pos
+	for parser.pos < len(parser.input) && unicode.IsDigit(parser.input[parser.pos]) {
+		parser.pos++
+	}
+	if start == parser.pos {
+		return 0, parser.errorf("expected the number of OutOf")
+	}
+	n, err := strconv.Atoi(string(parser.input[start:parser.pos]))
+	if err != nil || n < 1 {
+		parser.pos = start
+		return 0, parser.errorf("the number of OutOf should be a positive integer")
+	}
+	return n, nil
+}
+
+func (parser *policyParser) parsePrincipal() (*PolicyNode, error) {
+	quote := parser.input[parser.pos]
+	start := parser.pos
+	parser.pos++
+	for parser.pos < len(parser.input) && parser.input[parser.pos] != quote {
+		parser.pos++
+	}
+	if parser.pos >= len(parser.input) {
+		parser.pos = start
+		return nil, parser.errorf("the principal is not closed by %s", string(quote))
+	}
+	content := string(parser.input[start+1 : parser.pos])
+	parser.pos++
+
+	idx := strings.LastIndex(content, ".")
+	if idx <= 0 || idx == len(content)-1 {
+		return nil, &PolicySyntaxError{Position: start + 1, Message: fmt.Sprintf("principal %q should be <MSPID>.<role>", content)}
+	}
+	// The same as cauthdsl, the role is case sensitive.
+	mspID, role := content[:idx], content[idx+1:]
+	if !policyMSPID.MatchString(mspID) {
+		return nil, &PolicySyntaxError{Position: start + 2,
+			Message: fmt.Sprintf("MSP ID %q should be of letters, digits, '.' and '-' only", mspID)}
+	}
+	if !existRole(role) {
+		message := fmt.Sprintf("unknown role %q, expected one of %v", role, policyRoles)
+		if existRole(strings.ToLower(role)) {
+			message = fmt.Sprintf("role %q should be in lower case as %q", role, strings.ToLower(role))
+		}
+		return nil, &PolicySyntaxError{Position: start + idx + 3, Message: message}
+	}
+	return &PolicyNode{Type: PolicyNodePrincipal, MSPID: mspID, Role: role, Position: start + 1}, nil
+}
+
+func existRole(role string) bool {
+	for _, r := range policyRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// String to get the expression of the policy.
+func (node *PolicyNode) String() string {
+	if node.Type == PolicyNodePrincipal {
+		return fmt.Sprintf("'%s.%s'", node.MSPID, node.Role)
+	}
+	subs := []string{}
+	for _, child := range node.Children {
+		subs = append(subs, child.String())
+	}
+	if node.Type == PolicyNodeOutOf {
+		return fmt.Sprintf("OutOf(%d, %s)", node.N, strings.Join(subs, ", "))
+	}
+	return fmt.Sprintf("%s(%s)", node.Type, strings.Join(subs, ", "))
+}
+
+// Tree to render the policy as a text tree.
+func (node *PolicyNode) Tree() string {
+	buffer := &bytes.Buffer{}
+	node.writeTree(buffer, "", "")
+	return buffer.String()
+}
+
+func (node *PolicyNode) label() string {
+	switch node.Type {
+	case PolicyNodePrincipal:
+		return node.String()
+	case PolicyNodeOutOf:
+		return fmt.Sprintf("OutOf %d of %d", node.N, len(node.Children))
+	}
+	return node.Type
+}
+
+func (node *PolicyNode) writeTree(buffer *bytes.Buffer, prefix string, childPrefix string) {
+	buffer.WriteString(prefix + node.label() + "\n")
+	for idx, child := range node.Children {
+		if idx == len(node.Children)-1 {
+			child.writeTree(buffer, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			child.writeTree(buffer, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+// Principals to get all distinct principals.
+func (node *PolicyNode) Principals() []string {
+	principals := []string{}
+	var walk func(n *PolicyNode)
+	walk = func(n *PolicyNode) {
+		if n.Type == PolicyNodePrincipal {
+			p := n.String()
+			for _, exist := range principals {
+				if exist == p {
+					return
+				}
+			}
+			principals = append(principals, p)
+		}
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(node)
+	return principals
+}
+
+// Evaluate to find if the identities are enough to satisfy the policy.
+// The same as Fabric, each identity can only be used once, and the sub policies are evaluated in order.
+func (node *PolicyNode) Evaluate(identities []*PolicyIdentity) *PolicyEvaluation {
+	used := make([]bool, len(identities))
+	satisfied := node.evaluate(identities, used)
+
+	evaluation := &PolicyEvaluation{Satisfied: satisfied, Used: []string{}, Unused: []string{}}
+	for idx, identity := range identities {
+		if satisfied && used[idx] {
+			evaluation.Used = append(evaluation.Used, identity.Name)
+		} else {
+			evaluation.Unused = append(evaluation.Unused, identity.Name)
+		}
+	}
+	return evaluation
+}
+
+func (node *PolicyNode) evaluate(identities []*PolicyIdentity, used []bool) bool {
+	if node.Type == PolicyNodePrincipal {
+		for idx, identity := range identities {
+			if !used[idx] && node.matches(identity) {
+				used[idx] = true
+				return true
+			}
+		}
+		return false
+	}
+
+	verified := 0
+	for _, child := range node.Children {
+		tmpUsed := make([]bool, len(used))
+		copy(tmpUsed, used)
+		if child.evaluate(identities, tmpUsed) {
+			verified++
+			copy(used, tmpUsed)
+		}
+	}
+	return verified >= node.N
+}
+
+func (node *PolicyNode) matches(identity *PolicyIdentity) bool {
+	if identity.MSPID != node.MSPID {
+		return false
+	}
+	return node.Role == PolicyRoleMember || strings.ToLower(identity.Role) == node.Role
+}
+
+// PolicyFromEnvelope to build the policy tree from the signature policy, such as the one of ChaincodeData in lscc.
+func PolicyFromEnvelope(spe *common.SignaturePolicyEnvelope) (*PolicyNode, error) {
+	principals := make([]*PolicyNode, len(spe.GetIdentities()))
+	for idx, id := range spe.GetIdentities() {
+		if id.GetPrincipalClassification() != msp.MSPPrincipal_ROLE {
+			return nil, errors.Errorf("principal classification %s is not supported", id.GetPrincipalClassification().String())
+		}
+		role := &msp.MSPRole{}
+		if err := proto.Unmarshal(id.GetPrincipal(), role); err != nil {
+			return nil, errors.WithMessage(err, "Failed to unmarshal the MSP role.")
+		}
+		principals[idx] = &PolicyNode{Type: PolicyNodePrincipal, MSPID: role.GetMspIdentifier(), Role: strings.ToLower(role.GetRole().String())}
+	}
+	return policyFromSignaturePolicy(spe.GetRule(), principals)
+}
+
+func policyFromSignaturePolicy(sp *common.SignaturePolicy, principals []*PolicyNode) (*PolicyNode, error) {
+	if sp == nil {
+		return nil, errors.New("the signature policy is empty")
+	}
+	switch t := sp.GetType().(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if int(t.SignedBy) >= len(principals) || t.SignedBy < 0 {
+			return nil, errors.Errorf("identity index %d out of range", t.SignedBy)
+		}
+		principal := *principals[t.SignedBy]
+		return &principal, nil
+	case *common.SignaturePolicy_NOutOf_:
+		node := &PolicyNode{Type: PolicyNodeOutOf, N: int(t.NOutOf.GetN())}
+		for _, rule := range t.NOutOf.GetRules() {
+			child, err := policyFromSignaturePolicy(rule, principals)
+			if err != nil {
+				return nil, err
+			}
+			node.Children = append(node.Children, child)
+		}
+		// The same as cauthdsl, AND and OR are compiled to OutOf.
+		if len(node.Children) > 1 && node.N == len(node.Children) {
+			node.Type = PolicyNodeAnd
+		} else if len(node.Children) > 1 && node.N == 1 {
+			node.Type = PolicyNodeOr
+		}
+		return node, nil
+	}
+	return nil, errors.New("unknown signature policy type")
+}
+
+// QueryChaincodeData to query the chaincode data, including the endorsement policy, from lscc.
+func QueryChaincodeData(conn *NetworkConnection, channelID string, chaincodeID string) (*ChaincodeData, error) {
+	response, err := ExecuteChaincode(conn, channelID, LSCC, ChaincodeOperTypeQuery, nil, "getccdata", []string{channelID, chaincodeID})
+	if err != nil {
+		return nil, err
+	}
+	ccd := translateChaincodeData(response.Payload)
+	if ccd.Policy == nil {
+		return nil, errors.Errorf("no endorsement policy found for chaincode %s in channel %s", chaincodeID, channelID)
+	}
+	return ccd, nil
+}
+
+// PeerIdentities to get the identities of the peers by name or URL, to evaluate policies.
+func PeerIdentities(conn *NetworkConnection, peers []string) ([]*PolicyIdentity, error) {
+	identities := []*PolicyIdentity{}
+	for _, nameOrURL := range peers {
+		peer := conn.findPeer(nameOrURL)
+		if peer == nil {
+			return nil, errors.Errorf("peer %s is not found", nameOrURL)
+		}
+		identities = append(identities, &PolicyIdentity{Name: peer.Name, MSPID: peer.MSPID, Role: PolicyRolePeer})
+	}
+	return identities, nil
+}
+
+// OrgIdentities to get the identities of the organizations, each one is regarded as one endorsing peer.
+func OrgIdentities(mspIDs []string) []*PolicyIdentity {
+	identities := []*PolicyIdentity{}
+	for _, mspID := range mspIDs {
+		identities = append(identities, &PolicyIdentity{Name: mspID, MSPID: mspID, Role: PolicyRolePeer})
+	}
+	return identities
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+)
+
+func TestParsePolicy(t *testing.T) {
+	policies := map[string]string{
+		"OR ('Org1MSP.peer','Org2MSP.peer')":                                 "OR('Org1MSP.peer', 'Org2MSP.peer')",
+		`and("Org1MSP.member", OutOf(1, 'Org2MSP.admin', 'Org3MSP.client'))`: "AND('Org1MSP.member', OutOf(1, 'Org2MSP.admin', 'Org3MSP.client'))",
+		"outof(1, 'Org1MSP.peer')":                                           "OutOf(1, 'Org1MSP.peer')",
+	}
+	for policy, expected := range policies {
+		node, err := ParsePolicy(policy)
+		if err != nil {
+			t.Fatalf("%s: %s", policy, err.Error())
+		}
+		if node.String() != expected {
+			t.Errorf("expected %s but got %s", expected, node.String())
+		}
+	}
+
+	node, _ := ParsePolicy("AND('Org1MSP.peer', OR('Org2MSP.peer', 'Org3MSP.peer'))")
+	t.Log("\n" + node.Tree())
+
+	invalids := map[string]int{
+		"":                                   1,
+		"AND('Org1MSP.peer'":                 19,
+		"AND('Org1MSP.peer',)":               20,
+		"NAND('Org1MSP.peer')":               1,
+		"OR('Org1MSP.peer' 'Org2MSP.peer')":  19,
+		"OR('Org1MSP.peers')":                13,
+		"OR('Org1MSP')":                      4,
+		"OutOf(3, 'Org1MSP.peer', 'A.peer')": 1,
+		"OutOf(x, 'Org1MSP.peer')":           7,
+		"OR('Org1MSP.peer')) ":               19,
+		"OR('Org1MSP.peer)":                  4,
+		"'Org1MSP.PEER'":                     10,
+		"OR('Org1MSP.Member')":               13,
+		"OR('Org1_MSP.peer')":                5,
+		"aNd('Org1MSP.peer')":                1,
+	}
+	for policy, position := range invalids {
+		_, err := ParsePolicy(policy)
+		syntaxErr, ok := err.(*PolicySyntaxError)
+		if !ok {
+			t.Errorf("%s should be a syntax error, but got %v", policy, err)
+			continue
+		}
+		if syntaxErr.Position != position {
+			t.Errorf("%s: expected error at %d, but got %s", policy, position, syntaxErr.Error())
+		}
+	}
+}
+
+// The pre-check should agree with the parser of the SDK which is used at the instantiation.
+func TestParsePolicyAsSDK(t *testing.T) {
+	policies := []string{
+		"OR('Org1MSP.peer', 'Org2MSP.peer')",
+		"and('Org1MSP.peer', 'Org2MSP.peer')",
+		"OUTOF(1, 'Org1MSP.peer', 'Org2MSP.peer')",
+		"OR('Org1MSP.Member', 'Org2MSP.peer')",
+		"OR('Org1MSP.PEER')",
+		"OR('Org1_MSP.peer')",
+		"Or('org1.example.com.peer')",
+	}
+	for _, policy := range policies {
+		_, err := ParsePolicy(policy)
+		_, sdkErr := cauthdsl.FromString(policy)
+		if (err == nil) != (sdkErr == nil) {
+			t.Errorf("%s: got %v, but the SDK got %v", policy, err, sdkErr)
+		}
+	}
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	node, err := ParsePolicy("AND('Org1MSP.peer', OutOf(2, 'Org2MSP.peer', 'Org2MSP.peer', 'Org3MSP.member'))")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		identities []*PolicyIdentity
+		satisfied  bool
+	}{
+		{OrgIdentities([]string{mspIDOrg1, mspIDOrg2}), false},
+		{OrgIdentities([]string{mspIDOrg1, mspIDOrg2, mspIDOrg2}), true},
+		{OrgIdentities([]string{mspIDOrg1, mspIDOrg2, "Org3MSP"}), true},
+		{OrgIdentities([]string{mspIDOrg2, mspIDOrg2, "Org3MSP"}), false},
+		{[]*PolicyIdentity{{Name: "p1", MSPID: mspIDOrg1, Role: "client"}, {Name: "p2", MSPID: mspIDOrg2, Role: "peer"},
+			{Name: "p3", MSPID: "Org3MSP", Role: "admin"}}, false},
+	}
+	for idx, c := range cases {
+		evaluation := node.Evaluate(c.identities)
+		if evaluation.Satisfied != c.satisfied {
+			t.Errorf("case %d: expected %v but got %v", idx, c.satisfied, evaluation.Satisfied)
+		}
+	}
+
+	evaluation := node.Evaluate(OrgIdentities([]string{mspIDOrg1, mspIDOrg2, "Org3MSP", "Org4MSP"}))
+	if len(evaluation.Used) != 3 || len(evaluation.Unused) != 1 || evaluation.Unused[0] != "Org4MSP" {
+		t.Errorf("wrong used identities %v, unused %v", evaluation.Used, evaluation.Unused)
+	}
+}
+
+func TestPolicyFromEnvelope(t *testing.T) {
+	policies := []string{
+		"OR('Org1MSP.peer','Org2MSP.peer')",
+		"AND('Org1MSP.member', OR('Org2MSP.admin', 'Org3MSP.client'))",
+		"OutOf(2, 'Org1MSP.peer', 'Org2MSP.peer', 'Org3MSP.peer')",
+	}
+	for _, policy := range policies {
+		spe, err := cauthdsl.FromString(policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		node, err := PolicyFromEnvelope(spe)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, _ := ParsePolicy(policy)
+		if node.String() != parsed.String() {
+			t.Errorf("expected %s but got %s", parsed.String(), node.String())
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package api
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/ecdsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"hash"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// PEM types of private keys.
+const (
+	pemTypeEncryptedPrivateKey = "ENCRYPTED PRIVATE KEY"
+	pemTypePrivateKey          = "PRIVATE KEY"
+	pemTypeECParameters        = "EC PARAMETERS"
+)
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC     = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+// encryptedPrivateKeyInfo the encrypted PKCS#8 key of RFC 5208.
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// pbes2Params of RFC 8018.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// maxPBKDF2Iterations the limit of the iteration count, so that a crafted key doesn't make the derivation endless.
+const maxPBKDF2Iterations = 2000000
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// NormalizePrivateKey to convert the private key of PKCS#8, PKCS#1 or SEC1, in PEM or DER, which can be encrypted with the passphrase,
+// to the unencrypted PKCS#8 PEM which the SDK imports. Fabric identities are of ECDSA keys.
+func NormalizePrivateKey(keyBytes []byte, passphrase string) ([]byte, error) {
+	signer, err := parsePrivateKey(keyBytes, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return marshalFabricKey(signer)
+}
+
+func marshalFabricKey(signer crypto.Signer) ([]byte, error) {
+	if _, ok := signer.(*ecdsa.PrivateKey); !ok {
+		return nil, errors.Errorf("the private key of %T is not supported by Fabric identities, it should be ECDSA", signer.Public())
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when marshaling the private key.")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: der}), nil
+}
+
+// parsePrivateKey to parse the private key of PKCS#8, PKCS#1 or SEC1, in PEM or DER, and decrypt it with the passphrase if it is encrypted.
+func parsePrivateKey(keyBytes []byte, passphrase string) (crypto.Signer, error) {
+	block := privateKeyBlock(keyBytes)
+	if block == nil {
+		if len(bytes.TrimSpace(keyBytes)) == 0 {
+			return nil, errors.New("the private key is empty")
+		}
+		// Not PEM, it might be DER.
+		block = &pem.Block{Bytes: keyBytes}
+	}
+
+	der := block.Bytes
+	var err error
+	switch {
+	case block.Type == pemTypeEncryptedPrivateKey:
+		if passphrase == "" {
+			return nil, errors.New("the private key is encrypted, the passphrase is required")
+		}
+		if der, err = decryptPKCS8(der, []byte(passphrase)); err != nil {
+			return nil, err
+		}
+	case x509.IsEncryptedPEMBlock(block):
+		// The legacy encryption of OpenSSL, with Proc-Type and DEK-Info headers.
+		if passphrase == "" {
+			return nil, errors.New("the private key is encrypted, the passphrase is required")
+		}
+		if der, err = x509.DecryptPEMBlock(block, []byte(passphrase)); err != nil {
+			return nil, errors.New("the passphrase of the private key is incorrect")
+		}
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, errors.New("the private key is not for signing")
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if block.Type == pemTypeEncryptedPrivateKey || block.Headers["Proc-Type"] != "" {
+		// The padding might be correct by chance with a wrong passphrase.
+		return nil, errors.New("the passphrase of the private key is incorrect")
+	}
+	return nil, errors.New("the private key is not of PKCS#8, PKCS#1 or SEC1 (EC PRIVATE KEY)")
+}
+
+// privateKeyBlock to get the PEM block of the key, EC PARAMETERS written by "openssl ecparam -genkey" is skipped.
+func privateKeyBlock(keyBytes []byte) *pem.Block {
+	for {
+		block, rest := pem.Decode(keyBytes)
+		if block == nil || block.Type != pemTypeECParameters {
+			return block
+		}
+		keyBytes = rest
+	}
+}
+
+// decryptPKCS8 to decrypt the PKCS#8 key encrypted by PBES2 with PBKDF2, e.g. by "openssl pkcs8 -topk8".
+func decryptPKCS8(der []byte, passphrase []byte) ([]byte, error) {
+	info := encryptedPrivateKeyInfo{}
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing the encrypted private key.")
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, errors.Errorf("the encryption %s of the private key is not supported, it should be PBES2", info.Algorithm.Algorithm)
+	}
+	params := pbes2Params{}
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing PBES2 parameters of the private key.")
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, errors.Errorf("the key derivation %s of the private key is not supported, it should be PBKDF2", params.KeyDerivationFunc.Algorithm)
+	}
+	kdfParams := pbkdf2Params{}
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing PBKDF2 parameters of the private key.")
+	}
+
+	var prf func() hash.Hash
+	switch algorithm := kdfParams.PRF.Algorithm; {
+	case len(algorithm) == 0 || algorithm.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	case algorithm.Equal(oidHMACWithSHA512):
+		prf = sha512.New
+	default:
+		return nil, errors.Errorf("the PRF %s of the private key is not supported", algorithm)
+	}
+	var newCipher func([]byte) (cipher.Block, error)
+	keyLen := 0
+	switch algorithm := params.EncryptionScheme.Algorithm; {
+	case algorithm.Equal(oidAES128CBC):
+		newCipher, keyLen = aes.NewCipher, 16
+	case algorithm.Equal(oidAES192CBC):
+		newCipher, keyLen = aes.NewCipher, 24
+	case algorithm.Equal(oidAES256CBC):
+		newCipher, keyLen = aes.NewCipher, 32
+	case algorithm.Equal(oidDESEDE3CBC):
+		newCipher, keyLen = des.NewTripleDESCipher, 24
+	default:
+		return nil, errors.Errorf("the cipher %s of the private key is not supported", algorithm)
+	}
+	iv := []byte{}
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing the IV of the private key.")
+	}
+
+	if kdfParams.IterationCount <= 0 || kdfParams.IterationCount > maxPBKDF2Iterations {
+		return nil, errors.Errorf("the iteration count %d of the private key should be between 1 and %d", kdfParams.IterationCount, maxPBKDF2Iterations)
+	}
+	if kdfParams.KeyLength != 0 && kdfParams.KeyLength != keyLen {
+		return nil, errors.Errorf("the key length %d of the private key doesn't match the cipher, which is %d", kdfParams.KeyLength, keyLen)
+	}
+
+	block, err := newCipher(pbkdf2.Key(passphrase, kdfParams.Salt, kdfParams.IterationCount, keyLen, prf))
+	if err != nil {
+		return nil, err
+	}
+	data := info.EncryptedData
+	if len(iv) != block.BlockSize() || len(data) == 0 || len(data)%block.BlockSize() != 0 {
+		return nil, errors.New("the encrypted private key is malformed")
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data)
+
+	// PKCS#7 padding, a wrong passphrase results in a wrong padding mostly.
+	padding := int(plain[len(plain)-1])
+	if padding == 0 || padding > block.BlockSize() || !bytes.Equal(plain[len(plain)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, errors.New("the passphrase of the private key is incorrect")
+	}
+	return plain[:len(plain)-padding], nil
+}
+
+// keyMatchesCert to check the public key of the signer is of the certificate.
+func keyMatchesCert(signer crypto.Signer, cert *x509.Certificate) error {
+	keyPub, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return err
+	}
+	certPub, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(keyPub, certPub) {
+		return errors.New("the private key doesn't match the certificate")
+	}
+	return nil
+}
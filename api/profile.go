@@ -0,0 +1,546 @@
+package api
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Severities of profile findings, the profile is invalid if there is an error.
+const (
+	ProfileSeverityError   = "error"
+	ProfileSeverityWarning = "warning"
+)
+
+// Rules of profile findings.
+const (
+	ProfileRuleSyntax    = "syntax"
+	ProfileRuleSchema    = "schema"
+	ProfileRuleReference = "reference"
+	ProfileRuleTLS       = "tls"
+	ProfileRuleHostname  = "hostname"
+	ProfileRuleDuplicate = "duplicate"
+	ProfileRuleMSPID     = "mspid"
+)
+
+// ProfileCertExpiringDays a TLS CA certificate expiring in the days is warned.
+const ProfileCertExpiringDays = 30
+
+// ProfileFinding a problem of the connection profile. Line is 1-based, 0 if the line is unknown.
+type ProfileFinding struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	Message  string `json:"message"`
+}
+
+// ProfileValidation findings of a profile ordered by line, the profile is valid if none of them is an error.
+type ProfileValidation struct {
+	Valid    bool              `json:"valid"`
+	Findings []*ProfileFinding `json:"findings"`
+}
+
+// Known sections of a connection profile, see the fabric-sdk-go config.
+var profileSections = []string{"name", "version", "description", "x-type", "client", "channels", "organizations",
+	"orderers", "peers", "certificateAuthorities", "entityMatchers", "operations", "metrics"}
+
+var yamlErrLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// ValidateProfile to check the connection profile statically, without connecting to the network.
+// The profile is either yaml or json, MSPID of the identity is checked if it is not empty.
+func ValidateProfile(config []byte, MSPID string) *ProfileValidation {
+	return validateProfile(config, MSPID, time.Now())
+}
+
+func validateProfile(config []byte, MSPID string, now time.Time) *ProfileValidation {
+	v := &profileValidator{now: now}
+
+	raw := map[interface{}]interface{}{}
+	if err := yaml.Unmarshal(config, &raw); err != nil {
+		line := 0
+		if m := yamlErrLinePattern.FindStringSubmatch(err.Error()); m != nil {
+			line, _ = strconv.Atoi(m[1])
+		}
+		v.findings = append(v.findings, &ProfileFinding{Severity: ProfileSeverityError, Rule: ProfileRuleSyntax,
+			Line: line, Message: err.Error()})
+		return v.result()
+	}
+	profile, _ := normalizeYAML(raw).(map[string]interface{})
+	if len(profile) == 0 {
+		v.errorf(ProfileRuleSchema, nil, "the profile is empty")
+		return v.result()
+	}
+	v.profile = profile
+	root := &yamlv3.Node{}
+	if err := yamlv3.Unmarshal(config, root); err == nil {
+		v.root = root
+	}
+
+	v.checkSchema()
+	v.checkReferences()
+	v.checkEndpoints()
+	v.checkMSPID(MSPID)
+	return v.result()
+}
+
+// normalizeYAML to convert maps of yaml to map[string]interface{}, as json.
+func normalizeYAML(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, v := range value {
+			m[fmt.Sprint(k)] = normalizeYAML(v)
+		}
+		return m
+	case []interface{}:
+		for idx, item := range value {
+			value[idx] = normalizeYAML(item)
+		}
+	}
+	return value
+}
+
+type profileValidator struct {
+	root     *yamlv3.Node
+	profile  map[string]interface{}
+	now      time.Time
+	findings []*ProfileFinding
+}
+
+func (v *profileValidator) add(severity string, rule string, path []string, format string, args ...interface{}) {
+	v.findings = append(v.findings, &ProfileFinding{
+		Severity: severity,
+		Rule:     rule,
+		Path:     joinProfilePath(path),
+		Line:     v.line(path),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *profileValidator) errorf(rule string, path []string, format string, args ...interface{}) {
+	v.add(ProfileSeverityError, rule, path, format, args...)
+}
+
+func (v *profileValidator) warnf(rule string, path []string, format string, args ...interface{}) {
+	v.add(ProfileSeverityWarning, rule, path, format, args...)
+}
+
+func (v *profileValidator) result() *ProfileValidation {
+	sort.SliceStable(v.findings, func(i, j int) bool { return v.findings[i].Line < v.findings[j].Line })
+	valid := true
+	for _, finding := range v.findings {
+		if finding.Severity == ProfileSeverityError {
+			valid = false
+		}
+	}
+	return &ProfileValidation{Valid: valid, Findings: v.findings}
+}
+
+// joinProfilePath to join the path as fields of request validation, e.g. organizations.Org1.peers[0].
+func joinProfilePath(path []string) string {
+	joined := ""
+	for _, p := range path {
+		if strings.HasPrefix(p, "[") || joined == "" {
+			joined += p
+		} else {
+			joined += "." + p
+		}
+	}
+	return joined
+}
+
+// line to locate the path in the profile by the yaml nodes, which record lines of keys and items, json is yaml as well.
+// The line of the deepest found parent is returned if the path is not found.
+func (v *profileValidator) line(path []string) int {
+	if v.root == nil {
+		return 0
+	}
+	line, node := 0, v.root
+	if node.Kind == yamlv3.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, p := range path {
+		var keyNode, valueNode *yamlv3.Node
+		if strings.HasPrefix(p, "[") {
+			keyNode, valueNode = profileItemNode(node, p)
+		} else {
+			keyNode, valueNode = profileKeyNode(node, p)
+		}
+		if keyNode == nil {
+			break
+		}
+		line, node = keyNode.Line, valueNode
+	}
+	return line
+}
+
+// profileKeyNode returns the key and value nodes of the key in the mapping node, merged mappings of "<<" are searched as well.
+func profileKeyNode(node *yamlv3.Node, key string) (*yamlv3.Node, *yamlv3.Node) {
+	node = resolveYAMLAlias(node)
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return nil, nil
+	}
+	merged := []*yamlv3.Node{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+		if node.Content[i].Tag == "!!merge" {
+			merged = append(merged, node.Content[i+1])
+		}
+	}
+	for _, m := range merged {
+		m = resolveYAMLAlias(m)
+		if m.Kind == yamlv3.SequenceNode {
+			for _, item := range m.Content {
+				if keyNode, valueNode := profileKeyNode(item, key); keyNode != nil {
+					return keyNode, valueNode
+				}
+			}
+		} else if keyNode, valueNode := profileKeyNode(m, key); keyNode != nil {
+			return keyNode, valueNode
+		}
+	}
+	return nil, nil
+}
+
+// profileItemNode returns the item node of the sequence node by the index, e.g. [0].
+func profileItemNode(node *yamlv3.Node, index string) (*yamlv3.Node, *yamlv3.Node) {
+	node = resolveYAMLAlias(node)
+	idx, err := strconv.Atoi(strings.Trim(index, "[]"))
+	if node == nil || node.Kind != yamlv3.SequenceNode || err != nil || idx < 0 || idx >= len(node.Content) {
+		return nil, nil
+	}
+	return node.Content[idx], node.Content[idx]
+}
+
+func resolveYAMLAlias(node *yamlv3.Node) *yamlv3.Node {
+	// Avoid endless alias.
+	for depth := 0; node != nil && node.Kind == yamlv3.AliasNode && depth < 16; depth++ {
+		node = node.Alias
+	}
+	return node
+}
+
+// section to get a map section of the profile, an error is added if it is not a map and the path is given.
+func (v *profileValidator) section(value interface{}, path []string) map[string]interface{} {
+	if value == nil {
+		return map[string]interface{}{}
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok && path != nil {
+		v.errorf(ProfileRuleSchema, path, "%s should be a map", joinProfilePath(path))
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+func (v *profileValidator) stringList(value interface{}, path []string) []string {
+	if value == nil {
+		return nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		v.errorf(ProfileRuleSchema, path, "%s should be a list", joinProfilePath(path))
+		return nil
+	}
+	strs := []string{}
+	for _, item := range list {
+		strs = append(strs, fmt.Sprint(item))
+	}
+	return strs
+}
+
+// subPath to copy the path with the elements appended, so paths don't share the array.
+func subPath(path []string, elems ...string) []string {
+	return append(append([]string{}, path...), elems...)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (v *profileValidator) checkSchema() {
+	known := map[string]bool{}
+	for _, section := range profileSections {
+		known[section] = true
+	}
+	for _, key := range sortedKeys(v.profile) {
+		if !known[key] {
+			v.warnf(ProfileRuleSchema, []string{key}, "unknown section %s", key)
+		}
+	}
+
+	for _, key := range []string{"client", "channels", "orderers", "certificateAuthorities"} {
+		v.section(v.profile[key], []string{key})
+	}
+	if len(v.section(v.profile["organizations"], []string{"organizations"})) == 0 {
+		v.errorf(ProfileRuleSchema, []string{"organizations"}, "there is no organization")
+	}
+	if len(v.section(v.profile["peers"], []string{"peers"})) == 0 {
+		v.errorf(ProfileRuleSchema, []string{"peers"}, "there is no peer")
+	}
+	orgs := v.section(v.profile["organizations"], nil)
+	for _, name := range sortedKeys(orgs) {
+		path := []string{"organizations", name}
+		if mspid, _ := v.section(orgs[name], path)["mspid"].(string); mspid == "" {
+			v.errorf(ProfileRuleSchema, path, "mspid of organization %s is required", name)
+		}
+	}
+	for _, kind := range []string{"peers", "orderers", "certificateAuthorities"} {
+		endpoints := v.section(v.profile[kind], nil)
+		for _, name := range sortedKeys(endpoints) {
+			path := []string{kind, name}
+			rawURL, _ := v.section(endpoints[name], path)["url"].(string)
+			if rawURL == "" {
+				v.errorf(ProfileRuleSchema, path, "url of %s is required", name)
+			} else if _, _, err := splitEndpointURL(rawURL); err != nil {
+				v.errorf(ProfileRuleSchema, subPath(path, "url"), "url %s of %s is invalid: %s", rawURL, name, err.Error())
+			}
+		}
+	}
+	channels := v.section(v.profile["channels"], nil)
+	for _, channelID := range sortedKeys(channels) {
+		path := []string{"channels", channelID}
+		channelSection := v.section(channels[channelID], path)
+		v.section(channelSection["peers"], subPath(path, "peers"))
+		v.stringList(channelSection["orderers"], subPath(path, "orderers"))
+	}
+}
+
+func (v *profileValidator) checkReferences() {
+	peers := v.section(v.profile["peers"], nil)
+	orderers := v.section(v.profile["orderers"], nil)
+	cas := v.section(v.profile["certificateAuthorities"], nil)
+	orgs := v.section(v.profile["organizations"], nil)
+	// Names might be mapped to others by entity matchers, so unknown names are warned only.
+	severity := ProfileSeverityError
+	if _, ok := v.profile["entityMatchers"]; ok {
+		severity = ProfileSeverityWarning
+	}
+
+	if clientOrg, ok := v.section(v.profile["client"], nil)["organization"].(string); ok && clientOrg != "" {
+		if _, ok := orgs[clientOrg]; !ok {
+			v.errorf(ProfileRuleReference, []string{"client", "organization"}, "organization %s of the client is not defined", clientOrg)
+		}
+	}
+
+	orgPeers := map[string]bool{}
+	for _, orgName := range sortedKeys(orgs) {
+		org := v.section(orgs[orgName], nil)
+		for idx, peer := range v.stringList(org["peers"], []string{"organizations", orgName, "peers"}) {
+			orgPeers[peer] = true
+			if _, ok := peers[peer]; !ok {
+				v.add(severity, ProfileRuleReference, []string{"organizations", orgName, "peers", fmt.Sprintf("[%d]", idx)},
+					"peer %s of organization %s is not defined", peer, orgName)
+			}
+		}
+		for idx, ca := range v.stringList(org["certificateAuthorities"], []string{"organizations", orgName, "certificateAuthorities"}) {
+			if _, ok := cas[ca]; !ok {
+				v.add(severity, ProfileRuleReference, []string{"organizations", orgName, "certificateAuthorities", fmt.Sprintf("[%d]", idx)},
+					"certificate authority %s of organization %s is not defined", ca, orgName)
+			}
+		}
+	}
+	for _, peer := range sortedKeys(peers) {
+		if !orgPeers[peer] {
+			v.warnf(ProfileRuleReference, []string{"peers", peer}, "peer %s doesn't belong to any organization", peer)
+		}
+	}
+
+	channels := v.section(v.profile["channels"], nil)
+	for _, channelID := range sortedKeys(channels) {
+		channel, _ := channels[channelID].(map[string]interface{})
+		channelPeers, _ := channel["peers"].(map[string]interface{})
+		for _, peer := range sortedKeys(channelPeers) {
+			if _, ok := peers[peer]; !ok {
+				v.add(severity, ProfileRuleReference, []string{"channels", channelID, "peers", peer},
+					"peer %s of channel %s is not defined", peer, channelID)
+			}
+		}
+		channelOrderers, _ := channel["orderers"].([]interface{})
+		for idx, orderer := range channelOrderers {
+			if _, ok := orderers[fmt.Sprint(orderer)]; !ok {
+				v.add(severity, ProfileRuleReference, []string{"channels", channelID, "orderers", fmt.Sprintf("[%d]", idx)},
+					"orderer %v of channel %s is not defined", orderer, channelID)
+			}
+		}
+	}
+}
+
+// checkEndpoints to check duplicate URLs, TLS CA certificates and host name overrides of peers, orderers and CAs.
+func (v *profileValidator) checkEndpoints() {
+	urlPaths := map[string][]string{}
+	for _, kind := range []string{"peers", "orderers", "certificateAuthorities"} {
+		endpoints := v.section(v.profile[kind], nil)
+		for _, name := range sortedKeys(endpoints) {
+			path := []string{kind, name}
+			endpoint, _ := endpoints[name].(map[string]interface{})
+			rawURL, _ := endpoint["url"].(string)
+			scheme, host, err := splitEndpointURL(rawURL)
+			if err != nil {
+				continue
+			}
+			if former, ok := urlPaths[host]; ok {
+				v.errorf(ProfileRuleDuplicate, subPath(path, "url"), "url %s of %s is the same with %s", rawURL, name, joinProfilePath(former))
+			} else {
+				urlPaths[host] = path
+			}
+
+			certs := v.checkTLSCACerts(endpoint["tlsCACerts"], subPath(path, "tlsCACerts"), name)
+			if len(certs) == 0 && (scheme == "grpcs" || scheme == "https") {
+				v.warnf(ProfileRuleTLS, path, "%s uses TLS by %s, but has no TLS CA certificate", name, rawURL)
+			}
+
+			grpcOptions, _ := endpoint["grpcOptions"].(map[string]interface{})
+			override, _ := grpcOptions["ssl-target-name-override"].(string)
+			if override == "" {
+				continue
+			}
+			overridePath := subPath(path, "grpcOptions", "ssl-target-name-override")
+			for _, cert := range certs {
+				// A CA certificate without SAN doesn't name the server, e.g. a TLS CA by cryptogen.
+				if cert.IsCA && len(cert.DNSNames) == 0 && len(cert.IPAddresses) == 0 {
+					continue
+				}
+				if err := cert.VerifyHostname(override); err != nil {
+					v.errorf(ProfileRuleHostname, overridePath, "ssl-target-name-override %s of %s doesn't match the certificate %s: %s",
+						override, name, cert.Subject.CommonName, err.Error())
+				}
+			}
+		}
+	}
+}
+
+// checkTLSCACerts to parse the certificates in pem or path, and check if they are expired.
+func (v *profileValidator) checkTLSCACerts(value interface{}, path []string, name string) []*x509.Certificate {
+	tlsCACerts, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	pems := []string{}
+	switch p := tlsCACerts["pem"].(type) {
+	case string:
+		pems = append(pems, p)
+	case []interface{}:
+		for _, item := range p {
+			pems = append(pems, fmt.Sprint(item))
+		}
+	}
+	pemPath := subPath(path, "pem")
+	if certPath, _ := tlsCACerts["path"].(string); certPath != "" && !strings.Contains(certPath, "${") {
+		content, err := ioutil.ReadFile(certPath)
+		if err != nil {
+			v.errorf(ProfileRuleTLS, subPath(path, "path"), "TLS CA certificate of %s cannot be read: %s", name, err.Error())
+		} else {
+			pems = append(pems, string(content))
+			pemPath = subPath(path, "path")
+		}
+	}
+
+	certs := []*x509.Certificate{}
+	for _, p := range pems {
+		rest := []byte(p)
+		parsed := 0
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				v.errorf(ProfileRuleTLS, pemPath, "TLS CA certificate of %s cannot be parsed: %s", name, err.Error())
+				continue
+			}
+			parsed++
+			certs = append(certs, cert)
+			switch {
+			case v.now.After(cert.NotAfter):
+				v.errorf(ProfileRuleTLS, pemPath, "TLS CA certificate %s of %s expired at %s", cert.Subject.CommonName, name, cert.NotAfter.Format(time.RFC3339))
+			case v.now.Before(cert.NotBefore):
+				v.errorf(ProfileRuleTLS, pemPath, "TLS CA certificate %s of %s is not valid before %s", cert.Subject.CommonName, name, cert.NotBefore.Format(time.RFC3339))
+			case v.now.AddDate(0, 0, ProfileCertExpiringDays).After(cert.NotAfter):
+				v.warnf(ProfileRuleTLS, pemPath, "TLS CA certificate %s of %s expires at %s", cert.Subject.CommonName, name, cert.NotAfter.Format(time.RFC3339))
+			}
+		}
+		if parsed == 0 && strings.TrimSpace(p) != "" {
+			v.errorf(ProfileRuleTLS, pemPath, "TLS CA certificate of %s is not a PEM certificate", name)
+		}
+	}
+	return certs
+}
+
+// checkMSPID to check if the MSP ID of the identity is of an organization, and MSP IDs are unique.
+func (v *profileValidator) checkMSPID(MSPID string) {
+	orgs := v.section(v.profile["organizations"], nil)
+	mspOrgs := map[string]string{}
+	for _, orgName := range sortedKeys(orgs) {
+		org, _ := orgs[orgName].(map[string]interface{})
+		mspid, _ := org["mspid"].(string)
+		if mspid == "" {
+			continue
+		}
+		if former, ok := mspOrgs[mspid]; ok {
+			v.warnf(ProfileRuleMSPID, []string{"organizations", orgName, "mspid"}, "mspid %s of organization %s is the same with organization %s", mspid, orgName, former)
+			continue
+		}
+		mspOrgs[mspid] = orgName
+	}
+
+	if MSPID == "" {
+		return
+	}
+	if _, ok := mspOrgs[MSPID]; !ok {
+		v.errorf(ProfileRuleMSPID, []string{"organizations"}, "MSP ID %s of the identity is not of any organization", MSPID)
+		return
+	}
+	if clientOrg, _ := v.section(v.profile["client"], nil)["organization"].(string); clientOrg != "" && mspOrgs[MSPID] != clientOrg {
+		if _, ok := orgs[clientOrg]; ok {
+			v.warnf(ProfileRuleMSPID, []string{"client", "organization"}, "organization %s of the client is not of the MSP ID %s of the identity", clientOrg, MSPID)
+		}
+	}
+}
+
+// splitEndpointURL to get the scheme and host:port of an URL, either with a scheme or not.
+func splitEndpointURL(rawURL string) (string, string, error) {
+	scheme := ""
+	hostPort := rawURL
+	if strings.Contains(rawURL, "://") {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return "", "", err
+		}
+		scheme, hostPort = u.Scheme, u.Host
+	}
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return "", "", err
+	}
+	if host == "" {
+		return "", "", errors.New("host is empty")
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", "", errors.Errorf("port %s is invalid", port)
+	}
+	return scheme, strings.ToLower(hostPort), nil
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCertPEM(t *testing.T, cn string, dnsNames []string, notBefore time.Time, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     dnsNames,
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func indentPEM(p string, indent string) string {
+	return indent + strings.Replace(strings.TrimSpace(p), "\n", "\n"+indent, -1)
+}
+
+// lineOf the 1-based line of the nth line containing the text.
+func lineOf(content string, text string, nth int) int {
+	for idx, line := range strings.Split(content, "\n") {
+		if strings.Contains(line, text) {
+			if nth == 0 {
+				return idx + 1
+			}
+			nth--
+		}
+	}
+	return -1
+}
+
+func TestValidateProfile(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	peerCert := indentPEM(newTestCertPEM(t, "peer0", []string{"peer0.org1.example.com"}, now.AddDate(-1, 0, 0), now.AddDate(1, 0, 0)), "                ")
+	expiredCert := indentPEM(newTestCertPEM(t, "orderer", nil, now.AddDate(-2, 0, 0), now.AddDate(0, 0, -1)), "                ")
+
+	profile := fmt.Sprintf(`name: test
+client:
+    organization: Org3
+organizations:
+    Org1:
+        mspid: Org1MSP
+        peers:
+            - peer0.org1.example.com
+            - peer9.org1.example.com
+peers:
+    peer0.org1.example.com:
+        url: grpcs://peer0.org1.example.com:7051
+        grpcOptions:
+            ssl-target-name-override: peer0.org1.example.com
+        tlsCACerts:
+            pem: |
+%s
+    peer1.org1.example.com:
+        url: grpcs://PEER0.org1.example.com:7051
+        grpcOptions:
+            ssl-target-name-override: wrong.example.com
+        tlsCACerts:
+            pem: |
+%s
+orderers:
+    orderer.example.com:
+        url: localhost:7050
+        tlsCACerts:
+            pem: |
+%s
+channels:
+    mychannel:
+        orderers:
+            - orderer.example.com
+            - orderer2.example.com
+        peers:
+            peer0.org1.example.com: {}
+unknownSection: 1
+`, peerCert, peerCert, expiredCert)
+
+	validation := validateProfile([]byte(profile), "Org2MSP", now)
+	expected := []*ProfileFinding{
+		{ProfileSeverityError, ProfileRuleReference, "client.organization", lineOf(profile, "organization: Org3", 0), ""},
+		{ProfileSeverityError, ProfileRuleMSPID, "organizations", lineOf(profile, "organizations:", 0), ""},
+		{ProfileSeverityError, ProfileRuleReference, "organizations.Org1.peers[1]", lineOf(profile, "- peer9", 0), ""},
+		{ProfileSeverityWarning, ProfileRuleReference, "peers.peer1.org1.example.com", lineOf(profile, "peer1.org1.example.com:", 0), ""},
+		{ProfileSeverityError, ProfileRuleDuplicate, "peers.peer1.org1.example.com.url", lineOf(profile, "url: grpcs://PEER0", 0), ""},
+		{ProfileSeverityError, ProfileRuleHostname, "peers.peer1.org1.example.com.grpcOptions.ssl-target-name-override", lineOf(profile, "wrong.example.com", 0), ""},
+		{ProfileSeverityError, ProfileRuleTLS, "orderers.orderer.example.com.tlsCACerts.pem", lineOf(profile, "pem: |", 2), ""},
+		{ProfileSeverityError, ProfileRuleReference, "channels.mychannel.orderers[1]", lineOf(profile, "- orderer2", 0), ""},
+		{ProfileSeverityWarning, ProfileRuleSchema, "unknownSection", lineOf(profile, "unknownSection", 0), ""},
+	}
+	if validation.Valid || len(validation.Findings) != len(expected) {
+		for _, finding := range validation.Findings {
+			t.Logf("%+v", finding)
+		}
+		t.Fatalf("expected %d findings, but got %d", len(expected), len(validation.Findings))
+	}
+	for idx, finding := range validation.Findings {
+		e := expected[idx]
+		if finding.Severity != e.Severity || finding.Rule != e.Rule || finding.Path != e.Path || finding.Line != e.Line {
+			t.Errorf("expected %s %s %s at line %d, but got %+v", e.Severity, e.Rule, e.Path, e.Line, finding)
+		}
+	}
+
+	// Indented json.
+	jsonProfile := `{
+  "organizations": {
+    "Org1": {"mspid": "Org1MSP", "peers": ["peer0"]}
+  },
+  "peers": {
+    "peer0": {
+      "url": "peer0:7051"
+    },
+    "peer1": {
+      "url": "peer0:7051"
+    }
+  }
+}`
+	validation = validateProfile([]byte(jsonProfile), "Org1MSP", now)
+	if len(validation.Findings) != 2 || validation.Findings[0].Line != 9 || validation.Findings[1].Line != 10 ||
+		validation.Findings[1].Rule != ProfileRuleDuplicate {
+		for _, finding := range validation.Findings {
+			t.Errorf("unexpected finding %+v", finding)
+		}
+	}
+
+	// Flow maps of yaml, keys of the same line.
+	flowProfile := `organizations:
+  Org1: {mspid: Org1MSP, peers: [peer0]}
+peers: {peer0: {url: "peer0:7051"},
+  peer1: {url: "peer0:7051"}}
+`
+	validation = validateProfile([]byte(flowProfile), "Org1MSP", now)
+	if len(validation.Findings) != 2 || validation.Findings[0].Line != 4 || validation.Findings[1].Line != 4 ||
+		validation.Findings[1].Rule != ProfileRuleDuplicate {
+		for _, finding := range validation.Findings {
+			t.Errorf("unexpected finding %+v", finding)
+		}
+	}
+
+	validation = validateProfile([]byte("peers:\n  peer0:\n    url: a\n  - b\n"), "", now)
+	if validation.Valid || len(validation.Findings) != 1 || validation.Findings[0].Rule != ProfileRuleSyntax || validation.Findings[0].Line == 0 {
+		t.Errorf("expected a syntax error with the line, but got %+v", validation.Findings[0])
+	}
+}
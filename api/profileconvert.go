@@ -0,0 +1,206 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Types of connection profiles.
+const (
+	ProfileTypeYAML = "yaml"
+	ProfileTypeJSON = "json"
+)
+
+// DetectProfileType to detect the type by the content, it is json if it begins with {, otherwise yaml.
+func DetectProfileType(config []byte) string {
+	if bytes.HasPrefix(bytes.TrimSpace(config), []byte("{")) {
+		return ProfileTypeJSON
+	}
+	return ProfileTypeYAML
+}
+
+// ProfileConvertOption options to convert a profile.
+type ProfileConvertOption struct {
+	InlinePaths bool
+	BaseDir     string
+}
+
+// ProfileConvertOptionFunc to handle the convert option.
+type ProfileConvertOptionFunc func(opt *ProfileConvertOption)
+
+// WithInlinePaths to replace paths of certificates with the PEMs in the files, relative paths are of the base dir.
+// Only files in the base dir are read, and only certificates are inlined, paths of private keys are kept.
+func WithInlinePaths(baseDir string) ProfileConvertOptionFunc {
+	return func(opt *ProfileConvertOption) {
+		opt.InlinePaths = true
+		opt.BaseDir = baseDir
+	}
+}
+
+// Keys of which the value has a certificate in pem or path.
+var profileCertKeys = map[string]bool{"tlsCACerts": true, "cert": true}
+
+// ConvertProfile to convert the profile, of either type, to the type. The order of keys is kept.
+func ConvertProfile(config []byte, toType string, options ...ProfileConvertOptionFunc) ([]byte, error) {
+	opt := &ProfileConvertOption{}
+	for _, option := range options {
+		option(opt)
+	}
+
+	// Json is parsed as yaml, to keep the order of keys.
+	profile := yaml.MapSlice{}
+	if err := yaml.Unmarshal(config, &profile); err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing the connection profile.")
+	}
+	if opt.InlinePaths {
+		if opt.BaseDir == "" {
+			return nil, errors.New("the base dir of paths to inline is empty")
+		}
+		if err := inlineProfilePaths(profile, opt.BaseDir); err != nil {
+			return nil, err
+		}
+	}
+
+	return marshalProfile(profile, toType)
+}
+
+// marshalProfile to marshal the parsed profile as the type.
+func marshalProfile(profile yaml.MapSlice, toType string) ([]byte, error) {
+	switch toType {
+	case ProfileTypeYAML:
+		return yaml.Marshal(profile)
+	case ProfileTypeJSON:
+		b := &bytes.Buffer{}
+		if err := writeOrderedJSON(b, profile); err != nil {
+			return nil, err
+		}
+		indented := &bytes.Buffer{}
+		if err := json.Indent(indented, b.Bytes(), "", "  "); err != nil {
+			return nil, err
+		}
+		indented.WriteByte('\n')
+		return indented.Bytes(), nil
+	}
+	return nil, errors.Errorf("profile type %s is not supported, it should be %s or %s", toType, ProfileTypeYAML, ProfileTypeJSON)
+}
+
+func inlineProfilePaths(m yaml.MapSlice, baseDir string) error {
+	for idx, item := range m {
+		value, ok := item.Value.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+		if profileCertKeys[fmt.Sprint(item.Key)] {
+			inlined, err := inlineCertPath(value, baseDir)
+			if err != nil {
+				return errors.WithMessagef(err, "Error occurred when inlining %v.", item.Key)
+			}
+			m[idx].Value = inlined
+			value = inlined
+		}
+		if err := inlineProfilePaths(value, baseDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inlineCertPath to replace the path with the certificates in the file. Other blocks of the file, e.g. a private key, are dropped.
+func inlineCertPath(m yaml.MapSlice, baseDir string) (yaml.MapSlice, error) {
+	inlined := yaml.MapSlice{}
+	for _, item := range m {
+		certPath, ok := item.Value.(string)
+		if fmt.Sprint(item.Key) != "path" || !ok {
+			inlined = append(inlined, item)
+			continue
+		}
+		content, err := readFileInDir(baseDir, certPath)
+		if err != nil {
+			return nil, err
+		}
+		certs := []byte{}
+		for block, rest := pem.Decode(content); block != nil; block, rest = pem.Decode(rest) {
+			if block.Type == "CERTIFICATE" {
+				certs = append(certs, pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: block.Bytes})...)
+			}
+		}
+		if len(certs) == 0 {
+			return nil, errors.Errorf("%s is not a PEM certificate", certPath)
+		}
+		inlined = append(inlined, yaml.MapItem{Key: "pem", Value: string(certs)})
+	}
+	return inlined, nil
+}
+
+// readFileInDir to read the file of the path, relative to the dir. It fails if the file, with symbolic links resolved, is out of the dir.
+func readFileInDir(dir string, path string) ([]byte, error) {
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	realDir, err = filepath.Abs(realDir)
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, err
+	}
+	realPath, err = filepath.Abs(realPath)
+	if err != nil {
+		return nil, err
+	}
+	if rel, err := filepath.Rel(realDir, realPath); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, errors.Errorf("%s is out of %s", path, dir)
+	}
+	return ioutil.ReadFile(realPath)
+}
+
+// writeOrderedJSON to write the parsed yaml as json, keys are in the order of the yaml.
+func writeOrderedJSON(b *bytes.Buffer, value interface{}) error {
+	switch value := value.(type) {
+	case yaml.MapSlice:
+		b.WriteByte('{')
+		for idx, item := range value {
+			if idx > 0 {
+				b.WriteByte(',')
+			}
+			key, _ := json.Marshal(fmt.Sprint(item.Key))
+			b.Write(key)
+			b.WriteByte(':')
+			if err := writeOrderedJSON(b, item.Value); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	case []interface{}:
+		b.WriteByte('[')
+		for idx, item := range value {
+			if idx > 0 {
+				b.WriteByte(',')
+			}
+			if err := writeOrderedJSON(b, item); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	default:
+		content, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		b.Write(content)
+	}
+	return nil
+}
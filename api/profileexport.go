@@ -0,0 +1,209 @@
+package api
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ExportProfile to export the connection profile completed with the discovered peers, orderers, channels and TLS CA certificates.
+// Entries of the original profile are kept as they are, the type is of the original profile if it is empty.
+func ExportProfile(conn *NetworkConnection, toType string) ([]byte, error) {
+	profile := yaml.MapSlice{}
+	if err := yaml.Unmarshal(conn.ConnectionProfile.Config, &profile); err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing the connection profile.")
+	}
+	if toType == "" {
+		toType = DetectProfileType(conn.ConnectionProfile.Config)
+	}
+	endpointConfigs := conn.EndpointConfigs
+	if endpointConfigs == nil {
+		endpointConfigs = conn.newEndpointConfigs()
+	}
+
+	exporter := &profileExporter{profile: profile, names: make(map[string]string)}
+	ordererNames := []string{}
+	for name := range endpointConfigs.OrdererConfigs.OrdererConfigs {
+		ordererNames = append(ordererNames, name)
+	}
+	sort.Strings(ordererNames)
+	for _, name := range ordererNames {
+		ordCfg := endpointConfigs.OrdererConfigs.OrdererConfigs[name]
+		exporter.addEndpoint("orderers", name, ordCfg.URL, ordCfg.GRPCOptions, ordCfg.TLSCACert)
+	}
+
+	channelIDs := []string{}
+	for channelID := range endpointConfigs.ChannelConfigs.ChannelConfigs {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+	for _, channelID := range channelIDs {
+		channelPeers := map[string]fab.ChannelPeer{}
+		peerNames := []string{}
+		for _, channelPeer := range endpointConfigs.ChannelPeers.ChannelPeers(channelID) {
+			name, orgName := channelPeer.URL, channelPeer.MSPID
+			if peer := conn.findPeer(channelPeer.URL); peer != nil {
+				name = peer.Name
+				if peer.OrgName != "" {
+					orgName = peer.OrgName
+				}
+			}
+			name = exporter.addEndpoint("peers", name, channelPeer.URL, channelPeer.GRPCOptions, channelPeer.TLSCACert)
+			exporter.addOrgPeer(orgName, channelPeer.MSPID, name)
+			channelPeers[name] = channelPeer
+			peerNames = append(peerNames, name)
+		}
+		sort.Strings(peerNames)
+		for _, name := range peerNames {
+			exporter.addChannelPeer(channelID, name, channelPeers[name].PeerChannelConfig)
+		}
+
+		if channel, ok := conn.Channels[channelID]; ok {
+			orderers := channel.Orderers.StringList()
+			sort.Strings(orderers)
+			for _, orderer := range orderers {
+				if name, ok := exporter.names[conn.findOrdererName(orderer)]; ok {
+					exporter.addChannelOrderer(channelID, name)
+				}
+			}
+		}
+	}
+
+	return marshalProfile(exporter.profile, toType)
+}
+
+// profileExporter to add endpoints to the profile, names of added endpoints are mapped to names in the profile.
+type profileExporter struct {
+	profile yaml.MapSlice
+	names   map[string]string
+}
+
+func (e *profileExporter) addEndpoint(sectionName string, name string, URL string, grpcOptions map[string]interface{}, cert *x509.Certificate) string {
+	section, _ := mapValue(e.profile, sectionName).(yaml.MapSlice)
+	// The endpoint might be configured with another name, or with the scheme in the URL.
+	_, hostPort, _ := splitEndpointURL(URL)
+	for _, item := range section {
+		key := fmt.Sprint(item.Key)
+		configuredURL, _ := mapValue(toMapSlice(item.Value), "url").(string)
+		if _, configuredHostPort, err := splitEndpointURL(configuredURL); key == name || (err == nil && configuredHostPort == hostPort) {
+			e.names[name] = key
+			return key
+		}
+	}
+
+	if !strings.Contains(URL, "://") {
+		if cert != nil {
+			URL = "grpcs://" + URL
+		} else {
+			URL = "grpc://" + URL
+		}
+	}
+	endpoint := yaml.MapSlice{{Key: "url", Value: URL}}
+	if options := exportGRPCOptions(grpcOptions); len(options) > 0 {
+		endpoint = append(endpoint, yaml.MapItem{Key: "grpcOptions", Value: options})
+	}
+	if cert != nil {
+		endpoint = append(endpoint, yaml.MapItem{Key: "tlsCACerts", Value: yaml.MapSlice{
+			{Key: "pem", Value: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))}}})
+	}
+	e.profile = setMapValue(e.profile, sectionName, setMapValue(section, name, endpoint))
+	e.names[name] = name
+	return name
+}
+
+func (e *profileExporter) addOrgPeer(orgName string, MSPID string, peerName string) {
+	orgs, _ := mapValue(e.profile, "organizations").(yaml.MapSlice)
+	org := toMapSlice(mapValue(orgs, orgName))
+	if org == nil {
+		org = yaml.MapSlice{{Key: "mspid", Value: MSPID}}
+	}
+	peers, _ := mapValue(org, "peers").([]interface{})
+	for _, peer := range peers {
+		if fmt.Sprint(peer) == peerName {
+			return
+		}
+	}
+	org = setMapValue(org, "peers", append(peers, peerName))
+	e.profile = setMapValue(e.profile, "organizations", setMapValue(orgs, orgName, org))
+}
+
+func (e *profileExporter) addChannelPeer(channelID string, peerName string, peerChannelConfig fab.PeerChannelConfig) {
+	channels, _ := mapValue(e.profile, "channels").(yaml.MapSlice)
+	channel := toMapSlice(mapValue(channels, channelID))
+	peers := toMapSlice(mapValue(channel, "peers"))
+	if mapValue(peers, peerName) != nil {
+		return
+	}
+	peers = setMapValue(peers, peerName, yaml.MapSlice{
+		{Key: "endorsingPeer", Value: peerChannelConfig.EndorsingPeer},
+		{Key: "chaincodeQuery", Value: peerChannelConfig.ChaincodeQuery},
+		{Key: "ledgerQuery", Value: peerChannelConfig.LedgerQuery},
+		{Key: "eventSource", Value: peerChannelConfig.EventSource},
+	})
+	e.profile = setMapValue(e.profile, "channels", setMapValue(channels, channelID, setMapValue(channel, "peers", peers)))
+}
+
+func (e *profileExporter) addChannelOrderer(channelID string, ordererName string) {
+	channels, _ := mapValue(e.profile, "channels").(yaml.MapSlice)
+	channel := toMapSlice(mapValue(channels, channelID))
+	orderers, _ := mapValue(channel, "orderers").([]interface{})
+	for _, orderer := range orderers {
+		if fmt.Sprint(orderer) == ordererName {
+			return
+		}
+	}
+	channel = setMapValue(channel, "orderers", append(orderers, ordererName))
+	e.profile = setMapValue(e.profile, "channels", setMapValue(channels, channelID, channel))
+}
+
+// exportGRPCOptions to sort the options, durations are as strings.
+func exportGRPCOptions(grpcOptions map[string]interface{}) yaml.MapSlice {
+	keys := []string{}
+	for key := range grpcOptions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	options := yaml.MapSlice{}
+	for _, key := range keys {
+		value := grpcOptions[key]
+		if duration, ok := value.(time.Duration); ok {
+			value = duration.String()
+		}
+		options = append(options, yaml.MapItem{Key: key, Value: value})
+	}
+	return options
+}
+
+// mapValue to get the value of the key, nil if it doesn't exist.
+func mapValue(m yaml.MapSlice, key string) interface{} {
+	for _, item := range m {
+		if fmt.Sprint(item.Key) == key {
+			return item.Value
+		}
+	}
+	return nil
+}
+
+// setMapValue to replace the value of the key, or to append it.
+func setMapValue(m yaml.MapSlice, key string, value interface{}) yaml.MapSlice {
+	for idx, item := range m {
+		if fmt.Sprint(item.Key) == key {
+			m[idx].Value = value
+			return m
+		}
+	}
+	return append(m, yaml.MapItem{Key: key, Value: value})
+}
+
+// toMapSlice the value as a map, nil if it is not a map, e.g. an empty entry in yaml.
+func toMapSlice(value interface{}) yaml.MapSlice {
+	m, _ := value.(yaml.MapSlice)
+	return m
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/fablet/util"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestExportProfile(t *testing.T) {
+	certPEM := newTestCertPEM(t, "tlsca", nil, time.Now().AddDate(-1, 0, 0), time.Now().AddDate(1, 0, 0))
+	block, _ := pem.Decode([]byte(certPEM))
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile := `name: one-peer
+client:
+  organization: Org1
+organizations:
+  Org1:
+    mspid: Org1MSP
+    peers:
+      - peer0.org1.example.com
+peers:
+  peer0.org1.example.com:
+    url: grpcs://peer0.org1.example.com:7051
+    tlsCACerts:
+      path: /tmp/ca.pem
+`
+	channelConfig := &fab.PeerChannelConfig{EndorsingPeer: true, ChaincodeQuery: true, LedgerQuery: true, EventSource: true}
+	conn := &NetworkConnection{
+		ConnectionProfile: &ConnectionProfile{Config: []byte(profile)},
+		Peers: map[string]*Peer{
+			// The configured peer is discovered again by the host and port.
+			"peer0.org1.example.com": {Name: "peer0.org1.example.com", OrgName: "Org1", MSPID: "Org1MSP", URL: "grpcs://peer0.org1.example.com:7051",
+				TLSCACert: cert, PeerChannelConfigs: map[string]*fab.PeerChannelConfig{"mychannel": channelConfig}},
+			"peer0.org1.example.com:7051": {Name: "peer0.org1.example.com:7051", OrgName: "Org1", MSPID: "Org1MSP", URL: "peer0.org1.example.com:7051",
+				TLSCACert: cert, PeerChannelConfigs: map[string]*fab.PeerChannelConfig{"mychannel": channelConfig}},
+			"peer0.org2.example.com:9051": {Name: "peer0.org2.example.com:9051", OrgName: "Org2MSP", MSPID: "Org2MSP", URL: "peer0.org2.example.com:9051",
+				TLSCACert: cert, GRPCOptions: map[string]interface{}{"keep-alive-time": time.Second},
+				PeerChannelConfigs: map[string]*fab.PeerChannelConfig{"mychannel": channelConfig}},
+		},
+		Orderers: map[string]*Orderer{
+			"orderer.example.com:7050": {Name: "orderer.example.com:7050", URL: "orderer.example.com:7050", TLSCACert: cert},
+		},
+		Channels: map[string]*Channel{
+			"mychannel": {ChannelID: "mychannel",
+				Peers:    util.NewSet("peer0.org1.example.com", "peer0.org1.example.com:7051", "peer0.org2.example.com:9051"),
+				Orderers: util.NewSet("orderer.example.com:7050")},
+		},
+	}
+
+	exported, err := ExportProfile(conn, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := struct {
+		Name          string `yaml:"name"`
+		Organizations map[string]struct {
+			MSPID string   `yaml:"mspid"`
+			Peers []string `yaml:"peers"`
+		} `yaml:"organizations"`
+		Peers map[string]struct {
+			URL         string                 `yaml:"url"`
+			GRPCOptions map[string]interface{} `yaml:"grpcOptions"`
+			TLSCACerts  map[string]string      `yaml:"tlsCACerts"`
+		} `yaml:"peers"`
+		Orderers map[string]struct {
+			URL        string            `yaml:"url"`
+			TLSCACerts map[string]string `yaml:"tlsCACerts"`
+		} `yaml:"orderers"`
+		Channels map[string]struct {
+			Orderers []string                          `yaml:"orderers"`
+			Peers    map[string]map[string]interface{} `yaml:"peers"`
+		} `yaml:"channels"`
+	}{}
+	if err := yaml.Unmarshal(exported, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	// The configured entry is kept as it is.
+	if result.Name != "one-peer" || len(result.Peers) != 2 || result.Peers["peer0.org1.example.com"].TLSCACerts["path"] != "/tmp/ca.pem" {
+		t.Errorf("expected the configured peer kept, but got %s", exported)
+	}
+	peer := result.Peers["peer0.org2.example.com:9051"]
+	if peer.URL != "grpcs://peer0.org2.example.com:9051" || peer.TLSCACerts["pem"] != certPEM || peer.GRPCOptions["keep-alive-time"] != "1s" {
+		t.Errorf("expected the discovered peer, but got %+v", peer)
+	}
+	if result.Orderers["orderer.example.com:7050"].TLSCACerts["pem"] != certPEM {
+		t.Errorf("expected the discovered orderer, but got %s", exported)
+	}
+	if org := result.Organizations["Org1"]; len(org.Peers) != 1 {
+		t.Errorf("expected the configured organization, but got %+v", org)
+	}
+	if org := result.Organizations["Org2MSP"]; org.MSPID != "Org2MSP" || len(org.Peers) != 1 || org.Peers[0] != "peer0.org2.example.com:9051" {
+		t.Errorf("expected the discovered organization, but got %+v", org)
+	}
+	channel := result.Channels["mychannel"]
+	if len(channel.Peers) != 2 || channel.Peers["peer0.org1.example.com"]["endorsingPeer"] != true ||
+		len(channel.Orderers) != 1 || channel.Orderers[0] != "orderer.example.com:7050" {
+		t.Errorf("expected the discovered channel, but got %+v", channel)
+	}
+
+	exported, err = ExportProfile(conn, ProfileTypeJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if DetectProfileType(exported) != ProfileTypeJSON || !strings.Contains(string(exported), `"url": "grpcs://peer0.org2.example.com:9051"`) {
+		t.Errorf("expected the profile in json, but got %s", exported)
+	}
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IBM/fablet/util"
+	selectopts "github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SelectionStrategyMyOrg prefer peers of the organization of current participant, then the higher ledger height.
+	SelectionStrategyMyOrg = "myorg"
+	// SelectionStrategyLatency prefer peers with the lowest measured latency.
+	SelectionStrategyLatency = "latency"
+	// SelectionStrategyHeight prefer peers with the highest ledger height.
+	SelectionStrategyHeight = "height"
+)
+
+// DefaultSelectionStrategy the strategy used when no targets and no strategy specified.
+var DefaultSelectionStrategy = SelectionStrategyMyOrg
+
+// selectionCacheTime an endorser selection is reused within the time, since the discovery is a round trip to peers.
+const selectionCacheTime = time.Minute
+
+// SelectedEndorser an endorser selected for the execution.
+type SelectedEndorser struct {
+	Name        string `json:"name"`
+	URL         string `json:"URL"`
+	MSPID       string `json:"MSPID"`
+	BlockHeight uint64 `json:"blockHeight"`
+	Latency     int64  `json:"latency"` // Millisecond, -1 if it is not measured or unreachable.
+	Reason      string `json:"reason"`
+}
+
+// EndorserSelection the endorsers selected by the discovery endorsement plan and the strategy.
+type EndorserSelection struct {
+	ChannelID   string              `json:"channelID"`
+	ChaincodeID string              `json:"chaincodeID"`
+	Strategy    string              `json:"strategy"`
+	Endorsers   []*SelectedEndorser `json:"endorsers"`
+	// Error why the strategy is not applied, the SDK selects the endorsers by itself then.
+	Error      string `json:"error,omitempty"`
+	selectTime time.Time
+}
+
+// Targets to get URLs of the selected endorsers.
+func (selection *EndorserSelection) Targets() []string {
+	targets := []string{}
+	for _, endorser := range selection.Endorsers {
+		targets = append(targets, endorser.URL)
+	}
+	return targets
+}
+
+// IsSelectionStrategy to check if the strategy is supported.
+func IsSelectionStrategy(strategy string) bool {
+	return strategy == SelectionStrategyMyOrg || strategy == SelectionStrategyLatency || strategy == SelectionStrategyHeight
+}
+
+func peerBlockHeight(peer fab.Peer) uint64 {
+	if state, ok := peer.(fab.PeerState); ok {
+		return state.BlockHeight()
+	}
+	return 0
+}
+
+// measureLatencies to measure latencies of all peers of the channel concurrently, map[URL]latency.
+func (conn *NetworkConnection) measureLatencies(channelID string) map[string]time.Duration {
+	latencies := make(map[string]time.Duration)
+	channel, ok := conn.Channels[channelID]
+	if !ok {
+		return latencies
+	}
+
+	var wg sync.WaitGroup
+	var locker sync.Mutex
+	for _, peerName := range channel.Peers.StringList() {
+		peer := conn.findPeer(peerName)
+		if peer == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(URL string) {
+			defer wg.Done()
+			latency, err := util.GetEndpointLatency(URL)
+			if err != nil {
+				logger.Errorf("Failed to measure latency of %s: %s", URL, err.Error())
+				return
+			}
+			locker.Lock()
+			latencies[URL] = latency
+			locker.Unlock()
+		}(peer.URL)
+	}
+	wg.Wait()
+	return latencies
+}
+
+// comparePeers returns positive if peer1 is preferred, negative if peer2, otherwise 0.
+func comparePeers(strategy string, myMSPID string, latencies map[string]time.Duration, peer1, peer2 fab.Peer) int {
+	compareHeight := func() int {
+		h1, h2 := peerBlockHeight(peer1), peerBlockHeight(peer2)
+		if h1 > h2 {
+			return 1
+		} else if h1 < h2 {
+			return -1
+		}
+		return 0
+	}
+
+	switch strategy {
+	case SelectionStrategyMyOrg:
+		mine1, mine2 := peer1.MSPID() == myMSPID, peer2.MSPID() == myMSPID
+		if mine1 && !mine2 {
+			return 1
+		} else if !mine1 && mine2 {
+			return -1
+		}
+		return compareHeight()
+	case SelectionStrategyLatency:
+		l1, ok1 := latencies[peer1.URL()]
+		l2, ok2 := latencies[peer2.URL()]
+		if ok1 && (!ok2 || l1 < l2) {
+			return 1
+		} else if ok2 && (!ok1 || l2 < l1) {
+			return -1
+		}
+		return compareHeight()
+	}
+	return compareHeight()
+}
+
+// PlanEndorsers to select endorsers for the execution without targets. If the strategy can't be applied, the selection has
+// no endorsers but the error, so that the SDK selects the endorsers by itself.
+func PlanEndorsers(conn *NetworkConnection, channelID string, chaincodeID string, strategy string) *EndorserSelection {
+	if strategy == "" {
+		strategy = DefaultSelectionStrategy
+	}
+	selection, err := SelectEndorsers(conn, channelID, chaincodeID, strategy)
+	if err != nil {
+		logger.Warnf("Failed to select endorsers by strategy %s, the SDK selection is used: %s", strategy, err.Error())
+		return &EndorserSelection{ChannelID: channelID, ChaincodeID: chaincodeID, Strategy: strategy, Endorsers: []*SelectedEndorser{},
+			Error: fmt.Sprintf("the strategy is not applied and the SDK selects the endorsers: %s", err.Error())}
+	}
+	return selection
+}
+
+// SelectEndorsers to ask the discovery service for an endorsement plan of the chaincode, and pick peers by the strategy.
+// The selection is cached in the connection for a minute.
+func SelectEndorsers(conn *NetworkConnection, channelID string, chaincodeID string, strategy string) (*EndorserSelection, error) {
+	if strategy == "" {
+		strategy = DefaultSelectionStrategy
+	}
+	if !IsSelectionStrategy(strategy) {
+		return nil, errors.Errorf("%s is not a valid selection strategy", strategy)
+	}
+	key := channelID + "/" + chaincodeID + "/" + strategy
+	if selection := conn.cachedSelection(key); selection != nil {
+		return selection, nil
+	}
+
+	chCtx, err := conn.SDK.ChannelContext(channelID, fabsdk.WithIdentity(conn.SignID))()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Error occurred when creating context of channel %s.", channelID)
+	}
+	selectionService, err := chCtx.ChannelService().Selection()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Error occurred when getting selection service of channel %s.", channelID)
+	}
+
+	latencies := map[string]time.Duration{}
+	if strategy == SelectionStrategyLatency {
+		latencies = conn.measureLatencies(channelID)
+	}
+
+	myMSPID := conn.Participant.MSPID
+	peers, err := selectionService.GetEndorsersForChaincode(
+		[]*fab.ChaincodeCall{{ID: chaincodeID}},
+		selectopts.WithPrioritySelector(func(peer1, peer2 fab.Peer) int {
+			return comparePeers(strategy, myMSPID, latencies, peer1, peer2)
+		}))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Error occurred when selecting endorsers of chaincode %s in channel %s.", chaincodeID, channelID)
+	}
+	if len(peers) < 1 {
+		return nil, errors.Errorf("no any endorser found for chaincode %s in channel %s", chaincodeID, channelID)
+	}
+
+	// The same order as the strategy.
+	sort.SliceStable(peers, func(i, j int) bool {
+		return comparePeers(strategy, myMSPID, latencies, peers[i], peers[j]) > 0
+	})
+
+	selection := &EndorserSelection{ChannelID: channelID, ChaincodeID: chaincodeID, Strategy: strategy, Endorsers: []*SelectedEndorser{}}
+	for _, peer := range peers {
+		endorser := &SelectedEndorser{
+			Name:        peer.URL(),
+			URL:         peer.URL(),
+			MSPID:       peer.MSPID(),
+			BlockHeight: peerBlockHeight(peer),
+			Latency:     -1,
+		}
+		if connPeer := conn.findPeer(peer.URL()); connPeer != nil {
+			endorser.Name = connPeer.Name
+		}
+		if latency, ok := latencies[peer.URL()]; ok {
+			endorser.Latency = latency.Nanoseconds() / 1000000
+		}
+		endorser.Reason = selectionReason(strategy, myMSPID, endorser)
+		selection.Endorsers = append(selection.Endorsers, endorser)
+	}
+
+	logger.Infof("Selected endorsers %v for chaincode %s in channel %s by strategy %s.", selection.Targets(), chaincodeID, channelID, strategy)
+	selection.selectTime = time.Now()
+	conn.selectionLock.Lock()
+	if conn.selections == nil {
+		conn.selections = make(map[string]*EndorserSelection)
+	}
+	conn.selections[key] = selection
+	conn.selectionLock.Unlock()
+	return selection, nil
+}
+
+func (conn *NetworkConnection) cachedSelection(key string) *EndorserSelection {
+	conn.selectionLock.Lock()
+	defer conn.selectionLock.Unlock()
+	if selection, ok := conn.selections[key]; ok && time.Since(selection.selectTime) < selectionCacheTime {
+		return selection
+	}
+	return nil
+}
+
+func selectionReason(strategy string, myMSPID string, endorser *SelectedEndorser) string {
+	reason := fmt.Sprintf("required by the endorsement plan for %s", endorser.MSPID)
+	switch strategy {
+	case SelectionStrategyMyOrg:
+		if endorser.MSPID == myMSPID {
+			reason = fmt.Sprintf("in my organization %s", myMSPID)
+		}
+		return fmt.Sprintf("%s, ledger height %d", reason, endorser.BlockHeight)
+	case SelectionStrategyLatency:
+		if endorser.Latency < 0 {
+			return reason + ", latency not measured"
+		}
+		return fmt.Sprintf("%s, lowest latency %d ms", reason, endorser.Latency)
+	}
+	return fmt.Sprintf("%s, highest ledger height %d", reason, endorser.BlockHeight)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+type testStatePeer struct {
+	*mocks.MockPeer
+	height uint64
+}
+
+func (peer *testStatePeer) BlockHeight() uint64 {
+	return peer.height
+}
+
+func newTestStatePeer(url string, mspID string, height uint64) fab.Peer {
+	peer := mocks.NewMockPeer(url, url)
+	peer.MockMSP = mspID
+	return &testStatePeer{MockPeer: peer, height: height}
+}
+
+func TestComparePeers(t *testing.T) {
+	peers := []fab.Peer{
+		newTestStatePeer("peer0.org2:7051", mspIDOrg2, 10),
+		newTestStatePeer("peer1.org1:7051", mspIDOrg1, 8),
+		newTestStatePeer("peer0.org1:7051", mspIDOrg1, 9),
+		newTestStatePeer("peer1.org2:7051", mspIDOrg2, 11),
+	}
+	latencies := map[string]time.Duration{
+		"peer0.org2:7051": time.Millisecond * 3,
+		"peer1.org1:7051": time.Millisecond * 1,
+		"peer0.org1:7051": time.Millisecond * 2,
+	}
+
+	expected := map[string][]string{
+		SelectionStrategyMyOrg:   {"peer0.org1:7051", "peer1.org1:7051", "peer1.org2:7051", "peer0.org2:7051"},
+		SelectionStrategyLatency: {"peer1.org1:7051", "peer0.org1:7051", "peer0.org2:7051", "peer1.org2:7051"},
+		SelectionStrategyHeight:  {"peer1.org2:7051", "peer0.org2:7051", "peer0.org1:7051", "peer1.org1:7051"},
+	}
+	for strategy, order := range expected {
+		sorted := append([]fab.Peer{}, peers...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return comparePeers(strategy, mspIDOrg1, latencies, sorted[i], sorted[j]) > 0
+		})
+		for idx, peer := range sorted {
+			if peer.URL() != order[idx] {
+				t.Errorf("%s: expected %v at %d, but got %s", strategy, order, idx, peer.URL())
+			}
+		}
+	}
+
+	if IsSelectionStrategy("random") {
+		t.Error("random should not be a valid strategy")
+	}
+}
+
+func TestPlanEndorsers(t *testing.T) {
+	conn := &NetworkConnection{}
+	selection := PlanEndorsers(conn, "mychannel", "vehiclecc", "random")
+	if selection.Error == "" || len(selection.Targets()) != 0 {
+		t.Errorf("expected the strategy is not applied, but got %+v", selection)
+	}
+
+	conn.selections = map[string]*EndorserSelection{
+		"mychannel/vehiclecc/myorg":  {Endorsers: []*SelectedEndorser{{URL: "peer0.org1:7051"}}, selectTime: time.Now()},
+		"mychannel/vehiclecc/height": {Endorsers: []*SelectedEndorser{{URL: "peer0.org1:7051"}}, selectTime: time.Now().Add(-selectionCacheTime)},
+	}
+	if selection := conn.cachedSelection("mychannel/vehiclecc/myorg"); selection == nil {
+		t.Error("expected the cached selection")
+	}
+	if selection := conn.cachedSelection("mychannel/vehiclecc/height"); selection != nil {
+		t.Error("expected the expired selection is not used")
+	}
+}
@@ -0,0 +1,381 @@
+package api
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/event"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/pkg/errors"
+)
+
+const (
+	// TxStatusSubmitted the transaction is sent to the orderer, and not committed by any peer yet.
+	TxStatusSubmitted = "SUBMITTED"
+	// TxStatusCommitted the transaction is committed by at least one peer, see the validation code for if it is valid.
+	TxStatusCommitted = "COMMITTED"
+	// TxStatusTimeout the transaction is not committed by any peer before the timeout.
+	TxStatusTimeout = "TIMEOUT"
+
+	// TxCommitSourceEvent the commit is known by the deliver event.
+	TxCommitSourceEvent = "event"
+	// TxCommitSourceQuery the commit is known by querying the peer.
+	TxCommitSourceQuery = "query"
+)
+
+var (
+	// TxTrackTimeout the longest time to track a transaction.
+	TxTrackTimeout = time.Minute * 2
+	// TxTrackPollInterval interval of querying peers which have not reported the commit.
+	TxTrackPollInterval = time.Second * 2
+	// TxTrackRetention how long the status is kept after the tracking is finished.
+	TxTrackRetention = time.Minute * 10
+)
+
+// PeerCommit the commit of a transaction on a peer.
+type PeerCommit struct {
+	Peer           string `json:"peer"`
+	ValidationCode string `json:"validationCode"`
+	BlockNumber    uint64 `json:"blockNumber"`
+	CommitTime     int64  `json:"commitTime"` // When the commit is known, in milliseconds.
+	Source         string `json:"source"`
+}
+
+// TxStatus status of a tracked transaction.
+type TxStatus struct {
+	TxID           string        `json:"txID"`
+	ChannelID      string        `json:"channelID"`
+	Status         string        `json:"status"`
+	ValidationCode string        `json:"validationCode"`
+	BlockNumber    uint64        `json:"blockNumber"`
+	SubmitTime     int64         `json:"submitTime"`
+	CommitTime     int64         `json:"commitTime"` // The first commit.
+	Peers          []string      `json:"peers"`      // All peers of the channel being tracked.
+	Commits        []*PeerCommit `json:"commits"`
+	Finished       bool          `json:"finished"` // All peers committed, or timeout.
+}
+
+// txEventRegistration the registration of the status event of a transaction.
+type txEventRegistration struct {
+	eventService fab.EventService
+	reg          fab.Registration
+	notifier     <-chan *fab.TxStatusEvent
+}
+
+// registerTxStatusEvent returns nil if the registration failed, the peers will still be queried then.
+func registerTxStatusEvent(eventService fab.EventService, txID string) *txEventRegistration {
+	reg, notifier, err := eventService.RegisterTxStatusEvent(txID)
+	if err != nil {
+		logger.Errorf("Failed to register the status event of transaction %s: %s", txID, err.Error())
+		return nil
+	}
+	return &txEventRegistration{eventService: eventService, reg: reg, notifier: notifier}
+}
+
+func (registration *txEventRegistration) unregister() {
+	if registration != nil {
+		registration.eventService.Unregister(registration.reg)
+	}
+}
+
+type trackedTx struct {
+	status      *TxStatus
+	subscribers map[chan *TxStatus]bool
+	finishTime  time.Time
+}
+
+// TxTracker to track the commit of submitted transactions.
+type TxTracker struct {
+	txs  map[string]*trackedTx
+	lock sync.RWMutex
+}
+
+// NewTxTracker to create a tracker, the finished statuses are removed after the retention.
+func NewTxTracker() *TxTracker {
+	tracker := &TxTracker{txs: make(map[string]*trackedTx)}
+	go tracker.cleanup()
+	return tracker
+}
+
+func (tracker *TxTracker) cleanup() {
+	for range time.Tick(TxTrackRetention / 2) {
+		tracker.lock.Lock()
+		for txID, tx := range tracker.txs {
+			if tx.status.Finished && time.Since(tx.finishTime) > TxTrackRetention {
+				delete(tracker.txs, txID)
+			}
+		}
+		tracker.lock.Unlock()
+	}
+}
+
+func copyTxStatus(status *TxStatus) *TxStatus {
+	st := *status
+	st.Peers = append([]string{}, status.Peers...)
+	st.Commits = []*PeerCommit{}
+	for _, commit := range status.Commits {
+		c := *commit
+		st.Commits = append(st.Commits, &c)
+	}
+	return &st
+}
+
+// Track to begin to track the transaction on all peers of the channel, in background.
+func (tracker *TxTracker) Track(conn *NetworkConnection, channelID string, txID string) *TxStatus {
+	return tracker.track(conn, channelID, txID, nil)
+}
+
+// Submit to submit the transaction as SubmitChaincode and track it. The status event is registered before the transaction is sent,
+// so that a fast commit is not missed.
+func (tracker *TxTracker) Submit(conn *NetworkConnection, channelID string, chaincodeID string, targets []string,
+	funcName string, args []string,
+	options ...channel.RequestOption) (*channel.Response, *TxStatus, error) {
+	var registration *txEventRegistration
+	response, err := submitChaincode(conn, channelID, chaincodeID, targets, funcName, args, func(txID string, eventService fab.EventService) {
+		registration = registerTxStatusEvent(eventService, txID)
+	}, options...)
+	if err != nil {
+		registration.unregister()
+		return nil, nil, err
+	}
+	return response, tracker.track(conn, channelID, string(response.TransactionID), registration), nil
+}
+
+// track the registration is nil if the status event is not registered yet.
+func (tracker *TxTracker) track(conn *NetworkConnection, channelID string, txID string, registration *txEventRegistration) *TxStatus {
+	tracker.lock.Lock()
+	if tx, ok := tracker.txs[txID]; ok {
+		tracker.lock.Unlock()
+		registration.unregister()
+		return copyTxStatus(tx.status)
+	}
+	peers := []string{}
+	if channel, ok := conn.Channels[channelID]; ok {
+		for _, peerName := range channel.Peers.StringList() {
+			if p := conn.findPeer(peerName); p != nil {
+				peers = append(peers, p.URL)
+			}
+		}
+	}
+	status := &TxStatus{
+		TxID:       txID,
+		ChannelID:  channelID,
+		Status:     TxStatusSubmitted,
+		SubmitTime: time.Now().UnixNano() / 1000000,
+		Peers:      peers,
+		Commits:    []*PeerCommit{},
+	}
+	tracker.txs[txID] = &trackedTx{status: status, subscribers: make(map[chan *TxStatus]bool)}
+	tracker.lock.Unlock()
+
+	go tracker.run(conn, channelID, txID, peers, registration)
+	return copyTxStatus(status)
+}
+
+// GetStatus to get the status of a tracked transaction.
+func (tracker *TxTracker) GetStatus(txID string) (*TxStatus, bool) {
+	tracker.lock.RLock()
+	defer tracker.lock.RUnlock()
+	tx, ok := tracker.txs[txID]
+	if !ok {
+		return nil, false
+	}
+	return copyTxStatus(tx.status), true
+}
+
+// Subscribe to receive the status when it changes, the channel is closed when the tracking is finished.
+// The unsubscribe function should be called if the subscriber quits before that.
+func (tracker *TxTracker) Subscribe(txID string) (<-chan *TxStatus, func(), error) {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	tx, ok := tracker.txs[txID]
+	if !ok {
+		return nil, nil, errors.Errorf("transaction %s is not tracked", txID)
+	}
+
+	ch := make(chan *TxStatus, 16)
+	ch <- copyTxStatus(tx.status)
+	if tx.status.Finished {
+		close(ch)
+		return ch, func() {}, nil
+	}
+	tx.subscribers[ch] = true
+	unsubscribe := func() {
+		tracker.lock.Lock()
+		defer tracker.lock.Unlock()
+		if tx.subscribers[ch] {
+			delete(tx.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe, nil
+}
+
+// update to change the status and notify the subscribers, returns if the tracking is finished.
+func (tracker *TxTracker) update(txID string, commit *PeerCommit, timeout bool) bool {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	tx, ok := tracker.txs[txID]
+	if !ok || tx.status.Finished {
+		return true
+	}
+	status := tx.status
+
+	if commit != nil {
+		for _, c := range status.Commits {
+			if c.Peer == commit.Peer {
+				return false
+			}
+		}
+		status.Commits = append(status.Commits, commit)
+		if status.Status == TxStatusSubmitted {
+			status.Status = TxStatusCommitted
+			status.ValidationCode = commit.ValidationCode
+			status.BlockNumber = commit.BlockNumber
+			status.CommitTime = commit.CommitTime
+		}
+	}
+	if timeout {
+		if status.Status == TxStatusSubmitted {
+			status.Status = TxStatusTimeout
+		}
+		status.Finished = true
+	}
+	if len(status.Peers) > 0 && len(status.Commits) >= len(status.Peers) {
+		status.Finished = true
+	}
+
+	for ch := range tx.subscribers {
+		select {
+		case ch <- copyTxStatus(status):
+		default:
+			logger.Debugf("A subscriber of transaction %s is too slow, a status is dropped.", txID)
+		}
+		if status.Finished {
+			close(ch)
+			delete(tx.subscribers, ch)
+		}
+	}
+	if status.Finished {
+		tx.finishTime = time.Now()
+	}
+	return status.Finished
+}
+
+func (tracker *TxTracker) run(conn *NetworkConnection, channelID string, txID string, peers []string, registration *txEventRegistration) {
+	channelContext := conn.SDK.ChannelContext(channelID, fabsdk.WithIdentity(conn.SignID))
+
+	if registration == nil {
+		if eventClient, err := event.New(channelContext); err == nil {
+			registration = registerTxStatusEvent(eventClient, txID)
+		} else {
+			logger.Errorf("Failed to create the event client for transaction %s: %s", txID, err.Error())
+		}
+	}
+	defer registration.unregister()
+	var notifier <-chan *fab.TxStatusEvent
+	if registration != nil {
+		notifier = registration.notifier
+	}
+
+	ldgClient, err := ledger.New(channelContext)
+	if err != nil {
+		logger.Errorf("Failed to create the ledger client for transaction %s: %s", txID, err.Error())
+	}
+
+	timer := time.NewTimer(TxTrackTimeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(TxTrackPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case txEvent, ok := <-notifier:
+			if !ok {
+				notifier = nil
+				continue
+			}
+			commit := &PeerCommit{
+				Peer:           trimURLScheme(txEvent.SourceURL),
+				ValidationCode: txEvent.TxValidationCode.String(),
+				BlockNumber:    txEvent.BlockNumber,
+				CommitTime:     time.Now().UnixNano() / 1000000,
+				Source:         TxCommitSourceEvent,
+			}
+			if tracker.update(txID, commit, false) {
+				return
+			}
+		case <-ticker.C:
+			if ldgClient == nil {
+				continue
+			}
+			for _, commit := range tracker.queryCommits(ldgClient, txID, peers) {
+				if tracker.update(txID, commit, false) {
+					return
+				}
+			}
+		case <-timer.C:
+			tracker.update(txID, nil, true)
+			logger.Infof("Tracking of transaction %s timeout.", txID)
+			return
+		}
+	}
+}
+
+// trimURLScheme to have the same URL of peers as in the connection profile.
+func trimURLScheme(URL string) string {
+	if idx := strings.Index(URL, "://"); idx >= 0 {
+		return URL[idx+3:]
+	}
+	return URL
+}
+
+// queryCommits to query the peers which have not reported the commit yet.
+func (tracker *TxTracker) queryCommits(ldgClient *ledger.Client, txID string, peers []string) []*PeerCommit {
+	status, ok := tracker.GetStatus(txID)
+	if !ok {
+		return nil
+	}
+	committed := map[string]bool{}
+	for _, commit := range status.Commits {
+		committed[commit.Peer] = true
+	}
+
+	var wg sync.WaitGroup
+	var locker sync.Mutex
+	commits := []*PeerCommit{}
+	for _, target := range peers {
+		if committed[target] {
+			continue
+		}
+		wg.Add(1)
+		go func(target string) {
+			defer wg.Done()
+			ptx, err := ldgClient.QueryTransaction(fab.TransactionID(txID), ledger.WithTargetEndpoints(target))
+			if err != nil {
+				// Not committed yet in most cases.
+				logger.Debugf("Transaction %s is not found on %s: %s", txID, target, err.Error())
+				return
+			}
+			commit := &PeerCommit{
+				Peer:           target,
+				ValidationCode: peer.TxValidationCode(ptx.GetValidationCode()).String(),
+				CommitTime:     time.Now().UnixNano() / 1000000,
+				Source:         TxCommitSourceQuery,
+			}
+			if block, err := ldgClient.QueryBlockByTxID(fab.TransactionID(txID), ledger.WithTargetEndpoints(target)); err == nil {
+				commit.BlockNumber = block.GetHeader().GetNumber()
+			}
+			locker.Lock()
+			commits = append(commits, commit)
+			locker.Unlock()
+		}(target)
+	}
+	wg.Wait()
+	return commits
+}
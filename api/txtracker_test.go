@@ -0,0 +1,51 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestTxTrackerUpdate(t *testing.T) {
+	tracker := NewTxTracker()
+	tracker.txs["tx1"] = &trackedTx{
+		status:      &TxStatus{TxID: "tx1", Status: TxStatusSubmitted, Peers: []string{"peer0.org1:7051", "peer0.org2:7051"}, Commits: []*PeerCommit{}},
+		subscribers: map[chan *TxStatus]bool{},
+	}
+
+	statusChan, unsubscribe, err := tracker.Subscribe("tx1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unsubscribe()
+	if status := <-statusChan; status.Status != TxStatusSubmitted {
+		t.Fatalf("the current status should be sent first, but got %+v", status)
+	}
+
+	commit := &PeerCommit{Peer: trimURLScheme("grpcs://peer0.org1:7051"), ValidationCode: "VALID", BlockNumber: 5, Source: TxCommitSourceEvent}
+	if tracker.update("tx1", commit, false) {
+		t.Fatal("it should not be finished before all peers committed")
+	}
+	// The same peer reported by query.
+	tracker.update("tx1", &PeerCommit{Peer: "peer0.org1:7051", ValidationCode: "VALID", BlockNumber: 5, Source: TxCommitSourceQuery}, false)
+	if status := <-statusChan; status.Status != TxStatusCommitted || status.BlockNumber != 5 || len(status.Commits) != 1 {
+		t.Fatalf("wrong status %+v", status)
+	}
+
+	if !tracker.update("tx1", &PeerCommit{Peer: "peer0.org2:7051", ValidationCode: "VALID", BlockNumber: 5}, false) {
+		t.Fatal("it should be finished after all peers committed")
+	}
+	if status := <-statusChan; !status.Finished || len(status.Commits) != 2 {
+		t.Fatalf("wrong final status %+v", status)
+	}
+	if _, ok := <-statusChan; ok {
+		t.Error("the channel should be closed after finished")
+	}
+
+	if _, _, err := tracker.Subscribe("tx2"); err == nil {
+		t.Error("tx2 is not tracked")
+	}
+	tracker.txs["tx2"] = &trackedTx{status: &TxStatus{TxID: "tx2", Status: TxStatusSubmitted}, subscribers: map[chan *TxStatus]bool{}}
+	tracker.update("tx2", nil, true)
+	if status, _ := tracker.GetStatus("tx2"); status.Status != TxStatusTimeout || !status.Finished {
+		t.Errorf("wrong timeout status %+v", status)
+	}
+}
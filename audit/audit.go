@@ -0,0 +1,293 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ResultSuccess the operation succeeded.
+	ResultSuccess = "success"
+	// ResultFailure the operation failed.
+	ResultFailure = "failure"
+
+	// ExportFormatJSON export records as JSON lines, the same as stored.
+	ExportFormatJSON = "json"
+	// ExportFormatCSV export records as CSV.
+	ExportFormatCSV = "csv"
+)
+
+// Record an audit record of an operation.
+type Record struct {
+	Seq             uint64   `json:"seq"`
+	Time            int64    `json:"time"` // In milliseconds.
+	User            string   `json:"user"` // Fablet user, a label supplied by the client which is not authenticated.
+	RemoteAddr      string   `json:"remoteAddr"`
+	MSPID           string   `json:"MSPID"`
+	Subject         string   `json:"subject"` // Subject of the certificate of the Fabric identity.
+	Operation       string   `json:"operation"`
+	Resource        string   `json:"resource,omitempty"` // The workspace, CA identity, batch job or benchmark operated on.
+	ChannelID       string   `json:"channelID"`
+	ChaincodeID     string   `json:"chaincodeID"`
+	Targets         []string `json:"targets"`
+	Orderer         string   `json:"orderer"`
+	ArgumentDigests []string `json:"argumentDigests"` // SHA256 of each argument, the arguments themselves are not stored.
+	TxID            string   `json:"txID"`
+	Result          string   `json:"result"`
+	Error           string   `json:"error"`
+	Duration        int64    `json:"duration"` // In milliseconds.
+	PrevHash        string   `json:"prevHash"`
+	Hash            string   `json:"hash"` // SHA256 of the previous hash and this record without the hash.
+}
+
+// Filter to query the records, empty fields are ignored.
+type Filter struct {
+	User      string `json:"user"`
+	MSPID     string `json:"MSPID"`
+	Operation string `json:"operation"`
+	ChannelID string `json:"channelID"`
+	TxID      string `json:"txID"`
+	Result    string `json:"result"`
+	From      int64  `json:"from"` // Time in milliseconds, inclusive.
+	To        int64  `json:"to"`   // Time in milliseconds, exclusive.
+	Limit     int    `json:"limit"`
+}
+
+// Verification result of verifying the hash chain.
+type Verification struct {
+	Valid    bool   `json:"valid"`
+	Records  uint64 `json:"records"`
+	BrokenAt uint64 `json:"brokenAt"` // Number of the first broken record, from 1.
+	Reason   string `json:"reason"`
+}
+
+// Log an append-only audit log, hash chained and stored as JSON lines.
+type Log struct {
+	path     string
+	lastSeq  uint64
+	lastHash string
+	lock     sync.Mutex
+}
+
+// Digest to have the SHA256 hex of an argument.
+func Digest(arg string) string {
+	sum := sha256.Sum256([]byte(arg))
+	return hex.EncodeToString(sum[:])
+}
+
+// Digests to have the SHA256 hex of all arguments.
+func Digests(args []string) []string {
+	digests := make([]string, len(args))
+	for idx, arg := range args {
+		digests[idx] = Digest(arg)
+	}
+	return digests
+}
+
+// Open to open the audit log file, it is created if not existing.
+func Open(path string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when creating the audit folder.")
+	}
+	l := &Log{path: path}
+	err := l.scan(func(record *Record, line []byte) error {
+		l.lastSeq, l.lastHash = record.Seq, record.Hash
+		return nil
+	})
+	if err != nil && !os.IsNotExist(errors.Cause(err)) {
+		return nil, err
+	}
+	return l, nil
+}
+
+// calculateHash to calculate the hash of the record, the hash field itself is excluded.
+func calculateHash(record *Record) (string, error) {
+	r := *record
+	r.Hash = ""
+	content, err := json.Marshal(&r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(r.PrevHash), content...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Append to append a record, the sequence and hashes are set.
+func (l *Log) Append(record *Record) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	record.Seq = l.lastSeq + 1
+	record.PrevHash = l.lastHash
+	hash, err := calculateHash(record)
+	if err != nil {
+		return errors.WithMessage(err, "Error occurred when calculating the audit hash.")
+	}
+	record.Hash = hash
+
+	content, err := json.Marshal(record)
+	if err != nil {
+		return errors.WithMessage(err, "Error occurred when marshaling the audit record.")
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.WithMessage(err, "Error occurred when opening the audit log.")
+	}
+	defer file.Close()
+	if _, err := file.Write(append(content, '\n')); err != nil {
+		return errors.WithMessage(err, "Error occurred when writing the audit log.")
+	}
+	l.lastSeq, l.lastHash = record.Seq, record.Hash
+	return nil
+}
+
+// scan to read all records in order.
+func (l *Log) scan(handle func(record *Record, line []byte) error) error {
+	file, err := os.Open(l.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		record := &Record{}
+		if err := json.Unmarshal(line, record); err != nil {
+			return errors.WithMessagef(err, "Error occurred when parsing line %d of the audit log.", lineNum)
+		}
+		if err := handle(record, line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (filter *Filter) match(record *Record) bool {
+	return (filter.User == "" || filter.User == record.User) &&
+		(filter.MSPID == "" || filter.MSPID == record.MSPID) &&
+		(filter.Operation == "" || filter.Operation == record.Operation) &&
+		(filter.ChannelID == "" || filter.ChannelID == record.ChannelID) &&
+		(filter.TxID == "" || filter.TxID == record.TxID) &&
+		(filter.Result == "" || filter.Result == record.Result) &&
+		(filter.From == 0 || record.Time >= filter.From) &&
+		(filter.To == 0 || record.Time < filter.To)
+}
+
+// Query to find the records matching the filter, the latest first.
+func (l *Log) Query(filter *Filter) ([]*Record, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	records := []*Record{}
+	err := l.scan(func(record *Record, line []byte) error {
+		if filter.match(record) {
+			records = append(records, record)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// Reverse, the latest first.
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+	if filter.Limit > 0 && len(records) > filter.Limit {
+		records = records[:filter.Limit]
+	}
+	return records, nil
+}
+
+// Export to write the records matching the filter in order, as JSON lines or CSV.
+func (l *Log) Export(writer io.Writer, filter *Filter, format string) error {
+	records, err := l.Query(filter)
+	if err != nil {
+		return err
+	}
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+
+	switch format {
+	case ExportFormatJSON, "":
+		encoder := json.NewEncoder(writer)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ExportFormatCSV:
+		csvWriter := csv.NewWriter(writer)
+		csvWriter.Write([]string{"seq", "time", "user", "remoteAddr", "MSPID", "subject", "operation", "resource", "channelID", "chaincodeID",
+			"targets", "orderer", "argumentDigests", "txID", "result", "error", "duration", "prevHash", "hash"})
+		for _, r := range records {
+			csvWriter.Write([]string{fmt.Sprint(r.Seq), fmt.Sprint(r.Time), r.User, r.RemoteAddr, r.MSPID, r.Subject, r.Operation, r.Resource, r.ChannelID, r.ChaincodeID,
+				strings.Join(r.Targets, " "), r.Orderer, strings.Join(r.ArgumentDigests, " "), r.TxID, r.Result, r.Error, fmt.Sprint(r.Duration), r.PrevHash, r.Hash})
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	}
+	return errors.Errorf("%s is not a supported export format", format)
+}
+
+// Verify to check the hash chain of all records, any modified, inserted or deleted record breaks the chain.
+func (l *Log) Verify() (*Verification, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	verification := &Verification{Valid: true}
+	prevHash := ""
+	err := l.scan(func(record *Record, line []byte) error {
+		if !verification.Valid {
+			return nil
+		}
+		verification.Records++
+		hash, err := calculateHash(record)
+		if err != nil {
+			return err
+		}
+		reason := ""
+		switch {
+		case record.Seq != verification.Records:
+			reason = fmt.Sprintf("sequence %d is not expected %d", record.Seq, verification.Records)
+		case record.PrevHash != prevHash:
+			reason = "previous hash does not match"
+		case record.Hash != hash:
+			reason = "hash does not match the content"
+		}
+		if reason != "" {
+			verification.Valid, verification.BrokenAt, verification.Reason = false, verification.Records, reason
+		}
+		prevHash = record.Hash
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		// A record cannot be parsed means it is tampered.
+		return &Verification{Valid: false, Records: verification.Records, BrokenAt: verification.Records + 1, Reason: err.Error()}, nil
+	}
+	if verification.Valid && prevHash != l.lastHash {
+		verification.Valid, verification.Reason = false, "the last records are removed"
+	}
+	return verification, nil
+}
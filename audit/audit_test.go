@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fablet-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit", "audit.log")
+
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	operations := []string{"install", "execute", "execute"}
+	for idx, operation := range operations {
+		record := &Record{Time: int64(idx), User: "admin", MSPID: "Org1MSP", Operation: operation, Result: ResultSuccess,
+			ArgumentDigests: Digests([]string{"v001"})}
+		if err := l.Append(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Reopen to continue the chain.
+	l, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Append(&Record{Time: 3, Operation: "joinChannel", Result: ResultFailure}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := l.Query(&Filter{Operation: "execute", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Seq != 3 || records[0].ArgumentDigests[0] != Digest("v001") {
+		t.Fatalf("wrong records %+v", records)
+	}
+
+	verification, err := l.Verify()
+	if err != nil || !verification.Valid || verification.Records != 4 {
+		t.Fatalf("the log should be valid: %+v %v", verification, err)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := l.Export(buffer, &Filter{}, ExportFormatCSV); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buffer.String()), "\n"); len(lines) != 5 || !strings.HasPrefix(lines[1], "1,") {
+		t.Errorf("wrong csv export %s", buffer.String())
+	}
+
+	// Tamper the second record.
+	content, _ := ioutil.ReadFile(path)
+	tampered := strings.Replace(string(content), `"operation":"execute"`, `"operation":"query"`, 1)
+	ioutil.WriteFile(path, []byte(tampered), 0600)
+	verification, err = l.Verify()
+	if err != nil || verification.Valid || verification.BrokenAt != 2 {
+		t.Errorf("the tampered log should be invalid at 2: %+v %v", verification, err)
+	}
+}
@@ -0,0 +1,502 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/IBM/fablet/api"
+	"github.com/IBM/fablet/audit"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// ExecuteAction action types of ExecuteRequest.
+const (
+	ExecuteActionExecute = "execute"
+	ExecuteActionQuery   = "query"
+)
+
+// NetworkOverview result of /network/discover.
+type NetworkOverview struct {
+	Peers              []*api.Peer                 `json:"peers"`
+	Channels           []string                    `json:"channels"`
+	PeerStatuses       map[string]api.PeerStatus   `json:"peerStatuses"`
+	ChannelLedgers     map[string]*api.Ledger      `json:"channelLedgers"`
+	ChannelChaincodes  map[string][]*api.Chaincode `json:"channelChaincodes"`
+	ChannelOrderers    map[string][]*api.Orderer   `json:"channelOrderers"`
+	ChannelAnchorPeers map[string][]string         `json:"channelAnchorPeers"`
+	// Conn identifier of the connection, for resource routes.
+	Conn string `json:"conn"`
+}
+
+// PeerDetails result of /peer/details.
+type PeerDetails struct {
+	InstalledChaincodes   []*api.Chaincode            `json:"installedChaincodes"`
+	ChannelChaincodes     map[string][]*api.Chaincode `json:"channelChaincodes"`
+	InstalledCCQueryError bool                        `json:"installedCCQueryError"`
+	ChannelLedgers        map[string]*api.Ledger      `json:"channelLedgers"`
+	Channels              []string                    `json:"channels"`
+}
+
+// InstallRequest to install a chaincode, the package is a tar or tar.gz of the chaincode.
+type InstallRequest struct {
+	Chaincode     api.Chaincode `json:"chaincode"`
+	PackageFormat string        `json:"packageFormat"`
+	Targets       []string      `json:"targets"`
+}
+
+// InstantiateRequest to instantiate or upgrade a chaincode.
+type InstantiateRequest struct {
+	Chaincode api.Chaincode `json:"chaincode"`
+	Target    string        `json:"target"`
+	Orderer   string        `json:"orderer"`
+}
+
+// ExecuteRequest to execute, query or endorse a chaincode.
+type ExecuteRequest struct {
+	Chaincode         api.Chaincode `json:"chaincode"` // Name and ChannelID are required.
+	ActionType        string        `json:"actionType"`
+	FunctionName      string        `json:"functionName"`
+	Arguments         []string      `json:"arguments"`
+	Targets           []string      `json:"targets"`
+	SelectionStrategy string        `json:"selectionStrategy"`
+	Async             bool          `json:"async"`
+}
+
+// PeerResponse response of an endorser.
+type PeerResponse struct {
+	Endorser string `json:"endorser"`
+	Version  int32  `json:"version"`
+	Payload  string `json:"payload"`
+	Status   int32  `json:"status"`
+}
+
+// ExecuteResult result of /chaincode/execute.
+type ExecuteResult struct {
+	TransactionID     string                 `json:"transactionID"`
+	TxValidationCode  int32                  `json:"txValidationCode"` // Not set for asynchronous submission.
+	ChaincodeStatus   int32                  `json:"chaincodeStatus"`
+	Payload           string                 `json:"payload"`
+	PeerResponses     []*PeerResponse        `json:"peerResponses"`
+	EndorserSelection *api.EndorserSelection `json:"endorserSelection"`
+	TxStatus          *api.TxStatus          `json:"txStatus"` // Only for asynchronous submission.
+}
+
+// BatchRequest to start or resume a batch job.
+type BatchRequest struct {
+	Options      api.BatchOptions `json:"options"`
+	Format       string           `json:"format"`
+	Content      []byte           `json:"content"`
+	Columns      []string         `json:"columns"`
+	FunctionName string           `json:"functionName"`
+	ResumeJobID  string           `json:"resumeJobID"`
+}
+
+// BatchStarted result of /chaincode/batch.
+type BatchStarted struct {
+	JobID   string `json:"jobID"`
+	Total   int    `json:"total"`
+	Skipped int    `json:"skipped"`
+}
+
+// PolicyParseResult result of /policy/parse.
+type PolicyParseResult struct {
+	Valid       bool                   `json:"valid"`
+	SyntaxError *api.PolicySyntaxError `json:"syntaxError"`
+	Policy      *api.PolicyNode        `json:"policy"`
+	Expression  string                 `json:"expression"`
+	Tree        string                 `json:"tree"`
+	Principals  []string               `json:"principals"`
+}
+
+// PolicyEvaluateRequest to evaluate organizations or peers against a policy, or the policy of a chaincode.
+type PolicyEvaluateRequest struct {
+	Policy      string   `json:"policy"`
+	ChannelID   string   `json:"channelID"`
+	ChaincodeID string   `json:"chaincodeID"`
+	Orgs        []string `json:"orgs"`
+	Peers       []string `json:"peers"`
+}
+
+// PolicyEvaluateResult result of /policy/evaluate.
+type PolicyEvaluateResult struct {
+	Policy     *api.PolicyNode       `json:"policy"`
+	Expression string                `json:"expression"`
+	Tree       string                `json:"tree"`
+	Evaluation *api.PolicyEvaluation `json:"evaluation"`
+}
+
+// BenchmarkComparison result of /benchmark/compare.
+type BenchmarkComparison struct {
+	Reports     []*api.BenchmarkReport     `json:"reports"`
+	Comparisons []*api.BenchmarkComparison `json:"comparisons"`
+}
+
+// DiscoverNetwork to discover the network, the connection identifier is kept for resource routes.
+func (c *Client) DiscoverNetwork() (*NetworkOverview, error) {
+	result := &NetworkOverview{}
+	if err := c.call("/network/discover", c.base(), result); err != nil {
+		return nil, err
+	}
+	c.ConnectionID = result.Conn
+	return result, nil
+}
+
+// RefreshNetwork to recreate the connection and discover the network again, returns the connection identifier.
+func (c *Client) RefreshNetwork() (string, error) {
+	result := &struct {
+		Conn string `json:"conn"`
+	}{}
+	if err := c.call("/network/refresh", c.base(), result); err != nil {
+		return "", err
+	}
+	c.ConnectionID = result.Conn
+	return result.Conn, nil
+}
+
+// PeerDetails to get chaincodes and ledgers of a peer.
+func (c *Client) PeerDetails(target string, channels []string) (*PeerDetails, error) {
+	result := &PeerDetails{}
+	return result, c.call("/peer/details", &struct {
+		baseRequest
+		Target   string   `json:"target"`
+		Channels []string `json:"channels"`
+	}{c.base(), target, channels}, result)
+}
+
+// InstallChaincode to install a chaincode, the result is per peer.
+func (c *Client) InstallChaincode(req *InstallRequest) (map[string]api.ExecutionResult, error) {
+	result := &struct {
+		InstallRes map[string]api.ExecutionResult `json:"installRes"`
+	}{}
+	return result.InstallRes, c.call("/chaincode/install", &struct {
+		baseRequest
+		*InstallRequest
+	}{c.base(), req}, result)
+}
+
+// InspectChaincode to inspect the chaincode package before the installation, the package of the chaincode is a tar or tar.gz as InstallChaincode,
+// or a CDS or Fabric 2.x package.
+func (c *Client) InspectChaincode(cc *api.Chaincode, packageFormat string) (*api.ChaincodePackageInspection, error) {
+	result := &struct {
+		Inspection *api.ChaincodePackageInspection `json:"inspection"`
+	}{}
+	return result.Inspection, c.call("/chaincode/inspect", &struct {
+		Chaincode     *api.Chaincode `json:"chaincode"`
+		PackageFormat string         `json:"packageFormat"`
+	}{cc, packageFormat}, result)
+}
+
+// InstantiateChaincode to instantiate a chaincode, returns the transaction ID.
+func (c *Client) InstantiateChaincode(req *InstantiateRequest) (string, error) {
+	result := &struct {
+		InstantiateRes string `json:"instantiateRes"`
+	}{}
+	return result.InstantiateRes, c.call("/chaincode/instantiate", &struct {
+		baseRequest
+		*InstantiateRequest
+	}{c.base(), req}, result)
+}
+
+// UpgradeChaincode to upgrade a chaincode, returns the transaction ID.
+func (c *Client) UpgradeChaincode(req *InstantiateRequest) (string, error) {
+	result := &struct {
+		UpgradeRes string `json:"upgradeRes"`
+	}{}
+	return result.UpgradeRes, c.call("/chaincode/upgrade", &struct {
+		baseRequest
+		*InstantiateRequest
+	}{c.base(), req}, result)
+}
+
+// ExecuteChaincode to execute or query a chaincode.
+func (c *Client) ExecuteChaincode(req *ExecuteRequest) (*ExecuteResult, error) {
+	result := &ExecuteResult{}
+	return result, c.call("/chaincode/execute", &struct {
+		baseRequest
+		*ExecuteRequest
+	}{c.base(), req}, result)
+}
+
+// ChaincodeMetadata to get the contract metadata of a chaincode.
+func (c *Client) ChaincodeMetadata(channelID string, chaincodeID string, refresh bool) (*api.ContractMetadata, error) {
+	result := &struct {
+		Metadata *api.ContractMetadata `json:"metadata"`
+	}{}
+	return result.Metadata, c.call("/chaincode/metadata", &struct {
+		baseRequest
+		ChannelID   string `json:"channelID"`
+		ChaincodeID string `json:"chaincodeID"`
+		Refresh     bool   `json:"refresh"`
+	}{c.base(), channelID, chaincodeID, refresh}, result)
+}
+
+// EndorseChaincode to send the proposal to peers and compare the endorsements, without submitting.
+func (c *Client) EndorseChaincode(req *ExecuteRequest) (*api.EndorsementComparison, error) {
+	result := &struct {
+		Endorsement *api.EndorsementComparison `json:"endorsement"`
+	}{}
+	return result.Endorsement, c.call("/chaincode/endorse", &struct {
+		baseRequest
+		*ExecuteRequest
+	}{c.base(), req}, result)
+}
+
+// StartBatch to start or resume a batch invocation job.
+func (c *Client) StartBatch(req *BatchRequest) (*BatchStarted, error) {
+	result := &BatchStarted{}
+	return result, c.call("/chaincode/batch", &struct {
+		baseRequest
+		*BatchRequest
+	}{c.base(), req}, result)
+}
+
+// BatchStatus to get the report of a batch job.
+func (c *Client) BatchStatus(jobID string) (*api.BatchJob, error) {
+	result := &struct {
+		Job *api.BatchJob `json:"job"`
+	}{}
+	return result.Job, c.call("/chaincode/batch/status", &struct {
+		JobID string `json:"jobID"`
+	}{jobID}, result)
+}
+
+// QueryLedger to query the ledger of a channel.
+func (c *Client) QueryLedger(channelID string, targets []string) (*api.Ledger, error) {
+	result := &struct {
+		Ledger *api.Ledger `json:"ledger"`
+	}{}
+	return result.Ledger, c.call("/ledger/query", &struct {
+		baseRequest
+		ChannelID string   `json:"channelID"`
+		Targets   []string `json:"targets"`
+	}{c.base(), channelID, targets}, result)
+}
+
+// QueryBlocks to query blocks from the number.
+func (c *Client) QueryBlocks(channelID string, targets []string, begin uint64, length uint64) ([]*api.Block, error) {
+	result := &struct {
+		Blocks []*api.Block `json:"blocks"`
+	}{}
+	return result.Blocks, c.call("/ledger/block", &struct {
+		baseRequest
+		ChannelID string   `json:"channelID"`
+		Targets   []string `json:"targets"`
+		Begin     uint64   `json:"begin"`
+		Len       uint64   `json:"len"`
+	}{c.base(), channelID, targets, begin, length}, result)
+}
+
+// QueryBlockAny to query a block by number, hash or transaction ID.
+func (c *Client) QueryBlockAny(channelID string, targets []string, queryKey string) (*api.Block, error) {
+	result := &struct {
+		Block *api.Block `json:"block"`
+	}{}
+	return result.Block, c.call("/ledger/blockany", &struct {
+		baseRequest
+		ChannelID string   `json:"channelID"`
+		Targets   []string `json:"targets"`
+		QueryKey  string   `json:"queryKey"`
+	}{c.base(), channelID, targets, queryKey}, result)
+}
+
+// AnalyzeMVCC to analyze MVCC conflicts of blocks from begin to end.
+func (c *Client) AnalyzeMVCC(channelID string, targets []string, begin uint64, end uint64) (*api.MVCCAnalysis, error) {
+	result := &struct {
+		Analysis *api.MVCCAnalysis `json:"analysis"`
+	}{}
+	return result.Analysis, c.call("/ledger/mvcc", &struct {
+		baseRequest
+		ChannelID string   `json:"channelID"`
+		Targets   []string `json:"targets"`
+		Begin     uint64   `json:"begin"`
+		End       uint64   `json:"end"`
+	}{c.base(), channelID, targets, begin, end}, result)
+}
+
+// GraphQL to execute a GraphQL query, the data is unmarshalled into data. Errors of fields are returned with
+// the other fields resolved, while the error is of the request.
+func (c *Client) GraphQL(query string, variables map[string]interface{}, data interface{}) ([]gqlerrors.FormattedError, error) {
+	result := &struct {
+		Data   json.RawMessage            `json:"data"`
+		Errors []gqlerrors.FormattedError `json:"errors"`
+	}{}
+	if err := c.call("/graphql", &struct {
+		baseRequest
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}{c.base(), query, variables}, result); err != nil {
+		return nil, err
+	}
+	if data != nil && len(result.Data) > 0 {
+		if err := json.Unmarshal(result.Data, data); err != nil {
+			return nil, err
+		}
+	}
+	return result.Errors, nil
+}
+
+// ValidateProfile to check the connection profile and MSP ID of the connection statically.
+func (c *Client) ValidateProfile() (*api.ProfileValidation, error) {
+	result := &struct {
+		Validation *api.ProfileValidation `json:"validation"`
+	}{}
+	return result.Validation, c.call("/profile/validate", c.base(), result)
+}
+
+// ConvertProfile to convert a connection profile to yaml or json, paths of certificates are inlined at the server if inlinePaths.
+func (c *Client) ConvertProfile(connProfile string, format string, inlinePaths bool) (string, error) {
+	result := &struct {
+		ConnProfile string `json:"connProfile"`
+	}{}
+	return result.ConnProfile, c.call("/profile/convert", &struct {
+		ConnProfile string `json:"connProfile"`
+		Format      string `json:"format"`
+		InlinePaths bool   `json:"inlinePaths"`
+	}{connProfile, format, inlinePaths}, result)
+}
+
+// ExportProfile to export the connection profile completed with the discovered network, in the format of the connection profile if empty.
+func (c *Client) ExportProfile(format string) (string, error) {
+	result := &struct {
+		ConnProfile string `json:"connProfile"`
+	}{}
+	return result.ConnProfile, c.call("/network/profile/export", &struct {
+		baseRequest
+		Format string `json:"format,omitempty"`
+	}{c.base(), format}, result)
+}
+
+// CertInventory to collect certificates seen by the connection, the windows are of the server if empty.
+func (c *Client) CertInventory(windows []int) (*api.CertInventory, error) {
+	result := &struct {
+		Inventory *api.CertInventory `json:"inventory"`
+	}{}
+	return result.Inventory, c.call("/cert/inventory", &struct {
+		baseRequest
+		Windows []int `json:"windows"`
+	}{c.base(), windows}, result)
+}
+
+// InspectIdentity to decode the certificate of the identity, and check it against MSPs of channels unless offline.
+func (c *Client) InspectIdentity(offline bool) (*api.IdentityInspection, error) {
+	result := &struct {
+		Inspection *api.IdentityInspection `json:"inspection"`
+	}{}
+	return result.Inspection, c.call("/identity/inspect", &struct {
+		baseRequest
+		Offline bool `json:"offline"`
+	}{c.base(), offline}, result)
+}
+
+// CreateChannel to create a channel by the channel transaction, returns the channel ID.
+func (c *Client) CreateChannel(txContent []byte, orderer string) (string, error) {
+	result := &struct {
+		ChannelID string `json:"channelID"`
+	}{}
+	return result.ChannelID, c.call("/channel/create", &struct {
+		baseRequest
+		TxContent []byte `json:"txContent"`
+		Orderer   string `json:"orderer"`
+	}{c.base(), txContent, orderer}, result)
+}
+
+// JoinChannel for peers to join a channel.
+func (c *Client) JoinChannel(channelID string, targets []string, orderer string) error {
+	return c.call("/channel/join", &struct {
+		baseRequest
+		ChannelID string   `json:"channelID"`
+		Targets   []string `json:"targets"`
+		Orderer   string   `json:"orderer"`
+	}{c.base(), channelID, targets, orderer}, nil)
+}
+
+// QueryAudit to query audit records, the latest first.
+func (c *Client) QueryAudit(filter *audit.Filter) ([]*audit.Record, error) {
+	result := &struct {
+		Records []*audit.Record `json:"records"`
+	}{}
+	return result.Records, c.call("/audit/query", &struct {
+		Filter *audit.Filter `json:"filter"`
+	}{filter}, result)
+}
+
+// ExportAudit to export audit records to the writer, in format audit.ExportFormatJSON or audit.ExportFormatCSV.
+func (c *Client) ExportAudit(filter *audit.Filter, format string, writer io.Writer) error {
+	body, err := c.send("/audit/export", &struct {
+		Filter *audit.Filter `json:"filter"`
+		Format string        `json:"format"`
+	}{filter, format})
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	_, err = io.Copy(writer, body)
+	return err
+}
+
+// VerifyAudit to verify the hash chain of the audit log.
+func (c *Client) VerifyAudit() (*audit.Verification, error) {
+	result := &struct {
+		Verification *audit.Verification `json:"verification"`
+	}{}
+	return result.Verification, c.call("/audit/verify", struct{}{}, result)
+}
+
+// TxStatus to get the commit status of a transaction, it begins to be tracked if not yet.
+func (c *Client) TxStatus(channelID string, txID string) (*api.TxStatus, error) {
+	result := &struct {
+		TxStatus *api.TxStatus `json:"txStatus"`
+	}{}
+	return result.TxStatus, c.call("/tx/status", c.txStatusRequest(channelID, txID), result)
+}
+
+func (c *Client) txStatusRequest(channelID string, txID string) interface{} {
+	return &struct {
+		baseRequest
+		ChannelID string `json:"channelID"`
+		TxID      string `json:"txID"`
+	}{c.base(), channelID, txID}
+}
+
+// ParsePolicy to parse an endorsement policy, a syntax error is in the result rather than an error.
+func (c *Client) ParsePolicy(policy string) (*PolicyParseResult, error) {
+	result := &PolicyParseResult{}
+	return result, c.call("/policy/parse", &struct {
+		Policy string `json:"policy"`
+	}{policy}, result)
+}
+
+// EvaluatePolicy to evaluate if the organizations or peers satisfy the policy.
+func (c *Client) EvaluatePolicy(req *PolicyEvaluateRequest) (*PolicyEvaluateResult, error) {
+	result := &PolicyEvaluateResult{}
+	return result, c.call("/policy/evaluate", &struct {
+		baseRequest
+		*PolicyEvaluateRequest
+	}{c.base(), req}, result)
+}
+
+// ListBenchmarks to list all saved benchmark reports, the latest first.
+func (c *Client) ListBenchmarks() ([]*api.BenchmarkReport, error) {
+	result := &struct {
+		Reports []*api.BenchmarkReport `json:"reports"`
+	}{}
+	return result.Reports, c.call("/benchmark/list", struct{}{}, result)
+}
+
+// BenchmarkReports to get saved benchmark reports.
+func (c *Client) BenchmarkReports(IDs []string) ([]*api.BenchmarkReport, error) {
+	result := &struct {
+		Reports []*api.BenchmarkReport `json:"reports"`
+	}{}
+	return result.Reports, c.call("/benchmark/result", &struct {
+		IDs []string `json:"IDs"`
+	}{IDs}, result)
+}
+
+// CompareBenchmarks to compare saved benchmark reports against the first one.
+func (c *Client) CompareBenchmarks(IDs []string) (*BenchmarkComparison, error) {
+	result := &BenchmarkComparison{}
+	return result, c.call("/benchmark/compare", &struct {
+		IDs []string `json:"IDs"`
+	}{IDs}, result)
+}
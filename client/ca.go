@@ -0,0 +1,75 @@
+package client
+
+import "github.com/IBM/fablet/api"
+
+// CAEnrollResult result of /ca/enroll and /ca/reenroll.
+// The private key of the identity is only responded if the connection doesn't use a workspace.
+type CAEnrollResult struct {
+	Identity  *WorkspaceIdentity `json:"identity"`
+	Workspace *Workspace         `json:"workspace"`
+}
+
+type caRequest struct {
+	baseRequest
+	CA string `json:"CA"`
+}
+
+func (c *Client) caBase(ca string) caRequest {
+	return caRequest{c.base(), ca}
+}
+
+// ListCAs to list Fabric CAs of the connection profile.
+func (c *Client) ListCAs() ([]*api.CAConfig, error) {
+	result := &struct {
+		CAs []*api.CAConfig `json:"CAs"`
+	}{}
+	return result.CAs, c.call("/ca/list", c.base(), result)
+}
+
+// EnrollCA to enroll an identity by the secret, the CA can be empty if the profile has only one.
+// The identity is stored in the workspace of the connection, with the label, or the enrollment ID if the label is empty.
+func (c *Client) EnrollCA(ca string, enrollID string, secret string, label string) (*CAEnrollResult, error) {
+	result := &CAEnrollResult{}
+	return result, c.call("/ca/enroll", &struct {
+		caRequest
+		EnrollID string `json:"enrollID"`
+		Secret   string `json:"secret"`
+		Label    string `json:"label"`
+	}{c.caBase(ca), enrollID, secret, label}, result)
+}
+
+// ReenrollCA to renew the certificate of the identity of the connection.
+func (c *Client) ReenrollCA(ca string) (*CAEnrollResult, error) {
+	result := &CAEnrollResult{}
+	return result, c.call("/ca/reenroll", c.caBase(ca), result)
+}
+
+// RegisterCA to register an identity, returns the secret.
+func (c *Client) RegisterCA(ca string, registration *api.CARegistration) (string, error) {
+	result := &struct {
+		Secret string `json:"secret"`
+	}{}
+	return result.Secret, c.call("/ca/register", &struct {
+		caRequest
+		Registration *api.CARegistration `json:"registration"`
+	}{c.caBase(ca), registration}, result)
+}
+
+// RevokeCA to revoke certificates of an identity, or the certificate of the serial and AKI.
+func (c *Client) RevokeCA(ca string, revocation *api.CARevocation) (*api.CARevocationResult, error) {
+	result := &struct {
+		Result *api.CARevocationResult `json:"result"`
+	}{}
+	return result.Result, c.call("/ca/revoke", &struct {
+		caRequest
+		Revocation *api.CARevocation `json:"revocation"`
+	}{c.caBase(ca), revocation}, result)
+}
+
+// CAIdentities to list identities of the CA which the identity of the connection, or the registrar, is allowed to see.
+func (c *Client) CAIdentities(ca string) ([]*api.CAIdentity, error) {
+	result := &struct {
+		Identities []*api.CAIdentity `json:"identities"`
+	}{}
+	return result.Identities, c.call("/ca/identities", c.caBase(ca), result)
+}
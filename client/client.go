@@ -0,0 +1,184 @@
+// Package client is a typed Go client of the Fablet HTTP and websocket API.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/IBM/fablet/api"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ResCodeOK the response code of success.
+	ResCodeOK = 200
+	// DefaultTimeout timeout of a HTTP request.
+	DefaultTimeout = time.Minute * 5
+)
+
+// Connection the connection profile and identity, sent with each request.
+type Connection struct {
+	Label         string `json:"label"`
+	MSPID         string `json:"MSPID"`
+	CertContent   string `json:"certContent"`
+	PrvKeyContent string `json:"prvKeyContent"`
+	// Passphrase of PrvKeyContent if it is encrypted.
+	Passphrase string `json:"passphrase,omitempty"`
+	// PKCS11 the private key in a PKCS#11 token of the server instead of PrvKeyContent.
+	PKCS11      *api.PKCS11Config `json:"PKCS11,omitempty"`
+	ConnProfile string            `json:"connProfile"`
+	// Workspace on the server of which the profile and identity are used instead, see NewWorkspaceConnection.
+	Workspace string `json:"workspace,omitempty"`
+	Identity  string `json:"identity,omitempty"`
+}
+
+// NewWorkspaceConnection to create a connection of the workspace on the server, with the identity of the label,
+// the default identity of the workspace if the label is empty.
+func NewWorkspaceConnection(workspace string, identity string) *Connection {
+	return &Connection{Workspace: workspace, Identity: identity}
+}
+
+// FieldError an invalid field of the request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error an error responded by Fablet.
+type Error struct {
+	ResCode int32 `json:"resCode"`
+	// ErrCode the stable code of the failure type, e.g. ENDPOINT_UNREACHABLE.
+	ErrCode string `json:"errCode"`
+	Message string `json:"errMsg"`
+	// FieldErrors if the request is invalid.
+	FieldErrors []*FieldError `json:"fieldErrors"`
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("fablet error %d %s: %s", err.ResCode, err.ErrCode, err.Message)
+}
+
+// Client the client of a Fablet server.
+type Client struct {
+	// BaseURL of the server, e.g. http://localhost:8080
+	BaseURL    string
+	Connection *Connection
+	// User the Fablet user recorded in the audit log.
+	User string
+	// ConnectionID identifier of the connection for resource routes, it is set by DiscoverNetwork.
+	ConnectionID string
+	HTTPClient   *http.Client
+}
+
+// New to create a client.
+func New(baseURL string, conn *Connection) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		Connection: conn,
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// baseRequest the connection part of all requests.
+type baseRequest struct {
+	Connection *Connection `json:"connection,omitempty"`
+}
+
+func (c *Client) base() baseRequest {
+	return baseRequest{Connection: c.Connection}
+}
+
+// send to post the request and return the raw response body.
+func (c *Client) send(path string, reqBody interface{}) (io.ReadCloser, error) {
+	content, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when marshaling the request.")
+	}
+	req, err := http.NewRequest(http.MethodPost, c.BaseURL+path, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.do(path, req)
+}
+
+func (c *Client) do(path string, req *http.Request) (io.ReadCloser, error) {
+	if c.User != "" {
+		req.Header.Set("X-Fablet-User", c.User)
+	}
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Error occurred when requesting %s.", path)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		// The error is in the body, with the HTTP status as the response code.
+		resErr := &Error{}
+		if err := json.NewDecoder(res.Body).Decode(resErr); err != nil || resErr.ResCode == 0 {
+			resErr = &Error{ResCode: int32(res.StatusCode), Message: res.Status}
+		}
+		return nil, resErr
+	}
+	return res.Body, nil
+}
+
+// get to get a resource and unmarshal the result.
+func (c *Client) get(path string, query url.Values, result interface{}) error {
+	rawURL := c.BaseURL + path
+	if len(query) > 0 {
+		rawURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	if c.ConnectionID == "" && c.Connection != nil && c.Connection.Workspace != "" {
+		req.Header.Set("X-Fablet-Workspace", c.Connection.Workspace)
+	} else {
+		req.Header.Set("X-Fablet-Connection", c.ConnectionID)
+	}
+	body, err := c.do(path, req)
+	if err != nil {
+		return err
+	}
+	return readResult(path, body, result)
+}
+
+// call to post the request and unmarshal the result, a Fablet error is returned if the request failed.
+func (c *Client) call(path string, reqBody interface{}, result interface{}) error {
+	body, err := c.send(path, reqBody)
+	if err != nil {
+		return err
+	}
+	return readResult(path, body, result)
+}
+
+func readResult(path string, body io.ReadCloser, result interface{}) error {
+	defer body.Close()
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		return errors.WithMessagef(err, "Error occurred when reading response of %s.", path)
+	}
+
+	resErr := &Error{}
+	if err := json.Unmarshal(content, resErr); err != nil {
+		return errors.WithMessagef(err, "Error occurred when parsing response of %s.", path)
+	}
+	if resErr.ResCode != ResCodeOK {
+		return resErr
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(content, result); err != nil {
+		return errors.WithMessagef(err, "Error occurred when parsing result of %s.", path)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/IBM/fablet/api"
+	"github.com/IBM/fablet/audit"
+	"github.com/IBM/fablet/service"
+)
+
+func newTestClient(t *testing.T) (*Client, func()) {
+	mux := http.NewServeMux()
+	for path, handler := range service.GetHandlerMap() {
+		mux.HandleFunc(path, handler)
+	}
+	server := httptest.NewServer(mux)
+	client := New(server.URL, nil)
+	client.User = "tester"
+	return client, server.Close
+}
+
+func TestPolicy(t *testing.T) {
+	client, closeServer := newTestClient(t)
+	defer closeServer()
+
+	parsed, err := client.ParsePolicy("OR('Org1MSP.peer', 'Org2MSP.peer')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Valid || parsed.Expression != "OR('Org1MSP.peer', 'Org2MSP.peer')" || len(parsed.Principals) != 2 {
+		t.Errorf("wrong parse result %+v", parsed)
+	}
+
+	parsed, err = client.ParsePolicy("OR('Org1MSP.peer' 'Org2MSP.peer')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Valid || parsed.SyntaxError == nil || parsed.SyntaxError.Position != 19 {
+		t.Errorf("expected syntax error at 19, but got %+v", parsed)
+	}
+
+	evaluated, err := client.EvaluatePolicy(&PolicyEvaluateRequest{
+		Policy: "AND('Org1MSP.peer', 'Org2MSP.peer')",
+		Orgs:   []string{"Org1MSP"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evaluated.Evaluation == nil || evaluated.Evaluation.Satisfied {
+		t.Errorf("expected not satisfied, but got %+v", evaluated.Evaluation)
+	}
+
+	// An error of the service.
+	_, err = client.EvaluatePolicy(&PolicyEvaluateRequest{Policy: "AND("})
+	if resErr, ok := err.(*Error); !ok || resErr.ResCode != 400 {
+		t.Errorf("expected a Fablet error, but got %v", err)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	client, closeServer := newTestClient(t)
+	defer closeServer()
+
+	if _, err := client.QueryAudit(&audit.Filter{Limit: 10}); err != nil {
+		t.Fatal(err)
+	}
+	verification, err := client.VerifyAudit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verification.Valid {
+		t.Errorf("expected a valid audit log, but got %+v", verification)
+	}
+	buf := &bytes.Buffer{}
+	if err := client.ExportAudit(&audit.Filter{}, audit.ExportFormatCSV, buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() == 0 {
+		t.Error("expected the CSV header at least")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	client, closeServer := newTestClient(t)
+	defer closeServer()
+
+	_, err := client.BatchStatus("not-exist")
+	if resErr, ok := err.(*Error); !ok || resErr.ResCode != 404 || resErr.ErrCode != "NOT_FOUND" {
+		t.Errorf("expected not found of an unknown batch job, but got %v", err)
+	}
+	if _, err := client.TxStatus("mychannel", "not-tracked"); err == nil {
+		t.Error("expected error of tracking without connection")
+	}
+	_, err = client.send("/tx/status", struct{}{})
+	if resErr, ok := err.(*Error); !ok || resErr.ResCode != 400 || len(resErr.FieldErrors) != 2 {
+		t.Errorf("expected errors of missing fields, but got %v", err)
+	}
+	if _, err := client.ListBenchmarks(); err != nil {
+		t.Error(err)
+	}
+	if _, err := client.send("/not/exist", struct{}{}); err == nil {
+		t.Error("expected error of unknown route")
+	}
+
+	_, err = client.Channels()
+	if resErr, ok := err.(*Error); !ok || resErr.ResCode != 400 || resErr.ErrCode != "INVALID_REQUEST" {
+		t.Errorf("expected error of the missing connection header, but got %v", err)
+	}
+	client.ConnectionID = "not-exist"
+	_, err = client.Peer("peer0")
+	if resErr, ok := err.(*Error); !ok || resErr.ResCode != 404 || resErr.ErrCode != "CONNECTION_NOT_FOUND" {
+		t.Errorf("expected error of an unknown connection, but got %v", err)
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	client, closeServer := newTestClient(t)
+	defer closeServer()
+
+	client.Connection = &Connection{MSPID: "Org2MSP", ConnProfile: "organizations:\n  Org1:\n    mspid: Org1MSP\npeers:\n  peer0:\n    url: peer0:7051\n"}
+	validation, err := client.ValidateProfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The peer belongs to no organization, and the MSP ID is unknown.
+	if validation.Valid || len(validation.Findings) != 2 || validation.Findings[0].Line != 1 || validation.Findings[1].Line != 5 {
+		t.Errorf("expected findings of the MSP ID and the peer, but got %+v", validation)
+	}
+}
+
+func TestConvertProfile(t *testing.T) {
+	client, closeServer := newTestClient(t)
+	defer closeServer()
+
+	converted, err := client.ConvertProfile("name: test\nversion: 1.0.0\n", "json", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if converted != "{\n  \"name\": \"test\",\n  \"version\": \"1.0.0\"\n}\n" {
+		t.Errorf("expected the profile in json, but got %s", converted)
+	}
+	_, err = client.ConvertProfile(converted, "xml", false)
+	if resErr, ok := err.(*Error); !ok || resErr.ResCode != 400 {
+		t.Errorf("expected error of the invalid format, but got %v", err)
+	}
+	// Inlining paths is disabled unless the server is started with -inlinedir.
+	_, err = client.ConvertProfile("tlsCACerts:\n  path: /etc/hostname\n", "yaml", true)
+	if resErr, ok := err.(*Error); !ok || resErr.ResCode != 400 {
+		t.Errorf("expected error of inlining paths, but got %v", err)
+	}
+}
+
+func TestWebsocket(t *testing.T) {
+	client, closeServer := newTestClient(t)
+	defer closeServer()
+
+	// The server closes the websocket since there is no connection, then the client reconnects.
+	ctx, cancel := context.WithCancel(context.Background())
+	eventChan, errChan := client.BlockEvents(ctx, "mychannel")
+	select {
+	case err := <-errChan:
+		if err == nil {
+			t.Error("expected an error")
+		}
+	case <-time.After(time.Second * 10):
+		t.Error("expected an error of the closed websocket")
+	}
+	cancel()
+	for range eventChan {
+		t.Error("expected no event")
+	}
+	for range errChan {
+	}
+
+	statusChan, err := client.TxStatusEvents(context.Background(), "mychannel", "not-tracked")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for status := range statusChan {
+		t.Errorf("expected no status, but got %+v", status)
+	}
+
+	_, err = client.RunBenchmark(context.Background(), api.BenchmarkOptions{}, nil)
+	if _, ok := err.(*Error); !ok {
+		t.Errorf("expected a Fablet error, but got %v", err)
+	}
+}
+
+func TestWorkspace(t *testing.T) {
+	exeFolder := service.ExeFolder
+	tmpFolder, err := ioutil.TempDir("", "fablet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.ExeFolder = tmpFolder
+	defer func() {
+		service.ExeFolder = exeFolder
+		os.RemoveAll(tmpFolder)
+	}()
+
+	client, closeServer := newTestClient(t)
+	defer closeServer()
+
+	saved, err := client.SaveWorkspace(&Workspace{Name: "staging", Identities: []*WorkspaceIdentity{{Label: "admin", PrvKeyContent: "key"}}})
+	if err != nil || saved.Name != "staging" || saved.Identities[0].PrvKeyContent != "" {
+		t.Errorf("expected the saved workspace without the private key, but got %+v %v", saved, err)
+	}
+	list, err := client.SwitchWorkspace("staging")
+	if err != nil || list.Current != "staging" || len(list.Workspaces) != 1 {
+		t.Errorf("expected switched to staging, but got %+v %v", list, err)
+	}
+
+	client.Connection = NewWorkspaceConnection("prod", "")
+	_, err = client.Channels()
+	if resErr, ok := err.(*Error); !ok || resErr.ResCode != 404 || resErr.ErrCode != "NOT_FOUND" {
+		t.Errorf("expected error of an unknown workspace, but got %v", err)
+	}
+	overviews, err := client.CompareWorkspaces([]string{"prod"})
+	if err != nil || len(overviews) != 1 || overviews[0].ErrCode != "NOT_FOUND" {
+		t.Errorf("expected the error of an unknown workspace in the overview, but got %+v %v", overviews, err)
+	}
+
+	if list, err = client.DeleteWorkspace("staging"); err != nil || list.Current != "" || len(list.Workspaces) != 0 {
+		t.Errorf("expected no workspace, but got %+v %v", list, err)
+	}
+}
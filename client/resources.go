@@ -0,0 +1,138 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/IBM/fablet/api"
+)
+
+// Resource routes are read-only, the connection is identified by ConnectionID.
+
+// ChannelList result of /channels.
+type ChannelList struct {
+	Channels       []string               `json:"channels"`
+	ChannelLedgers map[string]*api.Ledger `json:"channelLedgers"`
+}
+
+// PeerList result of /peers.
+type PeerList struct {
+	Peers        []*api.Peer               `json:"peers"`
+	PeerStatuses map[string]api.PeerStatus `json:"peerStatuses"`
+}
+
+// PeerResource result of /peers/{peerName}.
+type PeerResource struct {
+	Peer                *api.Peer        `json:"peer"`
+	Channels            []string         `json:"channels"`
+	InstalledChaincodes []*api.Chaincode `json:"installedChaincodes"`
+}
+
+func targetsQuery(targets []string) url.Values {
+	query := url.Values{}
+	for _, target := range targets {
+		query.Add("targets", target)
+	}
+	return query
+}
+
+// Channels to list channels and their ledgers.
+func (c *Client) Channels() (*ChannelList, error) {
+	result := &ChannelList{}
+	if err := c.get("/channels", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Channel to get the ledger of a channel.
+func (c *Client) Channel(channelID string, targets []string) (*api.Ledger, error) {
+	result := &struct {
+		Ledger *api.Ledger `json:"ledger"`
+	}{}
+	if err := c.get("/channels/"+url.PathEscape(channelID), targetsQuery(targets), result); err != nil {
+		return nil, err
+	}
+	return result.Ledger, nil
+}
+
+// Blocks to get blocks of a channel from begin.
+func (c *Client) Blocks(channelID string, targets []string, begin uint64, length uint64) ([]*api.Block, error) {
+	query := targetsQuery(targets)
+	query.Set("begin", strconv.FormatUint(begin, 10))
+	query.Set("len", strconv.FormatUint(length, 10))
+	result := &struct {
+		Blocks []*api.Block `json:"blocks"`
+	}{}
+	if err := c.get("/channels/"+url.PathEscape(channelID)+"/blocks", query, result); err != nil {
+		return nil, err
+	}
+	return result.Blocks, nil
+}
+
+// Block to get a block by number or hash.
+func (c *Client) Block(channelID string, targets []string, blockID string) (*api.Block, error) {
+	result := &struct {
+		Block *api.Block `json:"block"`
+	}{}
+	path := "/channels/" + url.PathEscape(channelID) + "/blocks/" + url.PathEscape(blockID)
+	if err := c.get(path, targetsQuery(targets), result); err != nil {
+		return nil, err
+	}
+	return result.Block, nil
+}
+
+// Transaction to get a transaction and the number of its block.
+func (c *Client) Transaction(channelID string, targets []string, txID string) (*api.Transaction, uint64, error) {
+	result := &struct {
+		Transaction *api.Transaction `json:"transaction"`
+		BlockNumber uint64           `json:"blockNumber"`
+	}{}
+	path := "/channels/" + url.PathEscape(channelID) + "/transactions/" + url.PathEscape(txID)
+	if err := c.get(path, targetsQuery(targets), result); err != nil {
+		return nil, 0, err
+	}
+	return result.Transaction, result.BlockNumber, nil
+}
+
+// Chaincodes to list instantiated chaincodes of a channel.
+func (c *Client) Chaincodes(channelID string) ([]*api.Chaincode, error) {
+	result := &struct {
+		Chaincodes []*api.Chaincode `json:"chaincodes"`
+	}{}
+	if err := c.get("/channels/"+url.PathEscape(channelID)+"/chaincodes", nil, result); err != nil {
+		return nil, err
+	}
+	return result.Chaincodes, nil
+}
+
+// Chaincode to get an instantiated chaincode and its contract metadata, the metadata is nil if the chaincode has none.
+func (c *Client) Chaincode(channelID string, chaincodeID string) (*api.Chaincode, *api.ContractMetadata, error) {
+	result := &struct {
+		Chaincode *api.Chaincode        `json:"chaincode"`
+		Metadata  *api.ContractMetadata `json:"metadata"`
+	}{}
+	path := "/channels/" + url.PathEscape(channelID) + "/chaincodes/" + url.PathEscape(chaincodeID)
+	if err := c.get(path, nil, result); err != nil {
+		return nil, nil, err
+	}
+	return result.Chaincode, result.Metadata, nil
+}
+
+// Peers to list peers and their statuses.
+func (c *Client) Peers() (*PeerList, error) {
+	result := &PeerList{}
+	if err := c.get("/peers", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Peer to get a peer with its joined channels and installed chaincodes.
+func (c *Client) Peer(peerName string) (*PeerResource, error) {
+	result := &PeerResource{}
+	if err := c.get("/peers/"+url.PathEscape(peerName), nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
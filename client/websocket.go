@@ -0,0 +1,262 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/IBM/fablet/api"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MinReconnectInterval the first interval to reconnect an event websocket, doubled for each failure.
+	MinReconnectInterval = time.Second
+	// MaxReconnectInterval the max interval to reconnect an event websocket.
+	MaxReconnectInterval = time.Minute
+)
+
+// BlockEvent a block event.
+type BlockEvent struct {
+	Number     uint64 `json:"number"`
+	TXNumber   int    `json:"TXNumber"`
+	UpdateTime int64  `json:"updateTime"`
+	SourceURL  string `json:"sourceURL"`
+}
+
+// ChaincodeEvent a chaincode event.
+type ChaincodeEvent struct {
+	TXID        string `json:"TXID"`
+	ChaincodeID string `json:"chaincodeID"`
+	EventName   string `json:"eventName"`
+	Payload     string `json:"payload"`
+	BlockNumber uint64 `json:"blockNumber"`
+	SourceURL   string `json:"sourceURL"`
+}
+
+// BenchmarkMessage the progress or the result of a benchmark.
+type BenchmarkMessage struct {
+	Type   string               `json:"type"` // progress or result
+	Report *api.BenchmarkReport `json:"report"`
+}
+
+// wsError the error sent by Fablet in a websocket.
+type wsError struct {
+	Error string `json:"error"`
+}
+
+func (c *Client) wsURL(path string) string {
+	url := c.BaseURL + path
+	if strings.HasPrefix(url, "https://") {
+		return "wss://" + strings.TrimPrefix(url, "https://")
+	}
+	return "ws://" + strings.TrimPrefix(url, "http://")
+}
+
+// dial to open the websocket and send the request.
+func (c *Client) dial(ctx context.Context, path string, reqBody interface{}) (*websocket.Conn, error) {
+	header := http.Header{}
+	if c.User != "" {
+		header.Set("X-Fablet-User", c.User)
+	}
+	wsConn, _, err := websocket.DefaultDialer.DialContext(ctx, c.wsURL(path), header)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Error occurred when connecting to %s.", path)
+	}
+	if err := wsConn.WriteJSON(reqBody); err != nil {
+		wsConn.Close()
+		return nil, errors.WithMessagef(err, "Error occurred when sending request to %s.", path)
+	}
+	return wsConn, nil
+}
+
+// closeOnDone to close the websocket once the context is done, to stop the reading.
+// The returned function is to be called when the websocket is no longer used.
+func closeOnDone(ctx context.Context, wsConn *websocket.Conn) func() {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			wsConn.Close()
+		case <-done:
+		}
+	}()
+	return func() { close(done) }
+}
+
+// readMessage to read a message, an error message of Fablet is returned as an Error.
+func readMessage(wsConn *websocket.Conn, message interface{}) error {
+	_, content, err := wsConn.ReadMessage()
+	if err != nil {
+		return err
+	}
+	resErr := &wsError{}
+	if err := json.Unmarshal(content, resErr); err == nil && resErr.Error != "" {
+		return &Error{ResCode: http.StatusInternalServerError, Message: resErr.Error}
+	}
+	return json.Unmarshal(content, message)
+}
+
+// subscribe to read messages of an event websocket until the context is done.
+// The websocket is reconnected with an exponential backoff if it is closed, since the server closes it when the event connection is lost.
+// An error is sent to errChan for each failure, and both channels are closed at the end.
+func (c *Client) subscribe(ctx context.Context, path string, reqBody interface{}, newMessage func() interface{}, send func(interface{}), errChan chan<- error) {
+	defer close(errChan)
+
+	interval := MinReconnectInterval
+	for {
+		wsConn, err := c.dial(ctx, path, reqBody)
+		if err == nil {
+			release := closeOnDone(ctx, wsConn)
+			for {
+				message := newMessage()
+				if err = readMessage(wsConn, message); err != nil {
+					break
+				}
+				// The connection works, so start the backoff over.
+				interval = MinReconnectInterval
+				send(message)
+			}
+			release()
+			wsConn.Close()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case errChan <- err:
+		default:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+		interval *= 2
+		if interval > MaxReconnectInterval {
+			interval = MaxReconnectInterval
+		}
+	}
+}
+
+// BlockEvents to listen block events of a channel, until the context is done.
+// The errors channel receives failures of the websocket, which is reconnected automatically.
+func (c *Client) BlockEvents(ctx context.Context, channelID string) (<-chan *BlockEvent, <-chan error) {
+	eventChan := make(chan *BlockEvent, 1)
+	errChan := make(chan error, 1)
+	reqBody := &struct {
+		baseRequest
+		ChannelID string `json:"channelID"`
+	}{c.base(), channelID}
+
+	go func() {
+		defer close(eventChan)
+		c.subscribe(ctx, "/event/blockevent", reqBody,
+			func() interface{} { return &BlockEvent{} },
+			func(message interface{}) {
+				select {
+				case eventChan <- message.(*BlockEvent):
+				case <-ctx.Done():
+				}
+			}, errChan)
+	}()
+	return eventChan, errChan
+}
+
+// ChaincodeEvents to listen chaincode events matching the filter, until the context is done.
+// The errors channel receives failures of the websocket, which is reconnected automatically.
+func (c *Client) ChaincodeEvents(ctx context.Context, channelID string, chaincodeID string, eventFilter string) (<-chan *ChaincodeEvent, <-chan error) {
+	eventChan := make(chan *ChaincodeEvent, 1)
+	errChan := make(chan error, 1)
+	reqBody := &struct {
+		baseRequest
+		ChannelID   string `json:"channelID"`
+		ChaincodeID string `json:"chaincodeID"`
+		EventFilter string `json:"eventFilter"`
+	}{c.base(), channelID, chaincodeID, eventFilter}
+
+	go func() {
+		defer close(eventChan)
+		c.subscribe(ctx, "/event/chaincodeevent", reqBody,
+			func() interface{} { return &ChaincodeEvent{} },
+			func(message interface{}) {
+				select {
+				case eventChan <- message.(*ChaincodeEvent):
+				case <-ctx.Done():
+				}
+			}, errChan)
+	}()
+	return eventChan, errChan
+}
+
+// TxStatusEvents to receive the status of a transaction whenever it changes.
+// The status channel is closed when all peers committed, the tracking timed out, or the context is done.
+func (c *Client) TxStatusEvents(ctx context.Context, channelID string, txID string) (<-chan *api.TxStatus, error) {
+	wsConn, err := c.dial(ctx, "/event/txstatus", c.txStatusRequest(channelID, txID))
+	if err != nil {
+		return nil, err
+	}
+	release := closeOnDone(ctx, wsConn)
+	statusChan := make(chan *api.TxStatus, 1)
+	go func() {
+		defer close(statusChan)
+		defer wsConn.Close()
+		defer release()
+		for {
+			status := &api.TxStatus{}
+			if err := readMessage(wsConn, status); err != nil {
+				return
+			}
+			select {
+			case statusChan <- status:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return statusChan, nil
+}
+
+// RunBenchmark to run a benchmark, it blocks until the result, and calls progress for each progress report if not nil.
+// The benchmark is stopped when the context is done, and the result of the finished part is returned.
+func (c *Client) RunBenchmark(ctx context.Context, options api.BenchmarkOptions, progress func(*api.BenchmarkReport)) (*api.BenchmarkReport, error) {
+	// The websocket is not closed with ctx, to still receive the result after the stop.
+	wsConn, err := c.dial(ctx, "/event/benchmark", &struct {
+		baseRequest
+		Options api.BenchmarkOptions `json:"options"`
+	}{c.base(), options})
+	if err != nil {
+		return nil, err
+	}
+	defer wsConn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			wsConn.WriteJSON(map[string]string{"action": "stop"})
+		case <-done:
+		}
+	}()
+
+	for {
+		message := &BenchmarkMessage{}
+		if err := readMessage(wsConn, message); err != nil {
+			if _, ok := err.(*Error); ok {
+				return nil, err
+			}
+			return nil, errors.WithMessage(err, "Error occurred when reading benchmark result.")
+		}
+		if message.Type == "result" {
+			return message.Report, nil
+		}
+		if progress != nil {
+			progress(message.Report)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package client
+
+import "github.com/IBM/fablet/api"
+
+// Workspace a named network on the server, with its connection profile, identities and settings.
+type Workspace struct {
+	Name            string                 `json:"name"`
+	Description     string                 `json:"description"`
+	ConnProfile     string                 `json:"connProfile"`
+	Identities      []*WorkspaceIdentity   `json:"identities"`
+	DefaultIdentity string                 `json:"defaultIdentity"`
+	Settings        map[string]interface{} `json:"settings"`
+	UpdateTime      int64                  `json:"updateTime"`
+}
+
+// WorkspaceIdentity an identity of a workspace. The private key, the passphrase and the PIN are not responded, and kept if they are empty when saving.
+type WorkspaceIdentity struct {
+	Label         string            `json:"label"`
+	MSPID         string            `json:"MSPID"`
+	CertContent   string            `json:"certContent"`
+	PrvKeyContent string            `json:"prvKeyContent,omitempty"`
+	Passphrase    string            `json:"passphrase,omitempty"`
+	PKCS11        *api.PKCS11Config `json:"PKCS11,omitempty"`
+}
+
+// WorkspaceList result of /workspace/list.
+type WorkspaceList struct {
+	Workspaces []*Workspace `json:"workspaces"`
+	Current    string       `json:"current"`
+}
+
+// WorkspaceOverview the network of a workspace, or the error of it.
+type WorkspaceOverview struct {
+	Name           string                    `json:"name"`
+	Peers          []*api.Peer               `json:"peers"`
+	PeerStatuses   map[string]api.PeerStatus `json:"peerStatuses"`
+	Channels       []string                  `json:"channels"`
+	ChannelLedgers map[string]*api.Ledger    `json:"channelLedgers"`
+	ErrCode        string                    `json:"errCode"`
+	Error          string                    `json:"error"`
+}
+
+// Workspaces to list workspaces and the current one.
+func (c *Client) Workspaces() (*WorkspaceList, error) {
+	result := &WorkspaceList{}
+	return result, c.call("/workspace/list", struct{}{}, result)
+}
+
+// SaveWorkspace to create or update a workspace.
+func (c *Client) SaveWorkspace(ws *Workspace) (*Workspace, error) {
+	result := &struct {
+		Workspace *Workspace `json:"workspace"`
+	}{}
+	return result.Workspace, c.call("/workspace/save", &struct {
+		Workspace *Workspace `json:"workspace"`
+	}{ws}, result)
+}
+
+// DeleteWorkspace to delete a workspace.
+func (c *Client) DeleteWorkspace(name string) (*WorkspaceList, error) {
+	return c.updateWorkspace("/workspace/delete", name)
+}
+
+// SwitchWorkspace to switch the current workspace, which clients select, requests still name their workspace.
+func (c *Client) SwitchWorkspace(name string) (*WorkspaceList, error) {
+	return c.updateWorkspace("/workspace/switch", name)
+}
+
+func (c *Client) updateWorkspace(path string, name string) (*WorkspaceList, error) {
+	result := &WorkspaceList{}
+	return result, c.call(path, &struct {
+		Name string `json:"name"`
+	}{name}, result)
+}
+
+// CompareWorkspaces to discover networks of workspaces side by side.
+func (c *Client) CompareWorkspaces(names []string) ([]*WorkspaceOverview, error) {
+	result := &struct {
+		Overviews []*WorkspaceOverview `json:"overviews"`
+	}{}
+	return result.Overviews, c.call("/workspace/compare", &struct {
+		Names []string `json:"names"`
+	}{names}, result)
+}
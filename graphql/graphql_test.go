@@ -0,0 +1,157 @@
+package graphql
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+type testBase struct {
+	ID string `json:"id"`
+}
+
+type testItem struct {
+	testBase
+	Size     int      `json:"size"`
+	Children []string `json:"-"`
+}
+
+// newTestSchema a schema of items, of which children are loaded in batch, the batch calls are counted.
+func newTestSchema(t *testing.T, batchCalls *int) graphql.Schema {
+	items := map[string]*testItem{
+		"a": {testBase: testBase{ID: "a"}, Size: 1, Children: []string{"b", "c"}},
+		"b": {testBase: testBase{ID: "b"}, Size: 2, Children: []string{"c"}},
+		"c": {testBase: testBase{ID: "c"}, Size: 3},
+	}
+	loader := NewLoader(func(keys []interface{}) (map[interface{}]interface{}, error) {
+		*batchCalls++
+		values := map[interface{}]interface{}{}
+		for _, key := range keys {
+			if item, ok := items[key.(string)]; ok {
+				values[key] = item
+			}
+		}
+		return values, nil
+	})
+
+	var item *graphql.Object
+	item = NewObject(graphql.ObjectConfig{Name: "Item", Fields: graphql.FieldsThunk(func() graphql.Fields {
+		return graphql.Fields{
+			"id":   {Type: graphql.NewNonNull(graphql.ID)},
+			"size": {Type: graphql.Int},
+			"children": {Type: graphql.NewList(item), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				keys := []interface{}{}
+				for _, child := range p.Source.(*testItem).Children {
+					keys = append(keys, child)
+				}
+				return loader.LoadMany(keys), nil
+			}},
+		}
+	})})
+	query := NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+		"item": {Type: item, Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return loader.Load(p.Args["id"].(string)), nil
+			}},
+		"sizes": {Type: graphql.NewList(graphql.Int), Args: graphql.FieldConfigArgument{"max": {Type: graphql.Int, DefaultValue: 2}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				sizes := []int{}
+				for i := 1; i <= p.Args["max"].(int); i++ {
+					sizes = append(sizes, i)
+				}
+				return sizes, nil
+			}},
+	}})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: query})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+// assertData to compare the data as JSON values, since fields of the data are not in the order of the query.
+func assertData(t *testing.T, result *graphql.Result, expected string) {
+	var expectedData interface{}
+	if err := json.Unmarshal([]byte(expected), &expectedData); err != nil {
+		t.Fatal(err)
+	}
+	dataJSON, _ := json.Marshal(result.Data)
+	var data interface{}
+	json.Unmarshal(dataJSON, &data)
+	if !reflect.DeepEqual(data, expectedData) {
+		t.Errorf("expected %s, but got %s", expected, dataJSON)
+	}
+}
+
+func TestLoader(t *testing.T) {
+	batchCalls := 0
+	schema := newTestSchema(t, &batchCalls)
+
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: `
+		query Items($id: ID!) {
+			first: item(id: $id) { id size children { id children { id } } }
+			second: item(id: "b") { id }
+			sizes
+		}`, VariableValues: map[string]interface{}{"id": "a"}})
+	if len(result.Errors) > 0 {
+		t.Fatal(result.Errors)
+	}
+	assertData(t, result, `{"first":{"id":"a","size":1,"children":[{"id":"b","children":[{"id":"c"}]},{"id":"c","children":[]}]},`+
+		`"second":{"id":"b"},"sizes":[1,2]}`)
+	// Items of a level are loaded in one batch: a and b, then children b and c, and c is cached.
+	if batchCalls != 2 {
+		t.Errorf("expected 2 batch calls, but got %d", batchCalls)
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	loader := NewLoader(func(keys []interface{}) (map[interface{}]interface{}, error) {
+		return map[interface{}]interface{}{"a": 1, "b": errNotFound}, errBatch
+	})
+	a, b, c := loader.Load("a"), loader.Load("b"), loader.Load("c")
+	if value, err := a(); value != 1 || err != nil {
+		t.Errorf("expected 1, but got %v %v", value, err)
+	}
+	if _, err := b(); err != errNotFound {
+		t.Errorf("expected the error of the key, but got %v", err)
+	}
+	if _, err := c(); err != errBatch {
+		t.Errorf("expected the error of the batch, but got %v", err)
+	}
+}
+
+type testError string
+
+func (e testError) Error() string { return string(e) }
+
+const (
+	errNotFound = testError("not found")
+	errBatch    = testError("batch")
+)
+
+func TestIntrospection(t *testing.T) {
+	batchCalls := 0
+	result := graphql.Do(graphql.Params{Schema: newTestSchema(t, &batchCalls),
+		RequestString: `{ __schema { queryType { name } } __type(name: "Item") { fields { name } } }`})
+	if len(result.Errors) > 0 {
+		t.Fatal(result.Errors)
+	}
+	assertData(t, result, `{"__schema":{"queryType":{"name":"Query"}},`+
+		`"__type":{"fields":[{"name":"children"},{"name":"id"},{"name":"size"}]}}`)
+}
+
+func TestPrintSchema(t *testing.T) {
+	batchCalls := 0
+	sdl := PrintSchema(newTestSchema(t, &batchCalls))
+	for _, expected := range []string{"type Query {", "item(id: ID!): Item", "sizes(max: Int = 2): [Int]", "children: [Item]"} {
+		if !strings.Contains(sdl, expected) {
+			t.Errorf("expected %s in the SDL:\n%s", expected, sdl)
+		}
+	}
+	if strings.Contains(sdl, "__") || strings.Contains(sdl, "scalar String") {
+		t.Errorf("expected no introspection types and built-in scalars in the SDL:\n%s", sdl)
+	}
+}
@@ -0,0 +1,93 @@
+package graphql
+
+import "sync"
+
+// Thunk a value resolved later, which a resolver of graphql-go returns. Thunks are called level by level
+// after all resolvers of the level, so that loaders get all keys of the level before fetching.
+type Thunk = func() (interface{}, error)
+
+// BatchFunc to fetch values of the keys in one batch. A value which is an error is the error of the key.
+// Keys absent in the result are resolved as null, or as the error if it is not nil, so that an error of some keys
+// doesn't fail the others.
+type BatchFunc func(keys []interface{}) (map[interface{}]interface{}, error)
+
+// Loader to load values by keys in batch, and cache the values. Keys loaded in the same level of a query
+// are fetched by one call of the batch function, so nested fields don't fetch once per parent.
+// A loader is created for each request, since values are cached in it.
+type Loader struct {
+	batch   BatchFunc
+	lock    sync.Mutex
+	cache   map[interface{}]*loaderResult
+	pending []interface{}
+}
+
+type loaderResult struct {
+	done  bool
+	value interface{}
+	err   error
+}
+
+// NewLoader to create a loader with the batch function.
+func NewLoader(batch BatchFunc) *Loader {
+	return &Loader{batch: batch, cache: map[interface{}]*loaderResult{}}
+}
+
+// Load to load the value of the key, it is fetched when the thunk is called.
+func (l *Loader) Load(key interface{}) Thunk {
+	l.lock.Lock()
+	result, ok := l.cache[key]
+	if !ok {
+		result = &loaderResult{}
+		l.cache[key] = result
+		l.pending = append(l.pending, key)
+	}
+	l.lock.Unlock()
+
+	return func() (interface{}, error) {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		if !result.done {
+			l.dispatch()
+		}
+		return result.value, result.err
+	}
+}
+
+// LoadMany to load values of the keys, the values are in the order of the keys.
+func (l *Loader) LoadMany(keys []interface{}) Thunk {
+	thunks := make([]Thunk, len(keys))
+	for idx, key := range keys {
+		thunks[idx] = l.Load(key)
+	}
+	return func() (interface{}, error) {
+		values := make([]interface{}, len(keys))
+		for idx, thunk := range thunks {
+			value, err := thunk()
+			if err != nil {
+				return nil, err
+			}
+			values[idx] = value
+		}
+		return values, nil
+	}
+}
+
+// dispatch to fetch all pending keys, with the lock held.
+func (l *Loader) dispatch() {
+	keys := l.pending
+	l.pending = nil
+	values, err := l.batch(keys)
+	for _, key := range keys {
+		result := l.cache[key]
+		result.done = true
+		if value, ok := values[key]; ok {
+			if keyErr, isErr := value.(error); isErr {
+				result.err = keyErr
+			} else {
+				result.value = value
+			}
+		} else {
+			result.err = err
+		}
+	}
+}
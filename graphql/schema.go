@@ -0,0 +1,153 @@
+// Package graphql helps to build schemas of github.com/graphql-go/graphql backed by Go structs,
+// with loaders to fetch values of nested fields by keys.
+package graphql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// NewObject to create an object type, fields without a resolver are got from the source by ResolveField.
+// The fields can be a graphql.FieldsThunk for types which refer to each other.
+func NewObject(config graphql.ObjectConfig) *graphql.Object {
+	switch fields := config.Fields.(type) {
+	case graphql.Fields:
+		config.Fields = withResolveField(fields)
+	case graphql.FieldsThunk:
+		config.Fields = graphql.FieldsThunk(func() graphql.Fields {
+			return withResolveField(fields())
+		})
+	}
+	return graphql.NewObject(config)
+}
+
+func withResolveField(fields graphql.Fields) graphql.Fields {
+	for _, field := range fields {
+		if field.Resolve == nil {
+			field.Resolve = ResolveField
+		}
+	}
+	return fields
+}
+
+// ResolveField to get the field from a map, or from a struct by the name in the json tag or the field name.
+// Fields of embedded structs are got as encoding/json does, which the default resolver of graphql-go doesn't.
+func ResolveField(p graphql.ResolveParams) (interface{}, error) {
+	if m, ok := p.Source.(map[string]interface{}); ok {
+		return m[p.Info.FieldName], nil
+	}
+	value, _ := structField(reflect.ValueOf(p.Source), p.Info.FieldName)
+	return value, nil
+}
+
+func structField(rv reflect.Value, name string) (interface{}, bool) {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, false
+	}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tagName := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if sf.Anonymous && tagName == "" {
+			if value, ok := structField(rv.Field(i), name); ok {
+				return value, true
+			}
+			continue
+		}
+		if sf.PkgPath != "" || tagName == "-" {
+			continue
+		}
+		if tagName == name || (tagName == "" && strings.EqualFold(sf.Name, name)) {
+			return rv.Field(i).Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// PrintSchema to print the schema in the schema definition language, types and fields are sorted by name.
+// Introspection types and built-in scalars are omitted.
+func PrintSchema(schema graphql.Schema) string {
+	names := []string{}
+	for name := range schema.TypeMap() {
+		if !strings.HasPrefix(name, "__") && !isBuiltInScalar(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "schema {\n  query: %s\n}\n", schema.QueryType().Name())
+	for _, name := range names {
+		switch t := schema.TypeMap()[name].(type) {
+		case *graphql.Scalar:
+			b.WriteString("\n")
+			writeDescription(b, "", t.Description())
+			fmt.Fprintf(b, "scalar %s\n", t.Name())
+		case *graphql.Object:
+			b.WriteString("\n")
+			writeDescription(b, "", t.Description())
+			fmt.Fprintf(b, "type %s {\n", t.Name())
+			fields := t.Fields()
+			fieldNames := []string{}
+			for fieldName := range fields {
+				fieldNames = append(fieldNames, fieldName)
+			}
+			sort.Strings(fieldNames)
+			for _, fieldName := range fieldNames {
+				writeField(b, fields[fieldName])
+			}
+			b.WriteString("}\n")
+		}
+	}
+	return b.String()
+}
+
+func writeField(b *strings.Builder, field *graphql.FieldDefinition) {
+	writeDescription(b, "  ", field.Description)
+	b.WriteString("  " + field.Name)
+	if len(field.Args) > 0 {
+		args := append([]*graphql.Argument{}, field.Args...)
+		sort.Slice(args, func(i, j int) bool { return args[i].Name() < args[j].Name() })
+		argDefs := []string{}
+		for _, arg := range args {
+			argDef := arg.Name() + ": " + arg.Type.String()
+			if arg.DefaultValue != nil {
+				argDef += " = " + formatValue(arg.DefaultValue)
+			}
+			argDefs = append(argDefs, argDef)
+		}
+		b.WriteString("(" + strings.Join(argDefs, ", ") + ")")
+	}
+	b.WriteString(": " + field.Type.String() + "\n")
+}
+
+func isBuiltInScalar(name string) bool {
+	switch name {
+	case graphql.String.Name(), graphql.Int.Name(), graphql.Float.Name(), graphql.Boolean.Name(), graphql.ID.Name():
+		return true
+	}
+	return false
+}
+
+func writeDescription(b *strings.Builder, indent string, description string) {
+	if description != "" {
+		fmt.Fprintf(b, "%s%q\n", indent, description)
+	}
+}
+
+func formatValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return fmt.Sprintf("%q", str)
+	}
+	return fmt.Sprintf("%v", value)
+}
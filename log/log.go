@@ -52,3 +52,6 @@ func (logger *FabletLogger) Errorf(format string, msg ...interface{}) {
 func (logger *FabletLogger) Warn(msg ...interface{}) {
 	log.Println("[WRN]", fmt.Sprint(msg...))
 }
+func (logger *FabletLogger) Warnf(format string, msg ...interface{}) {
+	log.Println("[WRN]", fmt.Sprintf(format, msg...))
+}
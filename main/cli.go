@@ -0,0 +1,626 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/IBM/fablet/api"
+	"github.com/pkg/errors"
+)
+
+const (
+	// OutputJSON to print the result as JSON.
+	OutputJSON = "json"
+	// OutputTable to print the result as table.
+	OutputTable = "table"
+)
+
+// Command a subcommand of the command line.
+type Command struct {
+	Name        string
+	Description string
+	Run         func(args []string) error
+}
+
+func getCommands() []*Command {
+	return []*Command{
+		{"discover", "Discover the network: peers, channels, chaincodes and ledgers", runDiscover},
+		{"query", "Query a chaincode", runQuery},
+		{"invoke", "Invoke a chaincode and wait for the commit", runInvoke},
+		{"block", "Query blocks by number, or a block by transaction ID or hash", runBlock},
+		{"ledger", "Query the ledger height and current block hash of a channel", runLedger},
+		{"install", "Install a chaincode to peers", runInstall},
+		{"instantiate", "Instantiate or upgrade a chaincode in a channel", runInstantiate},
+		{"channel", "Channel operations: join, create", runChannel},
+		{"profile", "Connection profile operations: convert, export", runProfile},
+	}
+}
+
+// isCommand if the arguments are for a subcommand rather than the server.
+func isCommand(args []string) bool {
+	return len(args) > 0 && !strings.HasPrefix(args[0], "-")
+}
+
+// runCommand to run the subcommand, returns the exit code.
+func runCommand(args []string) int {
+	for _, cmd := range getCommands() {
+		if cmd.Name == args[0] {
+			err := cmd.Run(args[1:])
+			if err == flag.ErrHelp {
+				return 0
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+				return 1
+			}
+			return 0
+		}
+	}
+	printUsage(os.Stderr)
+	if args[0] == "help" {
+		return 0
+	}
+	return 2
+}
+
+func printUsage(writer io.Writer) {
+	fmt.Fprintln(writer, "Usage:")
+	fmt.Fprintln(writer, "  fablet [-addr address] [-port port] [-cert file -key file]   Start the server")
+	fmt.Fprintln(writer, "  fablet <command> [flags]                                   Run a command, -h for flags of the command")
+	fmt.Fprintln(writer, "\nCommands:")
+	tw := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+	for _, cmd := range getCommands() {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.Name, cmd.Description)
+	}
+	tw.Flush()
+}
+
+// PKCS11PINEnv the environment variable of the PIN of the token, which is not given by a flag to be hidden from the process list.
+const PKCS11PINEnv = "FABLET_PKCS11_PIN"
+
+// KeyPassphraseEnv the environment variable of the passphrase of the encrypted private key of -key.
+const KeyPassphraseEnv = "FABLET_KEY_PASSPHRASE"
+
+// connFlags flags of the connection, shared by all commands.
+type connFlags struct {
+	profile     *string
+	profileType *string
+	mspID       *string
+	cert        *string
+	key         *string
+	label       *string
+	pkcs11Lib   *string
+	pkcs11Slot  *uint
+	pkcs11Label *string
+	discovery   *bool
+	output      *string
+}
+
+func newFlagSet(name string) (*flag.FlagSet, *connFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	cf := &connFlags{
+		profile:     fs.String("profile", "", "Connection profile file (required)"),
+		profileType: fs.String("profileType", "", "Connection profile type: yaml or json, detected by the content if empty"),
+		mspID:       fs.String("mspid", "", "MSP ID of the identity (required)"),
+		cert:        fs.String("cert", "", "Certificate file of the identity (required)"),
+		key:         fs.String("key", "", "Private key file of the identity (required), the passphrase is of env "+KeyPassphraseEnv+" if it is encrypted"),
+		label:       fs.String("label", "", "Label of the identity"),
+		pkcs11Lib:   fs.String("pkcs11lib", "", "PKCS#11 library of the token with the private key instead of -key, the PIN is of env "+PKCS11PINEnv),
+		pkcs11Slot:  fs.Uint("pkcs11slot", 0, "Slot of the token with the private key"),
+		pkcs11Label: fs.String("pkcs11label", "", "Label of the private key in the token"),
+		discovery:   fs.Bool("discovery", true, "Use the discovery service"),
+		output:      fs.String("output", OutputJSON, "Output format: json or table"),
+	}
+	return fs, cf
+}
+
+func (cf *connFlags) connect() (*api.NetworkConnection, error) {
+	if *cf.output != OutputJSON && *cf.output != OutputTable {
+		return nil, errors.Errorf("%s is not a valid output format", *cf.output)
+	}
+	if *cf.profile == "" || *cf.mspID == "" || *cf.cert == "" || (*cf.key == "" && *cf.pkcs11Lib == "") {
+		return nil, errors.New("-profile, -mspid, -cert and -key (or -pkcs11lib) are required")
+	}
+	profile, err := ioutil.ReadFile(*cf.profile)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when reading the connection profile.")
+	}
+	cert, err := ioutil.ReadFile(*cf.cert)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when reading the certificate.")
+	}
+	participant := &api.Participant{Label: *cf.label, MSPID: *cf.mspID, Cert: cert, Passphrase: os.Getenv(KeyPassphraseEnv)}
+	if *cf.pkcs11Lib != "" {
+		// The library is given by the user of the command.
+		api.PKCS11Libraries = []string{*cf.pkcs11Lib}
+		participant.PKCS11 = &api.PKCS11Config{Library: *cf.pkcs11Lib, Slot: *cf.pkcs11Slot, PIN: os.Getenv(PKCS11PINEnv), KeyLabel: *cf.pkcs11Label}
+	} else if participant.PrivateKey, err = ioutil.ReadFile(*cf.key); err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when reading the private key.")
+	}
+	return api.NewConnection(
+		&api.ConnectionProfile{Config: profile, ConfigType: *cf.profileType},
+		participant,
+		*cf.discovery)
+}
+
+// splitList to split a comma separated list, empty items are ignored.
+func splitList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseArguments to get the arguments from a JSON array, or the remaining command line arguments.
+func parseArguments(argsJSON string, remaining []string) ([]string, error) {
+	if argsJSON == "" {
+		return remaining, nil
+	}
+	args := []string{}
+	if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+		return nil, errors.WithMessage(err, "-args should be a JSON array of strings.")
+	}
+	return args, nil
+}
+
+func printJSON(result interface{}) error {
+	content, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(content))
+	return nil
+}
+
+// printTable to print the rows with header, columns are aligned.
+func printTable(header []string, rows [][]string) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+}
+
+func runDiscover(args []string) error {
+	fs, cf := newFlagSet("discover")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	conn, err := cf.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	overview, err := api.DiscoverNetworkOverview(conn)
+	if err != nil {
+		return err
+	}
+	if *cf.output == OutputJSON {
+		return printJSON(overview)
+	}
+
+	rows := [][]string{}
+	for _, peer := range overview.Peers {
+		rows = append(rows, []string{peer.Name, peer.URL, peer.MSPID, strings.Join(peer.Channels.StringList(), ","),
+			overview.EndpointStatuses[peer.Name].String()})
+	}
+	printTable([]string{"PEER", "URL", "MSPID", "CHANNELS", "STATUS"}, rows)
+	fmt.Println()
+
+	rows = [][]string{}
+	for _, channel := range overview.Channels {
+		height := ""
+		if ledger, ok := overview.ChannelLedgers[channel.ChannelID]; ok && ledger != nil {
+			height = fmt.Sprint(ledger.Height)
+		}
+		ccs := []string{}
+		for _, cc := range overview.ChannelChainCodes[channel.ChannelID] {
+			ccs = append(ccs, cc.Name+":"+cc.Version)
+		}
+		rows = append(rows, []string{channel.ChannelID, height, strings.Join(channel.Peers.StringList(), ","), strings.Join(ccs, ",")})
+	}
+	printTable([]string{"CHANNEL", "HEIGHT", "PEERS", "CHAINCODES"}, rows)
+	return nil
+}
+
+func runQuery(args []string) error {
+	return runExecute("query", api.ChaincodeOperTypeQuery, args)
+}
+
+func runInvoke(args []string) error {
+	return runExecute("invoke", api.ChaincodeOperTypeExecute, args)
+}
+
+func runExecute(name string, operType api.ChaincodeOperType, args []string) error {
+	fs, cf := newFlagSet(name)
+	channelID := fs.String("channel", "", "Channel ID (required)")
+	chaincodeID := fs.String("chaincode", "", "Chaincode name (required)")
+	funcName := fs.String("fcn", "", "Function name (required)")
+	argsJSON := fs.String("args", "", "Arguments as a JSON array, or given after all flags")
+	targets := fs.String("targets", "", "Comma separated peers, selected by the strategy if empty")
+	strategy := fs.String("strategy", api.DefaultSelectionStrategy, "Endorser selection strategy: myorg, latency or height")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *channelID == "" || *chaincodeID == "" || *funcName == "" {
+		return errors.New("-channel, -chaincode and -fcn are required")
+	}
+	ccArgs, err := parseArguments(*argsJSON, fs.Args())
+	if err != nil {
+		return err
+	}
+	conn, err := cf.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := api.ValidateChaincodeArguments(conn, *channelID, *chaincodeID, *funcName, ccArgs); err != nil {
+		return err
+	}
+	peers := splitList(*targets)
+	if len(peers) < 1 {
+		peers = api.PlanEndorsers(conn, *channelID, *chaincodeID, *strategy).Targets()
+	}
+
+	begin := time.Now()
+	response, err := api.ExecuteChaincode(conn, *channelID, *chaincodeID, operType, peers, *funcName, ccArgs)
+	if err != nil {
+		return err
+	}
+	result := map[string]interface{}{
+		"transactionID":    response.TransactionID,
+		"txValidationCode": response.TxValidationCode.String(),
+		"chaincodeStatus":  response.ChaincodeStatus,
+		"payload":          string(response.Payload),
+		"targets":          peers,
+		"duration":         time.Since(begin).Nanoseconds() / 1000000,
+	}
+	if operType == api.ChaincodeOperTypeQuery {
+		delete(result, "txValidationCode")
+	}
+	if *cf.output == OutputJSON {
+		return printJSON(result)
+	}
+	rows := [][]string{}
+	for _, k := range []string{"transactionID", "txValidationCode", "chaincodeStatus", "payload", "targets", "duration"} {
+		if v, ok := result[k]; ok {
+			rows = append(rows, []string{k, fmt.Sprint(v)})
+		}
+	}
+	printTable([]string{"FIELD", "VALUE"}, rows)
+	return nil
+}
+
+func runBlock(args []string) error {
+	fs, cf := newFlagSet("block")
+	channelID := fs.String("channel", "", "Channel ID (required)")
+	number := fs.Uint64("number", 0, "Number of the first block")
+	length := fs.Uint64("len", 1, "Count of blocks")
+	txID := fs.String("txid", "", "Query the block by transaction ID")
+	hash := fs.String("hash", "", "Query the block by block hash")
+	targets := fs.String("targets", "", "Comma separated peers")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *channelID == "" {
+		return errors.New("-channel is required")
+	}
+	conn, err := cf.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	peers := splitList(*targets)
+	var blocks []*api.Block
+	switch {
+	case *txID != "":
+		block, err := api.QueryBlockByTxID(conn, *channelID, peers, *txID)
+		if err != nil {
+			return err
+		}
+		blocks = []*api.Block{block}
+	case *hash != "":
+		block, err := api.QueryBlockByHash(conn, *channelID, peers, *hash)
+		if err != nil {
+			return err
+		}
+		blocks = []*api.Block{block}
+	default:
+		if blocks, err = api.QueryBlock(conn, *channelID, peers, *number, *length); err != nil {
+			return err
+		}
+	}
+
+	if *cf.output == OutputJSON {
+		return printJSON(blocks)
+	}
+	rows := [][]string{}
+	for _, block := range blocks {
+		for idx, tx := range block.Transactions {
+			chaincode := ""
+			if len(tx.Actions) > 0 {
+				chaincode = tx.Actions[0].ChaincodeName
+			}
+			rows = append(rows, []string{fmt.Sprint(block.Number), fmt.Sprint(idx), tx.TxID, chaincode, tx.ValidationCode,
+				time.Unix(0, block.Time*1000000).Format(time.RFC3339)})
+		}
+	}
+	printTable([]string{"BLOCK", "TX", "TXID", "CHAINCODE", "VALIDATION", "TIME"}, rows)
+	return nil
+}
+
+func runLedger(args []string) error {
+	fs, cf := newFlagSet("ledger")
+	channelID := fs.String("channel", "", "Channel ID (required)")
+	targets := fs.String("targets", "", "Comma separated peers")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *channelID == "" {
+		return errors.New("-channel is required")
+	}
+	conn, err := cf.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ledger, err := api.QueryLedger(conn, *channelID, splitList(*targets))
+	if err != nil {
+		return err
+	}
+	if *cf.output == OutputJSON {
+		return printJSON(ledger)
+	}
+	printTable([]string{"CHANNEL", "HEIGHT", "CURRENT BLOCK HASH", "ENDORSER"},
+		[][]string{{*channelID, fmt.Sprint(ledger.Height), ledger.CurrentBlockHash, ledger.Endorser}})
+	return nil
+}
+
+func runInstall(args []string) error {
+	fs, cf := newFlagSet("install")
+	name := fs.String("name", "", "Chaincode name (required)")
+	version := fs.String("version", "", "Chaincode version (required)")
+	ccType := fs.String("type", api.ChaincodeType_GOLANG, "Chaincode type: golang, node or java")
+	path := fs.String("path", "", "For golang, the package path under <gopath>/src; otherwise the chaincode folder (required)")
+	gopath := fs.String("gopath", os.Getenv("GOPATH"), "Go path of golang chaincode")
+	targets := fs.String("targets", "", "Comma separated peers, all peers of my organization if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *name == "" || *version == "" || *path == "" {
+		return errors.New("-name, -version and -path are required")
+	}
+	conn, err := cf.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	cc := &api.Chaincode{Name: *name, Version: *version, Path: *path, Type: *ccType}
+	if *ccType == api.ChaincodeType_GOLANG {
+		cc.BasePath = *gopath
+	}
+	peers := splitList(*targets)
+	if len(peers) < 1 {
+		peers = orgPeers(conn)
+	}
+	results, err := api.InstallChaincode(conn, cc, peers)
+	if err != nil && results == nil {
+		return err
+	}
+
+	if *cf.output == OutputJSON {
+		if printErr := printJSON(results); printErr != nil {
+			return printErr
+		}
+	} else {
+		rows := [][]string{}
+		for peer, result := range results {
+			rows = append(rows, []string{peer, fmt.Sprint(result.Code), result.Message})
+		}
+		printTable([]string{"PEER", "CODE", "MESSAGE"}, rows)
+	}
+	// The installation might fail on some peers.
+	return err
+}
+
+// orgPeers returns the peers of the organization of the identity.
+func orgPeers(conn *api.NetworkConnection) []string {
+	peers := []string{}
+	for _, peer := range conn.Peers {
+		if peer.MSPID == conn.Participant.MSPID {
+			peers = append(peers, peer.URL)
+		}
+	}
+	sort.Strings(peers)
+	return peers
+}
+
+func runInstantiate(args []string) error {
+	fs, cf := newFlagSet("instantiate")
+	channelID := fs.String("channel", "", "Channel ID (required)")
+	name := fs.String("name", "", "Chaincode name (required)")
+	version := fs.String("version", "", "Chaincode version (required)")
+	ccType := fs.String("type", api.ChaincodeType_GOLANG, "Chaincode type: golang, node or java")
+	path := fs.String("path", "", "Chaincode path, the same as installation")
+	policy := fs.String("policy", "", "Endorsement policy, e.g. OR('Org1MSP.peer','Org2MSP.peer') (required)")
+	argsJSON := fs.String("args", "", "Constructor arguments as a JSON array, or given after all flags")
+	target := fs.String("target", "", "Peer to endorse the instantiation (required)")
+	orderer := fs.String("orderer", "", "Orderer (required)")
+	upgrade := fs.Bool("upgrade", false, "Upgrade the chaincode instead of instantiation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *channelID == "" || *name == "" || *version == "" || *policy == "" || *target == "" || *orderer == "" {
+		return errors.New("-channel, -name, -version, -policy, -target and -orderer are required")
+	}
+	if _, err := api.ParsePolicy(*policy); err != nil {
+		return err
+	}
+	constructor, err := parseArguments(*argsJSON, fs.Args())
+	if err != nil {
+		return err
+	}
+	conn, err := cf.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	cc := &api.Chaincode{Name: *name, Version: *version, Path: *path, Type: *ccType, ChannelID: *channelID,
+		Policy: *policy, Constructor: constructor}
+	oper := api.InstantiateChaincode
+	if *upgrade {
+		oper = api.UpgradeChaincode
+	}
+	txID, err := oper(conn, cc, *target, *orderer)
+	if err != nil {
+		return err
+	}
+
+	if *cf.output == OutputJSON {
+		return printJSON(map[string]interface{}{"transactionID": txID, "chaincode": cc.String()})
+	}
+	printTable([]string{"CHAINCODE", "CHANNEL", "TXID"}, [][]string{{cc.String(), *channelID, string(txID)}})
+	return nil
+}
+
+func runChannel(args []string) error {
+	if len(args) < 1 || (args[0] != "join" && args[0] != "create") {
+		return errors.New("usage: fablet channel join|create [flags]")
+	}
+	fs, cf := newFlagSet("channel " + args[0])
+	channelID := fs.String("channel", "", "Channel ID, required to join")
+	txFile := fs.String("tx", "", "Channel transaction file, required to create")
+	targets := fs.String("targets", "", "Comma separated peers to join, all peers of my organization if empty")
+	orderer := fs.String("orderer", "", "Orderer (required)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *orderer == "" {
+		return errors.New("-orderer is required")
+	}
+
+	if args[0] == "create" {
+		if *txFile == "" {
+			return errors.New("-tx is required")
+		}
+		txContent, err := ioutil.ReadFile(*txFile)
+		if err != nil {
+			return errors.WithMessage(err, "Error occurred when reading the channel transaction.")
+		}
+		conn, err := cf.connect()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		createdID, err := api.CreateChannel(conn, txContent, *orderer)
+		if err != nil {
+			return err
+		}
+		return printChannelResult(*cf.output, createdID, nil)
+	}
+
+	if *channelID == "" {
+		return errors.New("-channel is required")
+	}
+	conn, err := cf.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	peers := splitList(*targets)
+	if err := api.JoinChannel(conn, *channelID, peers, *orderer); err != nil {
+		return err
+	}
+	return printChannelResult(*cf.output, *channelID, peers)
+}
+
+func printChannelResult(output string, channelID string, peers []string) error {
+	if output == OutputJSON {
+		return printJSON(map[string]interface{}{"channelID": channelID, "peers": peers})
+	}
+	printTable([]string{"CHANNEL", "PEERS"}, [][]string{{channelID, strings.Join(peers, ",")}})
+	return nil
+}
+
+func runProfile(args []string) error {
+	if len(args) < 1 || (args[0] != "convert" && args[0] != "export") {
+		return errors.New("usage: fablet profile convert|export [flags]")
+	}
+	if args[0] == "export" {
+		return runProfileExport(args[1:])
+	}
+	fs := flag.NewFlagSet("profile convert", flag.ContinueOnError)
+	profile := fs.String("profile", "", "Connection profile file (required)")
+	format := fs.String("format", api.ProfileTypeJSON, "Format to convert to: yaml or json")
+	inline := fs.Bool("inline", false, "Replace paths of certificates with the PEMs, relative paths are of the profile")
+	inlineDir := fs.String("inlinedir", "", "Directory of certificates to inline, only files in it are read (default the directory of the profile)")
+	out := fs.String("out", "", "File to write, the standard output if empty")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *profile == "" {
+		return errors.New("-profile is required")
+	}
+	config, err := ioutil.ReadFile(*profile)
+	if err != nil {
+		return errors.WithMessage(err, "Error occurred when reading the connection profile.")
+	}
+	options := []api.ProfileConvertOptionFunc{}
+	if *inline {
+		if *inlineDir == "" {
+			*inlineDir = filepath.Dir(*profile)
+		}
+		options = append(options, api.WithInlinePaths(*inlineDir))
+	}
+	converted, err := api.ConvertProfile(config, *format, options...)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(converted)
+		return err
+	}
+	return ioutil.WriteFile(*out, converted, 0644)
+}
+
+func runProfileExport(args []string) error {
+	fs, cf := newFlagSet("profile export")
+	format := fs.String("format", "", "Format to export: yaml or json, the format of the profile if empty")
+	out := fs.String("out", "", "File to write, the standard output if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	conn, err := cf.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	exported, err := api.ExportProfile(conn, *format)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(exported)
+		return err
+	}
+	return ioutil.WriteFile(*out, exported, 0644)
+}
@@ -3,15 +3,20 @@ package main
 import (
 	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"syscall"
 
+	"github.com/IBM/fablet/api"
 	"github.com/IBM/fablet/log"
 
 	"github.com/IBM/fablet/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 // SrvPort The default http listening port
@@ -19,32 +24,12 @@ const DefaultSrvPort = 8080
 
 var logger = log.GetLogger()
 
-// TODO
-// TODO to persist the connection in session
-func getHandlerMap() map[string]service.HTTPHandler {
-	handlerMap := map[string]service.HTTPHandler{
-		// "/":                      service.HandleRoot,
-		//"/network/connect":       service.Post(service.HandleNetworkConnect),
-		"/network/discover":      service.Post(service.HandleNetworkDiscover),
-		"/network/refresh":       service.Post(service.HandleNetworkRefresh),
-		"/peer/details":          service.Post(service.HandlePeerDetails),
-		"/chaincode/install":     service.Post(service.HandleChaincodeInstall),
-		"/chaincode/instantiate": service.Post(service.HandleChaincodeInstantiate),
-		"/chaincode/upgrade":     service.Post(service.HandleChaincodeUpgrade),
-		"/chaincode/execute":     service.Post(service.HandleChaincodeExecute),
-		"/ledger/query":          service.Post(service.HandleLedgerQuery),
-		"/ledger/block":          service.Post(service.HandleBlockQuery),
-		"/ledger/blockany":       service.Post(service.HandleBlockQueryAny),
-		"/channel/create":        service.Post(service.HandleCreateChannel),
-		"/channel/join":          service.Post(service.HandleJoinChannel),
-		"/event/blockevent":      service.WS(service.HandleBlockEvent),
-		"/event/chaincodeevent":  service.WS(service.HandleChaincodeEvent),
+func main() {
+	// Subcommands for scripting, without the server.
+	if isCommand(os.Args[1:]) {
+		os.Exit(runCommand(os.Args[1:]))
 	}
 
-	return handlerMap
-}
-
-func main() {
 	s := make(chan os.Signal, 1)
 	signal.Notify(s, os.Interrupt, syscall.SIGTERM)
 
@@ -52,11 +37,28 @@ func main() {
 	port := flag.Int("port", DefaultSrvPort, "Listen on port")
 	cert := flag.String("cert", "", "TLS cert (default non-https)")
 	key := flag.String("key", "", "TLS key (default non-https)")
+	grpcPort := flag.Int("grpcport", 0, "gRPC listen on port (default disabled), TLS by the same cert and key")
+	certWindows := flag.String("certwindows", "7,30,90", "Comma separated windows in days to flag certificates which are expiring")
+	inlineDir := flag.String("inlinedir", "", "Directory of certificates which paths of connection profiles are inlined from (default disabled)")
+	pkcs11Libs := flag.String("pkcs11libs", "", "Comma separated PKCS#11 libraries which identities can use (default disabled)")
 	flag.Parse()
 
+	windows := []int{}
+	for _, window := range splitList(*certWindows) {
+		days, err := strconv.Atoi(window)
+		if err != nil || days <= 0 {
+			logger.Errorf("%s is not a valid window of certificates.", window)
+			os.Exit(2)
+		}
+		windows = append(windows, days)
+	}
+	service.CertExpiryWindows = windows
+	service.ProfileInlineDir = *inlineDir
+	api.PKCS11Libraries = splitList(*pkcs11Libs)
+
 	addrPort := fmt.Sprintf("%s:%d", *addr, *port)
 
-	for url, handler := range getHandlerMap() {
+	for url, handler := range service.GetHandlerMap() {
 		http.HandleFunc(url, handler)
 	}
 	http.Handle("/", http.FileServer(http.Dir(filepath.Join(service.ExeFolder, "web"))))
@@ -74,7 +76,34 @@ func main() {
 		}
 	}()
 
+	if *grpcPort > 0 {
+		grpcAddrPort := fmt.Sprintf("%s:%d", *addr, *grpcPort)
+		go func() {
+			// The HTTP server keeps serving without gRPC.
+			if err := serveGRPC(grpcAddrPort, *cert, *key); err != nil {
+				logger.Errorf("Fablet gRPC failed to serve at %s: %s", grpcAddrPort, err.Error())
+			}
+		}()
+		logger.Infof("Fablet gRPC serve at %s.", grpcAddrPort)
+	}
+
 	logger.Infof("Fablet serve at %s.", addrPort)
 	<-s
 	logger.Info("Fablet server Exits.")
 }
+
+func serveGRPC(addrPort string, cert string, key string) error {
+	options := []grpc.ServerOption{}
+	if cert != "" && key != "" {
+		creds, err := credentials.NewServerTLSFromFile(cert, key)
+		if err != nil {
+			return err
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+	lis, err := net.Listen("tcp", addrPort)
+	if err != nil {
+		return err
+	}
+	return service.NewGRPCServer(options...).Serve(lis)
+}
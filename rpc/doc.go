@@ -0,0 +1,4 @@
+// Package rpc the messages and the gRPC service of Fablet defined in fablet.proto, served by service.NewGRPCServer.
+package rpc
+
+//go:generate protoc --go_out=plugins=grpc:. fablet.proto
@@ -0,0 +1,3152 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: fablet.proto
+
+package rpc
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// ActionType type of ExecuteRequest.
+type ActionType int32
+
+const (
+	ActionType_EXECUTE ActionType = 0
+	ActionType_QUERY   ActionType = 1
+)
+
+var ActionType_name = map[int32]string{
+	0: "EXECUTE",
+	1: "QUERY",
+}
+
+var ActionType_value = map[string]int32{
+	"EXECUTE": 0,
+	"QUERY":   1,
+}
+
+func (x ActionType) String() string {
+	return proto.EnumName(ActionType_name, int32(x))
+}
+
+func (ActionType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{0}
+}
+
+// Connection the connection profile and the identity, connections are kept in session as the HTTP API does.
+type Connection struct {
+	Label         string `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	MspId         string `protobuf:"bytes,2,opt,name=msp_id,json=mspId,proto3" json:"msp_id,omitempty"`
+	CertContent   string `protobuf:"bytes,3,opt,name=cert_content,json=certContent,proto3" json:"cert_content,omitempty"`
+	PrvKeyContent string `protobuf:"bytes,4,opt,name=prv_key_content,json=prvKeyContent,proto3" json:"prv_key_content,omitempty"`
+	// Connection profile in yaml or json.
+	ConnProfile string `protobuf:"bytes,5,opt,name=conn_profile,json=connProfile,proto3" json:"conn_profile,omitempty"`
+	// Workspace of which the profile and identity are used instead of the above, see /workspace/list.
+	Workspace string `protobuf:"bytes,6,opt,name=workspace,proto3" json:"workspace,omitempty"`
+	// Label of the identity of the workspace, the default identity of the workspace if it is empty.
+	Identity string `protobuf:"bytes,7,opt,name=identity,proto3" json:"identity,omitempty"`
+	// Passphrase of prv_key_content if it is encrypted.
+	Passphrase string `protobuf:"bytes,8,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	// The private key in a PKCS#11 token instead of prv_key_content.
+	Pkcs11               *PKCS11Config `protobuf:"bytes,9,opt,name=pkcs11,proto3" json:"pkcs11,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *Connection) Reset()         { *m = Connection{} }
+func (m *Connection) String() string { return proto.CompactTextString(m) }
+func (*Connection) ProtoMessage()    {}
+func (*Connection) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{0}
+}
+
+func (m *Connection) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Connection.Unmarshal(m, b)
+}
+func (m *Connection) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Connection.Marshal(b, m, deterministic)
+}
+func (m *Connection) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Connection.Merge(m, src)
+}
+func (m *Connection) XXX_Size() int {
+	return xxx_messageInfo_Connection.Size(m)
+}
+func (m *Connection) XXX_DiscardUnknown() {
+	xxx_messageInfo_Connection.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Connection proto.InternalMessageInfo
+
+func (m *Connection) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *Connection) GetMspId() string {
+	if m != nil {
+		return m.MspId
+	}
+	return ""
+}
+
+func (m *Connection) GetCertContent() string {
+	if m != nil {
+		return m.CertContent
+	}
+	return ""
+}
+
+func (m *Connection) GetPrvKeyContent() string {
+	if m != nil {
+		return m.PrvKeyContent
+	}
+	return ""
+}
+
+func (m *Connection) GetConnProfile() string {
+	if m != nil {
+		return m.ConnProfile
+	}
+	return ""
+}
+
+func (m *Connection) GetWorkspace() string {
+	if m != nil {
+		return m.Workspace
+	}
+	return ""
+}
+
+func (m *Connection) GetIdentity() string {
+	if m != nil {
+		return m.Identity
+	}
+	return ""
+}
+
+func (m *Connection) GetPassphrase() string {
+	if m != nil {
+		return m.Passphrase
+	}
+	return ""
+}
+
+func (m *Connection) GetPkcs11() *PKCS11Config {
+	if m != nil {
+		return m.Pkcs11
+	}
+	return nil
+}
+
+// PKCS11Config the private key in a PKCS#11 token, found by the label.
+type PKCS11Config struct {
+	Library              string   `protobuf:"bytes,1,opt,name=library,proto3" json:"library,omitempty"`
+	Slot                 uint32   `protobuf:"varint,2,opt,name=slot,proto3" json:"slot,omitempty"`
+	Pin                  string   `protobuf:"bytes,3,opt,name=pin,proto3" json:"pin,omitempty"`
+	KeyLabel             string   `protobuf:"bytes,4,opt,name=key_label,json=keyLabel,proto3" json:"key_label,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PKCS11Config) Reset()         { *m = PKCS11Config{} }
+func (m *PKCS11Config) String() string { return proto.CompactTextString(m) }
+func (*PKCS11Config) ProtoMessage()    {}
+func (*PKCS11Config) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{1}
+}
+
+func (m *PKCS11Config) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PKCS11Config.Unmarshal(m, b)
+}
+func (m *PKCS11Config) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PKCS11Config.Marshal(b, m, deterministic)
+}
+func (m *PKCS11Config) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PKCS11Config.Merge(m, src)
+}
+func (m *PKCS11Config) XXX_Size() int {
+	return xxx_messageInfo_PKCS11Config.Size(m)
+}
+func (m *PKCS11Config) XXX_DiscardUnknown() {
+	xxx_messageInfo_PKCS11Config.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PKCS11Config proto.InternalMessageInfo
+
+func (m *PKCS11Config) GetLibrary() string {
+	if m != nil {
+		return m.Library
+	}
+	return ""
+}
+
+func (m *PKCS11Config) GetSlot() uint32 {
+	if m != nil {
+		return m.Slot
+	}
+	return 0
+}
+
+func (m *PKCS11Config) GetPin() string {
+	if m != nil {
+		return m.Pin
+	}
+	return ""
+}
+
+func (m *PKCS11Config) GetKeyLabel() string {
+	if m != nil {
+		return m.KeyLabel
+	}
+	return ""
+}
+
+// DiscoverRequest to discover the network.
+type DiscoverRequest struct {
+	Connection *Connection `protobuf:"bytes,1,opt,name=connection,proto3" json:"connection,omitempty"`
+	// To recreate the connection.
+	Refresh              bool     `protobuf:"varint,2,opt,name=refresh,proto3" json:"refresh,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiscoverRequest) Reset()         { *m = DiscoverRequest{} }
+func (m *DiscoverRequest) String() string { return proto.CompactTextString(m) }
+func (*DiscoverRequest) ProtoMessage()    {}
+func (*DiscoverRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{2}
+}
+
+func (m *DiscoverRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiscoverRequest.Unmarshal(m, b)
+}
+func (m *DiscoverRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiscoverRequest.Marshal(b, m, deterministic)
+}
+func (m *DiscoverRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiscoverRequest.Merge(m, src)
+}
+func (m *DiscoverRequest) XXX_Size() int {
+	return xxx_messageInfo_DiscoverRequest.Size(m)
+}
+func (m *DiscoverRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiscoverRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiscoverRequest proto.InternalMessageInfo
+
+func (m *DiscoverRequest) GetConnection() *Connection {
+	if m != nil {
+		return m.Connection
+	}
+	return nil
+}
+
+func (m *DiscoverRequest) GetRefresh() bool {
+	if m != nil {
+		return m.Refresh
+	}
+	return false
+}
+
+// PeerStatus status of a peer endpoint.
+type PeerStatus struct {
+	Ping                 bool     `protobuf:"varint,1,opt,name=ping,proto3" json:"ping,omitempty"`
+	Grpc                 bool     `protobuf:"varint,2,opt,name=grpc,proto3" json:"grpc,omitempty"`
+	Valid                bool     `protobuf:"varint,3,opt,name=valid,proto3" json:"valid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeerStatus) Reset()         { *m = PeerStatus{} }
+func (m *PeerStatus) String() string { return proto.CompactTextString(m) }
+func (*PeerStatus) ProtoMessage()    {}
+func (*PeerStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{3}
+}
+
+func (m *PeerStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerStatus.Unmarshal(m, b)
+}
+func (m *PeerStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerStatus.Marshal(b, m, deterministic)
+}
+func (m *PeerStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerStatus.Merge(m, src)
+}
+func (m *PeerStatus) XXX_Size() int {
+	return xxx_messageInfo_PeerStatus.Size(m)
+}
+func (m *PeerStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerStatus proto.InternalMessageInfo
+
+func (m *PeerStatus) GetPing() bool {
+	if m != nil {
+		return m.Ping
+	}
+	return false
+}
+
+func (m *PeerStatus) GetGrpc() bool {
+	if m != nil {
+		return m.Grpc
+	}
+	return false
+}
+
+func (m *PeerStatus) GetValid() bool {
+	if m != nil {
+		return m.Valid
+	}
+	return false
+}
+
+// Peer a peer of the network.
+type Peer struct {
+	Name                 string      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	OrgName              string      `protobuf:"bytes,2,opt,name=org_name,json=orgName,proto3" json:"org_name,omitempty"`
+	MspId                string      `protobuf:"bytes,3,opt,name=msp_id,json=mspId,proto3" json:"msp_id,omitempty"`
+	Url                  string      `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	Channels             []string    `protobuf:"bytes,5,rep,name=channels,proto3" json:"channels,omitempty"`
+	IsConfigured         bool        `protobuf:"varint,6,opt,name=is_configured,json=isConfigured,proto3" json:"is_configured,omitempty"`
+	UpdateTime           int64       `protobuf:"varint,7,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	Status               *PeerStatus `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *Peer) Reset()         { *m = Peer{} }
+func (m *Peer) String() string { return proto.CompactTextString(m) }
+func (*Peer) ProtoMessage()    {}
+func (*Peer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{4}
+}
+
+func (m *Peer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Peer.Unmarshal(m, b)
+}
+func (m *Peer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Peer.Marshal(b, m, deterministic)
+}
+func (m *Peer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Peer.Merge(m, src)
+}
+func (m *Peer) XXX_Size() int {
+	return xxx_messageInfo_Peer.Size(m)
+}
+func (m *Peer) XXX_DiscardUnknown() {
+	xxx_messageInfo_Peer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Peer proto.InternalMessageInfo
+
+func (m *Peer) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Peer) GetOrgName() string {
+	if m != nil {
+		return m.OrgName
+	}
+	return ""
+}
+
+func (m *Peer) GetMspId() string {
+	if m != nil {
+		return m.MspId
+	}
+	return ""
+}
+
+func (m *Peer) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *Peer) GetChannels() []string {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+func (m *Peer) GetIsConfigured() bool {
+	if m != nil {
+		return m.IsConfigured
+	}
+	return false
+}
+
+func (m *Peer) GetUpdateTime() int64 {
+	if m != nil {
+		return m.UpdateTime
+	}
+	return 0
+}
+
+func (m *Peer) GetStatus() *PeerStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+// Orderer an orderer of channels.
+type Orderer struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url                  string   `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Channels             []string `protobuf:"bytes,3,rep,name=channels,proto3" json:"channels,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Orderer) Reset()         { *m = Orderer{} }
+func (m *Orderer) String() string { return proto.CompactTextString(m) }
+func (*Orderer) ProtoMessage()    {}
+func (*Orderer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{5}
+}
+
+func (m *Orderer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Orderer.Unmarshal(m, b)
+}
+func (m *Orderer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Orderer.Marshal(b, m, deterministic)
+}
+func (m *Orderer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Orderer.Merge(m, src)
+}
+func (m *Orderer) XXX_Size() int {
+	return xxx_messageInfo_Orderer.Size(m)
+}
+func (m *Orderer) XXX_DiscardUnknown() {
+	xxx_messageInfo_Orderer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Orderer proto.InternalMessageInfo
+
+func (m *Orderer) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Orderer) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *Orderer) GetChannels() []string {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+// Ledger the ledger of a channel.
+type Ledger struct {
+	Height           uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	CurrentBlockHash string `protobuf:"bytes,2,opt,name=current_block_hash,json=currentBlockHash,proto3" json:"current_block_hash,omitempty"`
+	// The peer queried.
+	Endorser             string   `protobuf:"bytes,3,opt,name=endorser,proto3" json:"endorser,omitempty"`
+	Status               int32    `protobuf:"varint,4,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Ledger) Reset()         { *m = Ledger{} }
+func (m *Ledger) String() string { return proto.CompactTextString(m) }
+func (*Ledger) ProtoMessage()    {}
+func (*Ledger) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{6}
+}
+
+func (m *Ledger) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Ledger.Unmarshal(m, b)
+}
+func (m *Ledger) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Ledger.Marshal(b, m, deterministic)
+}
+func (m *Ledger) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Ledger.Merge(m, src)
+}
+func (m *Ledger) XXX_Size() int {
+	return xxx_messageInfo_Ledger.Size(m)
+}
+func (m *Ledger) XXX_DiscardUnknown() {
+	xxx_messageInfo_Ledger.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Ledger proto.InternalMessageInfo
+
+func (m *Ledger) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Ledger) GetCurrentBlockHash() string {
+	if m != nil {
+		return m.CurrentBlockHash
+	}
+	return ""
+}
+
+func (m *Ledger) GetEndorser() string {
+	if m != nil {
+		return m.Endorser
+	}
+	return ""
+}
+
+func (m *Ledger) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+// Chaincode a chaincode, either installed or instantiated.
+type Chaincode struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// For golang, it is the package.
+	Path string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	// One of golang, node and java.
+	Type      string `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	ChannelId string `protobuf:"bytes,5,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	// Endorsement policy.
+	Policy string `protobuf:"bytes,6,opt,name=policy,proto3" json:"policy,omitempty"`
+	// Arguments for instantiation.
+	Constructor          []string `protobuf:"bytes,7,rep,name=constructor,proto3" json:"constructor,omitempty"`
+	Installed            bool     `protobuf:"varint,8,opt,name=installed,proto3" json:"installed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Chaincode) Reset()         { *m = Chaincode{} }
+func (m *Chaincode) String() string { return proto.CompactTextString(m) }
+func (*Chaincode) ProtoMessage()    {}
+func (*Chaincode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{7}
+}
+
+func (m *Chaincode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Chaincode.Unmarshal(m, b)
+}
+func (m *Chaincode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Chaincode.Marshal(b, m, deterministic)
+}
+func (m *Chaincode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Chaincode.Merge(m, src)
+}
+func (m *Chaincode) XXX_Size() int {
+	return xxx_messageInfo_Chaincode.Size(m)
+}
+func (m *Chaincode) XXX_DiscardUnknown() {
+	xxx_messageInfo_Chaincode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Chaincode proto.InternalMessageInfo
+
+func (m *Chaincode) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Chaincode) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *Chaincode) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *Chaincode) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Chaincode) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *Chaincode) GetPolicy() string {
+	if m != nil {
+		return m.Policy
+	}
+	return ""
+}
+
+func (m *Chaincode) GetConstructor() []string {
+	if m != nil {
+		return m.Constructor
+	}
+	return nil
+}
+
+func (m *Chaincode) GetInstalled() bool {
+	if m != nil {
+		return m.Installed
+	}
+	return false
+}
+
+// ChannelOverview a channel with its ledger, chaincodes and orderers.
+type ChannelOverview struct {
+	ChannelId            string       `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	Ledger               *Ledger      `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Chaincodes           []*Chaincode `protobuf:"bytes,3,rep,name=chaincodes,proto3" json:"chaincodes,omitempty"`
+	Orderers             []*Orderer   `protobuf:"bytes,4,rep,name=orderers,proto3" json:"orderers,omitempty"`
+	AnchorPeers          []string     `protobuf:"bytes,5,rep,name=anchor_peers,json=anchorPeers,proto3" json:"anchor_peers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ChannelOverview) Reset()         { *m = ChannelOverview{} }
+func (m *ChannelOverview) String() string { return proto.CompactTextString(m) }
+func (*ChannelOverview) ProtoMessage()    {}
+func (*ChannelOverview) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{8}
+}
+
+func (m *ChannelOverview) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChannelOverview.Unmarshal(m, b)
+}
+func (m *ChannelOverview) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChannelOverview.Marshal(b, m, deterministic)
+}
+func (m *ChannelOverview) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChannelOverview.Merge(m, src)
+}
+func (m *ChannelOverview) XXX_Size() int {
+	return xxx_messageInfo_ChannelOverview.Size(m)
+}
+func (m *ChannelOverview) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChannelOverview.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChannelOverview proto.InternalMessageInfo
+
+func (m *ChannelOverview) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *ChannelOverview) GetLedger() *Ledger {
+	if m != nil {
+		return m.Ledger
+	}
+	return nil
+}
+
+func (m *ChannelOverview) GetChaincodes() []*Chaincode {
+	if m != nil {
+		return m.Chaincodes
+	}
+	return nil
+}
+
+func (m *ChannelOverview) GetOrderers() []*Orderer {
+	if m != nil {
+		return m.Orderers
+	}
+	return nil
+}
+
+func (m *ChannelOverview) GetAnchorPeers() []string {
+	if m != nil {
+		return m.AnchorPeers
+	}
+	return nil
+}
+
+// NetworkOverview result of DiscoverNetwork.
+type NetworkOverview struct {
+	// Identifier of the connection, the same as conn of the HTTP API.
+	Conn                 string             `protobuf:"bytes,1,opt,name=conn,proto3" json:"conn,omitempty"`
+	Peers                []*Peer            `protobuf:"bytes,2,rep,name=peers,proto3" json:"peers,omitempty"`
+	Channels             []*ChannelOverview `protobuf:"bytes,3,rep,name=channels,proto3" json:"channels,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *NetworkOverview) Reset()         { *m = NetworkOverview{} }
+func (m *NetworkOverview) String() string { return proto.CompactTextString(m) }
+func (*NetworkOverview) ProtoMessage()    {}
+func (*NetworkOverview) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{9}
+}
+
+func (m *NetworkOverview) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NetworkOverview.Unmarshal(m, b)
+}
+func (m *NetworkOverview) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NetworkOverview.Marshal(b, m, deterministic)
+}
+func (m *NetworkOverview) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkOverview.Merge(m, src)
+}
+func (m *NetworkOverview) XXX_Size() int {
+	return xxx_messageInfo_NetworkOverview.Size(m)
+}
+func (m *NetworkOverview) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkOverview.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkOverview proto.InternalMessageInfo
+
+func (m *NetworkOverview) GetConn() string {
+	if m != nil {
+		return m.Conn
+	}
+	return ""
+}
+
+func (m *NetworkOverview) GetPeers() []*Peer {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+func (m *NetworkOverview) GetChannels() []*ChannelOverview {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+// LedgerRequest to query the ledger.
+type LedgerRequest struct {
+	Connection           *Connection `protobuf:"bytes,1,opt,name=connection,proto3" json:"connection,omitempty"`
+	ChannelId            string      `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	Targets              []string    `protobuf:"bytes,3,rep,name=targets,proto3" json:"targets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *LedgerRequest) Reset()         { *m = LedgerRequest{} }
+func (m *LedgerRequest) String() string { return proto.CompactTextString(m) }
+func (*LedgerRequest) ProtoMessage()    {}
+func (*LedgerRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{10}
+}
+
+func (m *LedgerRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LedgerRequest.Unmarshal(m, b)
+}
+func (m *LedgerRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LedgerRequest.Marshal(b, m, deterministic)
+}
+func (m *LedgerRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LedgerRequest.Merge(m, src)
+}
+func (m *LedgerRequest) XXX_Size() int {
+	return xxx_messageInfo_LedgerRequest.Size(m)
+}
+func (m *LedgerRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LedgerRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LedgerRequest proto.InternalMessageInfo
+
+func (m *LedgerRequest) GetConnection() *Connection {
+	if m != nil {
+		return m.Connection
+	}
+	return nil
+}
+
+func (m *LedgerRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *LedgerRequest) GetTargets() []string {
+	if m != nil {
+		return m.Targets
+	}
+	return nil
+}
+
+// BlocksRequest to query blocks from begin.
+type BlocksRequest struct {
+	Connection           *Connection `protobuf:"bytes,1,opt,name=connection,proto3" json:"connection,omitempty"`
+	ChannelId            string      `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	Targets              []string    `protobuf:"bytes,3,rep,name=targets,proto3" json:"targets,omitempty"`
+	Begin                uint64      `protobuf:"varint,4,opt,name=begin,proto3" json:"begin,omitempty"`
+	Len                  uint64      `protobuf:"varint,5,opt,name=len,proto3" json:"len,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *BlocksRequest) Reset()         { *m = BlocksRequest{} }
+func (m *BlocksRequest) String() string { return proto.CompactTextString(m) }
+func (*BlocksRequest) ProtoMessage()    {}
+func (*BlocksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{11}
+}
+
+func (m *BlocksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlocksRequest.Unmarshal(m, b)
+}
+func (m *BlocksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlocksRequest.Marshal(b, m, deterministic)
+}
+func (m *BlocksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlocksRequest.Merge(m, src)
+}
+func (m *BlocksRequest) XXX_Size() int {
+	return xxx_messageInfo_BlocksRequest.Size(m)
+}
+func (m *BlocksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlocksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlocksRequest proto.InternalMessageInfo
+
+func (m *BlocksRequest) GetConnection() *Connection {
+	if m != nil {
+		return m.Connection
+	}
+	return nil
+}
+
+func (m *BlocksRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *BlocksRequest) GetTargets() []string {
+	if m != nil {
+		return m.Targets
+	}
+	return nil
+}
+
+func (m *BlocksRequest) GetBegin() uint64 {
+	if m != nil {
+		return m.Begin
+	}
+	return 0
+}
+
+func (m *BlocksRequest) GetLen() uint64 {
+	if m != nil {
+		return m.Len
+	}
+	return 0
+}
+
+// BlocksResponse result of QueryBlocks.
+type BlocksResponse struct {
+	Blocks               []*Block `protobuf:"bytes,1,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlocksResponse) Reset()         { *m = BlocksResponse{} }
+func (m *BlocksResponse) String() string { return proto.CompactTextString(m) }
+func (*BlocksResponse) ProtoMessage()    {}
+func (*BlocksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{12}
+}
+
+func (m *BlocksResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlocksResponse.Unmarshal(m, b)
+}
+func (m *BlocksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlocksResponse.Marshal(b, m, deterministic)
+}
+func (m *BlocksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlocksResponse.Merge(m, src)
+}
+func (m *BlocksResponse) XXX_Size() int {
+	return xxx_messageInfo_BlocksResponse.Size(m)
+}
+func (m *BlocksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlocksResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlocksResponse proto.InternalMessageInfo
+
+func (m *BlocksResponse) GetBlocks() []*Block {
+	if m != nil {
+		return m.Blocks
+	}
+	return nil
+}
+
+// BlockRequest to query a block.
+type BlockRequest struct {
+	Connection *Connection `protobuf:"bytes,1,opt,name=connection,proto3" json:"connection,omitempty"`
+	ChannelId  string      `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	Targets    []string    `protobuf:"bytes,3,rep,name=targets,proto3" json:"targets,omitempty"`
+	// Block number, block hash or transaction ID.
+	QueryKey             string   `protobuf:"bytes,4,opt,name=query_key,json=queryKey,proto3" json:"query_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockRequest) Reset()         { *m = BlockRequest{} }
+func (m *BlockRequest) String() string { return proto.CompactTextString(m) }
+func (*BlockRequest) ProtoMessage()    {}
+func (*BlockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{13}
+}
+
+func (m *BlockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockRequest.Unmarshal(m, b)
+}
+func (m *BlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockRequest.Marshal(b, m, deterministic)
+}
+func (m *BlockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockRequest.Merge(m, src)
+}
+func (m *BlockRequest) XXX_Size() int {
+	return xxx_messageInfo_BlockRequest.Size(m)
+}
+func (m *BlockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockRequest proto.InternalMessageInfo
+
+func (m *BlockRequest) GetConnection() *Connection {
+	if m != nil {
+		return m.Connection
+	}
+	return nil
+}
+
+func (m *BlockRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *BlockRequest) GetTargets() []string {
+	if m != nil {
+		return m.Targets
+	}
+	return nil
+}
+
+func (m *BlockRequest) GetQueryKey() string {
+	if m != nil {
+		return m.QueryKey
+	}
+	return ""
+}
+
+// Endorser signer of an endorsement.
+type Endorser struct {
+	CommonName           string   `protobuf:"bytes,1,opt,name=common_name,json=commonName,proto3" json:"common_name,omitempty"`
+	Subject              string   `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	MspId                string   `protobuf:"bytes,3,opt,name=msp_id,json=mspId,proto3" json:"msp_id,omitempty"`
+	Issuer               string   `protobuf:"bytes,4,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Endorser) Reset()         { *m = Endorser{} }
+func (m *Endorser) String() string { return proto.CompactTextString(m) }
+func (*Endorser) ProtoMessage()    {}
+func (*Endorser) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{14}
+}
+
+func (m *Endorser) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Endorser.Unmarshal(m, b)
+}
+func (m *Endorser) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Endorser.Marshal(b, m, deterministic)
+}
+func (m *Endorser) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Endorser.Merge(m, src)
+}
+func (m *Endorser) XXX_Size() int {
+	return xxx_messageInfo_Endorser.Size(m)
+}
+func (m *Endorser) XXX_DiscardUnknown() {
+	xxx_messageInfo_Endorser.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Endorser proto.InternalMessageInfo
+
+func (m *Endorser) GetCommonName() string {
+	if m != nil {
+		return m.CommonName
+	}
+	return ""
+}
+
+func (m *Endorser) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+func (m *Endorser) GetMspId() string {
+	if m != nil {
+		return m.MspId
+	}
+	return ""
+}
+
+func (m *Endorser) GetIssuer() string {
+	if m != nil {
+		return m.Issuer
+	}
+	return ""
+}
+
+// KVRead a read of the read write set.
+type KVRead struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	VerBlockNum          uint64   `protobuf:"varint,2,opt,name=ver_block_num,json=verBlockNum,proto3" json:"ver_block_num,omitempty"`
+	VerTxNum             uint64   `protobuf:"varint,3,opt,name=ver_tx_num,json=verTxNum,proto3" json:"ver_tx_num,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KVRead) Reset()         { *m = KVRead{} }
+func (m *KVRead) String() string { return proto.CompactTextString(m) }
+func (*KVRead) ProtoMessage()    {}
+func (*KVRead) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{15}
+}
+
+func (m *KVRead) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KVRead.Unmarshal(m, b)
+}
+func (m *KVRead) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KVRead.Marshal(b, m, deterministic)
+}
+func (m *KVRead) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KVRead.Merge(m, src)
+}
+func (m *KVRead) XXX_Size() int {
+	return xxx_messageInfo_KVRead.Size(m)
+}
+func (m *KVRead) XXX_DiscardUnknown() {
+	xxx_messageInfo_KVRead.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KVRead proto.InternalMessageInfo
+
+func (m *KVRead) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *KVRead) GetVerBlockNum() uint64 {
+	if m != nil {
+		return m.VerBlockNum
+	}
+	return 0
+}
+
+func (m *KVRead) GetVerTxNum() uint64 {
+	if m != nil {
+		return m.VerTxNum
+	}
+	return 0
+}
+
+// KVWrite a write of the read write set.
+type KVWrite struct {
+	Key      string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	IsDelete bool   `protobuf:"varint,2,opt,name=is_delete,json=isDelete,proto3" json:"is_delete,omitempty"`
+	// Value in JSON.
+	Value                string   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KVWrite) Reset()         { *m = KVWrite{} }
+func (m *KVWrite) String() string { return proto.CompactTextString(m) }
+func (*KVWrite) ProtoMessage()    {}
+func (*KVWrite) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{16}
+}
+
+func (m *KVWrite) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KVWrite.Unmarshal(m, b)
+}
+func (m *KVWrite) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KVWrite.Marshal(b, m, deterministic)
+}
+func (m *KVWrite) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KVWrite.Merge(m, src)
+}
+func (m *KVWrite) XXX_Size() int {
+	return xxx_messageInfo_KVWrite.Size(m)
+}
+func (m *KVWrite) XXX_DiscardUnknown() {
+	xxx_messageInfo_KVWrite.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KVWrite proto.InternalMessageInfo
+
+func (m *KVWrite) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *KVWrite) GetIsDelete() bool {
+	if m != nil {
+		return m.IsDelete
+	}
+	return false
+}
+
+func (m *KVWrite) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// NSReadWriteSet the read write set of a chaincode.
+type NSReadWriteSet struct {
+	Namespace            string     `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Reads                []*KVRead  `protobuf:"bytes,2,rep,name=reads,proto3" json:"reads,omitempty"`
+	Writes               []*KVWrite `protobuf:"bytes,3,rep,name=writes,proto3" json:"writes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *NSReadWriteSet) Reset()         { *m = NSReadWriteSet{} }
+func (m *NSReadWriteSet) String() string { return proto.CompactTextString(m) }
+func (*NSReadWriteSet) ProtoMessage()    {}
+func (*NSReadWriteSet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{17}
+}
+
+func (m *NSReadWriteSet) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NSReadWriteSet.Unmarshal(m, b)
+}
+func (m *NSReadWriteSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NSReadWriteSet.Marshal(b, m, deterministic)
+}
+func (m *NSReadWriteSet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NSReadWriteSet.Merge(m, src)
+}
+func (m *NSReadWriteSet) XXX_Size() int {
+	return xxx_messageInfo_NSReadWriteSet.Size(m)
+}
+func (m *NSReadWriteSet) XXX_DiscardUnknown() {
+	xxx_messageInfo_NSReadWriteSet.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NSReadWriteSet proto.InternalMessageInfo
+
+func (m *NSReadWriteSet) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *NSReadWriteSet) GetReads() []*KVRead {
+	if m != nil {
+		return m.Reads
+	}
+	return nil
+}
+
+func (m *NSReadWriteSet) GetWrites() []*KVWrite {
+	if m != nil {
+		return m.Writes
+	}
+	return nil
+}
+
+// Action a chaincode action of a transaction.
+type Action struct {
+	ChaincodeName        string            `protobuf:"bytes,1,opt,name=chaincode_name,json=chaincodeName,proto3" json:"chaincode_name,omitempty"`
+	ChaincodeVersion     string            `protobuf:"bytes,2,opt,name=chaincode_version,json=chaincodeVersion,proto3" json:"chaincode_version,omitempty"`
+	Arguments            []string          `protobuf:"bytes,3,rep,name=arguments,proto3" json:"arguments,omitempty"`
+	Endorsers            []*Endorser       `protobuf:"bytes,4,rep,name=endorsers,proto3" json:"endorsers,omitempty"`
+	RwSets               []*NSReadWriteSet `protobuf:"bytes,5,rep,name=rw_sets,json=rwSets,proto3" json:"rw_sets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Action) Reset()         { *m = Action{} }
+func (m *Action) String() string { return proto.CompactTextString(m) }
+func (*Action) ProtoMessage()    {}
+func (*Action) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{18}
+}
+
+func (m *Action) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Action.Unmarshal(m, b)
+}
+func (m *Action) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Action.Marshal(b, m, deterministic)
+}
+func (m *Action) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Action.Merge(m, src)
+}
+func (m *Action) XXX_Size() int {
+	return xxx_messageInfo_Action.Size(m)
+}
+func (m *Action) XXX_DiscardUnknown() {
+	xxx_messageInfo_Action.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Action proto.InternalMessageInfo
+
+func (m *Action) GetChaincodeName() string {
+	if m != nil {
+		return m.ChaincodeName
+	}
+	return ""
+}
+
+func (m *Action) GetChaincodeVersion() string {
+	if m != nil {
+		return m.ChaincodeVersion
+	}
+	return ""
+}
+
+func (m *Action) GetArguments() []string {
+	if m != nil {
+		return m.Arguments
+	}
+	return nil
+}
+
+func (m *Action) GetEndorsers() []*Endorser {
+	if m != nil {
+		return m.Endorsers
+	}
+	return nil
+}
+
+func (m *Action) GetRwSets() []*NSReadWriteSet {
+	if m != nil {
+		return m.RwSets
+	}
+	return nil
+}
+
+// Transaction a transaction of a block.
+type Transaction struct {
+	TxId                 string    `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	ValidationCode       string    `protobuf:"bytes,2,opt,name=validation_code,json=validationCode,proto3" json:"validation_code,omitempty"`
+	Actions              []*Action `protobuf:"bytes,3,rep,name=actions,proto3" json:"actions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Transaction) Reset()         { *m = Transaction{} }
+func (m *Transaction) String() string { return proto.CompactTextString(m) }
+func (*Transaction) ProtoMessage()    {}
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{19}
+}
+
+func (m *Transaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Transaction.Unmarshal(m, b)
+}
+func (m *Transaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Transaction.Marshal(b, m, deterministic)
+}
+func (m *Transaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transaction.Merge(m, src)
+}
+func (m *Transaction) XXX_Size() int {
+	return xxx_messageInfo_Transaction.Size(m)
+}
+func (m *Transaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Transaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Transaction proto.InternalMessageInfo
+
+func (m *Transaction) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *Transaction) GetValidationCode() string {
+	if m != nil {
+		return m.ValidationCode
+	}
+	return ""
+}
+
+func (m *Transaction) GetActions() []*Action {
+	if m != nil {
+		return m.Actions
+	}
+	return nil
+}
+
+// Block a block of a channel.
+type Block struct {
+	Number       uint64         `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	DataHash     string         `protobuf:"bytes,2,opt,name=data_hash,json=dataHash,proto3" json:"data_hash,omitempty"`
+	PreviousHash string         `protobuf:"bytes,3,opt,name=previous_hash,json=previousHash,proto3" json:"previous_hash,omitempty"`
+	BlockHash    string         `protobuf:"bytes,4,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	Transactions []*Transaction `protobuf:"bytes,5,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	// Time of the first transaction in milliseconds.
+	Time                 int64    `protobuf:"varint,6,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Block) Reset()         { *m = Block{} }
+func (m *Block) String() string { return proto.CompactTextString(m) }
+func (*Block) ProtoMessage()    {}
+func (*Block) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{20}
+}
+
+func (m *Block) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Block.Unmarshal(m, b)
+}
+func (m *Block) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Block.Marshal(b, m, deterministic)
+}
+func (m *Block) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Block.Merge(m, src)
+}
+func (m *Block) XXX_Size() int {
+	return xxx_messageInfo_Block.Size(m)
+}
+func (m *Block) XXX_DiscardUnknown() {
+	xxx_messageInfo_Block.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Block proto.InternalMessageInfo
+
+func (m *Block) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *Block) GetDataHash() string {
+	if m != nil {
+		return m.DataHash
+	}
+	return ""
+}
+
+func (m *Block) GetPreviousHash() string {
+	if m != nil {
+		return m.PreviousHash
+	}
+	return ""
+}
+
+func (m *Block) GetBlockHash() string {
+	if m != nil {
+		return m.BlockHash
+	}
+	return ""
+}
+
+func (m *Block) GetTransactions() []*Transaction {
+	if m != nil {
+		return m.Transactions
+	}
+	return nil
+}
+
+func (m *Block) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+// ExecuteRequest to execute a chaincode.
+type ExecuteRequest struct {
+	Connection   *Connection `protobuf:"bytes,1,opt,name=connection,proto3" json:"connection,omitempty"`
+	ChannelId    string      `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	ChaincodeId  string      `protobuf:"bytes,3,opt,name=chaincode_id,json=chaincodeId,proto3" json:"chaincode_id,omitempty"`
+	ActionType   ActionType  `protobuf:"varint,4,opt,name=action_type,json=actionType,proto3,enum=fablet.ActionType" json:"action_type,omitempty"`
+	FunctionName string      `protobuf:"bytes,5,opt,name=function_name,json=functionName,proto3" json:"function_name,omitempty"`
+	Arguments    []string    `protobuf:"bytes,6,rep,name=arguments,proto3" json:"arguments,omitempty"`
+	Targets      []string    `protobuf:"bytes,7,rep,name=targets,proto3" json:"targets,omitempty"`
+	// To select endorsers if no targets, one of myorg, latency and height.
+	SelectionStrategy    string   `protobuf:"bytes,8,opt,name=selection_strategy,json=selectionStrategy,proto3" json:"selection_strategy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExecuteRequest) Reset()         { *m = ExecuteRequest{} }
+func (m *ExecuteRequest) String() string { return proto.CompactTextString(m) }
+func (*ExecuteRequest) ProtoMessage()    {}
+func (*ExecuteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{21}
+}
+
+func (m *ExecuteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecuteRequest.Unmarshal(m, b)
+}
+func (m *ExecuteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecuteRequest.Marshal(b, m, deterministic)
+}
+func (m *ExecuteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecuteRequest.Merge(m, src)
+}
+func (m *ExecuteRequest) XXX_Size() int {
+	return xxx_messageInfo_ExecuteRequest.Size(m)
+}
+func (m *ExecuteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecuteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecuteRequest proto.InternalMessageInfo
+
+func (m *ExecuteRequest) GetConnection() *Connection {
+	if m != nil {
+		return m.Connection
+	}
+	return nil
+}
+
+func (m *ExecuteRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *ExecuteRequest) GetChaincodeId() string {
+	if m != nil {
+		return m.ChaincodeId
+	}
+	return ""
+}
+
+func (m *ExecuteRequest) GetActionType() ActionType {
+	if m != nil {
+		return m.ActionType
+	}
+	return ActionType_EXECUTE
+}
+
+func (m *ExecuteRequest) GetFunctionName() string {
+	if m != nil {
+		return m.FunctionName
+	}
+	return ""
+}
+
+func (m *ExecuteRequest) GetArguments() []string {
+	if m != nil {
+		return m.Arguments
+	}
+	return nil
+}
+
+func (m *ExecuteRequest) GetTargets() []string {
+	if m != nil {
+		return m.Targets
+	}
+	return nil
+}
+
+func (m *ExecuteRequest) GetSelectionStrategy() string {
+	if m != nil {
+		return m.SelectionStrategy
+	}
+	return ""
+}
+
+// PeerResponse response of an endorser.
+type PeerResponse struct {
+	Endorser             string   `protobuf:"bytes,1,opt,name=endorser,proto3" json:"endorser,omitempty"`
+	Version              int32    `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Payload              string   `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	Status               int32    `protobuf:"varint,4,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeerResponse) Reset()         { *m = PeerResponse{} }
+func (m *PeerResponse) String() string { return proto.CompactTextString(m) }
+func (*PeerResponse) ProtoMessage()    {}
+func (*PeerResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{22}
+}
+
+func (m *PeerResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerResponse.Unmarshal(m, b)
+}
+func (m *PeerResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerResponse.Marshal(b, m, deterministic)
+}
+func (m *PeerResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerResponse.Merge(m, src)
+}
+func (m *PeerResponse) XXX_Size() int {
+	return xxx_messageInfo_PeerResponse.Size(m)
+}
+func (m *PeerResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerResponse proto.InternalMessageInfo
+
+func (m *PeerResponse) GetEndorser() string {
+	if m != nil {
+		return m.Endorser
+	}
+	return ""
+}
+
+func (m *PeerResponse) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *PeerResponse) GetPayload() string {
+	if m != nil {
+		return m.Payload
+	}
+	return ""
+}
+
+func (m *PeerResponse) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+// ExecuteResponse result of ExecuteChaincode.
+type ExecuteResponse struct {
+	TransactionId        string          `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	TxValidationCode     string          `protobuf:"bytes,2,opt,name=tx_validation_code,json=txValidationCode,proto3" json:"tx_validation_code,omitempty"`
+	ChaincodeStatus      int32           `protobuf:"varint,3,opt,name=chaincode_status,json=chaincodeStatus,proto3" json:"chaincode_status,omitempty"`
+	Payload              string          `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	PeerResponses        []*PeerResponse `protobuf:"bytes,5,rep,name=peer_responses,json=peerResponses,proto3" json:"peer_responses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ExecuteResponse) Reset()         { *m = ExecuteResponse{} }
+func (m *ExecuteResponse) String() string { return proto.CompactTextString(m) }
+func (*ExecuteResponse) ProtoMessage()    {}
+func (*ExecuteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{23}
+}
+
+func (m *ExecuteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecuteResponse.Unmarshal(m, b)
+}
+func (m *ExecuteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecuteResponse.Marshal(b, m, deterministic)
+}
+func (m *ExecuteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecuteResponse.Merge(m, src)
+}
+func (m *ExecuteResponse) XXX_Size() int {
+	return xxx_messageInfo_ExecuteResponse.Size(m)
+}
+func (m *ExecuteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecuteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecuteResponse proto.InternalMessageInfo
+
+func (m *ExecuteResponse) GetTransactionId() string {
+	if m != nil {
+		return m.TransactionId
+	}
+	return ""
+}
+
+func (m *ExecuteResponse) GetTxValidationCode() string {
+	if m != nil {
+		return m.TxValidationCode
+	}
+	return ""
+}
+
+func (m *ExecuteResponse) GetChaincodeStatus() int32 {
+	if m != nil {
+		return m.ChaincodeStatus
+	}
+	return 0
+}
+
+func (m *ExecuteResponse) GetPayload() string {
+	if m != nil {
+		return m.Payload
+	}
+	return ""
+}
+
+func (m *ExecuteResponse) GetPeerResponses() []*PeerResponse {
+	if m != nil {
+		return m.PeerResponses
+	}
+	return nil
+}
+
+// InstallRequest to install a chaincode.
+type InstallRequest struct {
+	Connection *Connection `protobuf:"bytes,1,opt,name=connection,proto3" json:"connection,omitempty"`
+	Chaincode  *Chaincode  `protobuf:"bytes,2,opt,name=chaincode,proto3" json:"chaincode,omitempty"`
+	// The compressed chaincode source.
+	Package []byte `protobuf:"bytes,3,opt,name=package,proto3" json:"package,omitempty"`
+	// One of tar, tar.gz and zip.
+	PackageFormat        string   `protobuf:"bytes,4,opt,name=package_format,json=packageFormat,proto3" json:"package_format,omitempty"`
+	Targets              []string `protobuf:"bytes,5,rep,name=targets,proto3" json:"targets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InstallRequest) Reset()         { *m = InstallRequest{} }
+func (m *InstallRequest) String() string { return proto.CompactTextString(m) }
+func (*InstallRequest) ProtoMessage()    {}
+func (*InstallRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{24}
+}
+
+func (m *InstallRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InstallRequest.Unmarshal(m, b)
+}
+func (m *InstallRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InstallRequest.Marshal(b, m, deterministic)
+}
+func (m *InstallRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InstallRequest.Merge(m, src)
+}
+func (m *InstallRequest) XXX_Size() int {
+	return xxx_messageInfo_InstallRequest.Size(m)
+}
+func (m *InstallRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InstallRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InstallRequest proto.InternalMessageInfo
+
+func (m *InstallRequest) GetConnection() *Connection {
+	if m != nil {
+		return m.Connection
+	}
+	return nil
+}
+
+func (m *InstallRequest) GetChaincode() *Chaincode {
+	if m != nil {
+		return m.Chaincode
+	}
+	return nil
+}
+
+func (m *InstallRequest) GetPackage() []byte {
+	if m != nil {
+		return m.Package
+	}
+	return nil
+}
+
+func (m *InstallRequest) GetPackageFormat() string {
+	if m != nil {
+		return m.PackageFormat
+	}
+	return ""
+}
+
+func (m *InstallRequest) GetTargets() []string {
+	if m != nil {
+		return m.Targets
+	}
+	return nil
+}
+
+// InstallResult result of installing on a peer.
+type InstallResult struct {
+	Target string `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	// 0 is success.
+	Code                 uint32   `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Message              string   `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InstallResult) Reset()         { *m = InstallResult{} }
+func (m *InstallResult) String() string { return proto.CompactTextString(m) }
+func (*InstallResult) ProtoMessage()    {}
+func (*InstallResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{25}
+}
+
+func (m *InstallResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InstallResult.Unmarshal(m, b)
+}
+func (m *InstallResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InstallResult.Marshal(b, m, deterministic)
+}
+func (m *InstallResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InstallResult.Merge(m, src)
+}
+func (m *InstallResult) XXX_Size() int {
+	return xxx_messageInfo_InstallResult.Size(m)
+}
+func (m *InstallResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_InstallResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InstallResult proto.InternalMessageInfo
+
+func (m *InstallResult) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *InstallResult) GetCode() uint32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *InstallResult) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+// InstallResponse result of InstallChaincode.
+type InstallResponse struct {
+	Results              []*InstallResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *InstallResponse) Reset()         { *m = InstallResponse{} }
+func (m *InstallResponse) String() string { return proto.CompactTextString(m) }
+func (*InstallResponse) ProtoMessage()    {}
+func (*InstallResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{26}
+}
+
+func (m *InstallResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InstallResponse.Unmarshal(m, b)
+}
+func (m *InstallResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InstallResponse.Marshal(b, m, deterministic)
+}
+func (m *InstallResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InstallResponse.Merge(m, src)
+}
+func (m *InstallResponse) XXX_Size() int {
+	return xxx_messageInfo_InstallResponse.Size(m)
+}
+func (m *InstallResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InstallResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InstallResponse proto.InternalMessageInfo
+
+func (m *InstallResponse) GetResults() []*InstallResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// InstantiateRequest to instantiate or upgrade a chaincode.
+type InstantiateRequest struct {
+	Connection           *Connection `protobuf:"bytes,1,opt,name=connection,proto3" json:"connection,omitempty"`
+	Chaincode            *Chaincode  `protobuf:"bytes,2,opt,name=chaincode,proto3" json:"chaincode,omitempty"`
+	Target               string      `protobuf:"bytes,3,opt,name=target,proto3" json:"target,omitempty"`
+	Orderer              string      `protobuf:"bytes,4,opt,name=orderer,proto3" json:"orderer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *InstantiateRequest) Reset()         { *m = InstantiateRequest{} }
+func (m *InstantiateRequest) String() string { return proto.CompactTextString(m) }
+func (*InstantiateRequest) ProtoMessage()    {}
+func (*InstantiateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{27}
+}
+
+func (m *InstantiateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InstantiateRequest.Unmarshal(m, b)
+}
+func (m *InstantiateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InstantiateRequest.Marshal(b, m, deterministic)
+}
+func (m *InstantiateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InstantiateRequest.Merge(m, src)
+}
+func (m *InstantiateRequest) XXX_Size() int {
+	return xxx_messageInfo_InstantiateRequest.Size(m)
+}
+func (m *InstantiateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InstantiateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InstantiateRequest proto.InternalMessageInfo
+
+func (m *InstantiateRequest) GetConnection() *Connection {
+	if m != nil {
+		return m.Connection
+	}
+	return nil
+}
+
+func (m *InstantiateRequest) GetChaincode() *Chaincode {
+	if m != nil {
+		return m.Chaincode
+	}
+	return nil
+}
+
+func (m *InstantiateRequest) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *InstantiateRequest) GetOrderer() string {
+	if m != nil {
+		return m.Orderer
+	}
+	return ""
+}
+
+// InstantiateResponse result of InstantiateChaincode and UpgradeChaincode.
+type InstantiateResponse struct {
+	TransactionId        string   `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InstantiateResponse) Reset()         { *m = InstantiateResponse{} }
+func (m *InstantiateResponse) String() string { return proto.CompactTextString(m) }
+func (*InstantiateResponse) ProtoMessage()    {}
+func (*InstantiateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{28}
+}
+
+func (m *InstantiateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InstantiateResponse.Unmarshal(m, b)
+}
+func (m *InstantiateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InstantiateResponse.Marshal(b, m, deterministic)
+}
+func (m *InstantiateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InstantiateResponse.Merge(m, src)
+}
+func (m *InstantiateResponse) XXX_Size() int {
+	return xxx_messageInfo_InstantiateResponse.Size(m)
+}
+func (m *InstantiateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InstantiateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InstantiateResponse proto.InternalMessageInfo
+
+func (m *InstantiateResponse) GetTransactionId() string {
+	if m != nil {
+		return m.TransactionId
+	}
+	return ""
+}
+
+// CreateChannelRequest to create a channel.
+type CreateChannelRequest struct {
+	Connection           *Connection `protobuf:"bytes,1,opt,name=connection,proto3" json:"connection,omitempty"`
+	TxContent            []byte      `protobuf:"bytes,2,opt,name=tx_content,json=txContent,proto3" json:"tx_content,omitempty"`
+	Orderer              string      `protobuf:"bytes,3,opt,name=orderer,proto3" json:"orderer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *CreateChannelRequest) Reset()         { *m = CreateChannelRequest{} }
+func (m *CreateChannelRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateChannelRequest) ProtoMessage()    {}
+func (*CreateChannelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{29}
+}
+
+func (m *CreateChannelRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateChannelRequest.Unmarshal(m, b)
+}
+func (m *CreateChannelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateChannelRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateChannelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateChannelRequest.Merge(m, src)
+}
+func (m *CreateChannelRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateChannelRequest.Size(m)
+}
+func (m *CreateChannelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateChannelRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateChannelRequest proto.InternalMessageInfo
+
+func (m *CreateChannelRequest) GetConnection() *Connection {
+	if m != nil {
+		return m.Connection
+	}
+	return nil
+}
+
+func (m *CreateChannelRequest) GetTxContent() []byte {
+	if m != nil {
+		return m.TxContent
+	}
+	return nil
+}
+
+func (m *CreateChannelRequest) GetOrderer() string {
+	if m != nil {
+		return m.Orderer
+	}
+	return ""
+}
+
+// CreateChannelResponse result of CreateChannel.
+type CreateChannelResponse struct {
+	ChannelId            string   `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateChannelResponse) Reset()         { *m = CreateChannelResponse{} }
+func (m *CreateChannelResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateChannelResponse) ProtoMessage()    {}
+func (*CreateChannelResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{30}
+}
+
+func (m *CreateChannelResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateChannelResponse.Unmarshal(m, b)
+}
+func (m *CreateChannelResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateChannelResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateChannelResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateChannelResponse.Merge(m, src)
+}
+func (m *CreateChannelResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateChannelResponse.Size(m)
+}
+func (m *CreateChannelResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateChannelResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateChannelResponse proto.InternalMessageInfo
+
+func (m *CreateChannelResponse) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+// JoinChannelRequest to join peers to a channel.
+type JoinChannelRequest struct {
+	Connection           *Connection `protobuf:"bytes,1,opt,name=connection,proto3" json:"connection,omitempty"`
+	ChannelId            string      `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	Targets              []string    `protobuf:"bytes,3,rep,name=targets,proto3" json:"targets,omitempty"`
+	Orderer              string      `protobuf:"bytes,4,opt,name=orderer,proto3" json:"orderer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *JoinChannelRequest) Reset()         { *m = JoinChannelRequest{} }
+func (m *JoinChannelRequest) String() string { return proto.CompactTextString(m) }
+func (*JoinChannelRequest) ProtoMessage()    {}
+func (*JoinChannelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{31}
+}
+
+func (m *JoinChannelRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JoinChannelRequest.Unmarshal(m, b)
+}
+func (m *JoinChannelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JoinChannelRequest.Marshal(b, m, deterministic)
+}
+func (m *JoinChannelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JoinChannelRequest.Merge(m, src)
+}
+func (m *JoinChannelRequest) XXX_Size() int {
+	return xxx_messageInfo_JoinChannelRequest.Size(m)
+}
+func (m *JoinChannelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_JoinChannelRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JoinChannelRequest proto.InternalMessageInfo
+
+func (m *JoinChannelRequest) GetConnection() *Connection {
+	if m != nil {
+		return m.Connection
+	}
+	return nil
+}
+
+func (m *JoinChannelRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *JoinChannelRequest) GetTargets() []string {
+	if m != nil {
+		return m.Targets
+	}
+	return nil
+}
+
+func (m *JoinChannelRequest) GetOrderer() string {
+	if m != nil {
+		return m.Orderer
+	}
+	return ""
+}
+
+// JoinChannelResponse result of JoinChannel.
+type JoinChannelResponse struct {
+	ChannelId            string   `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JoinChannelResponse) Reset()         { *m = JoinChannelResponse{} }
+func (m *JoinChannelResponse) String() string { return proto.CompactTextString(m) }
+func (*JoinChannelResponse) ProtoMessage()    {}
+func (*JoinChannelResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{32}
+}
+
+func (m *JoinChannelResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JoinChannelResponse.Unmarshal(m, b)
+}
+func (m *JoinChannelResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JoinChannelResponse.Marshal(b, m, deterministic)
+}
+func (m *JoinChannelResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JoinChannelResponse.Merge(m, src)
+}
+func (m *JoinChannelResponse) XXX_Size() int {
+	return xxx_messageInfo_JoinChannelResponse.Size(m)
+}
+func (m *JoinChannelResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_JoinChannelResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JoinChannelResponse proto.InternalMessageInfo
+
+func (m *JoinChannelResponse) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+// BlockEventsRequest to receive block events.
+type BlockEventsRequest struct {
+	Connection           *Connection `protobuf:"bytes,1,opt,name=connection,proto3" json:"connection,omitempty"`
+	ChannelId            string      `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *BlockEventsRequest) Reset()         { *m = BlockEventsRequest{} }
+func (m *BlockEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*BlockEventsRequest) ProtoMessage()    {}
+func (*BlockEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{33}
+}
+
+func (m *BlockEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockEventsRequest.Unmarshal(m, b)
+}
+func (m *BlockEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *BlockEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockEventsRequest.Merge(m, src)
+}
+func (m *BlockEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_BlockEventsRequest.Size(m)
+}
+func (m *BlockEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockEventsRequest proto.InternalMessageInfo
+
+func (m *BlockEventsRequest) GetConnection() *Connection {
+	if m != nil {
+		return m.Connection
+	}
+	return nil
+}
+
+func (m *BlockEventsRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+// BlockEvent a filtered block event.
+type BlockEvent struct {
+	Number   uint64 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	TxNumber int32  `protobuf:"varint,2,opt,name=tx_number,json=txNumber,proto3" json:"tx_number,omitempty"`
+	// Time received in milliseconds.
+	UpdateTime           int64    `protobuf:"varint,3,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	SourceUrl            string   `protobuf:"bytes,4,opt,name=source_url,json=sourceUrl,proto3" json:"source_url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockEvent) Reset()         { *m = BlockEvent{} }
+func (m *BlockEvent) String() string { return proto.CompactTextString(m) }
+func (*BlockEvent) ProtoMessage()    {}
+func (*BlockEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{34}
+}
+
+func (m *BlockEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockEvent.Unmarshal(m, b)
+}
+func (m *BlockEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockEvent.Marshal(b, m, deterministic)
+}
+func (m *BlockEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockEvent.Merge(m, src)
+}
+func (m *BlockEvent) XXX_Size() int {
+	return xxx_messageInfo_BlockEvent.Size(m)
+}
+func (m *BlockEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockEvent proto.InternalMessageInfo
+
+func (m *BlockEvent) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *BlockEvent) GetTxNumber() int32 {
+	if m != nil {
+		return m.TxNumber
+	}
+	return 0
+}
+
+func (m *BlockEvent) GetUpdateTime() int64 {
+	if m != nil {
+		return m.UpdateTime
+	}
+	return 0
+}
+
+func (m *BlockEvent) GetSourceUrl() string {
+	if m != nil {
+		return m.SourceUrl
+	}
+	return ""
+}
+
+// ChaincodeEventsRequest to receive chaincode events.
+type ChaincodeEventsRequest struct {
+	Connection  *Connection `protobuf:"bytes,1,opt,name=connection,proto3" json:"connection,omitempty"`
+	ChannelId   string      `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	ChaincodeId string      `protobuf:"bytes,3,opt,name=chaincode_id,json=chaincodeId,proto3" json:"chaincode_id,omitempty"`
+	// Regular expression of event names.
+	EventFilter          string   `protobuf:"bytes,4,opt,name=event_filter,json=eventFilter,proto3" json:"event_filter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChaincodeEventsRequest) Reset()         { *m = ChaincodeEventsRequest{} }
+func (m *ChaincodeEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeEventsRequest) ProtoMessage()    {}
+func (*ChaincodeEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{35}
+}
+
+func (m *ChaincodeEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeEventsRequest.Unmarshal(m, b)
+}
+func (m *ChaincodeEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *ChaincodeEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeEventsRequest.Merge(m, src)
+}
+func (m *ChaincodeEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeEventsRequest.Size(m)
+}
+func (m *ChaincodeEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeEventsRequest proto.InternalMessageInfo
+
+func (m *ChaincodeEventsRequest) GetConnection() *Connection {
+	if m != nil {
+		return m.Connection
+	}
+	return nil
+}
+
+func (m *ChaincodeEventsRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *ChaincodeEventsRequest) GetChaincodeId() string {
+	if m != nil {
+		return m.ChaincodeId
+	}
+	return ""
+}
+
+func (m *ChaincodeEventsRequest) GetEventFilter() string {
+	if m != nil {
+		return m.EventFilter
+	}
+	return ""
+}
+
+// ChaincodeEvent a chaincode event.
+type ChaincodeEvent struct {
+	TxId                 string   `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	ChaincodeId          string   `protobuf:"bytes,2,opt,name=chaincode_id,json=chaincodeId,proto3" json:"chaincode_id,omitempty"`
+	EventName            string   `protobuf:"bytes,3,opt,name=event_name,json=eventName,proto3" json:"event_name,omitempty"`
+	Payload              []byte   `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	BlockNumber          uint64   `protobuf:"varint,5,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	SourceUrl            string   `protobuf:"bytes,6,opt,name=source_url,json=sourceUrl,proto3" json:"source_url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChaincodeEvent) Reset()         { *m = ChaincodeEvent{} }
+func (m *ChaincodeEvent) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeEvent) ProtoMessage()    {}
+func (*ChaincodeEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42409024f5ece4ea, []int{36}
+}
+
+func (m *ChaincodeEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeEvent.Unmarshal(m, b)
+}
+func (m *ChaincodeEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeEvent.Marshal(b, m, deterministic)
+}
+func (m *ChaincodeEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeEvent.Merge(m, src)
+}
+func (m *ChaincodeEvent) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeEvent.Size(m)
+}
+func (m *ChaincodeEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeEvent proto.InternalMessageInfo
+
+func (m *ChaincodeEvent) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *ChaincodeEvent) GetChaincodeId() string {
+	if m != nil {
+		return m.ChaincodeId
+	}
+	return ""
+}
+
+func (m *ChaincodeEvent) GetEventName() string {
+	if m != nil {
+		return m.EventName
+	}
+	return ""
+}
+
+func (m *ChaincodeEvent) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *ChaincodeEvent) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *ChaincodeEvent) GetSourceUrl() string {
+	if m != nil {
+		return m.SourceUrl
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("fablet.ActionType", ActionType_name, ActionType_value)
+	proto.RegisterType((*Connection)(nil), "fablet.Connection")
+	proto.RegisterType((*PKCS11Config)(nil), "fablet.PKCS11Config")
+	proto.RegisterType((*DiscoverRequest)(nil), "fablet.DiscoverRequest")
+	proto.RegisterType((*PeerStatus)(nil), "fablet.PeerStatus")
+	proto.RegisterType((*Peer)(nil), "fablet.Peer")
+	proto.RegisterType((*Orderer)(nil), "fablet.Orderer")
+	proto.RegisterType((*Ledger)(nil), "fablet.Ledger")
+	proto.RegisterType((*Chaincode)(nil), "fablet.Chaincode")
+	proto.RegisterType((*ChannelOverview)(nil), "fablet.ChannelOverview")
+	proto.RegisterType((*NetworkOverview)(nil), "fablet.NetworkOverview")
+	proto.RegisterType((*LedgerRequest)(nil), "fablet.LedgerRequest")
+	proto.RegisterType((*BlocksRequest)(nil), "fablet.BlocksRequest")
+	proto.RegisterType((*BlocksResponse)(nil), "fablet.BlocksResponse")
+	proto.RegisterType((*BlockRequest)(nil), "fablet.BlockRequest")
+	proto.RegisterType((*Endorser)(nil), "fablet.Endorser")
+	proto.RegisterType((*KVRead)(nil), "fablet.KVRead")
+	proto.RegisterType((*KVWrite)(nil), "fablet.KVWrite")
+	proto.RegisterType((*NSReadWriteSet)(nil), "fablet.NSReadWriteSet")
+	proto.RegisterType((*Action)(nil), "fablet.Action")
+	proto.RegisterType((*Transaction)(nil), "fablet.Transaction")
+	proto.RegisterType((*Block)(nil), "fablet.Block")
+	proto.RegisterType((*ExecuteRequest)(nil), "fablet.ExecuteRequest")
+	proto.RegisterType((*PeerResponse)(nil), "fablet.PeerResponse")
+	proto.RegisterType((*ExecuteResponse)(nil), "fablet.ExecuteResponse")
+	proto.RegisterType((*InstallRequest)(nil), "fablet.InstallRequest")
+	proto.RegisterType((*InstallResult)(nil), "fablet.InstallResult")
+	proto.RegisterType((*InstallResponse)(nil), "fablet.InstallResponse")
+	proto.RegisterType((*InstantiateRequest)(nil), "fablet.InstantiateRequest")
+	proto.RegisterType((*InstantiateResponse)(nil), "fablet.InstantiateResponse")
+	proto.RegisterType((*CreateChannelRequest)(nil), "fablet.CreateChannelRequest")
+	proto.RegisterType((*CreateChannelResponse)(nil), "fablet.CreateChannelResponse")
+	proto.RegisterType((*JoinChannelRequest)(nil), "fablet.JoinChannelRequest")
+	proto.RegisterType((*JoinChannelResponse)(nil), "fablet.JoinChannelResponse")
+	proto.RegisterType((*BlockEventsRequest)(nil), "fablet.BlockEventsRequest")
+	proto.RegisterType((*BlockEvent)(nil), "fablet.BlockEvent")
+	proto.RegisterType((*ChaincodeEventsRequest)(nil), "fablet.ChaincodeEventsRequest")
+	proto.RegisterType((*ChaincodeEvent)(nil), "fablet.ChaincodeEvent")
+}
+
+func init() { proto.RegisterFile("fablet.proto", fileDescriptor_42409024f5ece4ea) }
+
+var fileDescriptor_42409024f5ece4ea = []byte{
+	// 2133 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x19, 0xdb, 0x8e, 0x23, 0x47,
+	0x95, 0x1e, 0xdb, 0x6d, 0xfb, 0xb4, 0x6f, 0xa9, 0x9d, 0x9d, 0x35, 0xde, 0x9d, 0x64, 0xb7, 0x73,
+	0x5b, 0x92, 0xb0, 0xc9, 0xcc, 0x46, 0xe4, 0x81, 0x48, 0x88, 0x75, 0x66, 0xc5, 0x64, 0x56, 0xb3,
+	0x9b, 0x9e, 0x99, 0xe5, 0x22, 0xa4, 0x56, 0xbb, 0xbb, 0xc6, 0x6e, 0xa6, 0xdd, 0xdd, 0xa9, 0xaa,
+	0xf6, 0xd8, 0x12, 0x20, 0x01, 0x3f, 0x81, 0xc4, 0x2b, 0x6f, 0x3c, 0x20, 0xfe, 0x00, 0x89, 0x07,
+	0x9e, 0x90, 0x78, 0x44, 0xe2, 0x07, 0x78, 0xe0, 0x23, 0x50, 0xdd, 0xfa, 0xe2, 0xf1, 0x44, 0x81,
+	0xcd, 0x92, 0xb7, 0x3a, 0x97, 0x3e, 0x75, 0x6e, 0x75, 0xce, 0xa9, 0x6a, 0xe8, 0x9c, 0x7b, 0x93,
+	0x08, 0xb3, 0x07, 0x29, 0x49, 0x58, 0x82, 0x4c, 0x09, 0xd9, 0x7f, 0xdc, 0x02, 0x18, 0x27, 0x71,
+	0x8c, 0x7d, 0x16, 0x26, 0x31, 0xda, 0x86, 0x46, 0xe4, 0x4d, 0x70, 0x34, 0x34, 0xee, 0x1a, 0xf7,
+	0xdb, 0x8e, 0x04, 0xd0, 0x4d, 0x30, 0xe7, 0x34, 0x75, 0xc3, 0x60, 0xb8, 0x25, 0xd1, 0x73, 0x9a,
+	0x1e, 0x06, 0xe8, 0x1e, 0x74, 0x7c, 0x4c, 0x98, 0xeb, 0x27, 0x31, 0xc3, 0x31, 0x1b, 0xd6, 0x04,
+	0xd1, 0xe2, 0xb8, 0xb1, 0x44, 0xa1, 0xb7, 0xa0, 0x9f, 0x92, 0x85, 0x7b, 0x81, 0x57, 0x39, 0x57,
+	0x5d, 0x70, 0x75, 0x53, 0xb2, 0x38, 0xc2, 0x2b, 0xcd, 0xc7, 0x45, 0x25, 0x71, 0xec, 0xa6, 0x24,
+	0x39, 0x0f, 0x23, 0x3c, 0x6c, 0x28, 0x51, 0x49, 0x1c, 0x3f, 0x93, 0x28, 0x74, 0x07, 0xda, 0x97,
+	0x09, 0xb9, 0xa0, 0xa9, 0xe7, 0xe3, 0xa1, 0x29, 0xe8, 0x05, 0x02, 0x8d, 0xa0, 0x15, 0x06, 0x38,
+	0x66, 0x21, 0x5b, 0x0d, 0x9b, 0x82, 0x98, 0xc3, 0xe8, 0x55, 0x80, 0xd4, 0xa3, 0x34, 0x9d, 0x11,
+	0x8f, 0xe2, 0x61, 0x4b, 0x50, 0x4b, 0x18, 0xf4, 0x1e, 0x98, 0xe9, 0x85, 0x4f, 0xf7, 0xf6, 0x86,
+	0xed, 0xbb, 0xc6, 0x7d, 0x6b, 0x7f, 0xfb, 0x81, 0x72, 0xd5, 0xb3, 0xa3, 0xf1, 0xc9, 0xde, 0xde,
+	0x38, 0x89, 0xcf, 0xc3, 0xa9, 0xa3, 0x78, 0xec, 0x0b, 0xe8, 0x94, 0xf1, 0x68, 0x08, 0xcd, 0x28,
+	0x9c, 0x10, 0x8f, 0xac, 0x94, 0xd3, 0x34, 0x88, 0x10, 0xd4, 0x69, 0x94, 0x30, 0xe1, 0xb4, 0xae,
+	0x23, 0xd6, 0x68, 0x00, 0xb5, 0x34, 0x8c, 0x95, 0xab, 0xf8, 0x12, 0xdd, 0x86, 0x36, 0x77, 0x8f,
+	0x74, 0xbb, 0x74, 0x4e, 0xeb, 0x02, 0xaf, 0x9e, 0x70, 0xd8, 0x76, 0xa1, 0xff, 0x49, 0x48, 0xfd,
+	0x64, 0x81, 0x89, 0x83, 0x3f, 0xcf, 0x30, 0x65, 0x68, 0x1f, 0xc0, 0xcf, 0x03, 0x26, 0xb6, 0xb4,
+	0xf6, 0x91, 0xd6, 0xb8, 0x08, 0xa5, 0x53, 0xe2, 0xe2, 0x3a, 0x12, 0x7c, 0x4e, 0x30, 0x9d, 0x09,
+	0x65, 0x5a, 0x8e, 0x06, 0xed, 0x4f, 0x01, 0x9e, 0x61, 0x4c, 0x4e, 0x98, 0xc7, 0x32, 0xca, 0x35,
+	0x4e, 0xc3, 0x78, 0x2a, 0xa4, 0xb6, 0x1c, 0xb1, 0xe6, 0xb8, 0x29, 0x49, 0x7d, 0xf5, 0xa1, 0x58,
+	0xf3, 0x34, 0x59, 0x78, 0x51, 0x18, 0x08, 0x3b, 0x5a, 0x8e, 0x04, 0xec, 0x7f, 0x1b, 0x50, 0xe7,
+	0xc2, 0xf8, 0x27, 0xb1, 0x37, 0xc7, 0xca, 0x1f, 0x62, 0x8d, 0xbe, 0x09, 0xad, 0x84, 0x4c, 0x5d,
+	0x81, 0x97, 0x59, 0xd4, 0x4c, 0xc8, 0xf4, 0x98, 0x93, 0x8a, 0xf4, 0xaa, 0x95, 0xd3, 0x6b, 0x00,
+	0xb5, 0x8c, 0x68, 0x97, 0xf0, 0x25, 0x0f, 0xb2, 0x3f, 0xf3, 0xe2, 0x18, 0x47, 0x74, 0xd8, 0xb8,
+	0x5b, 0xe3, 0x9e, 0xd2, 0x30, 0x7a, 0x1d, 0xba, 0x21, 0xe5, 0x49, 0x76, 0x1e, 0x4e, 0x33, 0x82,
+	0x03, 0x91, 0x22, 0x2d, 0xa7, 0x13, 0xd2, 0x71, 0x8e, 0x43, 0xaf, 0x81, 0x95, 0xa5, 0x81, 0xc7,
+	0xb0, 0xcb, 0xc2, 0x39, 0x16, 0x89, 0x52, 0x73, 0x40, 0xa2, 0x4e, 0xc3, 0x39, 0x46, 0xef, 0x80,
+	0x49, 0x85, 0x2b, 0x86, 0xad, 0xaa, 0x63, 0x0b, 0x27, 0x39, 0x8a, 0xc3, 0x3e, 0x82, 0xe6, 0x53,
+	0x12, 0x60, 0x72, 0x8d, 0xc1, 0x4a, 0xfd, 0xad, 0xcd, 0xea, 0xd7, 0xaa, 0xea, 0xdb, 0xbf, 0x36,
+	0xc0, 0x7c, 0x82, 0x83, 0x29, 0x26, 0x68, 0x07, 0xcc, 0x19, 0x0e, 0xa7, 0x33, 0x26, 0xc4, 0xd5,
+	0x1d, 0x05, 0xa1, 0xf7, 0x00, 0xf9, 0x19, 0x21, 0x38, 0x66, 0xee, 0x24, 0x4a, 0xfc, 0x0b, 0x77,
+	0xe6, 0xa9, 0x78, 0xb6, 0x9d, 0x81, 0xa2, 0x3c, 0xe2, 0x84, 0x1f, 0x78, 0x74, 0xc6, 0x37, 0xc3,
+	0x71, 0x90, 0x10, 0x8a, 0x89, 0x72, 0x6b, 0x0e, 0xf3, 0x1d, 0x94, 0x95, 0xdc, 0xb9, 0x8d, 0xdc,
+	0xa2, 0x7f, 0x18, 0xd0, 0x1e, 0xcf, 0xbc, 0x30, 0xf6, 0x93, 0x00, 0x6f, 0x34, 0x6a, 0x08, 0xcd,
+	0x05, 0x26, 0x94, 0x67, 0x9e, 0x0a, 0xa2, 0x02, 0x45, 0xea, 0x78, 0x6c, 0xa6, 0xf6, 0x12, 0x6b,
+	0x8e, 0x63, 0xab, 0x14, 0xab, 0x10, 0x8a, 0x35, 0xda, 0x05, 0x50, 0x46, 0xf3, 0x80, 0xcb, 0x73,
+	0xde, 0x56, 0x98, 0xc3, 0x80, 0xab, 0x96, 0x26, 0x51, 0xe8, 0xaf, 0xd4, 0x11, 0x57, 0x10, 0xba,
+	0x0b, 0xbc, 0x18, 0x50, 0x46, 0x32, 0x9f, 0x25, 0x64, 0xd8, 0x14, 0xee, 0x2b, 0xa3, 0x78, 0x7d,
+	0x08, 0x63, 0xca, 0xbc, 0x28, 0xc2, 0x81, 0x88, 0x5e, 0xcb, 0x29, 0x10, 0xf6, 0x3f, 0x0d, 0xe8,
+	0x8f, 0xe5, 0x2e, 0x4f, 0x17, 0x98, 0x2c, 0x42, 0x7c, 0xb9, 0xa6, 0x8a, 0xb1, 0xae, 0xca, 0x5b,
+	0x60, 0x46, 0x22, 0x22, 0xc2, 0x54, 0x6b, 0xbf, 0xa7, 0x73, 0x41, 0xc6, 0xc9, 0x51, 0x54, 0xb4,
+	0x27, 0xc4, 0x48, 0xa7, 0xc9, 0xc0, 0x5a, 0xfb, 0xaf, 0xe4, 0x07, 0x52, 0x53, 0x9c, 0x12, 0x13,
+	0x7a, 0x97, 0x1f, 0x06, 0x91, 0x3a, 0x3c, 0x04, 0xfc, 0x83, 0xbe, 0xfe, 0x40, 0xa5, 0x94, 0x93,
+	0x33, 0xf0, 0xda, 0xe8, 0xc5, 0xfe, 0x2c, 0x21, 0x6e, 0x8a, 0x31, 0xd1, 0x99, 0x6f, 0x49, 0x1c,
+	0xcf, 0x4b, 0x6a, 0xff, 0x12, 0xfa, 0xc7, 0x98, 0xf1, 0x6a, 0x98, 0x1b, 0x87, 0xa0, 0xce, 0x0b,
+	0x80, 0x8e, 0x1e, 0x5f, 0x23, 0x1b, 0x1a, 0x52, 0xc4, 0x96, 0xd8, 0xb3, 0x53, 0x4e, 0x6e, 0x47,
+	0x92, 0xd0, 0xc3, 0xb5, 0x24, 0xb5, 0xf6, 0x6f, 0x95, 0x6c, 0x29, 0xfb, 0xaf, 0x94, 0xbd, 0x3f,
+	0x87, 0xae, 0x72, 0xca, 0x0b, 0x14, 0xa9, 0x6a, 0x38, 0xb6, 0xd6, 0xc3, 0x31, 0x84, 0x26, 0xf3,
+	0xc8, 0x14, 0x33, 0x7d, 0x78, 0x34, 0x68, 0xff, 0xde, 0x80, 0xae, 0x48, 0x7c, 0xfa, 0x75, 0x6c,
+	0xcf, 0x8b, 0xe1, 0x04, 0x4f, 0xc3, 0x58, 0xa4, 0x79, 0xdd, 0x91, 0x00, 0x3f, 0xfe, 0x11, 0x8e,
+	0x45, 0x82, 0xd7, 0x1d, 0xbe, 0xb4, 0x3f, 0x82, 0x9e, 0xd6, 0x92, 0xa6, 0x49, 0x4c, 0x31, 0x7a,
+	0x13, 0x4c, 0x71, 0x92, 0xe9, 0xd0, 0x10, 0x9e, 0xee, 0x6a, 0x15, 0x05, 0x9f, 0xa3, 0x88, 0xf6,
+	0x6f, 0x0d, 0xe8, 0x48, 0xcc, 0xd7, 0x61, 0xde, 0x6d, 0x68, 0x7f, 0x9e, 0x61, 0xb2, 0xe2, 0x4d,
+	0x5c, 0xf7, 0x27, 0x81, 0x38, 0xc2, 0x2b, 0x9b, 0x41, 0xeb, 0x40, 0x57, 0x95, 0xd7, 0xf8, 0x11,
+	0x9d, 0xcf, 0x93, 0xd8, 0x2d, 0x95, 0x0d, 0x90, 0xa8, 0x63, 0x55, 0x3c, 0x68, 0x36, 0xf9, 0x19,
+	0xf6, 0x99, 0x2e, 0x1e, 0x0a, 0xbc, 0xae, 0x03, 0xec, 0x80, 0x19, 0x52, 0x9a, 0x61, 0xa2, 0xf6,
+	0x55, 0x90, 0xfd, 0x53, 0x30, 0x8f, 0x9e, 0x3b, 0xd8, 0x13, 0x3d, 0x82, 0xab, 0x25, 0xf7, 0xe2,
+	0x4b, 0x64, 0x43, 0x77, 0x81, 0x89, 0xaa, 0x90, 0x71, 0x36, 0x17, 0x5b, 0xd5, 0x1d, 0x6b, 0x81,
+	0x89, 0xf0, 0xe1, 0x71, 0x36, 0x47, 0x77, 0x00, 0x38, 0x0f, 0x5b, 0x0a, 0x86, 0x9a, 0x60, 0x68,
+	0x2d, 0x30, 0x39, 0x5d, 0x1e, 0x67, 0x73, 0xfb, 0x18, 0x9a, 0x47, 0xcf, 0x7f, 0x48, 0x42, 0x86,
+	0x37, 0x88, 0xbf, 0x0d, 0xed, 0x90, 0xba, 0x01, 0x8e, 0x30, 0xc3, 0xaa, 0x25, 0xb6, 0x42, 0xfa,
+	0x89, 0x80, 0x55, 0x5b, 0xcc, 0xb0, 0xb6, 0x42, 0x00, 0xf6, 0x2f, 0xa0, 0x77, 0x7c, 0xc2, 0xb5,
+	0x15, 0x32, 0x4f, 0x30, 0xe3, 0xa5, 0x8a, 0xbb, 0x48, 0x8e, 0x32, 0xaa, 0xee, 0xe4, 0x08, 0xf4,
+	0x06, 0x34, 0x08, 0xf6, 0x02, 0x7d, 0x4a, 0xf3, 0xb2, 0x23, 0x4d, 0x76, 0x24, 0x11, 0xbd, 0x0d,
+	0xe6, 0x25, 0x97, 0xa7, 0x4f, 0x69, 0xbf, 0x60, 0x13, 0xfb, 0x38, 0x8a, 0xcc, 0x2b, 0x9f, 0xf9,
+	0x7d, 0x99, 0x03, 0x6f, 0x42, 0x2f, 0x2f, 0x42, 0xe5, 0x20, 0x75, 0x73, 0xac, 0x88, 0xd3, 0xbb,
+	0xf0, 0x4a, 0xc1, 0x56, 0x2d, 0xf7, 0x83, 0x9c, 0xf0, 0x5c, 0xe2, 0xb9, 0x2d, 0x1e, 0x99, 0x66,
+	0x73, 0x1c, 0xe7, 0xa9, 0x53, 0x20, 0xd0, 0x03, 0x68, 0xeb, 0xae, 0xa3, 0x2b, 0xdd, 0x40, 0x2b,
+	0xaa, 0x13, 0xc7, 0x29, 0x58, 0xd0, 0xfb, 0xd0, 0x24, 0x97, 0x2e, 0xc5, 0x4c, 0x96, 0x39, 0x6b,
+	0x7f, 0x47, 0x73, 0x57, 0x5d, 0xe8, 0x98, 0xe4, 0xf2, 0x84, 0x9f, 0xfd, 0x0c, 0xac, 0x53, 0xe2,
+	0xc5, 0xd4, 0x93, 0x16, 0xde, 0x80, 0x06, 0x5b, 0x16, 0xd5, 0xbc, 0xce, 0x96, 0x87, 0x01, 0x7a,
+	0x1b, 0xfa, 0x62, 0x40, 0xf1, 0x38, 0x8b, 0xcb, 0x95, 0x57, 0xd6, 0xf4, 0x0a, 0xf4, 0x98, 0x77,
+	0xbc, 0xfb, 0xd0, 0x94, 0x72, 0xb4, 0x53, 0x73, 0xdf, 0x4b, 0x07, 0x3a, 0x9a, 0x6c, 0xff, 0xcd,
+	0x80, 0x86, 0x48, 0x27, 0x9e, 0xa3, 0x71, 0x36, 0x9f, 0x60, 0xa2, 0xbb, 0xb5, 0x84, 0x78, 0xa2,
+	0x04, 0x1e, 0xf3, 0xca, 0x4d, 0xba, 0xc5, 0x11, 0xa2, 0x39, 0xbf, 0x0e, 0xdd, 0x94, 0xe0, 0x45,
+	0x98, 0x64, 0x54, 0x32, 0xc8, 0x84, 0xe9, 0x68, 0xa4, 0x60, 0xda, 0x05, 0x28, 0xf5, 0x79, 0x79,
+	0x02, 0xda, 0x93, 0xbc, 0xc1, 0x7f, 0x04, 0x1d, 0x56, 0x58, 0xae, 0xfd, 0x75, 0x43, 0x6b, 0x5c,
+	0xf2, 0x8a, 0x53, 0x61, 0x14, 0x5d, 0x99, 0x4f, 0x3f, 0xa6, 0x98, 0x7e, 0xc4, 0xda, 0xfe, 0xeb,
+	0x16, 0xf4, 0x0e, 0x96, 0xd8, 0xcf, 0x18, 0x7e, 0x89, 0x45, 0x86, 0x4f, 0xf9, 0x79, 0x62, 0xe5,
+	0x87, 0xdd, 0xca, 0x71, 0x87, 0x01, 0x7a, 0x08, 0x96, 0xd4, 0xd3, 0xcd, 0x27, 0x87, 0xde, 0x3e,
+	0xaa, 0x86, 0xe1, 0x74, 0x95, 0x62, 0x07, 0xbc, 0x7c, 0xcd, 0xdd, 0x79, 0x9e, 0xc5, 0xf2, 0x33,
+	0x91, 0xd6, 0x72, 0xac, 0xe8, 0x68, 0xa4, 0xc8, 0xea, 0x4a, 0xa2, 0x9a, 0xeb, 0x89, 0x5a, 0xaa,
+	0x7f, 0xcd, 0x6a, 0xfd, 0xfb, 0x36, 0x20, 0x8a, 0x23, 0x69, 0xa0, 0x4b, 0x19, 0xf1, 0x18, 0x9e,
+	0xae, 0xd4, 0x2d, 0xe2, 0x95, 0x9c, 0x72, 0xa2, 0x08, 0xf6, 0x02, 0x3a, 0xa2, 0x9d, 0xea, 0x1a,
+	0x5f, 0x9e, 0xc3, 0x8c, 0xb5, 0x39, 0x6c, 0x6d, 0x9a, 0x6a, 0x14, 0xd3, 0xd4, 0x10, 0x9a, 0xa9,
+	0xb7, 0x8a, 0x12, 0x4f, 0x3b, 0x49, 0x83, 0xd7, 0xce, 0x6e, 0xff, 0x32, 0xa0, 0x9f, 0x47, 0x30,
+	0xef, 0x2f, 0xbd, 0x52, 0xe4, 0x8b, 0x63, 0xd1, 0x2d, 0x61, 0x0f, 0x03, 0x3e, 0x58, 0xb2, 0xa5,
+	0xbb, 0xf9, 0x88, 0x0c, 0xd8, 0xf2, 0x79, 0xf5, 0x90, 0x7c, 0x0b, 0x8a, 0x22, 0xe0, 0x2a, 0x55,
+	0x6a, 0x42, 0x95, 0x7e, 0x8e, 0x57, 0xd7, 0x89, 0x92, 0x15, 0xf5, 0xaa, 0x15, 0xdf, 0x85, 0x1e,
+	0x1f, 0x37, 0x5c, 0xa2, 0x54, 0xd5, 0xe9, 0xbb, 0x5d, 0x19, 0x49, 0x14, 0xd1, 0xe9, 0xa6, 0x25,
+	0x88, 0xda, 0x7f, 0x37, 0xa0, 0x77, 0x28, 0x27, 0xbb, 0x17, 0x49, 0xd6, 0xf7, 0xa1, 0x9d, 0x2b,
+	0xac, 0x46, 0xbc, 0x0d, 0x63, 0x5b, 0xc1, 0x23, 0xcd, 0xf1, 0x2f, 0xbc, 0xa9, 0x2c, 0xf0, 0x1d,
+	0x47, 0x83, 0xdc, 0xd1, 0x6a, 0xe9, 0x9e, 0x27, 0x64, 0xee, 0x15, 0xb7, 0x5c, 0x89, 0x7d, 0x2c,
+	0x90, 0xe5, 0x24, 0x6b, 0x54, 0x47, 0x98, 0x33, 0xe8, 0xe6, 0x16, 0xd1, 0x2c, 0x62, 0x3c, 0xcc,
+	0x92, 0xa6, 0x42, 0xa6, 0x20, 0x39, 0xd6, 0x29, 0x7d, 0xbb, 0x4e, 0x5d, 0xeb, 0x35, 0xc7, 0x94,
+	0x6a, 0xbd, 0xda, 0x8e, 0x06, 0xed, 0x47, 0xd0, 0x2f, 0xc4, 0xca, 0x9c, 0xe0, 0x15, 0x56, 0x6c,
+	0xa1, 0x87, 0x8e, 0x9b, 0xda, 0xe6, 0x8a, 0x02, 0x8e, 0xe6, 0xb2, 0xff, 0x60, 0x00, 0x12, 0xa4,
+	0x98, 0x85, 0x1e, 0xc3, 0xff, 0x57, 0x8f, 0x17, 0x5e, 0xa8, 0x55, 0xbc, 0x30, 0x84, 0xa6, 0x1a,
+	0x8f, 0x75, 0x62, 0x29, 0xd0, 0xfe, 0x18, 0x6e, 0x54, 0x94, 0xfd, 0xaf, 0x4e, 0x82, 0xfd, 0x1b,
+	0x03, 0xb6, 0xc7, 0x04, 0x7b, 0x0c, 0xab, 0x59, 0xf7, 0x05, 0x8b, 0x21, 0x5b, 0xe6, 0xcf, 0x1e,
+	0x5b, 0x22, 0x63, 0xda, 0x6c, 0xa9, 0x9f, 0x3c, 0x4a, 0x36, 0xd4, 0xaa, 0x36, 0x7c, 0x07, 0x6e,
+	0xae, 0x29, 0xa1, 0xac, 0xf8, 0xe2, 0x0b, 0x8b, 0xfd, 0x3b, 0x03, 0xd0, 0xa7, 0x49, 0x18, 0x7f,
+	0x35, 0xba, 0xff, 0x6f, 0xd3, 0xe2, 0xf5, 0x91, 0xf9, 0x10, 0x6e, 0x54, 0x94, 0xfb, 0x72, 0x36,
+	0x4d, 0x01, 0x89, 0x3e, 0x7b, 0xb0, 0xe0, 0x65, 0xfa, 0xe5, 0x99, 0x64, 0xff, 0xca, 0x00, 0x28,
+	0x76, 0xfa, 0xa2, 0xb6, 0x2e, 0xc7, 0xc6, 0x89, 0xba, 0x17, 0x36, 0x9c, 0x16, 0x5b, 0x1e, 0x4b,
+	0xe2, 0xda, 0xf3, 0x42, 0xed, 0xca, 0xf3, 0xc2, 0x2e, 0x00, 0x4d, 0x32, 0xe2, 0x63, 0xb7, 0x78,
+	0xd9, 0x68, 0x4b, 0xcc, 0x19, 0x89, 0xec, 0x3f, 0x19, 0xb0, 0x93, 0x9f, 0x83, 0x97, 0x6d, 0xf1,
+	0x97, 0xe9, 0xc6, 0xf7, 0xa0, 0x83, 0xb9, 0x1a, 0xee, 0x79, 0x18, 0xb1, 0x3c, 0xa4, 0x96, 0xc0,
+	0x3d, 0x16, 0x28, 0xfb, 0x2f, 0x06, 0xf4, 0xaa, 0x3a, 0x6f, 0x1e, 0xc2, 0xd6, 0x77, 0xdb, 0xba,
+	0xba, 0xdb, 0x2e, 0x80, 0xdc, 0x4d, 0xf4, 0x70, 0xa9, 0x4e, 0x5b, 0x60, 0xf4, 0xf5, 0xa1, 0xdc,
+	0x4d, 0x3a, 0x45, 0x37, 0xb9, 0x07, 0x9d, 0x7c, 0xde, 0xe7, 0x71, 0x91, 0x97, 0x2e, 0x6b, 0xa2,
+	0xe6, 0x7d, 0x1e, 0x9a, 0xaa, 0xe7, 0xcd, 0x35, 0xcf, 0xbf, 0xf3, 0x06, 0x40, 0x31, 0x5b, 0x20,
+	0x0b, 0x9a, 0x07, 0x3f, 0x3a, 0x18, 0x9f, 0x9d, 0x1e, 0x0c, 0xbe, 0x81, 0xda, 0xd0, 0xf8, 0xec,
+	0xec, 0xc0, 0xf9, 0xf1, 0xc0, 0xd8, 0xff, 0xb3, 0x09, 0xe6, 0x63, 0xe1, 0x72, 0x34, 0x2e, 0x1e,
+	0xe6, 0xd4, 0xcd, 0x1b, 0xe5, 0xf7, 0xe4, 0xb5, 0x17, 0xbb, 0x51, 0x4e, 0x58, 0xbf, 0xa3, 0x7f,
+	0x08, 0xd6, 0x67, 0xfc, 0x26, 0xa5, 0x1e, 0x7e, 0x6e, 0xae, 0x3d, 0x30, 0xa8, 0xcf, 0xd7, 0xde,
+	0x1d, 0xd0, 0xc7, 0xea, 0x2b, 0x79, 0x99, 0x2c, 0xbe, 0xaa, 0x5c, 0x81, 0x47, 0x3b, 0xeb, 0x68,
+	0x75, 0xde, 0xf6, 0x00, 0x8a, 0xaf, 0xd1, 0x76, 0x85, 0x4b, 0x7f, 0x5b, 0xbd, 0x87, 0xa2, 0x31,
+	0x0c, 0xd4, 0x64, 0x51, 0x3c, 0x0e, 0xe5, 0xe2, 0xab, 0x53, 0xe3, 0xe8, 0xd6, 0x15, 0xbc, 0xda,
+	0x77, 0x0c, 0x03, 0xd5, 0x60, 0x36, 0x08, 0xa9, 0x76, 0xf3, 0xd1, 0xad, 0x2b, 0x78, 0x25, 0xe4,
+	0x29, 0x6c, 0x97, 0xaa, 0x7b, 0x21, 0x68, 0x54, 0xf9, 0xa0, 0xd2, 0xa8, 0x46, 0xb7, 0x37, 0xd2,
+	0x94, 0xc0, 0x23, 0x18, 0x9c, 0xa5, 0x53, 0xe2, 0x05, 0x5f, 0x85, 0xb0, 0x27, 0xd0, 0xad, 0xd4,
+	0x6d, 0x74, 0x27, 0x3f, 0xa0, 0x1b, 0x7a, 0xca, 0x68, 0xf7, 0x1a, 0xaa, 0x92, 0xf6, 0x18, 0xac,
+	0x52, 0xbd, 0x2c, 0xb4, 0xba, 0x5a, 0xe1, 0x47, 0xb7, 0x37, 0xd2, 0x94, 0x9c, 0xef, 0x81, 0x55,
+	0xaa, 0xa0, 0x85, 0x9c, 0xab, 0x65, 0x75, 0x84, 0xae, 0xd2, 0x3e, 0x30, 0xd0, 0x11, 0xf4, 0x73,
+	0xe7, 0x28, 0x21, 0xaf, 0x5e, 0xe9, 0xda, 0x55, 0x41, 0x3b, 0x9b, 0xe9, 0x1f, 0x18, 0x8f, 0x1a,
+	0x3f, 0xa9, 0x91, 0xd4, 0x9f, 0x98, 0xe2, 0x2f, 0xc4, 0xc3, 0xff, 0x0c, 0x00, 0xd5, 0x56, 0xb4,
+	0xaf, 0x95, 0x18, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// FabletClient is the client API for Fablet service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type FabletClient interface {
+	// DiscoverNetwork to discover peers, channels, chaincodes and orderers of the network.
+	DiscoverNetwork(ctx context.Context, in *DiscoverRequest, opts ...grpc.CallOption) (*NetworkOverview, error)
+	// QueryLedger to query the ledger of a channel.
+	QueryLedger(ctx context.Context, in *LedgerRequest, opts ...grpc.CallOption) (*Ledger, error)
+	// QueryBlocks to query blocks from a number.
+	QueryBlocks(ctx context.Context, in *BlocksRequest, opts ...grpc.CallOption) (*BlocksResponse, error)
+	// QueryBlock to query a block by number, hash or transaction ID.
+	QueryBlock(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Block, error)
+	// ExecuteChaincode to execute or query a chaincode.
+	ExecuteChaincode(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error)
+	// InstallChaincode to install a chaincode package on peers.
+	InstallChaincode(ctx context.Context, in *InstallRequest, opts ...grpc.CallOption) (*InstallResponse, error)
+	// InstantiateChaincode to instantiate an installed chaincode in a channel.
+	InstantiateChaincode(ctx context.Context, in *InstantiateRequest, opts ...grpc.CallOption) (*InstantiateResponse, error)
+	// UpgradeChaincode to upgrade an instantiated chaincode to a new version.
+	UpgradeChaincode(ctx context.Context, in *InstantiateRequest, opts ...grpc.CallOption) (*InstantiateResponse, error)
+	// CreateChannel to create a channel by the channel transaction.
+	CreateChannel(ctx context.Context, in *CreateChannelRequest, opts ...grpc.CallOption) (*CreateChannelResponse, error)
+	// JoinChannel to join peers to a channel.
+	JoinChannel(ctx context.Context, in *JoinChannelRequest, opts ...grpc.CallOption) (*JoinChannelResponse, error)
+	// BlockEvents to receive filtered block events of a channel.
+	BlockEvents(ctx context.Context, in *BlockEventsRequest, opts ...grpc.CallOption) (Fablet_BlockEventsClient, error)
+	// ChaincodeEvents to receive chaincode events.
+	ChaincodeEvents(ctx context.Context, in *ChaincodeEventsRequest, opts ...grpc.CallOption) (Fablet_ChaincodeEventsClient, error)
+}
+
+type fabletClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewFabletClient(cc *grpc.ClientConn) FabletClient {
+	return &fabletClient{cc}
+}
+
+func (c *fabletClient) DiscoverNetwork(ctx context.Context, in *DiscoverRequest, opts ...grpc.CallOption) (*NetworkOverview, error) {
+	out := new(NetworkOverview)
+	err := c.cc.Invoke(ctx, "/fablet.Fablet/DiscoverNetwork", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fabletClient) QueryLedger(ctx context.Context, in *LedgerRequest, opts ...grpc.CallOption) (*Ledger, error) {
+	out := new(Ledger)
+	err := c.cc.Invoke(ctx, "/fablet.Fablet/QueryLedger", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fabletClient) QueryBlocks(ctx context.Context, in *BlocksRequest, opts ...grpc.CallOption) (*BlocksResponse, error) {
+	out := new(BlocksResponse)
+	err := c.cc.Invoke(ctx, "/fablet.Fablet/QueryBlocks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fabletClient) QueryBlock(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, "/fablet.Fablet/QueryBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fabletClient) ExecuteChaincode(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error) {
+	out := new(ExecuteResponse)
+	err := c.cc.Invoke(ctx, "/fablet.Fablet/ExecuteChaincode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fabletClient) InstallChaincode(ctx context.Context, in *InstallRequest, opts ...grpc.CallOption) (*InstallResponse, error) {
+	out := new(InstallResponse)
+	err := c.cc.Invoke(ctx, "/fablet.Fablet/InstallChaincode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fabletClient) InstantiateChaincode(ctx context.Context, in *InstantiateRequest, opts ...grpc.CallOption) (*InstantiateResponse, error) {
+	out := new(InstantiateResponse)
+	err := c.cc.Invoke(ctx, "/fablet.Fablet/InstantiateChaincode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fabletClient) UpgradeChaincode(ctx context.Context, in *InstantiateRequest, opts ...grpc.CallOption) (*InstantiateResponse, error) {
+	out := new(InstantiateResponse)
+	err := c.cc.Invoke(ctx, "/fablet.Fablet/UpgradeChaincode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fabletClient) CreateChannel(ctx context.Context, in *CreateChannelRequest, opts ...grpc.CallOption) (*CreateChannelResponse, error) {
+	out := new(CreateChannelResponse)
+	err := c.cc.Invoke(ctx, "/fablet.Fablet/CreateChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fabletClient) JoinChannel(ctx context.Context, in *JoinChannelRequest, opts ...grpc.CallOption) (*JoinChannelResponse, error) {
+	out := new(JoinChannelResponse)
+	err := c.cc.Invoke(ctx, "/fablet.Fablet/JoinChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fabletClient) BlockEvents(ctx context.Context, in *BlockEventsRequest, opts ...grpc.CallOption) (Fablet_BlockEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Fablet_serviceDesc.Streams[0], "/fablet.Fablet/BlockEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fabletBlockEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Fablet_BlockEventsClient interface {
+	Recv() (*BlockEvent, error)
+	grpc.ClientStream
+}
+
+type fabletBlockEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *fabletBlockEventsClient) Recv() (*BlockEvent, error) {
+	m := new(BlockEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fabletClient) ChaincodeEvents(ctx context.Context, in *ChaincodeEventsRequest, opts ...grpc.CallOption) (Fablet_ChaincodeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Fablet_serviceDesc.Streams[1], "/fablet.Fablet/ChaincodeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fabletChaincodeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Fablet_ChaincodeEventsClient interface {
+	Recv() (*ChaincodeEvent, error)
+	grpc.ClientStream
+}
+
+type fabletChaincodeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *fabletChaincodeEventsClient) Recv() (*ChaincodeEvent, error) {
+	m := new(ChaincodeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FabletServer is the server API for Fablet service.
+type FabletServer interface {
+	// DiscoverNetwork to discover peers, channels, chaincodes and orderers of the network.
+	DiscoverNetwork(context.Context, *DiscoverRequest) (*NetworkOverview, error)
+	// QueryLedger to query the ledger of a channel.
+	QueryLedger(context.Context, *LedgerRequest) (*Ledger, error)
+	// QueryBlocks to query blocks from a number.
+	QueryBlocks(context.Context, *BlocksRequest) (*BlocksResponse, error)
+	// QueryBlock to query a block by number, hash or transaction ID.
+	QueryBlock(context.Context, *BlockRequest) (*Block, error)
+	// ExecuteChaincode to execute or query a chaincode.
+	ExecuteChaincode(context.Context, *ExecuteRequest) (*ExecuteResponse, error)
+	// InstallChaincode to install a chaincode package on peers.
+	InstallChaincode(context.Context, *InstallRequest) (*InstallResponse, error)
+	// InstantiateChaincode to instantiate an installed chaincode in a channel.
+	InstantiateChaincode(context.Context, *InstantiateRequest) (*InstantiateResponse, error)
+	// UpgradeChaincode to upgrade an instantiated chaincode to a new version.
+	UpgradeChaincode(context.Context, *InstantiateRequest) (*InstantiateResponse, error)
+	// CreateChannel to create a channel by the channel transaction.
+	CreateChannel(context.Context, *CreateChannelRequest) (*CreateChannelResponse, error)
+	// JoinChannel to join peers to a channel.
+	JoinChannel(context.Context, *JoinChannelRequest) (*JoinChannelResponse, error)
+	// BlockEvents to receive filtered block events of a channel.
+	BlockEvents(*BlockEventsRequest, Fablet_BlockEventsServer) error
+	// ChaincodeEvents to receive chaincode events.
+	ChaincodeEvents(*ChaincodeEventsRequest, Fablet_ChaincodeEventsServer) error
+}
+
+// UnimplementedFabletServer can be embedded to have forward compatible implementations.
+type UnimplementedFabletServer struct {
+}
+
+func (*UnimplementedFabletServer) DiscoverNetwork(ctx context.Context, req *DiscoverRequest) (*NetworkOverview, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscoverNetwork not implemented")
+}
+func (*UnimplementedFabletServer) QueryLedger(ctx context.Context, req *LedgerRequest) (*Ledger, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryLedger not implemented")
+}
+func (*UnimplementedFabletServer) QueryBlocks(ctx context.Context, req *BlocksRequest) (*BlocksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryBlocks not implemented")
+}
+func (*UnimplementedFabletServer) QueryBlock(ctx context.Context, req *BlockRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryBlock not implemented")
+}
+func (*UnimplementedFabletServer) ExecuteChaincode(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteChaincode not implemented")
+}
+func (*UnimplementedFabletServer) InstallChaincode(ctx context.Context, req *InstallRequest) (*InstallResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InstallChaincode not implemented")
+}
+func (*UnimplementedFabletServer) InstantiateChaincode(ctx context.Context, req *InstantiateRequest) (*InstantiateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InstantiateChaincode not implemented")
+}
+func (*UnimplementedFabletServer) UpgradeChaincode(ctx context.Context, req *InstantiateRequest) (*InstantiateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpgradeChaincode not implemented")
+}
+func (*UnimplementedFabletServer) CreateChannel(ctx context.Context, req *CreateChannelRequest) (*CreateChannelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateChannel not implemented")
+}
+func (*UnimplementedFabletServer) JoinChannel(ctx context.Context, req *JoinChannelRequest) (*JoinChannelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinChannel not implemented")
+}
+func (*UnimplementedFabletServer) BlockEvents(req *BlockEventsRequest, srv Fablet_BlockEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method BlockEvents not implemented")
+}
+func (*UnimplementedFabletServer) ChaincodeEvents(req *ChaincodeEventsRequest, srv Fablet_ChaincodeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ChaincodeEvents not implemented")
+}
+
+func RegisterFabletServer(s *grpc.Server, srv FabletServer) {
+	s.RegisterService(&_Fablet_serviceDesc, srv)
+}
+
+func _Fablet_DiscoverNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscoverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FabletServer).DiscoverNetwork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fablet.Fablet/DiscoverNetwork",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FabletServer).DiscoverNetwork(ctx, req.(*DiscoverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fablet_QueryLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LedgerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FabletServer).QueryLedger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fablet.Fablet/QueryLedger",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FabletServer).QueryLedger(ctx, req.(*LedgerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fablet_QueryBlocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlocksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FabletServer).QueryBlocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fablet.Fablet/QueryBlocks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FabletServer).QueryBlocks(ctx, req.(*BlocksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fablet_QueryBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FabletServer).QueryBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fablet.Fablet/QueryBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FabletServer).QueryBlock(ctx, req.(*BlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fablet_ExecuteChaincode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FabletServer).ExecuteChaincode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fablet.Fablet/ExecuteChaincode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FabletServer).ExecuteChaincode(ctx, req.(*ExecuteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fablet_InstallChaincode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstallRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FabletServer).InstallChaincode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fablet.Fablet/InstallChaincode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FabletServer).InstallChaincode(ctx, req.(*InstallRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fablet_InstantiateChaincode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstantiateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FabletServer).InstantiateChaincode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fablet.Fablet/InstantiateChaincode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FabletServer).InstantiateChaincode(ctx, req.(*InstantiateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fablet_UpgradeChaincode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstantiateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FabletServer).UpgradeChaincode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fablet.Fablet/UpgradeChaincode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FabletServer).UpgradeChaincode(ctx, req.(*InstantiateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fablet_CreateChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FabletServer).CreateChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fablet.Fablet/CreateChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FabletServer).CreateChannel(ctx, req.(*CreateChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fablet_JoinChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FabletServer).JoinChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fablet.Fablet/JoinChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FabletServer).JoinChannel(ctx, req.(*JoinChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fablet_BlockEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BlockEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FabletServer).BlockEvents(m, &fabletBlockEventsServer{stream})
+}
+
+type Fablet_BlockEventsServer interface {
+	Send(*BlockEvent) error
+	grpc.ServerStream
+}
+
+type fabletBlockEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *fabletBlockEventsServer) Send(m *BlockEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Fablet_ChaincodeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ChaincodeEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FabletServer).ChaincodeEvents(m, &fabletChaincodeEventsServer{stream})
+}
+
+type Fablet_ChaincodeEventsServer interface {
+	Send(*ChaincodeEvent) error
+	grpc.ServerStream
+}
+
+type fabletChaincodeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *fabletChaincodeEventsServer) Send(m *ChaincodeEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Fablet_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "fablet.Fablet",
+	HandlerType: (*FabletServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DiscoverNetwork",
+			Handler:    _Fablet_DiscoverNetwork_Handler,
+		},
+		{
+			MethodName: "QueryLedger",
+			Handler:    _Fablet_QueryLedger_Handler,
+		},
+		{
+			MethodName: "QueryBlocks",
+			Handler:    _Fablet_QueryBlocks_Handler,
+		},
+		{
+			MethodName: "QueryBlock",
+			Handler:    _Fablet_QueryBlock_Handler,
+		},
+		{
+			MethodName: "ExecuteChaincode",
+			Handler:    _Fablet_ExecuteChaincode_Handler,
+		},
+		{
+			MethodName: "InstallChaincode",
+			Handler:    _Fablet_InstallChaincode_Handler,
+		},
+		{
+			MethodName: "InstantiateChaincode",
+			Handler:    _Fablet_InstantiateChaincode_Handler,
+		},
+		{
+			MethodName: "UpgradeChaincode",
+			Handler:    _Fablet_UpgradeChaincode_Handler,
+		},
+		{
+			MethodName: "CreateChannel",
+			Handler:    _Fablet_CreateChannel_Handler,
+		},
+		{
+			MethodName: "JoinChannel",
+			Handler:    _Fablet_JoinChannel_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BlockEvents",
+			Handler:       _Fablet_BlockEvents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ChaincodeEvents",
+			Handler:       _Fablet_ChaincodeEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "fablet.proto",
+}
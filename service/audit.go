@@ -0,0 +1,191 @@
+package service
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/IBM/fablet/api"
+	"github.com/IBM/fablet/audit"
+	"github.com/pkg/errors"
+)
+
+// Operations recorded in the audit log.
+const (
+	AuditOperationInstall         = "installChaincode"
+	AuditOperationInstantiate     = "instantiateChaincode"
+	AuditOperationUpgrade         = "upgradeChaincode"
+	AuditOperationExecute         = "executeChaincode"
+	AuditOperationQuery           = "queryChaincode"
+	AuditOperationCreateChannel   = "createChannel"
+	AuditOperationJoinChannel     = "joinChannel"
+	AuditOperationBenchmark       = "benchmarkChaincode"
+	AuditOperationEnroll          = "enrollIdentity"
+	AuditOperationReenroll        = "reenrollIdentity"
+	AuditOperationRegister        = "registerIdentity"
+	AuditOperationRevoke          = "revokeIdentity"
+	AuditOperationSaveWorkspace   = "saveWorkspace"
+	AuditOperationDeleteWorkspace = "deleteWorkspace"
+	AuditOperationSwitchWorkspace = "switchWorkspace"
+
+	// AuditUserHeader the header of the Fablet user, the connection label is used if it is absent.
+	// It is a label supplied by the client and not authenticated, the MSP ID and subject of the record
+	// are of the Fabric identity which signs the operation.
+	AuditUserHeader = "X-Fablet-User"
+)
+
+// AuditQueryReq to query or export the audit log.
+type AuditQueryReq struct {
+	Filter audit.Filter `json:"filter"`
+	Format string       `json:"format" openapi:"enum=json|csv,default=json"` // For export only, see audit.ExportFormatJSON and audit.ExportFormatCSV.
+}
+
+var auditLog *audit.Log
+var auditLogLock sync.Mutex
+
+// getAuditLog to open the audit log at the first time, it is opened again at the next call if it fails.
+func getAuditLog() (*audit.Log, error) {
+	auditLogLock.Lock()
+	defer auditLogLock.Unlock()
+	if auditLog == nil {
+		l, err := audit.Open(filepath.Join(GetDataFolder("audit"), "audit.log"))
+		if err != nil {
+			return nil, err
+		}
+		auditLog = l
+	}
+	return auditLog, nil
+}
+
+// recordAudit to record an operation, the identity and result are filled.
+// Failure of the audit is logged only, it doesn't fail the operation which has been done.
+func recordAudit(req *http.Request, conn *api.NetworkConnection, record *audit.Record, begin time.Time, err error) {
+	var participant *api.Participant
+	if conn != nil {
+		participant = conn.Participant
+	}
+	recordParticipantAudit(req, participant, record, begin, err)
+}
+
+// recordParticipantAudit to record an operation signed by the participant without a network connection, such as of the CA.
+func recordParticipantAudit(req *http.Request, participant *api.Participant, record *audit.Record, begin time.Time, err error) {
+	record.RemoteAddr = req.RemoteAddr
+	record.User = req.Header.Get(AuditUserHeader)
+	appendAudit(participant, record, begin, err)
+}
+
+// appendAudit to record an operation of which the remote address and user are filled.
+func appendAudit(participant *api.Participant, record *audit.Record, begin time.Time, err error) {
+	record.Time = begin.UnixNano() / 1000000
+	record.Duration = time.Since(begin).Nanoseconds() / 1000000
+	if participant != nil {
+		if record.User == "" {
+			record.User = participant.Label
+		}
+		record.MSPID = participant.MSPID
+		if block, _ := pem.Decode(participant.Cert); block != nil {
+			if cert, certErr := x509.ParseCertificate(block.Bytes); certErr == nil {
+				record.Subject = cert.Subject.String()
+			}
+		}
+	}
+	record.Result = audit.ResultSuccess
+	if err != nil {
+		record.Result = audit.ResultFailure
+		record.Error = err.Error()
+	}
+
+	l, logErr := getAuditLog()
+	if logErr == nil {
+		logErr = l.Append(record)
+	}
+	if logErr != nil {
+		logger.Errorf("Error occurred when recording the audit of %s: %s", record.Operation, logErr.Error())
+	}
+}
+
+// HandleAuditQuery to query the audit records, the latest first.
+func HandleAuditQuery(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleAuditQuery")
+
+	reqBody := &AuditQueryReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	l, err := getAuditLog()
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when opening the audit log."))
+		return
+	}
+
+	records, err := l.Query(&reqBody.Filter)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when querying the audit log."))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"records": records,
+	})
+}
+
+// HandleAuditExport to export the audit records as a file.
+func HandleAuditExport(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleAuditExport")
+
+	reqBody := &AuditQueryReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	if reqBody.Format == "" {
+		reqBody.Format = audit.ExportFormatJSON
+	}
+	if reqBody.Format != audit.ExportFormatJSON && reqBody.Format != audit.ExportFormatCSV {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL,
+			NewServiceError(ERR_CODE_INVALID_REQUEST, errors.Errorf("%s is not a supported export format", reqBody.Format)))
+		return
+	}
+	l, err := getAuditLog()
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when opening the audit log."))
+		return
+	}
+
+	contentType, fileName := "application/x-ndjson", "audit.jsonl"
+	if reqBody.Format == audit.ExportFormatCSV {
+		contentType, fileName = "text/csv;charset=utf-8", "audit.csv"
+	}
+	SetHeader(res, req, map[string]string{
+		"Content-Type":        contentType,
+		"Content-Disposition": "attachment; filename=" + fileName,
+	})
+	if err := l.Export(res, &reqBody.Filter, reqBody.Format); err != nil {
+		// The header has been sent.
+		logger.Errorf("Error occurred when exporting the audit log: %s", err.Error())
+	}
+}
+
+// HandleAuditVerify to verify the hash chain of the audit log.
+func HandleAuditVerify(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleAuditVerify")
+
+	l, err := getAuditLog()
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when opening the audit log."))
+		return
+	}
+	verification, err := l.Verify()
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when verifying the audit log."))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"verification": verification,
+	})
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/IBM/fablet/api"
+	"github.com/IBM/fablet/audit"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ChaincodeBatchReq to start a batch invocation job.
+type ChaincodeBatchReq struct {
+	BaseRequest
+	Options api.BatchOptions `json:"options"`
+	// See api.BatchFormatCSV and api.BatchFormatJSONLines.
+	Format       string   `json:"format" openapi:"required,enum=csv|jsonl"`
+	Content      []byte   `json:"content"`      // The file content.
+	Columns      []string `json:"columns"`      // CSV columns mapped to arguments.
+	FunctionName string   `json:"functionName"` // Default function name of all rows.
+	// To resume a job, rows done successfully in the job will be skipped.
+	ResumeJobID string `json:"resumeJobID"`
+}
+
+// ChaincodeBatchStatusReq to get the report of a batch job.
+type ChaincodeBatchStatusReq struct {
+	JobID string `json:"jobID" openapi:"required"`
+}
+
+// BatchJobs to store all batch jobs started in current server.
+type BatchJobs struct {
+	Jobs map[string]*api.BatchJob
+	sync.RWMutex
+}
+
+var batchJobs = &BatchJobs{Jobs: make(map[string]*api.BatchJob)}
+
+var batchJobIDPattern = regexp.MustCompile(`^[0-9a-zA-Z\-]+$`)
+
+func getBatchReportFile(jobID string) string {
+	return filepath.Join(GetDataFolder("batch"), jobID+".json")
+}
+
+func saveBatchJob(job *api.BatchJob) {
+	report, err := json.Marshal(job.Snapshot())
+	if err != nil {
+		logger.Errorf("Error occurred when marshaling report of batch job %s: %s", job.ID, err.Error())
+		return
+	}
+	if err := os.MkdirAll(GetDataFolder("batch"), 0755); err != nil {
+		logger.Errorf("Error occurred when creating the batch report folder: %s", err.Error())
+		return
+	}
+	if err := ioutil.WriteFile(getBatchReportFile(job.ID), report, 0644); err != nil {
+		logger.Errorf("Error occurred when saving report of batch job %s: %s", job.ID, err.Error())
+	}
+}
+
+// findBatchJob to find the job from memory, or the saved report.
+func findBatchJob(jobID string) (*api.BatchJob, error) {
+	if !batchJobIDPattern.MatchString(jobID) {
+		return nil, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.Errorf("%s is not a valid batch job ID", jobID))
+	}
+
+	batchJobs.RLock()
+	job, ok := batchJobs.Jobs[jobID]
+	batchJobs.RUnlock()
+	if ok {
+		return job.Snapshot(), nil
+	}
+
+	report, err := ioutil.ReadFile(getBatchReportFile(jobID))
+	if err != nil {
+		return nil, NewServiceError(ERR_CODE_NOT_FOUND, errors.WithMessagef(err, "Batch job %s is not found.", jobID))
+	}
+	job = &api.BatchJob{}
+	if err := json.Unmarshal(report, job); err != nil {
+		return nil, errors.WithMessagef(err, "Error occurred when reading report of batch job %s.", jobID)
+	}
+	return job, nil
+}
+
+// HandleChaincodeBatch to start a batch invocation job in background, or resume a job.
+func HandleChaincodeBatch(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleChaincodeBatch")
+
+	reqBody := &ChaincodeBatchReq{}
+	conn, err := GetRequest(req, reqBody, true)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+
+	invocations, err := api.ParseBatchInvocations(reqBody.Format, reqBody.Content, reqBody.Columns, reqBody.FunctionName)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL,
+			NewServiceError(ERR_CODE_INVALID_REQUEST, errors.WithMessage(err, "Error occurred when parsing the batch file.")))
+		return
+	}
+
+	jobID := uuid.NewV1().String()
+	var previous *api.BatchJob
+	if reqBody.ResumeJobID != "" {
+		previous, err = findBatchJob(reqBody.ResumeJobID)
+		if err != nil {
+			ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when finding the batch job to resume."))
+			return
+		}
+		jobID = previous.ID
+	}
+
+	// Select endorsers once for all rows.
+	options := reqBody.Options
+	if len(options.Targets) < 1 {
+		options.Targets = api.PlanEndorsers(conn, options.ChannelID, options.ChaincodeID, api.DefaultSelectionStrategy).Targets()
+	}
+
+	job := api.NewBatchJob(jobID, options, len(invocations), previous)
+	// Check and replace the job at once, so that concurrent resumes don't run the same job.
+	batchJobs.Lock()
+	if current, ok := batchJobs.Jobs[jobID]; ok && current.Snapshot().Status == api.BatchStatusRunning {
+		batchJobs.Unlock()
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.Errorf("batch job %s is still running", jobID)))
+		return
+	}
+	batchJobs.Jobs[jobID] = job
+	batchJobs.Unlock()
+
+	// Each row is audited as an execution, the request is done before the rows.
+	remoteAddr, user := req.RemoteAddr, req.Header.Get(AuditUserHeader)
+	auditRow := func(invocation *api.BatchInvocation, result *api.BatchRowResult, begin time.Time) {
+		var rowErr error
+		if result.Error != "" {
+			rowErr = errors.New(result.Error)
+		}
+		appendAudit(conn.Participant, &audit.Record{User: user, RemoteAddr: remoteAddr, Operation: AuditOperationExecute,
+			Resource: "batch/" + jobID, ChannelID: options.ChannelID, ChaincodeID: options.ChaincodeID, Targets: options.Targets,
+			ArgumentDigests: audit.Digests(append([]string{invocation.FunctionName}, invocation.Arguments...)),
+			TxID:            result.TransactionID}, begin, rowErr)
+	}
+	go job.Run(conn, invocations, saveBatchJob, auditRow)
+
+	ResultOutput(res, req, map[string]interface{}{
+		"jobID":   jobID,
+		"total":   job.Total,
+		"skipped": job.Skipped,
+	})
+}
+
+// HandleChaincodeBatchStatus to get the report of a batch job.
+func HandleChaincodeBatchStatus(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleChaincodeBatchStatus")
+
+	reqBody := &ChaincodeBatchStatusReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+
+	job, err := findBatchJob(reqBody.JobID)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"job": job,
+	})
+}
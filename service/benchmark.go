@@ -0,0 +1,229 @@
+package service
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IBM/fablet/api"
+	"github.com/IBM/fablet/audit"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+// BenchmarkReq to start a benchmark.
+type BenchmarkReq struct {
+	BaseRequest
+	Options api.BenchmarkOptions `json:"options"`
+}
+
+// BenchmarkResultReq to get saved benchmark reports.
+type BenchmarkResultReq struct {
+	IDs []string `json:"IDs" openapi:"required"`
+}
+
+// BenchmarkMessage message sent to websocket client while the benchmark is running.
+type BenchmarkMessage struct {
+	Type   string               `json:"type"` // progress or result
+	Report *api.BenchmarkReport `json:"report"`
+}
+
+const (
+	// BenchmarkReportInterval interval of sending the progress.
+	BenchmarkReportInterval = time.Second
+	// BenchmarkActionStop the client sends the action to stop the benchmark.
+	BenchmarkActionStop = "stop"
+)
+
+var benchmarkIDPattern = regexp.MustCompile(`^[0-9a-zA-Z\-]+$`)
+
+func getBenchmarkFolder() string {
+	return GetDataFolder("benchmark")
+}
+
+func saveBenchmarkReport(report *api.BenchmarkReport) error {
+	content, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(getBenchmarkFolder(), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(getBenchmarkFolder(), report.ID+".json"), content, 0644)
+}
+
+func loadBenchmarkReport(id string) (*api.BenchmarkReport, error) {
+	if !benchmarkIDPattern.MatchString(id) {
+		return nil, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.Errorf("%s is not a valid benchmark ID", id))
+	}
+	content, err := ioutil.ReadFile(filepath.Join(getBenchmarkFolder(), id+".json"))
+	if err != nil {
+		return nil, NewServiceError(ERR_CODE_NOT_FOUND, errors.WithMessagef(err, "Benchmark %s is not found.", id))
+	}
+	report := &api.BenchmarkReport{}
+	if err := json.Unmarshal(content, report); err != nil {
+		return nil, errors.WithMessagef(err, "Error occurred when reading benchmark %s.", id)
+	}
+	return report, nil
+}
+
+// HandleBenchmark to run a benchmark, the progress is sent every interval and the result at the end.
+// The benchmark will be stopped if the client sends {"action": "stop"} or disconnects.
+func HandleBenchmark(wsConn *websocket.Conn, request []byte) error {
+	logger.Info("Service HandleBenchmark")
+	reqBody := &BenchmarkReq{}
+	if err := json.Unmarshal(request, reqBody); err != nil {
+		return err
+	}
+	conn, err := getConnOfReq(reqBody.GetReqConn(), true)
+	if err != nil {
+		return writeWSError(wsConn, err)
+	}
+
+	options := reqBody.Options
+	if len(options.Targets) < 1 {
+		options.Targets = api.PlanEndorsers(conn, options.ChannelID, options.ChaincodeID, api.DefaultSelectionStrategy).Targets()
+	}
+	benchmark, err := api.NewBenchmark(options)
+	if err != nil {
+		return writeWSError(wsConn, err)
+	}
+
+	stop := make(chan struct{})
+	// Monitor the client connection and the stop action.
+	go func() {
+		defer close(stop)
+		for {
+			action := map[string]string{}
+			if err := wsConn.ReadJSON(&action); err != nil {
+				logger.Errorf("Reading benchmark request with error: %s.", err.Error())
+				return
+			}
+			if action["action"] == BenchmarkActionStop {
+				return
+			}
+		}
+	}()
+
+	begin := time.Now()
+	resultChan := make(chan *api.BenchmarkReport, 1)
+	go func() {
+		resultChan <- benchmark.Run(conn, stop)
+	}()
+
+	reportTicker := time.NewTicker(BenchmarkReportInterval)
+	defer reportTicker.Stop()
+
+	for {
+		select {
+		case <-reportTicker.C:
+			if err := wsConn.WriteJSON(&BenchmarkMessage{Type: "progress", Report: benchmark.Report()}); err != nil {
+				logger.Errorf("Error of write benchmark progress: %s", err.Error())
+			}
+		case report := <-resultChan:
+			// The websocket has no user header, the connection label is the user.
+			appendAudit(conn.Participant, &audit.Record{RemoteAddr: wsConn.RemoteAddr().String(), Operation: AuditOperationBenchmark,
+				Resource: "benchmark/" + report.ID, ChannelID: options.ChannelID, ChaincodeID: options.ChaincodeID,
+				Targets: options.Targets}, begin, nil)
+			if err := saveBenchmarkReport(report); err != nil {
+				logger.Errorf("Error occurred when saving benchmark %s: %s", report.ID, err.Error())
+			}
+			return wsConn.WriteJSON(&BenchmarkMessage{Type: "result", Report: report})
+		}
+	}
+}
+
+func writeWSError(wsConn *websocket.Conn, err error) error {
+	if wErr := wsConn.WriteJSON(&ErrorResult{Error: err.Error(), ErrCode: ClassifyError(err)}); wErr != nil {
+		logger.Errorf("Error of write error result: %s", wErr.Error())
+	}
+	return err
+}
+
+// HandleBenchmarkList to list all saved benchmark reports, the latest first.
+func HandleBenchmarkList(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleBenchmarkList")
+
+	files, err := ioutil.ReadDir(getBenchmarkFolder())
+	if err != nil && !os.IsNotExist(err) {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when reading benchmark folder."))
+		return
+	}
+
+	reports := []*api.BenchmarkReport{}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		report, err := loadBenchmarkReport(strings.TrimSuffix(file.Name(), ".json"))
+		if err != nil {
+			logger.Errorf("Error occurred when loading benchmark: %s", err.Error())
+			continue
+		}
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].StartTime > reports[j].StartTime
+	})
+
+	ResultOutput(res, req, map[string]interface{}{
+		"reports": reports,
+	})
+}
+
+// HandleBenchmarkResult to get saved benchmark reports.
+func HandleBenchmarkResult(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleBenchmarkResult")
+
+	reports, err := readBenchmarkReports(req)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"reports": reports,
+	})
+}
+
+// HandleBenchmarkCompare to compare saved benchmark reports, against the first one.
+func HandleBenchmarkCompare(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleBenchmarkCompare")
+
+	reports, err := readBenchmarkReports(req)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	if len(reports) < 2 {
+		ErrorOutput(res, req, RES_CODE_ERR_BAD_REQUEST, errors.New("at least 2 benchmarks are required for comparison"))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"reports":     reports,
+		"comparisons": api.CompareBenchmarks(reports),
+	})
+}
+
+func readBenchmarkReports(req *http.Request) ([]*api.BenchmarkReport, error) {
+	reqBody := &BenchmarkResultReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing from request.")
+	}
+	reports := []*api.BenchmarkReport{}
+	for _, id := range reqBody.IDs {
+		report, err := loadBenchmarkReport(id)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
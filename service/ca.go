@@ -0,0 +1,423 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/fablet/api"
+	"github.com/IBM/fablet/audit"
+	"github.com/pkg/errors"
+)
+
+// CAReq a request to a Fabric CA of the connection profile.
+// The identity of the connection or of the workspace is used, or the registrar of the profile if there is no identity.
+type CAReq struct {
+	BaseRequest
+	// CA the name of the CA in the profile, it can be empty if the profile has only one.
+	CA string `json:"CA"`
+}
+
+// CAEnrollReq to enroll an identity, it is stored in the workspace if the request uses one.
+type CAEnrollReq struct {
+	CAReq
+	EnrollID string `json:"enrollID" openapi:"required"`
+	Secret   string `json:"secret" openapi:"required"`
+	// Label of the identity in the workspace, the enrollment ID if it is empty.
+	Label string `json:"label"`
+	// MSPID of the identity, the MSP of the organization of the CA if it is empty.
+	MSPID string   `json:"MSPID"`
+	Hosts []string `json:"hosts"`
+	// Overwrite the identity of the same label in the workspace, otherwise the label should be new.
+	Overwrite bool `json:"overwrite"`
+}
+
+// CARegisterReq to register an identity.
+type CARegisterReq struct {
+	CAReq
+	Registration api.CARegistration `json:"registration" openapi:"required"`
+}
+
+// CARevokeReq to revoke certificates of an identity.
+type CARevokeReq struct {
+	CAReq
+	Revocation api.CARevocation `json:"revocation" openapi:"required"`
+}
+
+// caRequest the CA of the request, with the workspace and the identity if there are.
+type caRequest struct {
+	workspace string
+	identity  *api.Participant
+	ca        *api.CAConfig
+	client    *api.CAClient
+}
+
+// caConnection to get the connection profile of the connection or of the workspace, with the identity if there is one.
+func caConnection(reqConn *RequestConnection) (*caRequest, string, error) {
+	if reqConn != nil && reqConn.Workspace == "" {
+		caReq := &caRequest{}
+		if reqConn.CertContent != "" {
+			caReq.identity = &api.Participant{Label: reqConn.Label, MSPID: reqConn.MSPID,
+				Cert: []byte(reqConn.CertContent), PrivateKey: []byte(reqConn.PrvKeyContent), Passphrase: reqConn.Passphrase, PKCS11: reqConn.PKCS11}
+		}
+		return caReq, reqConn.ConnProfile, nil
+	}
+	ws, identity, err := caWorkspace(reqConn)
+	if err != nil {
+		return nil, "", err
+	}
+	return &caRequest{workspace: ws.Name, identity: identity}, ws.ConnProfile, nil
+}
+
+// getCARequest to get the CA of the request, of the connection profile of the connection or of the workspace.
+func getCARequest(reqBody *CAReq) (*caRequest, error) {
+	caReq, connProfile, err := caConnection(reqBody.GetReqConn())
+	if err != nil {
+		return nil, err
+	}
+	cas, err := api.ProfileCAs([]byte(connProfile))
+	if err != nil {
+		return nil, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.WithMessage(err, "Error occurred when reading CAs of the connection profile."))
+	}
+	for _, ca := range cas {
+		if ca.Name == reqBody.CA || (reqBody.CA == "" && len(cas) == 1) {
+			caReq.ca = ca
+		}
+	}
+	if caReq.ca == nil {
+		if reqBody.CA == "" {
+			return nil, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.Errorf("the CA is required since the connection profile has %d CAs", len(cas)))
+		}
+		return nil, NewServiceError(ERR_CODE_NOT_FOUND, errors.Errorf("CA %s is not found in the connection profile", reqBody.CA))
+	}
+	caReq.client, err = api.NewCAClient(caReq.ca)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when creating the CA client.")
+	}
+	return caReq, nil
+}
+
+// caWorkspace to get the workspace of the request, with the identity if the workspace has one.
+func caWorkspace(reqConn *RequestConnection) (*Workspace, *api.Participant, error) {
+	ws, identity, err := requestWorkspace(reqConn, false)
+	if err != nil || identity == nil {
+		return ws, nil, err
+	}
+	return ws, &api.Participant{Label: identity.Label, MSPID: identity.MSPID,
+		Cert: []byte(identity.CertContent), PrivateKey: []byte(identity.PrvKeyContent), Passphrase: identity.Passphrase, PKCS11: identity.PKCS11}, nil
+}
+
+// registrarEnrollments enrollments of registrars of profiles, by the CA and the registrar. They are reused until the certificates expire.
+var registrarEnrollments = struct {
+	sync.Mutex
+	enrollments map[string]*registrarEnrollment
+}{enrollments: make(map[string]*registrarEnrollment)}
+
+type registrarEnrollment struct {
+	registrar *api.Participant
+	notAfter  time.Time
+}
+
+// registrar to get the identity of the request, or the enrollment of the registrar of the profile.
+func (caReq *caRequest) registrar() (*api.Participant, error) {
+	if caReq.identity != nil {
+		return caReq.identity, nil
+	}
+	secretHash := sha256.Sum256([]byte(caReq.ca.Registrar.EnrollSecret))
+	key := strings.Join([]string{caReq.ca.URL, caReq.ca.CAName, caReq.ca.Registrar.EnrollID, hex.EncodeToString(secretHash[:])}, "\n")
+
+	registrarEnrollments.Lock()
+	enrollment, ok := registrarEnrollments.enrollments[key]
+	registrarEnrollments.Unlock()
+	if ok && time.Now().Before(enrollment.notAfter) {
+		return enrollment.registrar, nil
+	}
+
+	registrar, err := caReq.client.EnrollRegistrar()
+	if err != nil {
+		return nil, err
+	}
+	if cert, err := api.TLSCertByBytes(registrar.Cert); err == nil {
+		registrarEnrollments.Lock()
+		registrarEnrollments.enrollments[key] = &registrarEnrollment{registrar: registrar, notAfter: cert.NotAfter}
+		registrarEnrollments.Unlock()
+	}
+	return registrar, nil
+}
+
+// storeEnrollment to store the enrolled identity in the workspace. An identity of the same label is replaced only if overwrite.
+// The private key is only responded if there is no workspace, or it is not saved since WorkspaceKeyEnv is not set.
+func (caReq *caRequest) storeEnrollment(label string, MSPID string, enrollment *api.CAEnrollment, overwrite bool) (*WorkspaceIdentity, *Workspace, error) {
+	identity := &WorkspaceIdentity{Label: label, MSPID: MSPID,
+		CertContent: string(enrollment.Cert), PrvKeyContent: string(enrollment.PrivateKey), PKCS11: enrollment.PKCS11}
+	if caReq.workspace == "" {
+		return identity, nil, nil
+	}
+
+	store, err := getWorkspaceStore()
+	if err != nil {
+		return nil, nil, err
+	}
+	store.Lock()
+	defer store.Unlock()
+	ws, err := store.find(caReq.workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkLabel(ws, label, overwrite); err != nil {
+		return nil, nil, err
+	}
+	if existing := ws.findIdentity(label); existing != nil {
+		*existing = *identity
+	} else {
+		ws.Identities = append(ws.Identities, identity)
+	}
+	if ws.DefaultIdentity == "" {
+		ws.DefaultIdentity = label
+	}
+	ws.UpdateTime = time.Now().UnixNano() / 1000000
+	if err := store.save(); err != nil {
+		return nil, nil, err
+	}
+	if os.Getenv(WorkspaceKeyEnv) == "" {
+		// The private key is not saved, it is given by requests.
+		return identity, ws.redacted(), nil
+	}
+	return identity.redacted(), ws.redacted(), nil
+}
+
+// checkLabel to check the label is new in the workspace, unless the identity of it is to be overwritten.
+func checkLabel(ws *Workspace, label string, overwrite bool) error {
+	if !overwrite && ws.findIdentity(label) != nil {
+		return NewServiceError(ERR_CODE_INVALID_REQUEST,
+			errors.Errorf("identity %s exists in workspace %s, give another label or overwrite it", label, ws.Name))
+	}
+	return nil
+}
+
+// caResource the audited resource of an identity of the CA.
+func caResource(ca *api.CAConfig, ID string) string {
+	return "ca/" + ca.Name + "/" + ID
+}
+
+// HandleCAList to list CAs of the connection profile, of the connection or of the workspace.
+func HandleCAList(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleCAList")
+
+	reqBody := &BaseRequest{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	_, connProfile, err := caConnection(reqBody.GetReqConn())
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	cas, err := api.ProfileCAs([]byte(connProfile))
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, NewServiceError(ERR_CODE_INVALID_REQUEST,
+			errors.WithMessage(err, "Error occurred when reading CAs of the connection profile.")))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"CAs": cas,
+	})
+}
+
+// HandleCAEnroll to enroll an identity by the secret, the identity is stored in the workspace of the request.
+func HandleCAEnroll(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleCAEnroll")
+
+	reqBody := &CAEnrollReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	caReq, err := getCARequest(&reqBody.CAReq)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	label, MSPID := reqBody.Label, reqBody.MSPID
+	if label == "" {
+		label = reqBody.EnrollID
+	}
+	// The label is checked before enrolling, since an enrollment may consume the secret.
+	if caReq.workspace != "" {
+		ws, _, err := requestWorkspace(reqBody.GetReqConn(), false)
+		if err == nil {
+			err = checkLabel(ws, label, reqBody.Overwrite)
+		}
+		if err != nil {
+			ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+			return
+		}
+	}
+	begin := time.Now()
+	enrollment, err := caReq.client.Enroll(reqBody.EnrollID, reqBody.Secret, reqBody.Hosts...)
+	recordParticipantAudit(req, caReq.identity, &audit.Record{Operation: AuditOperationEnroll,
+		Resource: caResource(caReq.ca, reqBody.EnrollID)}, begin, err)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when enrolling the identity."))
+		return
+	}
+
+	if MSPID == "" && len(caReq.ca.MSPIDs) > 0 {
+		MSPID = caReq.ca.MSPIDs[0]
+	}
+	identity, ws, err := caReq.storeEnrollment(label, MSPID, enrollment, reqBody.Overwrite)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when storing the identity."))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"identity":  identity,
+		"workspace": ws,
+	})
+}
+
+// HandleCAReenroll to renew the certificate of the identity of the request, it is updated in the workspace of the request.
+func HandleCAReenroll(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleCAReenroll")
+
+	reqBody := &CAReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	caReq, err := getCARequest(reqBody)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	if caReq.identity == nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.New("the identity to reenroll is empty")))
+		return
+	}
+	begin := time.Now()
+	enrollment, err := caReq.client.Reenroll(caReq.identity)
+	recordParticipantAudit(req, caReq.identity, &audit.Record{Operation: AuditOperationReenroll,
+		Resource: caResource(caReq.ca, caReq.identity.Label)}, begin, err)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when reenrolling the identity."))
+		return
+	}
+	// The reenrolled identity is replaced, with the new key, or the same key of the PKCS#11 token.
+	identity, ws, err := caReq.storeEnrollment(caReq.identity.Label, caReq.identity.MSPID, enrollment, true)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when storing the identity."))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"identity":  identity,
+		"workspace": ws,
+	})
+}
+
+// HandleCARegister to register an identity, returns the secret.
+func HandleCARegister(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleCARegister")
+
+	reqBody := &CARegisterReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	caReq, err := getCARequest(&reqBody.CAReq)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	registrar, err := caReq.registrar()
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	begin := time.Now()
+	secret, err := caReq.client.Register(registrar, &reqBody.Registration)
+	recordParticipantAudit(req, registrar, &audit.Record{Operation: AuditOperationRegister,
+		Resource: caResource(caReq.ca, reqBody.Registration.Name)}, begin, err)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when registering the identity."))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"secret": secret,
+	})
+}
+
+// HandleCARevoke to revoke certificates of an identity, or the certificate of the serial and AKI.
+func HandleCARevoke(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleCARevoke")
+
+	reqBody := &CARevokeReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	caReq, err := getCARequest(&reqBody.CAReq)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	registrar, err := caReq.registrar()
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	revoked := reqBody.Revocation.Name
+	if revoked == "" {
+		revoked = reqBody.Revocation.Serial
+	}
+	begin := time.Now()
+	result, err := caReq.client.Revoke(registrar, &reqBody.Revocation)
+	recordParticipantAudit(req, registrar, &audit.Record{Operation: AuditOperationRevoke,
+		Resource: caResource(caReq.ca, revoked)}, begin, err)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when revoking the identity."))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"result": result,
+	})
+}
+
+// HandleCAIdentities to list identities of the CA which the identity of the request, or the registrar, is allowed to see.
+func HandleCAIdentities(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleCAIdentities")
+
+	reqBody := &CAReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	caReq, err := getCARequest(reqBody)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	registrar, err := caReq.registrar()
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	identities, err := caReq.client.Identities(registrar)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when listing identities of the CA."))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"identities": identities,
+	})
+}
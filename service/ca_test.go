@@ -0,0 +1,160 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IBM/fablet/api"
+)
+
+// newEnrollCA a fake Fabric CA which only enrolls user1, the certificate is self signed by the key of the CSR.
+// Enrollments are counted if enrolls is not nil.
+func newEnrollCA(t *testing.T, enrolls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if enrolls != nil {
+			atomic.AddInt32(enrolls, 1)
+		}
+		if id, secret, ok := req.BasicAuth(); !ok || id != "user1" || secret != "pw" || req.URL.Path != "/api/v1/enroll" {
+			res.WriteHeader(http.StatusUnauthorized)
+			res.Write([]byte(`{"success": false, "errors": [{"code": 20, "message": "authentication failure"}]}`))
+			return
+		}
+		enrollReq := &struct {
+			CertificateRequest string `json:"certificate_request"`
+		}{}
+		json.NewDecoder(req.Body).Decode(enrollReq)
+		block, _ := pem.Decode([]byte(enrollReq.CertificateRequest))
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{SerialNumber: big.NewInt(2), Subject: csr.Subject,
+			NotBefore: time.Now(), NotAfter: time.Now().AddDate(1, 0, 0)}, &x509.Certificate{Subject: csr.Subject}, csr.PublicKey, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert := base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+		res.Write([]byte(fmt.Sprintf(`{"success": true, "result": {"Cert": "%s", "ServerInfo": {}}, "errors": []}`, cert)))
+	}))
+}
+
+func TestCAEnroll(t *testing.T) {
+	exeFolder := ExeFolder
+	tmpFolder, err := ioutil.TempDir("", "fablet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ExeFolder = tmpFolder
+	workspaceOnce = sync.Once{}
+	defer func() {
+		ExeFolder = exeFolder
+		workspaceOnce = sync.Once{}
+		os.RemoveAll(tmpFolder)
+	}()
+	os.Setenv(WorkspaceKeyEnv, "workspace key")
+	defer os.Unsetenv(WorkspaceKeyEnv)
+	srv := newEnrollCA(t, nil)
+	defer srv.Close()
+
+	profile, _ := json.Marshal(fmt.Sprintf(`organizations:
+  Org1:
+    mspid: Org1MSP
+    certificateAuthorities: [ca.org1.example.com]
+certificateAuthorities:
+  ca.org1.example.com:
+    url: %s
+`, srv.URL))
+	if code, result := postWorkspace(t, "/workspace/save", fmt.Sprintf(`{"workspace": {"name": "dev", "connProfile": %s}}`, profile)); code != 200 {
+		t.Fatalf("expected the workspace is saved, but got %d %v", code, result)
+	}
+
+	code, result := postWorkspace(t, "/ca/list", `{"connection": {"workspace": "dev"}}`)
+	if body, _ := json.Marshal(result); code != 200 || !strings.Contains(string(body), `"MSPIDs":["Org1MSP"]`) {
+		t.Errorf("expected CAs of the workspace, but got %d %s", code, body)
+	}
+	if code, _ = postWorkspace(t, "/ca/enroll", `{"connection": {"workspace": "dev"}, "enrollID": "user1", "secret": "wrong"}`); code != 500 {
+		t.Errorf("expected error of the wrong secret, but got %d", code)
+	}
+	if code, _ = postWorkspace(t, "/ca/enroll", `{"connection": {"workspace": "dev"}, "CA": "ca.org2", "enrollID": "user1", "secret": "pw"}`); code != 404 {
+		t.Errorf("expected 404 of an unknown CA, but got %d", code)
+	}
+
+	code, result = postWorkspace(t, "/ca/enroll", `{"connection": {"workspace": "dev"}, "enrollID": "user1", "secret": "pw"}`)
+	if body, _ := json.Marshal(result); code != 200 || strings.Contains(string(body), "prvKeyContent") || !strings.Contains(string(body), `"defaultIdentity":"user1"`) {
+		t.Fatalf("expected the identity is stored without the private key responded, but got %d %s", code, body)
+	}
+	// The identity of the same label is replaced only if overwrite.
+	code, result = postWorkspace(t, "/ca/enroll", `{"connection": {"workspace": "dev"}, "enrollID": "user1", "secret": "pw"}`)
+	if code != 400 || !strings.Contains(fmt.Sprint(result["errMsg"]), "exists") {
+		t.Errorf("expected error of the existing label, but got %d %v", code, result)
+	}
+	if code, result = postWorkspace(t, "/ca/enroll", `{"connection": {"workspace": "dev"}, "enrollID": "user1", "secret": "pw", "overwrite": true}`); code != 200 {
+		t.Errorf("expected the identity is overwritten, but got %d %v", code, result)
+	}
+	reqConn, err := resolveWorkspace(&RequestConnection{Workspace: "dev"})
+	if err != nil || reqConn.Label != "user1" || reqConn.MSPID != "Org1MSP" ||
+		!strings.Contains(reqConn.CertContent, "CERTIFICATE") || !strings.Contains(reqConn.PrvKeyContent, "PRIVATE KEY") {
+		t.Errorf("expected the enrolled identity in the workspace, but got %+v %v", reqConn, err)
+	}
+
+	// The private key is responded if it is not saved.
+	os.Unsetenv(WorkspaceKeyEnv)
+	code, result = postWorkspace(t, "/ca/enroll", `{"connection": {"workspace": "dev"}, "enrollID": "user1", "secret": "pw", "label": "u2"}`)
+	if identity, _ := result["identity"].(map[string]interface{}); code != 200 || identity["prvKeyContent"] == nil {
+		t.Errorf("expected the identity with the private key, but got %d %v", code, result)
+	}
+
+	// Without a workspace, the private key is responded.
+	code, result = postWorkspace(t, "/ca/enroll", fmt.Sprintf(`{"connection": {"connProfile": %s}, "enrollID": "user1", "secret": "pw", "label": "u1"}`, profile))
+	if identity, _ := result["identity"].(map[string]interface{}); code != 200 || identity["label"] != "u1" || identity["prvKeyContent"] == nil {
+		t.Errorf("expected the identity with the private key, but got %d %v", code, result)
+	}
+}
+
+func TestCARegistrar(t *testing.T) {
+	var enrolls int32
+	srv := newEnrollCA(t, &enrolls)
+	defer srv.Close()
+
+	cas, err := api.ProfileCAs([]byte(fmt.Sprintf(`certificateAuthorities:
+  ca.org1.example.com:
+    url: %s
+    registrar:
+      enrollId: user1
+      enrollSecret: pw
+`, srv.URL)))
+	if err != nil || len(cas) != 1 {
+		t.Fatalf("expected the CA, but got %v %v", cas, err)
+	}
+	client, err := api.NewCAClient(cas[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	caReq := &caRequest{ca: cas[0], client: client}
+	// The registrar is enrolled once for the CA.
+	for i := 0; i < 2; i++ {
+		if registrar, err := caReq.registrar(); err != nil || registrar.Label != "user1" {
+			t.Errorf("expected the registrar, but got %v %v", registrar, err)
+		}
+	}
+	if enrolls != 1 {
+		t.Errorf("expected 1 enrollment of the registrar, but got %d", enrolls)
+	}
+}
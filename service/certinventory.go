@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/fablet/api"
+	"github.com/pkg/errors"
+)
+
+// CertMetricsInterval to collect certificate inventories of connections again for metrics.
+const CertMetricsInterval = time.Minute * 10
+
+// CertExpiryWindows windows in days to flag certificates which are expiring, it can be set by the flag -certwindows.
+var CertExpiryWindows = api.DefaultCertExpiryWindows
+
+// CertInventoryReq to collect certificates seen by the connection.
+type CertInventoryReq struct {
+	BaseRequest
+	// Windows in days, CertExpiryWindows if empty.
+	Windows []int `json:"windows"`
+	// EndorserBlocks the number of latest blocks per channel to find endorser certificates, 0 not to find.
+	EndorserBlocks *uint64 `json:"endorserBlocks"`
+}
+
+// certInventories the latest inventories of connections, for metrics.
+var certInventories = struct {
+	sync.Mutex
+	inventories map[string]*api.CertInventory
+}{inventories: make(map[string]*api.CertInventory)}
+
+// HandleCertInventory to collect certificates of peers, orderers, the identity, MSPs of channels and endorsers, with the expiry.
+func HandleCertInventory(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleCertInventory")
+
+	reqBody := &CertInventoryReq{}
+	conn, err := GetRequest(req, reqBody, true)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	windows := reqBody.Windows
+	if len(windows) == 0 {
+		windows = CertExpiryWindows
+	}
+	options := []api.CertInventoryOptionFunc{api.WithExpiryWindows(windows...)}
+	if reqBody.EndorserBlocks != nil {
+		options = append(options, api.WithEndorserBlocks(*reqBody.EndorserBlocks))
+	}
+	inventory := api.CollectCertInventory(conn, options...)
+	storeCertInventory(conn.Identifier, inventory)
+
+	ResultOutput(res, req, map[string]interface{}{
+		"inventory": inventory,
+	})
+}
+
+func storeCertInventory(id string, inventory *api.CertInventory) {
+	certInventories.Lock()
+	defer certInventories.Unlock()
+	certInventories.inventories[id] = inventory
+}
+
+// connCertInventories to get inventories of all connections of the session, collected again if they are older than CertMetricsInterval.
+// Stale inventories are collected concurrently without the lock, so that a slow network doesn't block the others.
+func connCertInventories() map[string]*api.CertInventory {
+	connSession.RLock()
+	conns := []*api.NetworkConnection{}
+	for _, conn := range connSession.Connections {
+		conns = append(conns, conn)
+	}
+	connSession.RUnlock()
+
+	inventories := make(map[string]*api.CertInventory)
+	stale := []*api.NetworkConnection{}
+	certInventories.Lock()
+	for _, conn := range conns {
+		inventory, ok := certInventories.inventories[conn.Identifier]
+		if !ok || time.Since(inventory.CollectTime) > CertMetricsInterval {
+			stale = append(stale, conn)
+			continue
+		}
+		inventories[conn.Identifier] = inventory
+	}
+	certInventories.Unlock()
+
+	collected := make([]*api.CertInventory, len(stale))
+	wg := sync.WaitGroup{}
+	for idx, conn := range stale {
+		wg.Add(1)
+		go func(idx int, conn *api.NetworkConnection) {
+			defer wg.Done()
+			collected[idx] = api.CollectCertInventory(conn, api.WithExpiryWindows(CertExpiryWindows...))
+		}(idx, conn)
+	}
+	wg.Wait()
+
+	certInventories.Lock()
+	defer certInventories.Unlock()
+	for idx, conn := range stale {
+		// An inventory collected by a request meanwhile is newer.
+		if stored, ok := certInventories.inventories[conn.Identifier]; ok && stored.CollectTime.After(collected[idx].CollectTime) {
+			collected[idx] = stored
+		}
+		inventories[conn.Identifier] = collected[idx]
+		certInventories.inventories[conn.Identifier] = collected[idx]
+	}
+	// Inventories of removed connections are dropped.
+	for id := range certInventories.inventories {
+		if _, ok := inventories[id]; !ok {
+			delete(certInventories.inventories, id)
+		}
+	}
+	return inventories
+}
+
+// HandleMetrics to output metrics of certificates of all connections, in the Prometheus text format.
+func HandleMetrics(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleMetrics")
+
+	SetHeader(res, req, map[string]string{"Content-Type": "text/plain; version=0.0.4; charset=utf-8"})
+	res.Write(formatCertMetrics(connCertInventories(), time.Now()))
+}
+
+func formatCertMetrics(inventories map[string]*api.CertInventory, now time.Time) []byte {
+	ids := []string{}
+	for id := range inventories {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	b := &bytes.Buffer{}
+	b.WriteString("# HELP fablet_cert_expiry_timestamp_seconds Expiry time of the certificate.\n")
+	b.WriteString("# TYPE fablet_cert_expiry_timestamp_seconds gauge\n")
+	for _, id := range ids {
+		for _, info := range inventories[id].Certs {
+			sources := []string{}
+			for _, source := range info.Sources {
+				sources = append(sources, source.Type+":"+source.Name)
+			}
+			fmt.Fprintf(b, "fablet_cert_expiry_timestamp_seconds{conn=%s,fingerprint=%s,subject=%s,sources=%s} %d\n",
+				metricLabel(id), metricLabel(info.Fingerprint), metricLabel(info.Subject), metricLabel(strings.Join(sources, ",")), info.NotAfter.Unix())
+		}
+	}
+
+	b.WriteString("# HELP fablet_certs_expiring Number of certificates expiring within the window in days.\n")
+	b.WriteString("# TYPE fablet_certs_expiring gauge\n")
+	for _, id := range ids {
+		for _, window := range CertExpiryWindows {
+			count := 0
+			for _, info := range inventories[id].Certs {
+				if left := info.NotAfter.Sub(now); left > 0 && left <= time.Duration(window)*24*time.Hour {
+					count++
+				}
+			}
+			fmt.Fprintf(b, "fablet_certs_expiring{conn=%s,window=\"%d\"} %d\n", metricLabel(id), window, count)
+		}
+	}
+
+	b.WriteString("# HELP fablet_certs_expired Number of expired certificates.\n")
+	b.WriteString("# TYPE fablet_certs_expired gauge\n")
+	for _, id := range ids {
+		count := 0
+		for _, info := range inventories[id].Certs {
+			if !info.NotAfter.After(now) {
+				count++
+			}
+		}
+		fmt.Fprintf(b, "fablet_certs_expired{conn=%s} %d\n", metricLabel(id), count)
+	}
+
+	b.WriteString("# HELP fablet_connections Number of connections of the session.\n")
+	b.WriteString("# TYPE fablet_connections gauge\n")
+	fmt.Fprintf(b, "fablet_connections %d\n", len(inventories))
+	return b.Bytes()
+}
+
+// metricLabel to quote the label value, in the Prometheus text format.
+func metricLabel(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/fablet/api"
+)
+
+func TestCertMetrics(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "admin"},
+		NotBefore:    time.Now().AddDate(-1, 0, 0),
+		NotAfter:     time.Now().AddDate(0, 0, 20),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without channels, the inventory doesn't call peers.
+	conn := &api.NetworkConnection{
+		Identifier:  "test-metrics",
+		Participant: &api.Participant{Label: "admin", Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})},
+	}
+	connSession.storeConn(conn)
+	defer connSession.removeConn(conn.Identifier)
+
+	res := httptest.NewRecorder()
+	GetHandlerMap()["/metrics"](res, httptest.NewRequest("GET", "/metrics", nil))
+	if res.Code != 200 {
+		t.Fatalf("expected metrics, but got %d", res.Code)
+	}
+	for _, expected := range []string{
+		`fablet_cert_expiry_timestamp_seconds{conn="test-metrics",fingerprint=`,
+		`subject="CN=admin",sources="identity:admin"} `,
+		`fablet_certs_expiring{conn="test-metrics",window="7"} 0`,
+		`fablet_certs_expiring{conn="test-metrics",window="30"} 1`,
+		`fablet_certs_expired{conn="test-metrics"} 0`,
+		"fablet_connections 1",
+	} {
+		if !strings.Contains(res.Body.String(), expected) {
+			t.Errorf("expected %s in the metrics:\n%s", expected, res.Body.String())
+		}
+	}
+	if label := metricLabel("a\"b\\c\n"); label != `"a\"b\\c\n"` {
+		t.Errorf("expected the label escaped, but got %s", label)
+	}
+}
@@ -1,14 +1,14 @@
 package service
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/IBM/fablet/api"
-	"github.com/IBM/fablet/util"
+	"github.com/IBM/fablet/audit"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
 	"github.com/pkg/errors"
 )
 
@@ -20,6 +20,13 @@ type ChaincodeInstallReq struct {
 	Targets       []string      `json:"targets"`
 }
 
+// ChaincodeInspectReq to inspect a chaincode package before the installation, it is the same upload as ChaincodeInstallReq,
+// or a CDS or Fabric 2.x package.
+type ChaincodeInspectReq struct {
+	Chaincode     api.Chaincode `json:"chaincode"`
+	PackageFormat string        `json:"packageFormat"`
+}
+
 // ChaincodeInstantiateReq for instantiate a chaincode.
 type ChaincodeInstantiateReq struct {
 	BaseRequest
@@ -33,10 +40,30 @@ type ChaincodeInstantiateReq struct {
 type ChaincodeExecuteReq struct {
 	BaseRequest
 	Chaincode    api.Chaincode `json:"chaincode"`
-	ActionType   string        `json:"actionType"`
+	ActionType   string        `json:"actionType" openapi:"enum=execute|query,default=execute"`
 	FunctionName string        `json:"functionName"`
 	Arguments    []string      `json:"arguments"`
 	Targets      []string      `json:"targets"`
+	// To select endorsers if no targets, see api.SelectionStrategyMyOrg, api.SelectionStrategyLatency and api.SelectionStrategyHeight.
+	SelectionStrategy string `json:"selectionStrategy" openapi:"enum=myorg|latency|height,default=myorg"`
+	// To return right after the transaction is sent to the orderer, the commit is tracked by /tx/status.
+	Async bool `json:"async"`
+}
+
+// ChaincodePeerResponse response of an endorser.
+type ChaincodePeerResponse struct {
+	Endorser string `json:"endorser"`
+	Version  int32  `json:"version"`
+	Payload  string `json:"payload"`
+	Status   int32  `json:"status"`
+}
+
+// ChaincodeMetadataReq to get the contract metadata of a chaincode.
+type ChaincodeMetadataReq struct {
+	BaseRequest
+	ChannelID   string `json:"channelID" openapi:"required"`
+	ChaincodeID string `json:"chaincodeID" openapi:"required"`
+	Refresh     bool   `json:"refresh"`
 }
 
 // HandleChaincodeInstall to install a chaincode
@@ -50,36 +77,19 @@ func HandleChaincodeInstall(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	// TODO remove tmp folder defer
-	tmpFolder := GetTmpFolder()
-	defer func() {
-		err := os.RemoveAll(tmpFolder)
-		if err != nil {
-			logger.Errorf("Error in removing temp folder: %s", err.Error())
-		}
-	}()
-
-	logger.Debugf("Set temp folder %s", tmpFolder)
-
 	chaincode := &reqBody.Chaincode
-
-	// TODO to move this tar related to fablet api.
-	err = util.UnTar(chaincode.Package, reqBody.PackageFormat, tmpFolder)
+	tmpFolder, err := unpackChaincode(chaincode, reqBody.PackageFormat)
+	defer removeTmpFolder(tmpFolder)
 	if err != nil {
 		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when uncompress the chaincode package."))
 		return
 	}
 
-	if chaincode.Type == api.ChaincodeType_GOLANG {
-		chaincode.BasePath = tmpFolder
-	} else {
-		// for Node and Java type chaincode
-		chaincode.Path = tmpFolder
-	}
-
 	logger.Debugf(fmt.Sprintf("Begin to install %s:%s", chaincode.Name, chaincode.Version))
 	// installRes length will always be identical to the peers length.
+	begin := time.Now()
 	installRes, err := api.InstallChaincode(conn, chaincode, reqBody.Targets)
+	recordAudit(req, conn, &audit.Record{Operation: AuditOperationInstall, ChaincodeID: chaincode.String(), Targets: reqBody.Targets}, begin, err)
 
 	if err != nil && installRes == nil {
 		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when installing the chaincode."))
@@ -93,6 +103,42 @@ func HandleChaincodeInstall(res http.ResponseWriter, req *http.Request) {
 
 }
 
+// HandleChaincodeInspect to list files, detect the language and entry point, check the path, and compute the ID of a chaincode package,
+// the network is not connected.
+func HandleChaincodeInspect(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleChaincodeInspect")
+
+	reqBody := &ChaincodeInspectReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	inspection, err := api.InspectChaincodePackage(&reqBody.Chaincode, reqBody.PackageFormat)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, NewServiceError(ERR_CODE_INVALID_REQUEST, err))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"inspection": inspection,
+	})
+}
+
+// unpackChaincode to uncompress the package into a temp folder, and set the path of the chaincode to it.
+func unpackChaincode(chaincode *api.Chaincode, packageFormat string) (string, error) {
+	// TODO remove tmp folder defer
+	tmpFolder := GetTmpFolder()
+	logger.Debugf("Set temp folder %s", tmpFolder)
+
+	return tmpFolder, api.UnpackChaincode(chaincode, packageFormat, tmpFolder)
+}
+
+func removeTmpFolder(tmpFolder string) {
+	if err := os.RemoveAll(tmpFolder); err != nil {
+		logger.Errorf("Error in removing temp folder: %s", err.Error())
+	}
+}
+
 // HandleChaincodeInstantiate to instantiate a chaincode.
 func HandleChaincodeInstantiate(res http.ResponseWriter, req *http.Request) {
 	logger.Info("Service HandleChaincodeInstantiate")
@@ -105,7 +151,17 @@ func HandleChaincodeInstantiate(res http.ResponseWriter, req *http.Request) {
 
 	logger.Info(fmt.Sprintf("Begin to instantiate %s:%s", reqBody.Chaincode.Name, reqBody.Chaincode.Version))
 
+	// To report the syntax error with position, before it fails in the SDK.
+	if _, err := api.ParsePolicy(reqBody.Chaincode.Policy); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "The endorsement policy is invalid."))
+		return
+	}
+
+	begin := time.Now()
 	transID, err := api.InstantiateChaincode(conn, &reqBody.Chaincode, reqBody.Target, reqBody.Orderer)
+	recordAudit(req, conn, &audit.Record{Operation: AuditOperationInstantiate, ChannelID: reqBody.Chaincode.ChannelID,
+		ChaincodeID: reqBody.Chaincode.String(), Targets: []string{reqBody.Target}, Orderer: reqBody.Orderer,
+		ArgumentDigests: audit.Digests(reqBody.Chaincode.Constructor), TxID: string(transID)}, begin, err)
 
 	if err != nil {
 		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when instantiating the chaincode."))
@@ -120,38 +176,26 @@ func HandleChaincodeInstantiate(res http.ResponseWriter, req *http.Request) {
 
 // HandleChaincodeUpgrade to upgrade chaincode.
 func HandleChaincodeUpgrade(res http.ResponseWriter, req *http.Request) {
-	// TODO to consolidate all same operations: reqBody, conn...
 	logger.Info("Service HandleChaincodeUpgrade")
-	body, err := ioutil.ReadAll(req.Body)
-	if err != nil {
-		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when read from request."))
-		return
-	}
-
 	reqBody := &ChaincodeInstantiateReq{}
-	err = json.Unmarshal(body, reqBody)
+	conn, err := GetRequest(req, reqBody, true)
 	if err != nil {
-		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when unmarshal ReqBody from request."))
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
 		return
 	}
 
-	reqConn := reqBody.Connection
-	conn, err := GetConnection(
-		// TODO to support multiple config file type
-		&api.ConnectionProfile{Config: []byte(reqConn.ConnProfile), ConfigType: "yaml"},
-		&api.Participant{Label: reqConn.Label, OrgName: "", MSPID: reqConn.MSPID,
-			Cert: []byte(reqConn.CertContent), PrivateKey: []byte(reqConn.PrvKeyContent), SignID: nil},
-		true)
-	// TODO useDiscovery
+	logger.Info(fmt.Sprintf("Begin to upgrade %s:%s", reqBody.Chaincode.Name, reqBody.Chaincode.Version))
 
-	if err != nil {
-		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when create Fablet connection."))
+	if _, err := api.ParsePolicy(reqBody.Chaincode.Policy); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "The endorsement policy is invalid."))
 		return
 	}
 
-	logger.Info(fmt.Sprintf("Begin to upgrade %s:%s", reqBody.Chaincode.Name, reqBody.Chaincode.Version))
-
+	begin := time.Now()
 	transID, err := api.UpgradeChaincode(conn, &reqBody.Chaincode, reqBody.Target, reqBody.Orderer)
+	recordAudit(req, conn, &audit.Record{Operation: AuditOperationUpgrade, ChannelID: reqBody.Chaincode.ChannelID,
+		ChaincodeID: reqBody.Chaincode.String(), Targets: []string{reqBody.Target}, Orderer: reqBody.Orderer,
+		ArgumentDigests: audit.Digests(reqBody.Chaincode.Constructor), TxID: string(transID)}, begin, err)
 
 	if err != nil {
 		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when upgrading the chaincode."))
@@ -205,8 +249,42 @@ func HandleChaincodeExecute(res http.ResponseWriter, req *http.Request) {
 		ccOperType = api.ChaincodeOperTypeQuery
 	}
 
-	// TODO target is not supported now
-	cceRes, err := api.ExecuteChaincode(conn, reqBody.Chaincode.ChannelID, reqBody.Chaincode.Name, ccOperType, reqBody.Targets, reqBody.FunctionName, reqBody.Arguments)
+	// Check the arguments before sending the proposal, only for chaincodes written with the contract API.
+	err = api.ValidateChaincodeArguments(conn, reqBody.Chaincode.ChannelID, reqBody.Chaincode.Name, reqBody.FunctionName, reqBody.Arguments)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "The arguments do not match the contract metadata."))
+		return
+	}
+
+	// Select the endorsers if no targets, and let the SDK determine them if the strategy can't be applied.
+	var selection *api.EndorserSelection
+	if len(reqBody.Targets) < 1 {
+		if reqBody.SelectionStrategy != "" && !api.IsSelectionStrategy(reqBody.SelectionStrategy) {
+			ErrorOutput(res, req, RES_CODE_ERR_BAD_REQUEST, errors.Errorf("%s is not a valid selection strategy", reqBody.SelectionStrategy))
+			return
+		}
+		selection = api.PlanEndorsers(conn, reqBody.Chaincode.ChannelID, reqBody.Chaincode.Name, reqBody.SelectionStrategy)
+		reqBody.Targets = selection.Targets()
+	}
+
+	async := reqBody.Async && ccOperType == api.ChaincodeOperTypeExecute
+	var cceRes *channel.Response
+	var txStatus *api.TxStatus
+	begin := time.Now()
+	if async {
+		cceRes, txStatus, err = txTracker.Submit(conn, reqBody.Chaincode.ChannelID, reqBody.Chaincode.Name, reqBody.Targets, reqBody.FunctionName, reqBody.Arguments)
+	} else {
+		cceRes, err = api.ExecuteChaincode(conn, reqBody.Chaincode.ChannelID, reqBody.Chaincode.Name, ccOperType, reqBody.Targets, reqBody.FunctionName, reqBody.Arguments)
+	}
+	auditRecord := &audit.Record{Operation: AuditOperationExecute, ChannelID: reqBody.Chaincode.ChannelID, ChaincodeID: reqBody.Chaincode.Name,
+		Targets: reqBody.Targets, ArgumentDigests: audit.Digests(append([]string{reqBody.FunctionName}, reqBody.Arguments...))}
+	if ccOperType == api.ChaincodeOperTypeQuery {
+		auditRecord.Operation = AuditOperationQuery
+	}
+	if cceRes != nil {
+		auditRecord.TxID = string(cceRes.TransactionID)
+	}
+	recordAudit(req, conn, auditRecord, begin, err)
 
 	if err != nil {
 		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL,
@@ -215,21 +293,80 @@ func HandleChaincodeExecute(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	peerRes := []map[string]interface{}{}
+	peerRes := []*ChaincodePeerResponse{}
 	for _, pr := range cceRes.Responses {
-		peerRes = append(peerRes, map[string]interface{}{
-			"endorser": pr.Endorser,
-			"version":  pr.GetVersion(),
-			"payload":  string(pr.GetResponse().GetPayload()),
-			"status":   pr.GetResponse().GetStatus(),
+		peerRes = append(peerRes, &ChaincodePeerResponse{
+			Endorser: pr.Endorser,
+			Version:  pr.GetVersion(),
+			Payload:  string(pr.GetResponse().GetPayload()),
+			Status:   pr.GetResponse().GetStatus(),
 		})
 	}
 
+	result := map[string]interface{}{
+		"transactionID":     cceRes.TransactionID,
+		"txValidationCode":  cceRes.TxValidationCode,
+		"chaincodeStatus":   cceRes.ChaincodeStatus,
+		"payload":           string(cceRes.Payload),
+		"peerResponses":     peerRes,
+		"endorserSelection": selection,
+	}
+
+	// The validation code is unknown before the commit.
+	if async {
+		delete(result, "txValidationCode")
+		result["txStatus"] = txStatus
+	}
+
+	ResultOutput(res, req, result)
+}
+
+// HandleChaincodeMetadata to get the contract metadata, for the UI to build typed forms.
+func HandleChaincodeMetadata(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleChaincodeMetadata")
+
+	reqBody := &ChaincodeMetadataReq{}
+	conn, err := GetRequest(req, reqBody, true)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+
+	metadata, err := api.GetChaincodeMetadata(conn, reqBody.ChannelID, reqBody.ChaincodeID, reqBody.Refresh)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL,
+			errors.WithMessagef(err, "Error occurred when getting the contract metadata of chaincode %s in channel %s.", reqBody.ChaincodeID, reqBody.ChannelID))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"metadata": metadata,
+	})
+}
+
+// HandleChaincodeEndorse to send the same proposal to all peers holding the chaincode, without submitting to the orderer,
+// and then to compare the endorsements.
+func HandleChaincodeEndorse(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleChaincodeEndorse")
+
+	reqBody := &ChaincodeExecuteReq{}
+	conn, err := GetRequest(req, reqBody, true)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+
+	logger.Info(fmt.Sprintf("Begin to endorse chaincode %s:%s", reqBody.Chaincode.Name, reqBody.Chaincode.Version))
+
+	comparison, err := api.EndorseChaincode(conn, reqBody.Chaincode.ChannelID, reqBody.Chaincode.Name, reqBody.Targets, reqBody.FunctionName, reqBody.Arguments)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL,
+			errors.WithMessagef(err, "Error occurred when endorse the chaincode %s in channel %s, with arguments %v, with targets %v.",
+				reqBody.Chaincode.Name, reqBody.Chaincode.ChannelID, reqBody.Arguments, reqBody.Targets))
+		return
+	}
+
 	ResultOutput(res, req, map[string]interface{}{
-		"transactionID":    cceRes.TransactionID,
-		"txValidationCode": cceRes.TxValidationCode,
-		"chaincodeStatus":  cceRes.ChaincodeStatus,
-		"payload":          string(cceRes.Payload),
-		"peerResponses":    peerRes,
+		"endorsement": comparison,
 	})
 }
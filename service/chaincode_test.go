@@ -0,0 +1,33 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"testing"
+)
+
+func TestChaincodeInspect(t *testing.T) {
+	var buf bytes.Buffer
+	tarWriter := tar.NewWriter(&buf)
+	content := []byte(`{"scripts": {"start": "node index.js"}}`)
+	tarWriter.WriteHeader(&tar.Header{Name: "src/", Typeflag: tar.TypeDir, Mode: 0755})
+	tarWriter.WriteHeader(&tar.Header{Name: "src/package.json", Mode: 0644, Size: int64(len(content))})
+	tarWriter.Write(content)
+	tarWriter.Close()
+
+	code, result := postWorkspace(t, "/chaincode/inspect", fmt.Sprintf(`{"chaincode": {"name": "vs", "version": "1.0", "package": "%s"}, "packageFormat": "tar"}`,
+		base64.StdEncoding.EncodeToString(buf.Bytes())))
+	if code != 200 {
+		t.Fatalf("expected the inspection, but got %d %v", code, result)
+	}
+	inspection := result["inspection"].(map[string]interface{})
+	if inspection["type"] != "node" || inspection["entryPoint"] != "node index.js" || inspection["packageID"] == "" || len(inspection["errors"].([]interface{})) != 0 {
+		t.Errorf("expected the Node chaincode, but got %v", inspection)
+	}
+
+	if code, result = postWorkspace(t, "/chaincode/inspect", `{"chaincode": {"name": "vs", "package": "Y2hhaW5jb2Rl"}}`); code != 400 {
+		t.Errorf("expected the invalid request, but got %d %v", code, result)
+	}
+}
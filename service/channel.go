@@ -2,15 +2,17 @@ package service
 
 import (
 	"net/http"
+	"time"
 
-	"github.com/pkg/errors"
 	"github.com/IBM/fablet/api"
+	"github.com/IBM/fablet/audit"
+	"github.com/pkg/errors"
 )
 
 // JoinChannelReq to join a channel
 type JoinChannelReq struct {
 	BaseRequest
-	ChannelID string   `json:"channelID"`
+	ChannelID string   `json:"channelID" openapi:"required"`
 	Targets   []string `json:"targets"`
 	Orderer   string   `json:"orderer"`
 }
@@ -18,7 +20,7 @@ type JoinChannelReq struct {
 // CreateChannelReq to create a channel
 type CreateChannelReq struct {
 	BaseRequest
-	TxContent []byte `json:"txContent"`
+	TxContent []byte `json:"txContent" openapi:"required"`
 	Orderer   string `json:"orderer"`
 }
 
@@ -33,7 +35,10 @@ func HandleCreateChannel(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	begin := time.Now()
 	channelID, err := api.CreateChannel(conn, reqBody.TxContent, reqBody.Orderer)
+	recordAudit(req, conn, &audit.Record{Operation: AuditOperationCreateChannel, ChannelID: channelID, Orderer: reqBody.Orderer,
+		ArgumentDigests: []string{audit.Digest(string(reqBody.TxContent))}}, begin, err)
 	if err != nil {
 		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL,
 			errors.WithMessagef(err, "Error occurs when create channel %s via orderer %s.", channelID, reqBody.Orderer))
@@ -56,7 +61,10 @@ func HandleJoinChannel(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	begin := time.Now()
 	err = api.JoinChannel(conn, reqBody.ChannelID, reqBody.Targets, reqBody.Orderer)
+	recordAudit(req, conn, &audit.Record{Operation: AuditOperationJoinChannel, ChannelID: reqBody.ChannelID,
+		Targets: reqBody.Targets, Orderer: reqBody.Orderer}, begin, err)
 
 	if err != nil {
 		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL,
@@ -2,6 +2,7 @@ package service
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -21,11 +22,27 @@ type HTTPHandler func(res http.ResponseWriter, req *http.Request)
 
 // Post to return HTTPHandler only process post method.
 func Post(hh HTTPHandler) HTTPHandler {
+	return Method(http.MethodPost, hh)
+}
+
+// Get to return HTTPHandler only process get method.
+func Get(hh HTTPHandler) HTTPHandler {
+	return Method(http.MethodGet, hh)
+}
+
+// Method to return HTTPHandler only process the method, others are not allowed except the CORS preflight.
+func Method(method string, hh HTTPHandler) HTTPHandler {
 	return func(res http.ResponseWriter, req *http.Request) {
-		if req.Method != http.MethodPost {
+		if req.Method == http.MethodOptions {
 			PlainOutput(res, req, []byte(""))
 			return
 		}
+		if req.Method != method {
+			res.Header().Set("Allow", method+", "+http.MethodOptions)
+			errorOutput(res, req, RES_CODE_ERR_METHOD, ERR_CODE_METHOD_NOT_ALLOWED,
+				fmt.Sprintf("Method %s is not allowed, use %s.", req.Method, method), nil)
+			return
+		}
 		hh(res, req)
 	}
 }
@@ -35,6 +52,11 @@ func GetTmpFolder() string {
 	return filepath.Join(ExeFolder, "tmp", uuid.NewV1().String())
 }
 
+// GetDataFolder to get the folder for persisted data, e.g. reports of jobs.
+func GetDataFolder(sub string) string {
+	return filepath.Join(ExeFolder, "data", sub)
+}
+
 // GetExeFolder to get the folder of current executable binary file.
 func GetExeFolder() string {
 	exe, err := os.Executable()
@@ -50,7 +72,14 @@ type RequestConnection struct {
 	MSPID         string `json:"MSPID"`
 	CertContent   string `json:"certContent"`
 	PrvKeyContent string `json:"prvKeyContent"`
-	ConnProfile   string `json:"connProfile"`
+	// Passphrase of prvKeyContent if it is encrypted.
+	Passphrase string `json:"passphrase"`
+	// PKCS11 the private key in a PKCS#11 token instead of prvKeyContent.
+	PKCS11      *api.PKCS11Config `json:"PKCS11"`
+	ConnProfile string            `json:"connProfile"`
+	// Workspace of which the profile and identity are used instead of the above, with the identity of the label.
+	Workspace string `json:"workspace"`
+	Identity  string `json:"identity"`
 }
 
 type Request interface {
@@ -95,7 +124,7 @@ func SetHeader(res http.ResponseWriter, req *http.Request, addHeaders map[string
 	// TODO CORS is not safe now.
 	header.Set("Access-Control-Allow-Origin", "*")
 	header.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS, PUT, PATCH, DELETE")
-	header.Set("Access-Control-Allow-Headers", "X-Requested-With,content-type")
+	header.Set("Access-Control-Allow-Headers", "X-Requested-With,content-type,"+AuditUserHeader+","+ConnectionHeader)
 	header.Set("Access-Control-Allow-Credentials", "true")
 	if addHeaders != nil {
 		for k, v := range addHeaders {
@@ -115,13 +144,36 @@ func JsonOutput(res http.ResponseWriter, req *http.Request, result map[string]in
 }
 
 // TODO all function should has return value.
+// ErrorOutput to output the error.
+// The internal error is refined by the error code of the error, other response codes given by handlers are kept.
 func ErrorOutput(res http.ResponseWriter, req *http.Request, resCode ResCode, err error) {
+	errCode, ok := defaultErrCodes[resCode]
+	if !ok {
+		errCode = ClassifyError(err)
+		resCode = ErrCodeResCodes[errCode]
+	}
+	logger.Error(err.Error())
+	errorOutput(res, req, resCode, errCode, err.Error(), nil)
+}
+
+// errorOutput to output the error with the HTTP status of the response code.
+func errorOutput(res http.ResponseWriter, req *http.Request, resCode ResCode, errCode ErrCode, errMsg string, details map[string]interface{}) {
 	result := map[string]interface{}{
 		"resCode": resCode,
-		"errMsg":  err.Error(),
+		"errCode": errCode,
+		"errMsg":  errMsg,
 	}
-	logger.Error(err.Error())
-	JsonOutput(res, req, result)
+	for k, v := range details {
+		result[k] = v
+	}
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		logger.Error("Error occurs when marshal result to json.", err.Error())
+		return
+	}
+	SetHeader(res, req, map[string]string{"Content-Type": "application/json;charset=utf-8"})
+	res.WriteHeader(int(resCode))
+	res.Write(resultJSON)
 }
 
 func ResultOutput(res http.ResponseWriter, req *http.Request, result map[string]interface{}) {
@@ -136,44 +188,68 @@ func PlainOutput(res http.ResponseWriter, req *http.Request, content []byte) {
 
 type ResCode int32
 
+// Response codes, which are also the HTTP status of responses. See ErrCodeResCodes for the error codes of each.
 const (
-	RES_CODE_ERR_INTERNAL = ResCode(500)
-	RES_CODE_OK           = ResCode(200)
+	RES_CODE_ERR_INTERNAL    = ResCode(500)
+	RES_CODE_ERR_BAD_REQUEST = ResCode(400)
+	RES_CODE_ERR_IDENTITY    = ResCode(401)
+	RES_CODE_ERR_FORBIDDEN   = ResCode(403)
+	RES_CODE_ERR_NOT_FOUND   = ResCode(404)
+	RES_CODE_ERR_METHOD      = ResCode(405)
+	RES_CODE_ERR_CONFLICT    = ResCode(409)
+	RES_CODE_ERR_UNREACHABLE = ResCode(502)
+	RES_CODE_ERR_TIMEOUT     = ResCode(504)
+	RES_CODE_OK              = ResCode(200)
 )
 
 // GetRequest get request from http
 // TODO to use GetRequest for all services, and, all connections are not closed after calling, to hold connection in session.
 func GetRequest(req *http.Request, reqBody Request, useDiscovery bool, options ...RequestOptionFunc) (*api.NetworkConnection, error) {
 	logger.Info("Service common function GetReuest")
+	if err := ReadRequest(req, reqBody); err != nil {
+		return nil, err
+	}
+
+	reqConn := reqBody.GetReqConn()
+	return getConnOfReq(reqConn, useDiscovery, options...)
+}
+
+// ReadRequest to read the request body only, for the requests which don't need the connection.
+func ReadRequest(req *http.Request, reqBody interface{}) error {
 	body, err := ioutil.ReadAll(req.Body)
 	if err != nil {
-		return nil, errors.WithMessage(err, "Error occurred when read from request.")
+		return errors.WithMessage(err, "Error occurred when read from request.")
 	}
 
 	err = json.Unmarshal(body, reqBody)
 	if err != nil {
-		return nil, errors.WithMessage(err, "Error occurred when unmarshal ReqBody from request.")
+		return NewServiceError(ERR_CODE_INVALID_REQUEST, errors.WithMessage(err, "Error occurred when unmarshal ReqBody from request."))
 	}
-
-	reqConn := reqBody.GetReqConn()
-	conn, err := getConnOfReq(reqConn, useDiscovery, options...)
-	return conn, nil
+	return nil
 }
 
+// getConnOfReq to get the connection of the request, or of the workspace named by the request.
 func getConnOfReq(reqConn *RequestConnection, useDiscovery bool, options ...RequestOptionFunc) (*api.NetworkConnection, error) {
+	reqConn, err := resolveWorkspace(reqConn)
+	if err != nil {
+		return nil, err
+	}
 	opt := generateOption(options...)
 	connFunc := GetConnection
 	if opt.Refresh {
 		connFunc = NewConnection
 	}
 	conn, err := connFunc(
-		// TODO to support multiple config file type
-		&api.ConnectionProfile{Config: []byte(reqConn.ConnProfile), ConfigType: "yaml"},
+		&api.ConnectionProfile{Config: []byte(reqConn.ConnProfile), ConfigType: api.DetectProfileType([]byte(reqConn.ConnProfile))},
 		&api.Participant{Label: reqConn.Label, OrgName: "", MSPID: reqConn.MSPID,
-			Cert: []byte(reqConn.CertContent), PrivateKey: []byte(reqConn.PrvKeyContent), SignID: nil},
+			Cert: []byte(reqConn.CertContent), PrivateKey: []byte(reqConn.PrvKeyContent), Passphrase: reqConn.Passphrase, SignID: nil, PKCS11: reqConn.PKCS11},
 		useDiscovery)
 
 	if err != nil {
+		// Mistakes of the profile are given, which are vague in errors of the sdk.
+		if summary := profileErrors(reqConn); summary != "" {
+			return nil, errors.WithMessagef(err, "Error occurred when create Fablet connection, the profile has errors: %s.", summary)
+		}
 		return nil, errors.WithMessage(err, "Error occurred when create Fablet connection.")
 	}
 	return conn, nil
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/IBM/fablet/api"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"google.golang.org/grpc/codes"
+)
+
+// ErrCode a stable code of the failure type, responded as errCode with the resCode.
+type ErrCode string
+
+// The catalog of error codes, the response code of each is in ErrCodeResCodes.
+const (
+	ERR_CODE_INTERNAL             = ErrCode("INTERNAL")
+	ERR_CODE_INVALID_REQUEST      = ErrCode("INVALID_REQUEST")
+	ERR_CODE_IDENTITY_INVALID     = ErrCode("IDENTITY_INVALID")
+	ERR_CODE_CONNECTION_NOT_FOUND = ErrCode("CONNECTION_NOT_FOUND")
+	ERR_CODE_POLICY_FAILURE       = ErrCode("POLICY_FAILURE")
+	ERR_CODE_NOT_FOUND            = ErrCode("NOT_FOUND")
+	ERR_CODE_METHOD_NOT_ALLOWED   = ErrCode("METHOD_NOT_ALLOWED")
+	ERR_CODE_ENDORSEMENT_MISMATCH = ErrCode("ENDORSEMENT_MISMATCH")
+	ERR_CODE_MVCC_CONFLICT        = ErrCode("MVCC_CONFLICT")
+	ERR_CODE_CHAINCODE_ERROR      = ErrCode("CHAINCODE_ERROR")
+	ERR_CODE_ENDPOINT_UNREACHABLE = ErrCode("ENDPOINT_UNREACHABLE")
+	ERR_CODE_TIMEOUT              = ErrCode("TIMEOUT")
+)
+
+// ErrCodeResCodes the response code, which is also the HTTP status, of each error code.
+var ErrCodeResCodes = map[ErrCode]ResCode{
+	ERR_CODE_INTERNAL:             RES_CODE_ERR_INTERNAL,
+	ERR_CODE_INVALID_REQUEST:      RES_CODE_ERR_BAD_REQUEST,
+	ERR_CODE_IDENTITY_INVALID:     RES_CODE_ERR_IDENTITY,
+	ERR_CODE_CONNECTION_NOT_FOUND: RES_CODE_ERR_NOT_FOUND,
+	ERR_CODE_POLICY_FAILURE:       RES_CODE_ERR_FORBIDDEN,
+	ERR_CODE_NOT_FOUND:            RES_CODE_ERR_NOT_FOUND,
+	ERR_CODE_METHOD_NOT_ALLOWED:   RES_CODE_ERR_METHOD,
+	ERR_CODE_ENDORSEMENT_MISMATCH: RES_CODE_ERR_CONFLICT,
+	ERR_CODE_MVCC_CONFLICT:        RES_CODE_ERR_CONFLICT,
+	ERR_CODE_CHAINCODE_ERROR:      RES_CODE_ERR_INTERNAL,
+	ERR_CODE_ENDPOINT_UNREACHABLE: RES_CODE_ERR_UNREACHABLE,
+	ERR_CODE_TIMEOUT:              RES_CODE_ERR_TIMEOUT,
+}
+
+// defaultErrCodes the error code of a response code given by the handler.
+var defaultErrCodes = map[ResCode]ErrCode{
+	RES_CODE_ERR_BAD_REQUEST: ERR_CODE_INVALID_REQUEST,
+	RES_CODE_ERR_IDENTITY:    ERR_CODE_IDENTITY_INVALID,
+	RES_CODE_ERR_FORBIDDEN:   ERR_CODE_POLICY_FAILURE,
+	RES_CODE_ERR_NOT_FOUND:   ERR_CODE_NOT_FOUND,
+	RES_CODE_ERR_METHOD:      ERR_CODE_METHOD_NOT_ALLOWED,
+	RES_CODE_ERR_CONFLICT:    ERR_CODE_ENDORSEMENT_MISMATCH,
+	RES_CODE_ERR_UNREACHABLE: ERR_CODE_ENDPOINT_UNREACHABLE,
+	RES_CODE_ERR_TIMEOUT:     ERR_CODE_TIMEOUT,
+}
+
+// ServiceError an error with the known error code, it can be wrapped by errors.WithMessage.
+type ServiceError struct {
+	ErrCode ErrCode
+	Err     error
+}
+
+func (err *ServiceError) Error() string {
+	return err.Err.Error()
+}
+
+// NewServiceError to mark the error with the error code.
+func NewServiceError(errCode ErrCode, err error) error {
+	return &ServiceError{ErrCode: errCode, Err: err}
+}
+
+// ClassifyError to get the error code of the error, errors of the SDK, network and contexts are recognized.
+func ClassifyError(err error) ErrCode {
+	if errCode, ok := classifyError(err); ok {
+		return errCode
+	}
+	return ERR_CODE_INTERNAL
+}
+
+type causer interface {
+	Cause() error
+}
+
+func classifyError(err error) (ErrCode, bool) {
+	// Go through the wrapped errors, since the message of wrapper is not useful.
+	for e := err; e != nil; {
+		if errCode, ok := classifyTypedError(e); ok {
+			return errCode, true
+		}
+		c, ok := e.(causer)
+		if !ok {
+			break
+		}
+		e = c.Cause()
+	}
+	if err == nil {
+		return "", false
+	}
+	return classifyMessage(err.Error())
+}
+
+func classifyTypedError(err error) (ErrCode, bool) {
+	switch e := err.(type) {
+	case *ServiceError:
+		return e.ErrCode, true
+	case *api.IdentityError:
+		return ERR_CODE_IDENTITY_INVALID, true
+	case *api.PolicySyntaxError, *api.ArgumentValidationError, *api.PKCS11LibraryError:
+		return ERR_CODE_INVALID_REQUEST, true
+	case *status.Status:
+		return classifyStatus(e)
+	case net.Error:
+		if e.Timeout() {
+			return ERR_CODE_TIMEOUT, true
+		}
+		return ERR_CODE_ENDPOINT_UNREACHABLE, true
+	}
+	if err == context.DeadlineExceeded {
+		return ERR_CODE_TIMEOUT, true
+	}
+	return "", false
+}
+
+// classifyStatus to classify errors of the SDK.
+func classifyStatus(s *status.Status) (ErrCode, bool) {
+	switch s.Group {
+	case status.GRPCTransportStatus:
+		switch codes.Code(s.Code) {
+		case codes.Unavailable:
+			return ERR_CODE_ENDPOINT_UNREACHABLE, true
+		case codes.DeadlineExceeded:
+			return ERR_CODE_TIMEOUT, true
+		}
+	case status.EventServerStatus:
+		switch peer.TxValidationCode(s.Code) {
+		case peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE:
+			return ERR_CODE_POLICY_FAILURE, true
+		case peer.TxValidationCode_MVCC_READ_CONFLICT, peer.TxValidationCode_PHANTOM_READ_CONFLICT:
+			return ERR_CODE_MVCC_CONFLICT, true
+		}
+	case status.EndorserServerStatus, status.ChaincodeStatus:
+		// The message may be of the chaincode, only the codes of Fabric are matched.
+		if errCode, ok := matchMessage(s.Message, messageErrCodes); ok {
+			return errCode, true
+		}
+		return ERR_CODE_CHAINCODE_ERROR, true
+	case status.ClientStatus, status.EndorserClientStatus, status.OrdererClientStatus:
+		switch status.Code(s.Code) {
+		case status.ConnectionFailed, status.NoPeersFound:
+			return ERR_CODE_ENDPOINT_UNREACHABLE, true
+		case status.Timeout:
+			return ERR_CODE_TIMEOUT, true
+		case status.EndorsementMismatch:
+			return ERR_CODE_ENDORSEMENT_MISMATCH, true
+		case status.EmptyCert:
+			return ERR_CODE_IDENTITY_INVALID, true
+		case status.MultipleErrors:
+			for _, detail := range s.Details {
+				if detailErr, ok := detail.(error); ok {
+					if errCode, ok := classifyError(detailErr); ok {
+						return errCode, true
+					}
+				}
+			}
+		}
+	}
+	return classifyMessage(s.Message)
+}
+
+// messageErrCode the error code of messages including any of the keywords.
+type messageErrCode struct {
+	keywords []string
+	errCode  ErrCode
+}
+
+// messageErrCodes the error codes of messages, for errors of the SDK which are only in text.
+// The keywords are codes and messages of Fabric, which are not expected in messages of chaincodes.
+var messageErrCodes = []messageErrCode{
+	{[]string{"endorsement_policy_failure"}, ERR_CODE_POLICY_FAILURE},
+	{[]string{"mvcc_read_conflict", "phantom_read_conflict"}, ERR_CODE_MVCC_CONFLICT},
+	{[]string{"endorsementmismatch", "responses do not match", "payloads do not match"}, ERR_CODE_ENDORSEMENT_MISMATCH},
+	{[]string{"access denied", "creator certificate is not valid", "failed to create signing identity"}, ERR_CODE_IDENTITY_INVALID},
+}
+
+// transportMessageErrCodes the error codes of messages of gRPC and the network. They are not matched in messages
+// of chaincodes, and are as the errors are formatted, not common words such as timeout.
+var transportMessageErrCodes = []messageErrCode{
+	{[]string{"context deadline exceeded", "code = deadlineexceeded", "i/o timeout", "request timed out"}, ERR_CODE_TIMEOUT},
+	{[]string{"connection refused", "no such host", "transient_failure", "failed to connect", "code = unavailable"}, ERR_CODE_ENDPOINT_UNREACHABLE},
+}
+
+func classifyMessage(msg string) (ErrCode, bool) {
+	if errCode, ok := matchMessage(msg, messageErrCodes); ok {
+		return errCode, true
+	}
+	return matchMessage(msg, transportMessageErrCodes)
+}
+
+func matchMessage(msg string, mecs []messageErrCode) (ErrCode, bool) {
+	msg = strings.ToLower(msg)
+	for _, mec := range mecs {
+		for _, keyword := range mec.keywords {
+			if strings.Contains(msg, keyword) {
+				return mec.errCode, true
+			}
+		}
+	}
+	return "", false
+}
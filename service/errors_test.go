@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IBM/fablet/api"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/pkg/errors"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		err     error
+		errCode ErrCode
+	}{
+		{status.New(status.EventServerStatus, int32(peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE), "", nil), ERR_CODE_POLICY_FAILURE},
+		{status.New(status.EventServerStatus, int32(peer.TxValidationCode_MVCC_READ_CONFLICT), "", nil), ERR_CODE_MVCC_CONFLICT},
+		{status.New(status.ClientStatus, status.Timeout.ToInt32(), "request timed out", nil), ERR_CODE_TIMEOUT},
+		{status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "", nil), ERR_CODE_ENDORSEMENT_MISMATCH},
+		{status.New(status.ChaincodeStatus, 500, "incorrect arguments", nil), ERR_CODE_CHAINCODE_ERROR},
+		{errors.WithMessage(status.New(status.ClientStatus, status.MultipleErrors.ToInt32(), "",
+			[]interface{}{status.New(status.ClientStatus, status.ConnectionFailed.ToInt32(), "", nil)}), "Error occurred."), ERR_CODE_ENDPOINT_UNREACHABLE},
+		{errors.WithMessage(NewServiceError(ERR_CODE_NOT_FOUND, errors.New("not found")), "Error occurred."), ERR_CODE_NOT_FOUND},
+		{&net.OpError{Op: "dial", Err: errors.New("refused")}, ERR_CODE_ENDPOINT_UNREACHABLE},
+		{errors.Wrap(context.DeadlineExceeded, "waiting"), ERR_CODE_TIMEOUT},
+		{errors.New("transaction returned with failure: MVCC_READ_CONFLICT"), ERR_CODE_MVCC_CONFLICT},
+		{errors.New("something else"), ERR_CODE_INTERNAL},
+		{errors.WithMessage(&api.PolicySyntaxError{Position: 4, Message: "unexpected end"}, "Error occurred."), ERR_CODE_INVALID_REQUEST},
+		{&api.ArgumentValidationError{Function: "CreateVehicle"}, ERR_CODE_INVALID_REQUEST},
+		{errors.WithMessage(&api.PKCS11LibraryError{Library: "/tmp/evil.so"}, "Error occurred."), ERR_CODE_INVALID_REQUEST},
+		// Words of chaincode messages are not of the network.
+		{status.New(status.ChaincodeStatus, 500, "timeout of the order is over, service unavailable", nil), ERR_CODE_CHAINCODE_ERROR},
+		{errors.New("rpc error: code = Unavailable desc = all SubConns are in TransientFailure"), ERR_CODE_ENDPOINT_UNREACHABLE},
+		{errors.New("the timeout of the settlement is unavailable"), ERR_CODE_INTERNAL},
+		// Only the answer of the peer that the entry is not in the ledger is not found.
+		{ledgerQueryError(status.New(status.EndorserServerStatus, 500, "no such transaction ID [abc] in index", nil), "transaction abc"), ERR_CODE_NOT_FOUND},
+		{ledgerQueryError(status.New(status.ClientStatus, status.ConnectionFailed.ToInt32(), "", nil), "transaction abc"), ERR_CODE_ENDPOINT_UNREACHABLE},
+	}
+	for _, c := range cases {
+		if errCode := ClassifyError(c.err); errCode != c.errCode {
+			t.Errorf("expected %s of %v, but got %s", c.errCode, c.err, errCode)
+		}
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	handlerMap := GetHandlerMap()
+	cases := []struct {
+		method  string
+		path    string
+		resCode ResCode
+		errCode ErrCode
+	}{
+		{"GET", "/policy/parse", RES_CODE_ERR_METHOD, ERR_CODE_METHOD_NOT_ALLOWED},
+		{"POST", "/channels", RES_CODE_ERR_METHOD, ERR_CODE_METHOD_NOT_ALLOWED},
+		{"GET", "/channels", RES_CODE_ERR_BAD_REQUEST, ERR_CODE_INVALID_REQUEST},
+		{"GET", "/channels/mychannel/unknown", RES_CODE_ERR_NOT_FOUND, ERR_CODE_NOT_FOUND},
+	}
+	for _, c := range cases {
+		handler := handlerMap[c.path]
+		if handler == nil {
+			handler = handlerMap["/channels/"]
+		}
+		res := httptest.NewRecorder()
+		handler(res, httptest.NewRequest(c.method, c.path, nil))
+
+		result := &struct {
+			ResCode ResCode `json:"resCode"`
+			ErrCode ErrCode `json:"errCode"`
+		}{}
+		if err := json.Unmarshal(res.Body.Bytes(), result); err != nil {
+			t.Fatal(err)
+		}
+		if res.Code != int(c.resCode) || result.ResCode != c.resCode || result.ErrCode != c.errCode {
+			t.Errorf("expected %d %s of %s %s, but got %d %s", c.resCode, c.errCode, c.method, c.path, res.Code, result.ErrCode)
+		}
+	}
+}
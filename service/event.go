@@ -42,8 +42,12 @@ type ChaincodeEventResult struct {
 	SourceURL   string `json:"sourceURL"`
 }
 
+// ErrorResult error sent in websocket.
 type ErrorResult struct {
-	Error string `json:error`
+	Error   string  `json:"error"`
+	ErrCode ErrCode `json:"errCode,omitempty"`
+	// FieldErrors of an invalid request.
+	FieldErrors []*FieldError `json:"fieldErrors,omitempty"`
 }
 
 const (
@@ -54,10 +58,10 @@ const (
 )
 
 // HandleBlockEvent handle event
-func HandleBlockEvent(wsConn *websocket.Conn) error {
+func HandleBlockEvent(wsConn *websocket.Conn, request []byte) error {
 	logger.Info("Service HandleBlockEvent")
 	reqBody := &BlockEventReq{}
-	if err := wsConn.ReadJSON(reqBody); err != nil {
+	if err := json.Unmarshal(request, reqBody); err != nil {
 		return err
 	}
 	conn, err := getConnOfReq(reqBody.GetReqConn(), true)
@@ -137,7 +141,7 @@ func HandleBlockEvent(wsConn *websocket.Conn) error {
 }
 
 // HandleChaincodeEvent handle event
-func HandleChaincodeEvent(wsConn *websocket.Conn) error {
+func HandleChaincodeEvent(wsConn *websocket.Conn, request []byte) error {
 	logger.Info("Service HandleChaincodeEvent")
 
 	listeners := 0
@@ -159,6 +163,12 @@ func HandleChaincodeEvent(wsConn *websocket.Conn) error {
 		pingTicker.Stop()
 	}()
 
+	// The first request, the later ones are read from the connection.
+	reqBody := &ChaincodeEventReq{}
+	if err := json.Unmarshal(request, reqBody); err != nil {
+		return err
+	}
+	reqChan <- reqBody
 	go readCCEventRequest(wsConn, reqChan, errChan)
 
 	for {
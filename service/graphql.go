@@ -0,0 +1,547 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/IBM/fablet/api"
+	gqlutil "github.com/IBM/fablet/graphql"
+	"github.com/graphql-go/graphql"
+	"github.com/pkg/errors"
+)
+
+// GraphQLReq a GraphQL query over the network and ledgers of the connection.
+type GraphQLReq struct {
+	BaseRequest
+	Query         string                 `json:"query" openapi:"required"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// graphQLLoadConcurrency the number of keys of a loader fetched from peers at the same time.
+const graphQLLoadConcurrency = 16
+
+// HandleGraphQL to execute a GraphQL query, errors of fields are in the result with the other fields.
+func HandleGraphQL(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleGraphQL")
+
+	reqBody := &GraphQLReq{}
+	conn, err := GetRequest(req, reqBody, true)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+
+	ctx := context.WithValue(req.Context(), graphQLSessionKey{}, newGraphQLSession(conn))
+	result := executeGraphQL(ctx, reqBody.Query, reqBody.Variables, reqBody.OperationName)
+	// The query is not executed if it is invalid.
+	if result.Data == nil && len(result.Errors) > 0 {
+		msgs := []string{}
+		for _, gqlErr := range result.Errors {
+			msgs = append(msgs, gqlErr.Message)
+		}
+		ErrorOutput(res, req, RES_CODE_ERR_BAD_REQUEST, errors.Errorf("Error occurred when executing the query: %s.", strings.Join(msgs, "; ")))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"data":   result.Data,
+		"errors": result.Errors,
+	})
+}
+
+// HandleGraphQLSchema to get the GraphQL schema in the schema definition language, it is also served by introspection.
+func HandleGraphQLSchema(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleGraphQLSchema")
+	PlainOutput(res, req, []byte(gqlutil.PrintSchema(getGraphQLSchema())))
+}
+
+func executeGraphQL(ctx context.Context, query string, variables map[string]interface{}, operationName string) *graphql.Result {
+	return graphql.Do(graphql.Params{
+		Schema:         getGraphQLSchema(),
+		RequestString:  query,
+		VariableValues: variables,
+		OperationName:  operationName,
+		Context:        ctx,
+	})
+}
+
+type graphQLSessionKey struct{}
+
+// graphQLSession the data of a query, the loaders cache what are fetched from peers, so they are per query.
+type graphQLSession struct {
+	conn     *api.NetworkConnection
+	overview *api.NetworkOverview
+	orgs     []*api.Organization
+	orderers []*api.Orderer
+
+	ledgers   *gqlutil.Loader // ledgerKey -> *api.Ledger
+	blocks    *gqlutil.Loader // blockKey -> *api.Block
+	txs       *gqlutil.Loader // txKey -> *graphQLTransaction
+	installed *gqlutil.Loader // peer URL -> []*api.Chaincode
+}
+
+type ledgerKey struct {
+	channelID string
+	target    string
+}
+
+type blockKey struct {
+	channelID string
+	target    string
+	number    uint64
+}
+
+type txKey struct {
+	channelID string
+	target    string
+	txID      string
+}
+
+// graphQLTransaction a transaction with the number of its block.
+type graphQLTransaction struct {
+	*api.Transaction
+	BlockNumber uint64 `json:"blockNumber"`
+}
+
+func newGraphQLSession(conn *api.NetworkConnection) *graphQLSession {
+	session := &graphQLSession{conn: conn}
+	session.overview, _ = api.DiscoverNetworkOverview(conn)
+
+	for _, org := range conn.Organizations {
+		session.orgs = append(session.orgs, org)
+	}
+	sort.Slice(session.orgs, func(i, j int) bool { return session.orgs[i].Name < session.orgs[j].Name })
+	for _, orderer := range conn.Orderers {
+		session.orderers = append(session.orderers, orderer)
+	}
+	sort.Slice(session.orderers, func(i, j int) bool { return session.orderers[i].Name < session.orderers[j].Name })
+	sort.Slice(session.overview.Channels, func(i, j int) bool {
+		return session.overview.Channels[i].ChannelID < session.overview.Channels[j].ChannelID
+	})
+
+	session.ledgers = gqlutil.NewLoader(session.loadLedgers)
+	session.blocks = gqlutil.NewLoader(session.loadBlocks)
+	session.txs = gqlutil.NewLoader(session.loadTransactions)
+	session.installed = gqlutil.NewLoader(session.loadInstalledChaincodes)
+	return session
+}
+
+func getGraphQLSession(ctx context.Context) *graphQLSession {
+	return ctx.Value(graphQLSessionKey{}).(*graphQLSession)
+}
+
+func targetsOf(target string) []string {
+	if target == "" {
+		return nil
+	}
+	return []string{target}
+}
+
+// loadConcurrently to load values of the keys one by one, since peers have no batch queries of them,
+// but at most graphQLLoadConcurrency keys at the same time. The error of a key is its value.
+func loadConcurrently(keys []interface{}, load func(key interface{}) (interface{}, error)) (map[interface{}]interface{}, error) {
+	values := map[interface{}]interface{}{}
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, graphQLLoadConcurrency)
+	for _, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(key interface{}) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			value, err := load(key)
+			if err != nil {
+				value = err
+			}
+			lock.Lock()
+			values[key] = value
+			lock.Unlock()
+		}(key)
+	}
+	wg.Wait()
+	return values, nil
+}
+
+func (session *graphQLSession) loadLedgers(keys []interface{}) (map[interface{}]interface{}, error) {
+	return loadConcurrently(keys, func(key interface{}) (interface{}, error) {
+		lk := key.(ledgerKey)
+		ledger, err := api.QueryLedger(session.conn, lk.channelID, targetsOf(lk.target))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "Error occurred when query ledger of %s.", lk.channelID)
+		}
+		return ledger, nil
+	})
+}
+
+func (session *graphQLSession) loadBlocks(keys []interface{}) (map[interface{}]interface{}, error) {
+	return loadConcurrently(keys, func(key interface{}) (interface{}, error) {
+		bk := key.(blockKey)
+		block, err := api.QueryBlockByNumber(session.conn, bk.channelID, targetsOf(bk.target), bk.number)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "Error occurred when query block %d of %s.", bk.number, bk.channelID)
+		}
+		return block, nil
+	})
+}
+
+func (session *graphQLSession) loadTransactions(keys []interface{}) (map[interface{}]interface{}, error) {
+	return loadConcurrently(keys, func(key interface{}) (interface{}, error) {
+		tk := key.(txKey)
+		block, err := api.QueryBlockByTxID(session.conn, tk.channelID, targetsOf(tk.target), tk.txID)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "Error occurred when query transaction %s.", tk.txID)
+		}
+		for _, tx := range block.Transactions {
+			if tx.TxID == tk.txID {
+				return &graphQLTransaction{Transaction: tx, BlockNumber: block.Number}, nil
+			}
+		}
+		return nil, nil
+	})
+}
+
+func (session *graphQLSession) loadInstalledChaincodes(keys []interface{}) (map[interface{}]interface{}, error) {
+	return loadConcurrently(keys, func(key interface{}) (interface{}, error) {
+		chaincodes, err := api.QueryInstalledChaincodes(session.conn, key.(string))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "Error occurred when query installed chaincodes of peer %s.", key)
+		}
+		return chaincodes, nil
+	})
+}
+
+// findPeers to find peers by names or URLs, as members of channels and organizations are either.
+func (session *graphQLSession) findPeers(nameOrURLs []string) []*api.Peer {
+	peers := []*api.Peer{}
+	for _, peer := range session.overview.Peers {
+		for _, nameOrURL := range nameOrURLs {
+			if peer.Name == nameOrURL || peer.URL == nameOrURL {
+				peers = append(peers, peer)
+				break
+			}
+		}
+	}
+	return peers
+}
+
+func (session *graphQLSession) findChannels(channelIDs []string) []*api.Channel {
+	channels := []*api.Channel{}
+	for _, channel := range session.overview.Channels {
+		for _, channelID := range channelIDs {
+			if channel.ChannelID == channelID {
+				channels = append(channels, channel)
+				break
+			}
+		}
+	}
+	return channels
+}
+
+var (
+	graphQLSchema     graphql.Schema
+	graphQLSchemaOnce sync.Once
+)
+
+func getGraphQLSchema() graphql.Schema {
+	graphQLSchemaOnce.Do(func() {
+		var err error
+		graphQLSchema, err = newGraphQLSchema()
+		if err != nil {
+			// The schema is static, it is a bug if it is invalid.
+			panic(errors.WithMessage(err, "Error occurred when creating the GraphQL schema."))
+		}
+	})
+	return graphQLSchema
+}
+
+// newGraphQLSchema the schema of the network and ledgers, of which resolvers are backed by the api functions.
+func newGraphQLSchema() (graphql.Schema, error) {
+	// Long a 64-bit integer, e.g. a block number, out of the range of Int.
+	long := graphql.NewScalar(graphql.ScalarConfig{Name: "Long", Description: "A 64-bit integer, output only.",
+		Serialize: func(value interface{}) interface{} {
+			return value
+		}})
+	str := graphql.String
+
+	peerStatus := gqlutil.NewObject(graphql.ObjectConfig{Name: "PeerStatus", Fields: graphql.Fields{
+		"ping":  {Type: graphql.Boolean},
+		"GRPC":  {Type: graphql.Boolean},
+		"valid": {Type: graphql.Boolean},
+	}})
+	ledger := gqlutil.NewObject(graphql.ObjectConfig{Name: "Ledger", Fields: graphql.Fields{
+		"height":           {Type: long},
+		"currentBlockHash": {Type: str},
+		"endorser":         {Type: str},
+		"status":           {Type: graphql.Int},
+	}})
+	chaincode := gqlutil.NewObject(graphql.ObjectConfig{Name: "Chaincode", Fields: graphql.Fields{
+		"name":      {Type: str},
+		"version":   {Type: str},
+		"path":      {Type: str},
+		"type":      {Type: str},
+		"channelID": {Type: str},
+		"policy":    {Type: str},
+		"installed": {Type: graphql.Boolean},
+		"packageID": {Type: str},
+	}})
+	endorser := gqlutil.NewObject(graphql.ObjectConfig{Name: "Endorser", Fields: graphql.Fields{
+		"commonName": {Type: str},
+		"subject":    {Type: str},
+		"MSPID":      {Type: str},
+		"issuer":     {Type: str},
+		"isCA":       {Type: graphql.Boolean},
+	}})
+	kvRead := gqlutil.NewObject(graphql.ObjectConfig{Name: "KVRead", Fields: graphql.Fields{
+		"key":         {Type: str},
+		"verBlockNum": {Type: long},
+		"verTxNum":    {Type: long},
+	}})
+	kvWrite := gqlutil.NewObject(graphql.ObjectConfig{Name: "KVWrite", Fields: graphql.Fields{
+		"key":      {Type: str},
+		"isDelete": {Type: graphql.Boolean},
+		"value": {Type: str, Description: "The value, in JSON if it is not a string.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				value := p.Source.(*api.KVWrite).Value
+				if s, ok := value.(string); ok || value == nil {
+					return s, nil
+				}
+				valueJSON, err := json.Marshal(value)
+				return string(valueJSON), err
+			}},
+	}})
+	rwset := gqlutil.NewObject(graphql.ObjectConfig{Name: "NSReadWriteSet", Fields: graphql.Fields{
+		"namespace": {Type: str, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*api.NSReadWriteSet).NameSpace, nil
+		}},
+		"reads": {Type: graphql.NewList(kvRead), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*api.NSReadWriteSet).KVReadSet, nil
+		}},
+		"writes": {Type: graphql.NewList(kvWrite), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*api.NSReadWriteSet).KVWriteSet, nil
+		}},
+	}})
+	action := gqlutil.NewObject(graphql.ObjectConfig{Name: "Action", Fields: graphql.Fields{
+		"chaincodeName":    {Type: str},
+		"chaincodeVersion": {Type: str},
+		"arguments":        {Type: graphql.NewList(str)},
+		"endorsers":        {Type: graphql.NewList(endorser)},
+		"rwsets": {Type: graphql.NewList(rwset), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			response := p.Source.(*api.Action).ProposalResponse
+			if response == nil || response.TXReadWriteSet == nil {
+				return nil, nil
+			}
+			return response.TXReadWriteSet.NSReadWriteSets, nil
+		}},
+	}})
+	transaction := gqlutil.NewObject(graphql.ObjectConfig{Name: "Transaction", Fields: graphql.Fields{
+		"txID":           {Type: graphql.NewNonNull(graphql.ID)},
+		"validationCode": {Type: str},
+		"blockNumber":    {Type: long},
+		"actions":        {Type: graphql.NewList(action)},
+	}})
+	block := gqlutil.NewObject(graphql.ObjectConfig{Name: "Block", Fields: graphql.Fields{
+		"number":       {Type: graphql.NewNonNull(long)},
+		"dataHash":     {Type: str},
+		"previousHash": {Type: str},
+		"blockHash":    {Type: str},
+		"time":         {Type: long, Description: "Timestamp in milliseconds."},
+		"transactions": {Type: graphql.NewList(transaction), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			b := p.Source.(*api.Block)
+			txs := []*graphQLTransaction{}
+			for _, tx := range b.Transactions {
+				txs = append(txs, &graphQLTransaction{Transaction: tx, BlockNumber: b.Number})
+			}
+			return txs, nil
+		}},
+	}})
+
+	targetArg := &graphql.ArgumentConfig{Type: str, Description: "The peer to query, any peer of the channel if absent."}
+	var channel, peer, orderer *graphql.Object
+
+	channel = gqlutil.NewObject(graphql.ObjectConfig{Name: "Channel", Fields: graphql.FieldsThunk(func() graphql.Fields {
+		return graphql.Fields{
+			"id": {Type: graphql.NewNonNull(graphql.ID), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*api.Channel).ChannelID, nil
+			}},
+			"peers": {Type: graphql.NewList(peer), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return getGraphQLSession(p.Context).findPeers(p.Source.(*api.Channel).Peers.StringList()), nil
+			}},
+			"anchorPeers": {Type: graphql.NewList(str), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return getGraphQLSession(p.Context).overview.ChannelAnchorPeers[p.Source.(*api.Channel).ChannelID], nil
+			}},
+			"orderers": {Type: graphql.NewList(orderer), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return getGraphQLSession(p.Context).overview.ChannelOrderers[p.Source.(*api.Channel).ChannelID], nil
+			}},
+			"chaincodes": {Type: graphql.NewList(chaincode), Description: "Instantiated chaincodes.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return getGraphQLSession(p.Context).overview.ChannelChainCodes[p.Source.(*api.Channel).ChannelID], nil
+				}},
+			"ledger": {Type: ledger, Args: graphql.FieldConfigArgument{"target": targetArg},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					session := getGraphQLSession(p.Context)
+					channelID := p.Source.(*api.Channel).ChannelID
+					target, _ := p.Args["target"].(string)
+					if target == "" {
+						if ledger, ok := session.overview.ChannelLedgers[channelID]; ok {
+							return ledger, nil
+						}
+					}
+					return session.ledgers.Load(ledgerKey{channelID: channelID, target: target}), nil
+				}},
+			"blocks": {Type: graphql.NewList(block), Description: "Blocks from begin, the latest blocks if begin is absent.",
+				Args: graphql.FieldConfigArgument{
+					"begin":  {Type: graphql.Int},
+					"len":    {Type: graphql.Int, DefaultValue: 10},
+					"target": targetArg,
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					session := getGraphQLSession(p.Context)
+					channelID := p.Source.(*api.Channel).ChannelID
+					target, _ := p.Args["target"].(string)
+					length, _ := p.Args["len"].(int)
+					if length <= 0 || length > 512 {
+						return nil, errors.Errorf("len should be between 1 and 512, but it is %d", length)
+					}
+
+					var begin uint64
+					if b, ok := p.Args["begin"].(int); ok {
+						if b < 0 {
+							return nil, errors.Errorf("begin should not be negative, but it is %d", b)
+						}
+						begin = uint64(b)
+					} else {
+						ledger, ok := session.overview.ChannelLedgers[channelID]
+						if !ok {
+							return nil, errors.Errorf("The height of channel %s is unknown, begin is required.", channelID)
+						}
+						if ledger.Height > uint64(length) {
+							begin = ledger.Height - uint64(length)
+						}
+						if length > int(ledger.Height-begin) {
+							length = int(ledger.Height - begin)
+						}
+					}
+
+					thunks := []gqlutil.Thunk{}
+					for n := 0; n < length; n++ {
+						thunks = append(thunks, session.blocks.Load(blockKey{channelID: channelID, target: target, number: begin + uint64(n)}))
+					}
+					// Blocks failed, e.g. beyond the height, are omitted, unless all are failed.
+					return gqlutil.Thunk(func() (interface{}, error) {
+						blocks := []*api.Block{}
+						var lastErr error
+						for _, thunk := range thunks {
+							b, err := thunk()
+							if err != nil {
+								lastErr = err
+								continue
+							}
+							blocks = append(blocks, b.(*api.Block))
+						}
+						if len(blocks) == 0 && lastErr != nil {
+							return nil, lastErr
+						}
+						return blocks, nil
+					}), nil
+				}},
+			"block": {Type: block, Args: graphql.FieldConfigArgument{"number": {Type: graphql.NewNonNull(graphql.Int)}, "target": targetArg},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					number := p.Args["number"].(int)
+					if number < 0 {
+						return nil, errors.Errorf("number should not be negative, but it is %d", number)
+					}
+					target, _ := p.Args["target"].(string)
+					return getGraphQLSession(p.Context).blocks.Load(
+						blockKey{channelID: p.Source.(*api.Channel).ChannelID, target: target, number: uint64(number)}), nil
+				}},
+			"transaction": {Type: transaction, Args: graphql.FieldConfigArgument{"txID": {Type: graphql.NewNonNull(graphql.ID)}, "target": targetArg},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					target, _ := p.Args["target"].(string)
+					return getGraphQLSession(p.Context).txs.Load(
+						txKey{channelID: p.Source.(*api.Channel).ChannelID, target: target, txID: p.Args["txID"].(string)}), nil
+				}},
+		}
+	})})
+
+	peer = gqlutil.NewObject(graphql.ObjectConfig{Name: "Peer", Fields: graphql.FieldsThunk(func() graphql.Fields {
+		return graphql.Fields{
+			"name":         {Type: graphql.NewNonNull(str)},
+			"orgName":      {Type: str},
+			"MSPID":        {Type: str},
+			"URL":          {Type: str},
+			"isConfigured": {Type: graphql.Boolean},
+			"status": {Type: peerStatus, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				statuses := transPeerStatuses(getGraphQLSession(p.Context).overview.EndpointStatuses)
+				if status, ok := statuses[p.Source.(*api.Peer).Name]; ok {
+					return status, nil
+				}
+				return nil, nil
+			}},
+			"channels": {Type: graphql.NewList(channel), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return getGraphQLSession(p.Context).findChannels(p.Source.(*api.Peer).Channels.StringList()), nil
+			}},
+			"installedChaincodes": {Type: graphql.NewList(chaincode), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return getGraphQLSession(p.Context).installed.Load(p.Source.(*api.Peer).URL), nil
+			}},
+		}
+	})})
+
+	orderer = gqlutil.NewObject(graphql.ObjectConfig{Name: "Orderer", Fields: graphql.FieldsThunk(func() graphql.Fields {
+		return graphql.Fields{
+			"name": {Type: graphql.NewNonNull(str)},
+			"URL":  {Type: str},
+			"channels": {Type: graphql.NewList(channel), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return getGraphQLSession(p.Context).findChannels(p.Source.(*api.Orderer).Channels.StringList()), nil
+			}},
+		}
+	})})
+
+	org := gqlutil.NewObject(graphql.ObjectConfig{Name: "Org", Fields: graphql.Fields{
+		"name":  {Type: graphql.NewNonNull(str)},
+		"MSPID": {Type: str},
+		"peers": {Type: graphql.NewList(peer), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return getGraphQLSession(p.Context).findPeers(p.Source.(*api.Organization).Peers.StringList()), nil
+		}},
+	}})
+
+	query := gqlutil.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+		"peers": {Type: graphql.NewList(peer), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return getGraphQLSession(p.Context).overview.Peers, nil
+		}},
+		"peer": {Type: peer, Args: graphql.FieldConfigArgument{"name": {Type: graphql.NewNonNull(str), Description: "Name or URL of the peer."}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				peers := getGraphQLSession(p.Context).findPeers([]string{p.Args["name"].(string)})
+				if len(peers) == 0 {
+					return nil, nil
+				}
+				return peers[0], nil
+			}},
+		"orgs": {Type: graphql.NewList(org), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return getGraphQLSession(p.Context).orgs, nil
+		}},
+		"channels": {Type: graphql.NewList(channel), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return getGraphQLSession(p.Context).overview.Channels, nil
+		}},
+		"channel": {Type: channel, Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				channels := getGraphQLSession(p.Context).findChannels([]string{p.Args["id"].(string)})
+				if len(channels) == 0 {
+					return nil, nil
+				}
+				return channels[0], nil
+			}},
+		"orderers": {Type: graphql.NewList(orderer), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return getGraphQLSession(p.Context).orderers, nil
+		}},
+	}})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/IBM/fablet/api"
+	"github.com/IBM/fablet/util"
+)
+
+func TestGraphQLQuery(t *testing.T) {
+	// Fields resolved by the discovered network don't call peers.
+	conn := &api.NetworkConnection{
+		Peers: map[string]*api.Peer{
+			"peer0.org1": {Name: "peer0.org1", MSPID: "Org1MSP", URL: "grpcs://localhost:7051", Channels: util.NewStringSet("mychannel")},
+			"peer0.org2": {Name: "peer0.org2", MSPID: "Org2MSP", URL: "grpcs://localhost:9051", Channels: util.NewStringSet()},
+		},
+		Channels: map[string]*api.Channel{
+			"mychannel": {ChannelID: "mychannel", Peers: util.NewStringSet("grpcs://localhost:7051")},
+		},
+		Organizations: map[string]*api.Organization{
+			"org2": {Name: "org2", MSPID: "Org2MSP", Peers: util.NewStringSet("peer0.org2")},
+			"org1": {Name: "org1", MSPID: "Org1MSP", Peers: util.NewStringSet("peer0.org1")},
+		},
+		EndpointStatuses: map[string]util.EndPointStatus{"peer0.org1": util.EndPointStatus_Valid},
+		ChannelLedgers:   map[string]*api.Ledger{"mychannel": {Height: 5}},
+	}
+
+	ctx := context.WithValue(context.Background(), graphQLSessionKey{}, newGraphQLSession(conn))
+	result := executeGraphQL(ctx, `{
+		channels { id peers { name status { valid } } ledger { height } }
+		orgs { name peers { name } }
+		peer(name: "grpcs://localhost:9051") { name channels { id } }
+		blocks: channel(id: "mychannel") { blocks(len: 0) { number } }
+	}`, nil, "")
+	// Fields of the data are not in the order of the query, so they are compared as JSON values.
+	dataJSON, _ := json.Marshal(result.Data)
+	var data, expectedData interface{}
+	json.Unmarshal(dataJSON, &data)
+	expected := `{"channels":[{"id":"mychannel","peers":[{"name":"peer0.org1","status":{"valid":true}}],"ledger":{"height":5}}],` +
+		`"orgs":[{"name":"org1","peers":[{"name":"peer0.org1"}]},{"name":"org2","peers":[{"name":"peer0.org2"}]}],` +
+		`"peer":{"name":"peer0.org2","channels":[]},"blocks":{"blocks":null}}`
+	json.Unmarshal([]byte(expected), &expectedData)
+	if !reflect.DeepEqual(data, expectedData) {
+		t.Errorf("expected %s, but got %s", expected, dataJSON)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "len") {
+		t.Errorf("expected the error of len, but got %v", result.Errors)
+	}
+}
+
+func TestGraphQLSchema(t *testing.T) {
+	res := httptest.NewRecorder()
+	GetHandlerMap()["/graphql/schema"](res, httptest.NewRequest("GET", "/graphql/schema", nil))
+	for _, expected := range []string{"scalar Long", "type Query {", "channel(id: ID!): Channel",
+		"blocks(begin: Int, len: Int = 10, target: String): [Block]", "rwsets: [NSReadWriteSet]"} {
+		if !strings.Contains(res.Body.String(), expected) {
+			t.Errorf("expected %s in the schema:\n%s", expected, res.Body.String())
+		}
+	}
+}
+
+func TestGraphQLIntrospection(t *testing.T) {
+	result := executeGraphQL(context.Background(), `{ __type(name: "Block") { fields { name } } }`, nil, "")
+	if len(result.Errors) > 0 {
+		t.Fatal(result.Errors)
+	}
+	fieldsJSON, _ := json.Marshal(result.Data)
+	for _, expected := range []string{`"number"`, `"transactions"`, `"time"`} {
+		if !strings.Contains(string(fieldsJSON), expected) {
+			t.Errorf("expected field %s of Block, but got %s", expected, fieldsJSON)
+		}
+	}
+}
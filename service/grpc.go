@@ -0,0 +1,547 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IBM/fablet/api"
+	"github.com/IBM/fablet/audit"
+	"github.com/IBM/fablet/rpc"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCErrCodeKey the trailer key of the error code of a failed RPC.
+const GRPCErrCodeKey = "fablet-err-code"
+
+// errCodeGRPCCodes the gRPC status code of each error code.
+var errCodeGRPCCodes = map[ErrCode]codes.Code{
+	ERR_CODE_INTERNAL:             codes.Internal,
+	ERR_CODE_INVALID_REQUEST:      codes.InvalidArgument,
+	ERR_CODE_IDENTITY_INVALID:     codes.Unauthenticated,
+	ERR_CODE_CONNECTION_NOT_FOUND: codes.NotFound,
+	ERR_CODE_POLICY_FAILURE:       codes.PermissionDenied,
+	ERR_CODE_NOT_FOUND:            codes.NotFound,
+	ERR_CODE_METHOD_NOT_ALLOWED:   codes.Unimplemented,
+	ERR_CODE_ENDORSEMENT_MISMATCH: codes.Aborted,
+	ERR_CODE_MVCC_CONFLICT:        codes.Aborted,
+	ERR_CODE_CHAINCODE_ERROR:      codes.FailedPrecondition,
+	ERR_CODE_ENDPOINT_UNREACHABLE: codes.Unavailable,
+	ERR_CODE_TIMEOUT:              codes.DeadlineExceeded,
+}
+
+// grpcStatus to convert the error to the gRPC status, with the error code which is also set in the trailer.
+func grpcStatus(err error) (metadata.MD, error) {
+	if _, ok := status.FromError(err); ok {
+		return nil, err
+	}
+	errCode := ClassifyError(err)
+	logger.Errorf("RPC error %s: %s", errCode, err.Error())
+	return metadata.Pairs(GRPCErrCodeKey, string(errCode)), status.Error(errCodeGRPCCodes[errCode], err.Error())
+}
+
+func unaryErrorInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	res, err := handler(ctx, req)
+	if err != nil {
+		trailer, statusErr := grpcStatus(err)
+		if trailer != nil {
+			grpc.SetTrailer(ctx, trailer)
+		}
+		return nil, statusErr
+	}
+	return res, nil
+}
+
+func streamErrorInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, ss); err != nil {
+		trailer, statusErr := grpcStatus(err)
+		if trailer != nil {
+			ss.SetTrailer(trailer)
+		}
+		return statusErr
+	}
+	return nil
+}
+
+// NewGRPCServer to create the gRPC server with the Fablet service registered.
+func NewGRPCServer(options ...grpc.ServerOption) *grpc.Server {
+	options = append(options, grpc.UnaryInterceptor(unaryErrorInterceptor), grpc.StreamInterceptor(streamErrorInterceptor))
+	server := grpc.NewServer(options...)
+	rpc.RegisterFabletServer(server, &GRPCService{})
+	return server
+}
+
+// GRPCService the gRPC service, it works as the HTTP handlers do.
+type GRPCService struct{}
+
+func getConnOfRPC(reqConn *rpc.Connection, options ...RequestOptionFunc) (*api.NetworkConnection, error) {
+	if reqConn == nil {
+		return nil, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.New("the connection of the request is empty"))
+	}
+	var pkcs11 *api.PKCS11Config
+	if cfg := reqConn.GetPkcs11(); cfg != nil {
+		pkcs11 = &api.PKCS11Config{Library: cfg.GetLibrary(), Slot: uint(cfg.GetSlot()), PIN: cfg.GetPin(), KeyLabel: cfg.GetKeyLabel()}
+	}
+	return getConnOfReq(&RequestConnection{
+		Label:         reqConn.GetLabel(),
+		MSPID:         reqConn.GetMspId(),
+		CertContent:   reqConn.GetCertContent(),
+		PrvKeyContent: reqConn.GetPrvKeyContent(),
+		Passphrase:    reqConn.GetPassphrase(),
+		PKCS11:        pkcs11,
+		ConnProfile:   reqConn.GetConnProfile(),
+		Workspace:     reqConn.GetWorkspace(),
+		Identity:      reqConn.GetIdentity(),
+	}, true, options...)
+}
+
+// appendRPCAudit to record an operation, the user is in the metadata as the header of HTTP.
+func appendRPCAudit(ctx context.Context, conn *api.NetworkConnection, record *audit.Record, begin time.Time, err error) {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		record.RemoteAddr = p.Addr.String()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if users := md.Get(strings.ToLower(AuditUserHeader)); len(users) > 0 {
+			record.User = users[0]
+		}
+	}
+	var participant *api.Participant
+	if conn != nil {
+		participant = conn.Participant
+	}
+	appendAudit(participant, record, begin, err)
+}
+
+// DiscoverNetwork to discover the network.
+func (s *GRPCService) DiscoverNetwork(ctx context.Context, req *rpc.DiscoverRequest) (*rpc.NetworkOverview, error) {
+	logger.Info("Service GRPC DiscoverNetwork")
+
+	conn, err := getConnOfRPC(req.GetConnection(), WithRefresh(req.GetRefresh()))
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing from request.")
+	}
+	overview, err := api.DiscoverNetworkOverview(conn)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurs when get network overview.")
+	}
+
+	res := &rpc.NetworkOverview{Conn: conn.Identifier}
+	statuses := transPeerStatuses(overview.EndpointStatuses)
+	for _, p := range overview.Peers {
+		peerStatus := statuses[p.Name]
+		res.Peers = append(res.Peers, &rpc.Peer{
+			Name:         p.Name,
+			OrgName:      p.OrgName,
+			MspId:        p.MSPID,
+			Url:          p.URL,
+			Channels:     p.Channels.StringList(),
+			IsConfigured: p.IsConfigured,
+			UpdateTime:   p.UpdateTime,
+			Status:       &rpc.PeerStatus{Ping: peerStatus.Ping, Grpc: peerStatus.GRPC, Valid: peerStatus.Valid},
+		})
+	}
+	for _, channel := range overview.Channels {
+		channelID := channel.ChannelID
+		channelRes := &rpc.ChannelOverview{
+			ChannelId:   channelID,
+			Ledger:      transLedger(overview.ChannelLedgers[channelID]),
+			AnchorPeers: overview.ChannelAnchorPeers[channelID],
+		}
+		for _, chaincode := range overview.ChannelChainCodes[channelID] {
+			channelRes.Chaincodes = append(channelRes.Chaincodes, transChaincode(chaincode))
+		}
+		for _, orderer := range overview.ChannelOrderers[channelID] {
+			channelRes.Orderers = append(channelRes.Orderers, &rpc.Orderer{
+				Name: orderer.Name, Url: orderer.URL, Channels: orderer.Channels.StringList(),
+			})
+		}
+		res.Channels = append(res.Channels, channelRes)
+	}
+	return res, nil
+}
+
+// QueryLedger to query the ledger of a channel.
+func (s *GRPCService) QueryLedger(ctx context.Context, req *rpc.LedgerRequest) (*rpc.Ledger, error) {
+	logger.Info("Service GRPC QueryLedger")
+
+	conn, err := getConnOfRPC(req.GetConnection())
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing from request.")
+	}
+	ledger, err := api.QueryLedger(conn, req.GetChannelId(), req.GetTargets())
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Error occurred when query the ledger of channel %s.", req.GetChannelId())
+	}
+	return transLedger(ledger), nil
+}
+
+// QueryBlocks to query blocks from begin.
+func (s *GRPCService) QueryBlocks(ctx context.Context, req *rpc.BlocksRequest) (*rpc.BlocksResponse, error) {
+	logger.Info("Service GRPC QueryBlocks")
+
+	conn, err := getConnOfRPC(req.GetConnection())
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing from request.")
+	}
+	blocks, err := api.QueryBlock(conn, req.GetChannelId(), req.GetTargets(), req.GetBegin(), req.GetLen())
+	if err != nil && len(blocks) < 1 {
+		return nil, errors.WithMessagef(err, "Error occurred when query blocks of channel %s.", req.GetChannelId())
+	}
+	res := &rpc.BlocksResponse{}
+	for _, block := range blocks {
+		res.Blocks = append(res.Blocks, transBlock(block))
+	}
+	return res, nil
+}
+
+// QueryBlock to query a block by number, hash or transaction ID.
+func (s *GRPCService) QueryBlock(ctx context.Context, req *rpc.BlockRequest) (*rpc.Block, error) {
+	logger.Info("Service GRPC QueryBlock")
+
+	conn, err := getConnOfRPC(req.GetConnection())
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing from request.")
+	}
+	block, err := queryBlockAny(conn, req.GetChannelId(), req.GetTargets(), req.GetQueryKey())
+	if err != nil {
+		return nil, err
+	}
+	return transBlock(block), nil
+}
+
+// ExecuteChaincode to execute or query a chaincode.
+func (s *GRPCService) ExecuteChaincode(ctx context.Context, req *rpc.ExecuteRequest) (*rpc.ExecuteResponse, error) {
+	logger.Info("Service GRPC ExecuteChaincode")
+
+	conn, err := getConnOfRPC(req.GetConnection())
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing from request.")
+	}
+
+	channelID, chaincodeID := req.GetChannelId(), req.GetChaincodeId()
+	ccOperType := api.ChaincodeOperTypeExecute
+	if req.GetActionType() == rpc.ActionType_QUERY {
+		ccOperType = api.ChaincodeOperTypeQuery
+	}
+	if err := api.ValidateChaincodeArguments(conn, channelID, chaincodeID, req.GetFunctionName(), req.GetArguments()); err != nil {
+		return nil, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.WithMessage(err, "The arguments do not match the contract metadata."))
+	}
+	targets := req.GetTargets()
+	if len(targets) < 1 {
+		if strategy := req.GetSelectionStrategy(); strategy != "" && !api.IsSelectionStrategy(strategy) {
+			return nil, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.Errorf("%s is not a valid selection strategy", strategy))
+		}
+		targets = api.PlanEndorsers(conn, channelID, chaincodeID, req.GetSelectionStrategy()).Targets()
+	}
+
+	begin := time.Now()
+	cceRes, err := api.ExecuteChaincode(conn, channelID, chaincodeID, ccOperType, targets, req.GetFunctionName(), req.GetArguments())
+	auditRecord := &audit.Record{Operation: AuditOperationExecute, ChannelID: channelID, ChaincodeID: chaincodeID,
+		Targets: targets, ArgumentDigests: audit.Digests(append([]string{req.GetFunctionName()}, req.GetArguments()...))}
+	if ccOperType == api.ChaincodeOperTypeQuery {
+		auditRecord.Operation = AuditOperationQuery
+	}
+	if cceRes != nil {
+		auditRecord.TxID = string(cceRes.TransactionID)
+	}
+	appendRPCAudit(ctx, conn, auditRecord, begin, err)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Error occurred when execute the chaincode %s in channel %s, with arguments %v, with targets %v.",
+			chaincodeID, channelID, req.GetArguments(), targets)
+	}
+
+	res := &rpc.ExecuteResponse{
+		TransactionId:    string(cceRes.TransactionID),
+		TxValidationCode: cceRes.TxValidationCode.String(),
+		ChaincodeStatus:  cceRes.ChaincodeStatus,
+		Payload:          string(cceRes.Payload),
+	}
+	for _, pr := range cceRes.Responses {
+		res.PeerResponses = append(res.PeerResponses, &rpc.PeerResponse{
+			Endorser: pr.Endorser,
+			Version:  pr.GetVersion(),
+			Payload:  string(pr.GetResponse().GetPayload()),
+			Status:   pr.GetResponse().GetStatus(),
+		})
+	}
+	return res, nil
+}
+
+// InstallChaincode to install a chaincode package.
+func (s *GRPCService) InstallChaincode(ctx context.Context, req *rpc.InstallRequest) (*rpc.InstallResponse, error) {
+	logger.Info("Service GRPC InstallChaincode")
+
+	conn, err := getConnOfRPC(req.GetConnection())
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing from request.")
+	}
+
+	chaincode := fromRPCChaincode(req.GetChaincode())
+	chaincode.Package = req.GetPackage()
+	tmpFolder, err := unpackChaincode(chaincode, req.GetPackageFormat())
+	defer removeTmpFolder(tmpFolder)
+	if err != nil {
+		return nil, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.WithMessage(err, "Error occurred when uncompress the chaincode package."))
+	}
+
+	begin := time.Now()
+	installRes, err := api.InstallChaincode(conn, chaincode, req.GetTargets())
+	appendRPCAudit(ctx, conn, &audit.Record{Operation: AuditOperationInstall, ChaincodeID: chaincode.String(), Targets: req.GetTargets()}, begin, err)
+	if err != nil && installRes == nil {
+		return nil, errors.WithMessage(err, "Error occurred when installing the chaincode.")
+	}
+
+	// The result of each target, even if some failed.
+	res := &rpc.InstallResponse{}
+	for target, result := range installRes {
+		res.Results = append(res.Results, &rpc.InstallResult{Target: target, Code: uint32(result.Code), Message: result.Message})
+	}
+	sort.Slice(res.Results, func(i, j int) bool { return res.Results[i].Target < res.Results[j].Target })
+	return res, nil
+}
+
+// InstantiateChaincode to instantiate a chaincode.
+func (s *GRPCService) InstantiateChaincode(ctx context.Context, req *rpc.InstantiateRequest) (*rpc.InstantiateResponse, error) {
+	logger.Info("Service GRPC InstantiateChaincode")
+	return s.instantiate(ctx, req, false)
+}
+
+// UpgradeChaincode to upgrade a chaincode.
+func (s *GRPCService) UpgradeChaincode(ctx context.Context, req *rpc.InstantiateRequest) (*rpc.InstantiateResponse, error) {
+	logger.Info("Service GRPC UpgradeChaincode")
+	return s.instantiate(ctx, req, true)
+}
+
+func (s *GRPCService) instantiate(ctx context.Context, req *rpc.InstantiateRequest, isUpgrade bool) (*rpc.InstantiateResponse, error) {
+	conn, err := getConnOfRPC(req.GetConnection())
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing from request.")
+	}
+
+	chaincode := fromRPCChaincode(req.GetChaincode())
+	if _, err := api.ParsePolicy(chaincode.Policy); err != nil {
+		return nil, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.WithMessage(err, "The endorsement policy is invalid."))
+	}
+
+	operation, instantiate, errMsg := AuditOperationInstantiate, api.InstantiateChaincode, "Error occurred when instantiating the chaincode."
+	if isUpgrade {
+		operation, instantiate, errMsg = AuditOperationUpgrade, api.UpgradeChaincode, "Error occurred when upgrading the chaincode."
+	}
+	begin := time.Now()
+	transID, err := instantiate(conn, chaincode, req.GetTarget(), req.GetOrderer())
+	appendRPCAudit(ctx, conn, &audit.Record{Operation: operation, ChannelID: chaincode.ChannelID,
+		ChaincodeID: chaincode.String(), Targets: []string{req.GetTarget()}, Orderer: req.GetOrderer(),
+		ArgumentDigests: audit.Digests(chaincode.Constructor), TxID: string(transID)}, begin, err)
+	if err != nil {
+		return nil, errors.WithMessage(err, errMsg)
+	}
+	return &rpc.InstantiateResponse{TransactionId: string(transID)}, nil
+}
+
+// CreateChannel to create a channel via the orderer.
+func (s *GRPCService) CreateChannel(ctx context.Context, req *rpc.CreateChannelRequest) (*rpc.CreateChannelResponse, error) {
+	logger.Info("Service GRPC CreateChannel")
+
+	conn, err := getConnOfRPC(req.GetConnection())
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing from request.")
+	}
+	begin := time.Now()
+	channelID, err := api.CreateChannel(conn, req.GetTxContent(), req.GetOrderer())
+	appendRPCAudit(ctx, conn, &audit.Record{Operation: AuditOperationCreateChannel, ChannelID: channelID, Orderer: req.GetOrderer(),
+		ArgumentDigests: []string{audit.Digest(string(req.GetTxContent()))}}, begin, err)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Error occurs when create channel %s via orderer %s.", channelID, req.GetOrderer())
+	}
+	return &rpc.CreateChannelResponse{ChannelId: channelID}, nil
+}
+
+// JoinChannel to join peers to a channel.
+func (s *GRPCService) JoinChannel(ctx context.Context, req *rpc.JoinChannelRequest) (*rpc.JoinChannelResponse, error) {
+	logger.Info("Service GRPC JoinChannel")
+
+	conn, err := getConnOfRPC(req.GetConnection())
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error occurred when parsing from request.")
+	}
+	begin := time.Now()
+	err = api.JoinChannel(conn, req.GetChannelId(), req.GetTargets(), req.GetOrderer())
+	appendRPCAudit(ctx, conn, &audit.Record{Operation: AuditOperationJoinChannel, ChannelID: req.GetChannelId(),
+		Targets: req.GetTargets(), Orderer: req.GetOrderer()}, begin, err)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Error occurs when peer %v join channel %s.", req.GetTargets(), req.GetChannelId())
+	}
+	return &rpc.JoinChannelResponse{ChannelId: req.GetChannelId()}, nil
+}
+
+// BlockEvents to send block events until the client cancels.
+func (s *GRPCService) BlockEvents(req *rpc.BlockEventsRequest, stream rpc.Fablet_BlockEventsServer) error {
+	logger.Info("Service GRPC BlockEvents")
+
+	conn, err := getConnOfRPC(req.GetConnection())
+	if err != nil {
+		return errors.WithMessage(err, "Error occurred when parsing from request.")
+	}
+
+	eventChan := make(chan *fab.FilteredBlockEvent, 1)
+	closeChan := make(chan int, 1)
+	eventCloseChan := make(chan int, 1)
+	go api.MonitorBlockEvent(conn, req.GetChannelId(), eventChan, closeChan, eventCloseChan)
+	defer func() {
+		logger.Info("Service GRPC BlockEvents end")
+		closeChan <- 0
+	}()
+
+	for {
+		select {
+		case event := <-eventChan:
+			if event == nil {
+				continue
+			}
+			if err := stream.Send(&rpc.BlockEvent{
+				Number:     event.FilteredBlock.GetNumber(),
+				TxNumber:   int32(len(event.FilteredBlock.GetFilteredTransactions())),
+				UpdateTime: time.Now().UnixNano() / 1000000,
+				SourceUrl:  event.SourceURL,
+			}); err != nil {
+				return errors.WithMessage(err, "Error of send event data.")
+			}
+		case <-eventCloseChan:
+			return errors.Errorf("the block event of channel %s is closed", req.GetChannelId())
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// ChaincodeEvents to send chaincode events until the client cancels.
+func (s *GRPCService) ChaincodeEvents(req *rpc.ChaincodeEventsRequest, stream rpc.Fablet_ChaincodeEventsServer) error {
+	logger.Info("Service GRPC ChaincodeEvents")
+
+	conn, err := getConnOfRPC(req.GetConnection())
+	if err != nil {
+		return errors.WithMessage(err, "Error occurred when parsing from request.")
+	}
+
+	eventChan := make(chan *fab.CCEvent, 1)
+	closeChan := make(chan int, 1)
+	eventCloseChan := make(chan error, 1)
+	go api.MonitorChaincodeEvent(conn, req.GetChannelId(), req.GetChaincodeId(), req.GetEventFilter(), eventChan, closeChan, eventCloseChan)
+	defer func() {
+		logger.Info("Service GRPC ChaincodeEvents end")
+		closeChan <- 0
+	}()
+
+	for {
+		select {
+		case event := <-eventChan:
+			if event == nil {
+				continue
+			}
+			if err := stream.Send(&rpc.ChaincodeEvent{
+				TxId:        event.TxID,
+				ChaincodeId: event.ChaincodeID,
+				EventName:   event.EventName,
+				Payload:     event.Payload,
+				BlockNumber: event.BlockNumber,
+				SourceUrl:   event.SourceURL,
+			}); err != nil {
+				return errors.WithMessage(err, "Error of send event data.")
+			}
+		case err := <-eventCloseChan:
+			if err != nil {
+				return errors.WithMessage(err, "Error occurred when monitoring the chaincode event.")
+			}
+			return errors.Errorf("the chaincode event of %s is closed", req.GetChaincodeId())
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func transLedger(ledger *api.Ledger) *rpc.Ledger {
+	if ledger == nil {
+		return nil
+	}
+	return &rpc.Ledger{Height: ledger.Height, CurrentBlockHash: ledger.CurrentBlockHash, Endorser: ledger.Endorser, Status: ledger.Status}
+}
+
+func transChaincode(chaincode *api.Chaincode) *rpc.Chaincode {
+	return &rpc.Chaincode{
+		Name:        chaincode.Name,
+		Version:     chaincode.Version,
+		Path:        chaincode.Path,
+		Type:        chaincode.Type,
+		ChannelId:   chaincode.ChannelID,
+		Policy:      chaincode.Policy,
+		Constructor: chaincode.Constructor,
+		Installed:   chaincode.Installed,
+	}
+}
+
+func fromRPCChaincode(chaincode *rpc.Chaincode) *api.Chaincode {
+	return &api.Chaincode{
+		Name:        chaincode.GetName(),
+		Version:     chaincode.GetVersion(),
+		Path:        chaincode.GetPath(),
+		Type:        chaincode.GetType(),
+		ChannelID:   chaincode.GetChannelId(),
+		Policy:      chaincode.GetPolicy(),
+		Constructor: chaincode.GetConstructor(),
+	}
+}
+
+func transBlock(block *api.Block) *rpc.Block {
+	res := &rpc.Block{
+		Number:       block.Number,
+		DataHash:     block.DataHash,
+		PreviousHash: block.PreviousHash,
+		BlockHash:    block.BlockHash,
+		Time:         block.Time,
+	}
+	for _, tx := range block.Transactions {
+		txRes := &rpc.Transaction{TxId: tx.TxID, ValidationCode: tx.ValidationCode}
+		for _, action := range tx.Actions {
+			txRes.Actions = append(txRes.Actions, transAction(action))
+		}
+		res.Transactions = append(res.Transactions, txRes)
+	}
+	return res
+}
+
+func transAction(action *api.Action) *rpc.Action {
+	res := &rpc.Action{
+		ChaincodeName:    action.ChaincodeName,
+		ChaincodeVersion: action.ChaincodeVersion,
+		Arguments:        action.Arguments,
+	}
+	for _, endorser := range action.Endorsers {
+		res.Endorsers = append(res.Endorsers, &rpc.Endorser{
+			CommonName: endorser.CommonName, Subject: endorser.Subject, MspId: endorser.MSPID, Issuer: endorser.Issuer,
+		})
+	}
+	if action.ProposalResponse == nil || action.ProposalResponse.TXReadWriteSet == nil {
+		return res
+	}
+	for _, nsrwset := range action.ProposalResponse.TXReadWriteSet.NSReadWriteSets {
+		rwset := &rpc.NSReadWriteSet{Namespace: nsrwset.NameSpace}
+		for _, read := range nsrwset.KVReadSet {
+			rwset.Reads = append(rwset.Reads, &rpc.KVRead{Key: read.Key, VerBlockNum: read.VerBlockNum, VerTxNum: read.VerTxNum})
+		}
+		for _, write := range nsrwset.KVWriteSet {
+			// The value is parsed as JSON if possible, so it is sent in JSON.
+			value, _ := json.Marshal(write.Value)
+			rwset.Writes = append(rwset.Writes, &rpc.KVWrite{Key: write.Key, IsDelete: write.IsDelete, Value: string(value)})
+		}
+		res.RwSets = append(res.RwSets, rwset)
+	}
+	return res
+}
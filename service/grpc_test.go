@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/IBM/fablet/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestGRPCClient(t *testing.T) (rpc.FabletClient, func()) {
+	lis := bufconn.Listen(1024 * 1024)
+	server := NewGRPCServer()
+	go server.Serve(lis)
+
+	cc, err := grpc.Dial("bufnet", grpc.WithInsecure(),
+		grpc.WithDialer(func(string, time.Duration) (net.Conn, error) { return lis.Dial() }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rpc.NewFabletClient(cc), func() {
+		cc.Close()
+		server.Stop()
+	}
+}
+
+func TestGRPCErrors(t *testing.T) {
+	client, closeServer := newTestGRPCClient(t)
+	defer closeServer()
+
+	// The connection is required.
+	var trailer metadata.MD
+	_, err := client.QueryLedger(context.Background(), &rpc.LedgerRequest{ChannelId: "mychannel"}, grpc.Trailer(&trailer))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected invalid argument, but got %v", err)
+	}
+	if errCodes := trailer.Get(GRPCErrCodeKey); len(errCodes) != 1 || errCodes[0] != string(ERR_CODE_INVALID_REQUEST) {
+		t.Errorf("expected the error code in trailer, but got %v", trailer)
+	}
+
+	stream, err := client.BlockEvents(context.Background(), &rpc.BlockEventsRequest{ChannelId: "mychannel"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected invalid argument of the stream, but got %v", err)
+	}
+	if errCodes := stream.Trailer().Get(GRPCErrCodeKey); len(errCodes) != 1 || errCodes[0] != string(ERR_CODE_INVALID_REQUEST) {
+		t.Errorf("expected the error code in trailer of the stream, but got %v", stream.Trailer())
+	}
+}
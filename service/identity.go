@@ -0,0 +1,50 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/IBM/fablet/api"
+	"github.com/pkg/errors"
+)
+
+// IdentityInspectReq to inspect the identity of the connection or of the workspace.
+type IdentityInspectReq struct {
+	BaseRequest
+	// Offline not to connect to the network, the identity is not checked against MSPs of channels.
+	Offline bool `json:"offline"`
+}
+
+// HandleIdentityInspect to decode the certificate of the identity, check it against MSPs of channels, and predict operations it can do.
+func HandleIdentityInspect(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleIdentityInspect")
+
+	reqBody := &IdentityInspectReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	reqConn, err := resolveWorkspace(reqBody.GetReqConn())
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	inspection, err := api.InspectIdentity(&api.Participant{Label: reqConn.Label, MSPID: reqConn.MSPID,
+		Cert: []byte(reqConn.CertContent), PrivateKey: []byte(reqConn.PrvKeyContent), Passphrase: reqConn.Passphrase, PKCS11: reqConn.PKCS11})
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, NewServiceError(ERR_CODE_INVALID_REQUEST, err))
+		return
+	}
+
+	// The inspection is responded without channels if the network is unreachable.
+	if !reqBody.Offline {
+		if conn, err := getConnOfReq(reqConn, true); err != nil {
+			inspection.Errors = append(inspection.Errors, "connection: "+err.Error())
+		} else {
+			api.CheckIdentityChannels(conn, inspection)
+		}
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"inspection": inspection,
+	})
+}
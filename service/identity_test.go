@@ -0,0 +1,50 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestIdentityInspect(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Admin@org1.example.com", OrganizationalUnit: []string{"admin"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, _ := x509.MarshalPKCS8PrivateKey(key)
+	cert, _ := json.Marshal(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	prvKey, _ := json.Marshal(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})))
+
+	code, result := postWorkspace(t, "/identity/inspect", fmt.Sprintf(`{"connection": {"MSPID": "Org1MSP", "certContent": %s, "prvKeyContent": %s}, "offline": true}`, cert, prvKey))
+	if code != 200 {
+		t.Fatalf("expected the inspection, but got %d %v", code, result)
+	}
+	inspection := result["inspection"].(map[string]interface{})
+	if inspection["role"] != "admin" || inspection["keyMatch"] != true || len(inspection["channels"].([]interface{})) != 0 {
+		t.Errorf("expected the admin identity of which the key matches, but got %v", inspection)
+	}
+	for _, capability := range inspection["capabilities"].([]interface{}) {
+		if capability.(map[string]interface{})["allowed"] != true {
+			t.Errorf("expected the admin is allowed, but got %v", capability)
+		}
+	}
+
+	if code, result = postWorkspace(t, "/identity/inspect", `{"connection": {"MSPID": "Org1MSP", "certContent": "cert"}, "offline": true}`); code != 400 {
+		t.Errorf("expected the invalid request, but got %d %v", code, result)
+	}
+}
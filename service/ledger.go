@@ -12,14 +12,14 @@ import (
 // LedgerQueryReq to query a ledger
 type LedgerQueryReq struct {
 	BaseRequest
-	ChannelID string   `json:"channelID"`
+	ChannelID string   `json:"channelID" openapi:"required"`
 	Targets   []string `json:"targets"`
 }
 
 // BlockQueryReq to query blocks
 type BlockQueryReq struct {
 	BaseRequest
-	ChannelID string   `json:"channelID"`
+	ChannelID string   `json:"channelID" openapi:"required"`
 	Targets   []string `json:"targets"`
 	Begin     uint64   `json:"begin"`
 	Len       uint64   `json:"len"`
@@ -28,9 +28,18 @@ type BlockQueryReq struct {
 // BlockQueryAnyReq to query block with any: tx id, block hash, block number
 type BlockQueryAnyReq struct {
 	BaseRequest
-	ChannelID string   `json:"channelID"`
+	ChannelID string   `json:"channelID" openapi:"required"`
 	Targets   []string `json:"targets"`
-	QueryKey  string   `json:"queryKey"`
+	QueryKey  string   `json:"queryKey" openapi:"required"`
+}
+
+// MVCCAnalyzeReq to analyze MVCC conflicts of a block range.
+type MVCCAnalyzeReq struct {
+	BaseRequest
+	ChannelID string   `json:"channelID" openapi:"required"`
+	Targets   []string `json:"targets"`
+	Begin     uint64   `json:"begin"`
+	End       uint64   `json:"end"` // Inclusive
 }
 
 // HandleLedgerQuery to query a ledger of a channel
@@ -85,27 +94,58 @@ func HandleBlockQueryAny(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	var block *api.Block
+	block, err := queryBlockAny(conn, reqBody.ChannelID, reqBody.Targets, reqBody.QueryKey)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_NOT_FOUND, err)
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"block": block,
+	})
+}
 
-	blkNumber, err := strconv.ParseUint(reqBody.QueryKey, 10, 64)
+// queryBlockAny to query a block by number, hash or transaction ID.
+func queryBlockAny(conn *api.NetworkConnection, channelID string, targets []string, queryKey string) (*api.Block, error) {
+	var block *api.Block
+	blkNumber, err := strconv.ParseUint(queryKey, 10, 64)
 	if err == nil {
-		blocks, err := api.QueryBlock(conn, reqBody.ChannelID, reqBody.Targets, blkNumber, 1)
+		var blocks []*api.Block
+		blocks, err = api.QueryBlock(conn, channelID, targets, blkNumber, 1)
 		if err == nil && len(blocks) > 0 {
 			block = blocks[0]
 		}
 	} else {
-		block, err = api.QueryBlockByHash(conn, reqBody.ChannelID, reqBody.Targets, reqBody.QueryKey)
+		block, err = api.QueryBlockByHash(conn, channelID, targets, queryKey)
 		if err != nil {
-			block, err = api.QueryBlockByTxID(conn, reqBody.ChannelID, reqBody.Targets, reqBody.QueryKey)
+			block, err = api.QueryBlockByTxID(conn, channelID, targets, queryKey)
 		}
 	}
 
+	if err != nil || block == nil {
+		return nil, NewServiceError(ERR_CODE_NOT_FOUND, errors.Errorf("Error occurred when query the block, cannot find the block by number, hash or transaction ID."))
+	}
+	return block, nil
+}
+
+// HandleMVCCAnalyze to find transactions invalidated for MVCC conflicts, and rank the hot keys.
+func HandleMVCCAnalyze(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleMVCCAnalyze")
+
+	reqBody := &MVCCAnalyzeReq{}
+	conn, err := GetRequest(req, reqBody, true)
 	if err != nil {
-		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.Errorf("Error occurred when query the block, cannot find the block by number, hash or transaction ID."))
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+
+	analysis, err := api.AnalyzeMVCC(conn, reqBody.ChannelID, reqBody.Targets, reqBody.Begin, reqBody.End)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when analyzing MVCC conflicts."))
 		return
 	}
 
 	ResultOutput(res, req, map[string]interface{}{
-		"block": block,
+		"analysis": analysis,
 	})
 }
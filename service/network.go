@@ -18,6 +18,12 @@ type NetworkRefreshReq struct {
 	BaseRequest
 }
 
+// NetworkProfileExportReq to export the connection profile of the discovered network.
+type NetworkProfileExportReq struct {
+	BaseRequest
+	Format string `json:"format" openapi:"enum=yaml|json"`
+}
+
 // HandleNetworkDiscover to discover all network
 func HandleNetworkDiscover(res http.ResponseWriter, req *http.Request) {
 	logger.Info("Service HandleNetworkDiscover")
@@ -43,6 +49,7 @@ func HandleNetworkDiscover(res http.ResponseWriter, req *http.Request) {
 		"channelChaincodes":  networkOverview.ChannelChainCodes,
 		"channelOrderers":    networkOverview.ChannelOrderers,
 		"channelAnchorPeers": networkOverview.ChannelAnchorPeers,
+		"conn":               conn.Identifier,
 	})
 }
 
@@ -97,3 +104,28 @@ func HandleNetworkRefresh(res http.ResponseWriter, req *http.Request) {
 		"conn": conn.Identifier,
 	})
 }
+
+// HandleNetworkProfileExport to export the connection profile completed with the discovered network, the format is of the original profile if empty.
+func HandleNetworkProfileExport(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleNetworkProfileExport")
+
+	reqBody := &NetworkProfileExportReq{}
+	conn, err := GetRequest(req, reqBody, true)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	format := reqBody.Format
+	if format == "" {
+		format = api.DetectProfileType(conn.ConnectionProfile.Config)
+	}
+	exported, err := api.ExportProfile(conn, format)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when exporting the connection profile."))
+		return
+	}
+	ResultOutput(res, req, map[string]interface{}{
+		"connProfile": string(exported),
+		"format":      format,
+	})
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIVersion version of the API in the OpenAPI document.
+const APIVersion = "0.1.0"
+
+// Schema an OpenAPI schema object, only the parts used by Fablet.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Default              string             `json:"default,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+}
+
+// schemaRefPrefix prefix of references to the components.
+const schemaRefPrefix = "#/components/schemas/"
+
+// schemaGenerator to generate schemas of Go types by reflection, named structs are kept as components.
+// Fields are described by the openapi tag, e.g. `openapi:"required,enum=execute|query,default=execute"`.
+type schemaGenerator struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{components: map[string]*Schema{}, names: map[reflect.Type]string{}}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// schemaOf to get the schema of a type, nil if the type cannot be in JSON.
+func (g *schemaGenerator) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	// Types with their own JSON form, e.g. big.Int.
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return &Schema{}
+	}
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		min := float64(0)
+		return &Schema{Type: "integer", Format: "int64", Minimum: &min}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		// Bytes are in base64.
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name, ok := g.names[t]
+		if !ok {
+			name = t.Name()
+			if _, exists := g.components[name]; exists {
+				name = path.Base(t.PkgPath()) + "." + t.Name()
+			}
+			g.names[t] = name
+			// Registered before the fields, for recursive types.
+			g.components[name] = &Schema{}
+			*g.components[name] = *g.structSchema(t)
+		}
+		return &Schema{Ref: schemaRefPrefix + name}
+	}
+	// Channels, functions and others are not in JSON.
+	return nil
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(schema, t)
+	return schema
+}
+
+// addFields to add fields of the struct, fields of embedded structs are added as the same level as encoding/json does.
+func (g *schemaGenerator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, jsonOpt := parseTag(field.Tag.Get("json"))
+		if name == "-" && jsonOpt == "" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(schema, ft)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := g.schemaOf(field.Type)
+		if fieldSchema == nil {
+			continue
+		}
+		for _, opt := range strings.Split(field.Tag.Get("openapi"), ",") {
+			switch {
+			case opt == "required":
+				schema.Required = append(schema.Required, name)
+			case strings.HasPrefix(opt, "enum="):
+				fieldSchema.Enum = strings.Split(strings.TrimPrefix(opt, "enum="), "|")
+			case strings.HasPrefix(opt, "default="):
+				fieldSchema.Default = strings.TrimPrefix(opt, "default=")
+			}
+		}
+		schema.Properties[name] = fieldSchema
+	}
+}
+
+func parseTag(tag string) (string, string) {
+	if idx := strings.Index(tag, ","); idx >= 0 {
+		return tag[:idx], tag[idx+1:]
+	}
+	return tag, ""
+}
+
+// resultSchema to get the schema of the result, with the response code and error message which are in all results.
+func (g *schemaGenerator) resultSchema(fields map[string]interface{}) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{
+		"resCode": {Type: "integer", Format: "int32"},
+		"errCode": {Type: "string", Enum: errCodeEnum()},
+		"errMsg":  {Type: "string"},
+	}, Required: []string{"resCode"}}
+	schema.Properties["fieldErrors"] = g.schemaOf(reflect.TypeOf([]*FieldError{}))
+	for name, value := range fields {
+		if fieldSchema := g.schemaOf(reflect.TypeOf(value)); fieldSchema != nil {
+			schema.Properties[name] = fieldSchema
+		}
+	}
+	return schema
+}
+
+func errCodeEnum() []string {
+	errCodes := []string{}
+	for errCode := range ErrCodeResCodes {
+		errCodes = append(errCodes, string(errCode))
+	}
+	sort.Strings(errCodes)
+	return errCodes
+}
+
+// resolve to get the schema referred.
+func (g *schemaGenerator) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = g.components[strings.TrimPrefix(schema.Ref, schemaRefPrefix)]
+	}
+	return schema
+}
+
+func jsonContent(schema *Schema) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
+
+// operation to get the OpenAPI operation of a route.
+func (g *schemaGenerator) operation(route *Route) map[string]interface{} {
+	operation := map[string]interface{}{
+		"operationId": strings.NewReplacer("/", "_", "{", "", "}", "").Replace(strings.Trim(route.Path, "/")),
+		"summary":     route.Summary,
+	}
+	if route.WSHandler != nil {
+		// OpenAPI doesn't describe websocket, so the messages are in extensions.
+		operation["description"] = "Websocket. The request is the first message sent by the client, it is validated as request bodies of other routes."
+		operation["x-websocket-request"] = g.schemaOf(reflect.TypeOf(route.Request))
+		operation["x-websocket-message"] = g.schemaOf(reflect.TypeOf(route.Message))
+		operation["responses"] = map[string]interface{}{
+			"101": map[string]interface{}{"description": "Switching to websocket."},
+		}
+		return map[string]interface{}{"get": operation}
+	}
+
+	if route.Method == http.MethodGet {
+		parameters := []map[string]interface{}{
+			{"name": ConnectionHeader, "in": "header", "required": true, "schema": &Schema{Type: "string"}},
+		}
+		for _, seg := range strings.Split(route.Path, "/") {
+			if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+				parameters = append(parameters, map[string]interface{}{
+					"name": seg[1 : len(seg)-1], "in": "path", "required": true, "schema": &Schema{Type: "string"},
+				})
+			}
+		}
+		for _, name := range route.Query {
+			parameters = append(parameters, map[string]interface{}{"name": name, "in": "query", "schema": &Schema{Type: "string"}})
+		}
+		operation["parameters"] = parameters
+		operation["responses"] = g.responses(route.Result)
+		return map[string]interface{}{"get": operation}
+	}
+
+	operation["requestBody"] = map[string]interface{}{
+		"required": true,
+		"content":  jsonContent(g.schemaOf(reflect.TypeOf(route.Request))),
+	}
+	if len(route.Produces) > 0 {
+		content := map[string]interface{}{}
+		for _, contentType := range route.Produces {
+			content[contentType] = map[string]interface{}{"schema": &Schema{Type: "string"}}
+		}
+		operation["responses"] = map[string]interface{}{
+			"200": map[string]interface{}{"description": "The file.", "content": content},
+			"default": map[string]interface{}{
+				"description": "The error.",
+				"content":     jsonContent(g.resultSchema(nil)),
+			},
+		}
+		return map[string]interface{}{"post": operation}
+	}
+	operation["responses"] = g.responses(route.Result)
+	return map[string]interface{}{"post": operation}
+}
+
+// responses to get the result, and the error which is in the HTTP status of the response code.
+func (g *schemaGenerator) responses(result map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"200": map[string]interface{}{
+			"description": "The result.",
+			"content":     jsonContent(g.resultSchema(result)),
+		},
+		"default": map[string]interface{}{
+			"description": "The error, errCode is one of the error codes of the response code.",
+			"content":     jsonContent(g.resultSchema(nil)),
+		},
+	}
+}
+
+// apiDoc the OpenAPI document and the schemas for validation, generated once.
+type apiDoc struct {
+	document   map[string]interface{}
+	generator  *schemaGenerator
+	reqSchemas map[string]*Schema
+}
+
+var apiDocOnce sync.Once
+var apiDocument *apiDoc
+
+func getAPIDoc() *apiDoc {
+	apiDocOnce.Do(func() {
+		g := newSchemaGenerator()
+		paths := map[string]interface{}{}
+		reqSchemas := map[string]*Schema{}
+		for _, route := range Routes {
+			paths[route.Path] = g.operation(route)
+			if route.Request != nil {
+				reqSchemas[route.Path] = g.schemaOf(reflect.TypeOf(route.Request))
+			}
+		}
+		apiDocument = &apiDoc{
+			document: map[string]interface{}{
+				"openapi": "3.0.3",
+				"info": map[string]interface{}{
+					"title":   "Fablet API",
+					"version": APIVersion,
+				},
+				"paths":      paths,
+				"components": map[string]interface{}{"schemas": g.components},
+			},
+			generator:  g,
+			reqSchemas: reqSchemas,
+		}
+	})
+	return apiDocument
+}
+
+// HandleOpenAPI to return the OpenAPI document of all routes.
+func HandleOpenAPI(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleOpenAPI")
+	JsonOutput(res, req, getAPIDoc().document)
+}
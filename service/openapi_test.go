@@ -0,0 +1,96 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+	res := httptest.NewRecorder()
+	HandleOpenAPI(res, httptest.NewRequest("GET", "/openapi.json", nil))
+
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(res.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	paths := doc["paths"].(map[string]interface{})
+	for _, route := range Routes {
+		if _, ok := paths[route.Path]; !ok {
+			t.Errorf("%s is not in the document", route.Path)
+		}
+	}
+
+	// All references are resolvable.
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for _, ref := range strings.Split(res.Body.String(), `"$ref":"#/components/schemas/`)[1:] {
+		name := ref[:strings.Index(ref, `"`)]
+		if _, ok := schemas[name]; !ok {
+			t.Errorf("schema %s is not found", name)
+		}
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	cases := []struct {
+		path   string
+		body   string
+		fields []string
+	}{
+		{"/ledger/block", `{"channelID": "mychannel", "begin": 1, "len": 10}`, nil},
+		{"/ledger/block", `{"begin": -1, "len": "10", "targets": ["peer0", 1]}`,
+			[]string{"channelID", "begin", "len", "targets[1]"}},
+		// Required is the presence of the field, not the value.
+		{"/ledger/block", `{"channelID": "", "begin": 1, "len": 10}`, nil},
+		{"/chaincode/execute", `{"chaincode": {"name": "mycc"}, "actionType": "invoke", "arguments": "a"}`,
+			[]string{"actionType", "arguments"}},
+		{"/chaincode/execute", `{"chaincode": {"name": 1}, "actionType": "", "unknown": 1}`, []string{"chaincode.name"}},
+		{"/tx/status", ``, []string{"channelID", "txID"}},
+		{"/policy/parse", `{"policy": "OR('Org1MSP.peer')"}`, nil},
+	}
+	for _, c := range cases {
+		fieldErrs, err := ValidateRequest(c.path, []byte(c.body))
+		if err != nil {
+			t.Fatalf("%s: %s", c.body, err.Error())
+		}
+		fields := []string{}
+		for _, fieldErr := range fieldErrs {
+			fields = append(fields, fieldErr.Field)
+		}
+		if strings.Join(fields, ",") != strings.Join(c.fields, ",") {
+			t.Errorf("%s: expected errors of %v, but got %v", c.body, c.fields, fields)
+		}
+	}
+
+	if _, err := ValidateRequest("/ledger/block", []byte(`{"channelID": `)); err == nil {
+		t.Error("expected error of invalid JSON")
+	}
+}
+
+func TestValidateWSRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(WS("/event/txstatus", func(wsConn *websocket.Conn, request []byte) error {
+		t.Error("the handler should not be called with an invalid request")
+		return nil
+	})))
+	defer server.Close()
+
+	wsConn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wsConn.Close()
+	if err := wsConn.WriteJSON(map[string]interface{}{"channelID": 1}); err != nil {
+		t.Fatal(err)
+	}
+	result := &ErrorResult{}
+	if err := wsConn.ReadJSON(result); err != nil {
+		t.Fatal(err)
+	}
+	if result.ErrCode != ERR_CODE_INVALID_REQUEST || len(result.FieldErrors) != 2 {
+		t.Errorf("expected the invalid channelID and missing txID, but got %+v", result)
+	}
+}
@@ -10,7 +10,7 @@ import (
 // PeerDetailsReq to get details of a peer
 type PeerDetailsReq struct {
 	BaseRequest
-	Target   string   `json:"target"`
+	Target   string   `json:"target" openapi:"required"`
 	Channels []string `json:"channels"`
 }
 
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/IBM/fablet/api"
+	"github.com/pkg/errors"
+)
+
+// PolicyParseReq to parse an endorsement policy.
+type PolicyParseReq struct {
+	Policy string `json:"policy" openapi:"required"`
+}
+
+// PolicyEvaluateReq to evaluate if the organizations or peers are enough for an endorsement policy.
+// The policy is either the expression, or the one of the instantiated chaincode.
+type PolicyEvaluateReq struct {
+	BaseRequest
+	Policy      string   `json:"policy"`
+	ChannelID   string   `json:"channelID"`
+	ChaincodeID string   `json:"chaincodeID"`
+	Orgs        []string `json:"orgs"`  // MSPIDs
+	Peers       []string `json:"peers"` // Peer names or URLs
+}
+
+// HandlePolicyParse to parse the policy, and render it as a tree.
+func HandlePolicyParse(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandlePolicyParse")
+
+	reqBody := &PolicyParseReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+
+	policy, err := api.ParsePolicy(reqBody.Policy)
+	if err != nil {
+		// The syntax error is a result of parsing, but not of the service.
+		if syntaxErr, ok := err.(*api.PolicySyntaxError); ok {
+			ResultOutput(res, req, map[string]interface{}{
+				"valid":       false,
+				"syntaxError": syntaxErr,
+			})
+			return
+		}
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing the policy."))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"valid":      true,
+		"policy":     policy,
+		"expression": policy.String(),
+		"tree":       policy.Tree(),
+		"principals": policy.Principals(),
+	})
+}
+
+// HandlePolicyEvaluate to answer if the set of orgs or peers is enough for the policy.
+func HandlePolicyEvaluate(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandlePolicyEvaluate")
+
+	reqBody := &PolicyEvaluateReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+
+	// The connection is only required for the instantiated chaincode or peers.
+	var conn *api.NetworkConnection
+	var err error
+	if reqBody.Policy == "" || len(reqBody.Peers) > 0 {
+		conn, err = getConnOfReq(reqBody.GetReqConn(), true)
+		if err != nil {
+			ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+			return
+		}
+	}
+
+	var policy *api.PolicyNode
+	if reqBody.Policy != "" {
+		policy, err = api.ParsePolicy(reqBody.Policy)
+		if err != nil {
+			ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing the policy."))
+			return
+		}
+	} else {
+		ccData, err := api.QueryChaincodeData(conn, reqBody.ChannelID, reqBody.ChaincodeID)
+		if err != nil {
+			ErrorOutput(res, req, RES_CODE_ERR_INTERNAL,
+				errors.WithMessagef(err, "Error occurred when query the policy of chaincode %s in channel %s.", reqBody.ChaincodeID, reqBody.ChannelID))
+			return
+		}
+		policy = ccData.Policy
+	}
+
+	identities := api.OrgIdentities(reqBody.Orgs)
+	if len(reqBody.Peers) > 0 {
+		peerIdentities, err := api.PeerIdentities(conn, reqBody.Peers)
+		if err != nil {
+			ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when finding the peers."))
+			return
+		}
+		identities = append(identities, peerIdentities...)
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"policy":     policy,
+		"expression": policy.String(),
+		"tree":       policy.Tree(),
+		"evaluation": policy.Evaluate(identities),
+	})
+}
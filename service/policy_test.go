@@ -0,0 +1,28 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandlePolicyParse(t *testing.T) {
+	cases := map[string]bool{
+		`{"policy": "OR('Org1MSP.peer', 'Org2MSP.peer')"}`: true,
+		`{"policy": "OR('Org1MSP.peer' 'Org2MSP.peer')"}`:  false,
+	}
+	for body, valid := range cases {
+		res := httptest.NewRecorder()
+		HandlePolicyParse(res, httptest.NewRequest("POST", "/policy/parse", strings.NewReader(body)))
+
+		result := map[string]interface{}{}
+		if err := json.Unmarshal(res.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		if result["valid"] != valid {
+			t.Errorf("%s: expected valid %v, got %v", body, valid, result)
+		}
+		t.Log(result)
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/IBM/fablet/api"
+	"github.com/pkg/errors"
+)
+
+// ProfileInlineDir the directory which paths of certificates are inlined from by /profile/convert, it can be set by the flag -inlinedir.
+// Inlining paths is disabled if it is empty, since the files are read at the server.
+var ProfileInlineDir = ""
+
+// ProfileValidateReq to validate the connection profile of the connection, or of the workspace.
+type ProfileValidateReq struct {
+	BaseRequest
+}
+
+// HandleProfileValidate to check the connection profile statically, the findings are with line numbers.
+func HandleProfileValidate(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleProfileValidate")
+
+	reqBody := &ProfileValidateReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	reqConn, err := resolveWorkspace(reqBody.GetReqConn())
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"validation": api.ValidateProfile([]byte(reqConn.ConnProfile), reqConn.MSPID),
+	})
+}
+
+// ProfileConvertReq to convert a connection profile between yaml and json.
+type ProfileConvertReq struct {
+	ConnProfile string `json:"connProfile" openapi:"required"`
+	Format      string `json:"format" openapi:"required,enum=yaml|json"`
+	// InlinePaths to replace paths of certificates with the PEMs, the files are read at the server in ProfileInlineDir.
+	InlinePaths bool `json:"inlinePaths"`
+}
+
+// HandleProfileConvert to convert a connection profile between yaml and json, the order of keys is kept.
+func HandleProfileConvert(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleProfileConvert")
+
+	reqBody := &ProfileConvertReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	options := []api.ProfileConvertOptionFunc{}
+	if reqBody.InlinePaths {
+		if ProfileInlineDir == "" {
+			ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, NewServiceError(ERR_CODE_INVALID_REQUEST,
+				errors.New("Inlining paths is disabled, it is enabled by the flag -inlinedir of the server.")))
+			return
+		}
+		options = append(options, api.WithInlinePaths(ProfileInlineDir))
+	}
+	converted, err := api.ConvertProfile([]byte(reqBody.ConnProfile), reqBody.Format, options...)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, NewServiceError(ERR_CODE_INVALID_REQUEST,
+			errors.WithMessage(err, "Error occurred when converting the connection profile.")))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"connProfile": string(converted),
+		"format":      reqBody.Format,
+	})
+}
+
+// profileErrors to summarize errors of the profile, empty if there is no error.
+func profileErrors(reqConn *RequestConnection) string {
+	validation := api.ValidateProfile([]byte(reqConn.ConnProfile), reqConn.MSPID)
+	summary := ""
+	count := 0
+	for _, finding := range validation.Findings {
+		if finding.Severity != api.ProfileSeverityError {
+			continue
+		}
+		if count == 0 {
+			summary = fmt.Sprintf("line %d: %s", finding.Line, finding.Message)
+		}
+		count++
+	}
+	if count > 1 {
+		summary += fmt.Sprintf(", and %d more, see /profile/validate", count-1)
+	}
+	return summary
+}
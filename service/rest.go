@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/IBM/fablet/api"
+	"github.com/pkg/errors"
+)
+
+// ConnectionHeader the header of GET routes, the connection identifier responded as conn by /network/discover.
+// The connection is created by a POST route, with the connection profile and the identity in the body.
+const ConnectionHeader = "X-Fablet-Connection"
+
+type pathParamsKey struct{}
+
+// PathParam to get the parameter in the path of a resource route, e.g. channelID of /channels/{channelID}.
+func PathParam(req *http.Request, name string) string {
+	params, _ := req.Context().Value(pathParamsKey{}).(map[string]string)
+	return params[name]
+}
+
+// matchPath to match the path against the template, and get the parameters.
+func matchPath(template string, path string) (map[string]string, bool) {
+	tSegs := strings.Split(strings.Trim(template, "/"), "/")
+	pSegs := strings.Split(strings.Trim(path, "/"), "/")
+	if len(tSegs) != len(pSegs) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for idx, tSeg := range tSegs {
+		if strings.HasPrefix(tSeg, "{") && strings.HasSuffix(tSeg, "}") {
+			if pSegs[idx] == "" {
+				return nil, false
+			}
+			params[tSeg[1:len(tSeg)-1]] = pSegs[idx]
+		} else if tSeg != pSegs[idx] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// Resources to return HTTPHandler which dispatches the request to the resource route matching the path.
+func Resources(routes []*Route) HTTPHandler {
+	return func(res http.ResponseWriter, req *http.Request) {
+		for _, route := range routes {
+			if params, ok := matchPath(route.Path, req.URL.Path); ok {
+				req = req.WithContext(context.WithValue(req.Context(), pathParamsKey{}, params))
+				Method(route.Method, route.Handler)(res, req)
+				return
+			}
+		}
+		errorOutput(res, req, RES_CODE_ERR_NOT_FOUND, ERR_CODE_NOT_FOUND, "Resource "+req.URL.Path+" is not found.", nil)
+	}
+}
+
+// getConnOfHeader to get the connection in session by the identifier in the header, or of the workspace in the header.
+func getConnOfHeader(req *http.Request) (*api.NetworkConnection, error) {
+	id := req.Header.Get(ConnectionHeader)
+	if id == "" {
+		if workspace := req.Header.Get(WorkspaceHeader); workspace != "" {
+			return getConnOfReq(&RequestConnection{Workspace: workspace}, true)
+		}
+		return nil, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.Errorf("the header %s or %s is required", ConnectionHeader, WorkspaceHeader))
+	}
+	conn, ok := FindConnection(id)
+	if !ok {
+		return nil, NewServiceError(ERR_CODE_CONNECTION_NOT_FOUND,
+			errors.Errorf("connection %s is not found or expired, please create it by /network/discover", id))
+	}
+	return conn, nil
+}
+
+// queryTargets to get the targets in query, either repeated or separated by comma.
+func queryTargets(req *http.Request) []string {
+	targets := []string{}
+	for _, value := range req.URL.Query()["targets"] {
+		for _, target := range strings.Split(value, ",") {
+			if target = strings.TrimSpace(target); target != "" {
+				targets = append(targets, target)
+			}
+		}
+	}
+	return targets
+}
+
+func queryUint(req *http.Request, name string, defaultValue uint64) (uint64, error) {
+	value := req.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.Errorf("query parameter %s should be a non-negative integer, but got %s", name, value))
+	}
+	return number, nil
+}
+
+// HandleChannelList to list channels of the connection.
+func HandleChannelList(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleChannelList")
+
+	conn, err := getConnOfHeader(req)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	channelIDs := []string{}
+	for channelID := range conn.Channels {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+
+	ResultOutput(res, req, map[string]interface{}{
+		"channels":       channelIDs,
+		"channelLedgers": conn.ChannelLedgers,
+	})
+}
+
+// HandleChannelGet to get the ledger of a channel.
+func HandleChannelGet(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleChannelGet")
+
+	conn, err := getConnOfHeader(req)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	channelID := PathParam(req, "channelID")
+	ledger, err := api.QueryLedger(conn, channelID, queryTargets(req))
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessagef(err, "Error occurred when query the ledger of channel %s.", channelID))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"channelID": channelID,
+		"ledger":    ledger,
+	})
+}
+
+// HandleBlockList to get blocks from begin.
+func HandleBlockList(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleBlockList")
+
+	conn, err := getConnOfHeader(req)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	begin, err := queryUint(req, "begin", 0)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	length, err := queryUint(req, "len", 10)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	channelID := PathParam(req, "channelID")
+	blocks, err := api.QueryBlock(conn, channelID, queryTargets(req), begin, length)
+	if err != nil && len(blocks) < 1 {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessagef(err, "Error occurred when query blocks of channel %s.", channelID))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"blocks": blocks,
+	})
+}
+
+// HandleBlockGet to get a block by number or hash.
+func HandleBlockGet(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleBlockGet")
+
+	conn, err := getConnOfHeader(req)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	channelID, blockID := PathParam(req, "channelID"), PathParam(req, "blockID")
+	var block *api.Block
+	if number, numErr := strconv.ParseUint(blockID, 10, 64); numErr == nil {
+		block, err = api.QueryBlockByNumber(conn, channelID, queryTargets(req), number)
+	} else if _, hexErr := hex.DecodeString(blockID); hexErr == nil {
+		block, err = api.QueryBlockByHash(conn, channelID, queryTargets(req), blockID)
+	} else {
+		ErrorOutput(res, req, RES_CODE_ERR_BAD_REQUEST, errors.Errorf("%s is neither a block number nor a block hash.", blockID))
+		return
+	}
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, ledgerQueryError(err, fmt.Sprintf("block %s of channel %s", blockID, channelID)))
+		return
+	}
+	if block == nil {
+		ErrorOutput(res, req, RES_CODE_ERR_NOT_FOUND, errors.Errorf("Block %s of channel %s is not found.", blockID, channelID))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"block": block,
+	})
+}
+
+// HandleTransactionGet to get a transaction and the number of the block.
+func HandleTransactionGet(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleTransactionGet")
+
+	conn, err := getConnOfHeader(req)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	channelID, txID := PathParam(req, "channelID"), PathParam(req, "txID")
+	block, err := api.QueryBlockByTxID(conn, channelID, queryTargets(req), txID)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, ledgerQueryError(err, fmt.Sprintf("transaction %s of channel %s", txID, channelID)))
+		return
+	}
+	for _, tx := range block.Transactions {
+		if tx.TxID == txID {
+			ResultOutput(res, req, map[string]interface{}{
+				"transaction": tx,
+				"blockNumber": block.Number,
+			})
+			return
+		}
+	}
+	ErrorOutput(res, req, RES_CODE_ERR_NOT_FOUND, errors.Errorf("Transaction %s of channel %s is not found.", txID, channelID))
+}
+
+// ledgerNotFoundMessages messages of the peer if the entry is not in the ledger, of Fabric 1.4 and 2.x.
+var ledgerNotFoundMessages = []string{"entry not found in index", "no such block", "no such transaction"}
+
+// ledgerQueryError to mark the error as not found if the peer answers that the entry is not in the ledger,
+// other errors are classified as they are.
+func ledgerQueryError(err error, entry string) error {
+	msg := strings.ToLower(err.Error())
+	for _, notFound := range ledgerNotFoundMessages {
+		if strings.Contains(msg, notFound) {
+			return NewServiceError(ERR_CODE_NOT_FOUND, errors.WithMessagef(err, "The %s is not found.", entry))
+		}
+	}
+	return errors.WithMessagef(err, "Error occurred when querying the %s.", entry)
+}
+
+// HandleTransactionStatus to get the commit status of a transaction, it begins to be tracked if not yet.
+func HandleTransactionStatus(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleTransactionStatus")
+
+	channelID, txID := PathParam(req, "channelID"), PathParam(req, "txID")
+	status, ok := txTracker.GetStatus(txID)
+	if !ok {
+		conn, err := getConnOfHeader(req)
+		if err != nil {
+			ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+			return
+		}
+		status = txTracker.Track(conn, channelID, txID)
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"txStatus": status,
+	})
+}
+
+// HandleChaincodeList to list instantiated chaincodes of a channel.
+func HandleChaincodeList(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleChaincodeList")
+
+	conn, err := getConnOfHeader(req)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	channelID := PathParam(req, "channelID")
+	chaincodes, err := api.QueryInstantiatedChaincodes(conn, channelID)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessagef(err, "Error occurred when query chaincodes of channel %s.", channelID))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"chaincodes": chaincodes,
+	})
+}
+
+// HandleChaincodeGet to get an instantiated chaincode and its contract metadata.
+func HandleChaincodeGet(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleChaincodeGet")
+
+	conn, err := getConnOfHeader(req)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	channelID, chaincodeID := PathParam(req, "channelID"), PathParam(req, "chaincodeID")
+	chaincodes, err := api.QueryInstantiatedChaincodes(conn, channelID)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessagef(err, "Error occurred when query chaincodes of channel %s.", channelID))
+		return
+	}
+	for _, chaincode := range chaincodes {
+		if chaincode.Name == chaincodeID {
+			// The metadata is only for chaincodes written with the contract API.
+			metadata, _ := api.GetChaincodeMetadata(conn, channelID, chaincodeID, false)
+			ResultOutput(res, req, map[string]interface{}{
+				"chaincode": chaincode,
+				"metadata":  metadata,
+			})
+			return
+		}
+	}
+	ErrorOutput(res, req, RES_CODE_ERR_NOT_FOUND, errors.Errorf("Chaincode %s is not instantiated in channel %s.", chaincodeID, channelID))
+}
+
+// HandlePeerList to list peers of the connection and their statuses.
+func HandlePeerList(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandlePeerList")
+
+	conn, err := getConnOfHeader(req)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	peers := []*api.Peer{}
+	for _, peer := range conn.Peers {
+		peers = append(peers, peer)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Name < peers[j].Name })
+
+	ResultOutput(res, req, map[string]interface{}{
+		"peers":        peers,
+		"peerStatuses": transPeerStatuses(conn.EndpointStatuses),
+	})
+}
+
+// HandlePeerGet to get a peer with its joined channels and installed chaincodes.
+func HandlePeerGet(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandlePeerGet")
+
+	conn, err := getConnOfHeader(req)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	peerName := PathParam(req, "peerName")
+	peer, ok := conn.Peers[peerName]
+	if !ok {
+		ErrorOutput(res, req, RES_CODE_ERR_NOT_FOUND, errors.Errorf("Peer %s is not found.", peerName))
+		return
+	}
+
+	channels, err := api.GetJoinedChannels(conn, peer.URL)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessagef(err, "Error occurred when query channels of peer %s.", peerName))
+		return
+	}
+	channelIDs := []string{}
+	for _, channel := range channels {
+		channelIDs = append(channelIDs, channel.GetChannelId())
+	}
+	installedChaincodes, err := api.QueryInstalledChaincodes(conn, peer.URL)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessagef(err, "Error occurred when query installed chaincodes of peer %s.", peerName))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"peer":                peer,
+		"channels":            channelIDs,
+		"installedChaincodes": installedChaincodes,
+	})
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/IBM/fablet/api"
+	"github.com/IBM/fablet/audit"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// Route an API route, which is also described in the OpenAPI document.
+type Route struct {
+	// Path of the route, parameters of a resource route are in braces, e.g. /channels/{channelID}.
+	Path    string
+	Summary string
+	// Method http.MethodPost by default, resource routes are http.MethodGet.
+	Method string
+	// Query parameters of a resource route.
+	Query []string
+	// Handler of a POST route, the request body is validated before it.
+	Handler HTTPHandler
+	// WSHandler of a websocket route.
+	WSHandler WSHandler
+	// Request the request body, or the first message of a websocket.
+	Request interface{}
+	// Result fields of the result besides resCode, with typed values.
+	Result map[string]interface{}
+	// Produces content types of a route not responding JSON, e.g. a file.
+	Produces []string
+	// Message a message sent by a websocket route.
+	Message interface{}
+}
+
+// Routes all routes of the API.
+// TODO to persist the connection in session
+var Routes = []*Route{
+	// {Path: "/", Handler: HandleRoot},
+	// {Path: "/network/connect", Handler: HandleNetworkConnect},
+	{Path: "/network/discover", Summary: "Discover peers, channels, chaincodes and orderers of the network.",
+		Handler: HandleNetworkDiscover, Request: NetworkDiscoverReq{},
+		Result: map[string]interface{}{
+			"peers":              []*api.Peer{},
+			"channels":           []string{},
+			"peerStatuses":       map[string]api.PeerStatus{},
+			"channelLedgers":     map[string]*api.Ledger{},
+			"channelChaincodes":  map[string][]*api.Chaincode{},
+			"channelOrderers":    map[string][]*api.Orderer{},
+			"channelAnchorPeers": map[string][]string{},
+			"conn":               "",
+		}},
+	{Path: "/network/refresh", Summary: "Recreate the connection and discover the network again.",
+		Handler: HandleNetworkRefresh, Request: NetworkRefreshReq{},
+		Result: map[string]interface{}{"conn": ""}},
+	{Path: "/cert/inventory", Summary: "Collect certificates of peers, orderers, the identity, MSPs of channels and endorsers, with the expiry.",
+		Handler: HandleCertInventory, Request: CertInventoryReq{},
+		Result: map[string]interface{}{"inventory": &api.CertInventory{}}},
+	{Path: "/identity/inspect", Summary: "Decode the certificate of the identity, check it against MSPs of channels, and predict operations it can do.",
+		Handler: HandleIdentityInspect, Request: IdentityInspectReq{},
+		Result: map[string]interface{}{"inspection": &api.IdentityInspection{}}},
+	{Path: "/network/profile/export", Summary: "Export the connection profile completed with the discovered peers, orderers, channels and TLS CA certificates.",
+		Handler: HandleNetworkProfileExport, Request: NetworkProfileExportReq{},
+		Result: map[string]interface{}{"connProfile": "", "format": ""}},
+	{Path: "/peer/details", Summary: "Get installed chaincodes, and chaincodes and ledgers of channels of a peer.",
+		Handler: HandlePeerDetails, Request: PeerDetailsReq{},
+		Result: map[string]interface{}{
+			"installedChaincodes":   []*api.Chaincode{},
+			"channelChaincodes":     map[string][]*api.Chaincode{},
+			"installedCCQueryError": false,
+			"channelLedgers":        map[string]*api.Ledger{},
+			"channels":              []string{},
+		}},
+	{Path: "/chaincode/install", Summary: "Install a chaincode package on peers.",
+		Handler: HandleChaincodeInstall, Request: ChaincodeInstallReq{},
+		Result: map[string]interface{}{"installRes": map[string]api.ExecutionResult{}}},
+	{Path: "/chaincode/inspect", Summary: "Inspect a chaincode package before the installation: files, language, entry point, path and the ID the peer reports.",
+		Handler: HandleChaincodeInspect, Request: ChaincodeInspectReq{},
+		Result: map[string]interface{}{"inspection": &api.ChaincodePackageInspection{}}},
+	{Path: "/chaincode/instantiate", Summary: "Instantiate a chaincode, the result is the transaction ID.",
+		Handler: HandleChaincodeInstantiate, Request: ChaincodeInstantiateReq{},
+		Result: map[string]interface{}{"instantiateRes": ""}},
+	{Path: "/chaincode/upgrade", Summary: "Upgrade a chaincode, the result is the transaction ID.",
+		Handler: HandleChaincodeUpgrade, Request: ChaincodeInstantiateReq{},
+		Result: map[string]interface{}{"upgradeRes": ""}},
+	{Path: "/chaincode/execute", Summary: "Execute or query a chaincode.",
+		Handler: HandleChaincodeExecute, Request: ChaincodeExecuteReq{},
+		Result: map[string]interface{}{
+			"transactionID":     "",
+			"txValidationCode":  int32(0),
+			"chaincodeStatus":   int32(0),
+			"payload":           "",
+			"peerResponses":     []*ChaincodePeerResponse{},
+			"endorserSelection": &api.EndorserSelection{},
+			"txStatus":          &api.TxStatus{},
+		}},
+	{Path: "/chaincode/metadata", Summary: "Get the contract metadata of a chaincode.",
+		Handler: HandleChaincodeMetadata, Request: ChaincodeMetadataReq{},
+		Result: map[string]interface{}{"metadata": &api.ContractMetadata{}}},
+	{Path: "/chaincode/endorse", Summary: "Send the proposal to peers and compare the endorsements, without submitting.",
+		Handler: HandleChaincodeEndorse, Request: ChaincodeExecuteReq{},
+		Result: map[string]interface{}{"endorsement": &api.EndorsementComparison{}}},
+	{Path: "/chaincode/batch", Summary: "Start or resume a batch invocation job. Without waitForCommit, rows are done once submitted, even if they are invalidated later.",
+		Handler: HandleChaincodeBatch, Request: ChaincodeBatchReq{},
+		Result: map[string]interface{}{"jobID": "", "total": 0, "skipped": 0}},
+	{Path: "/chaincode/batch/status", Summary: "Get the report of a batch job.",
+		Handler: HandleChaincodeBatchStatus, Request: ChaincodeBatchStatusReq{},
+		Result: map[string]interface{}{"job": &api.BatchJob{}}},
+	{Path: "/ledger/query", Summary: "Query the ledger of a channel.",
+		Handler: HandleLedgerQuery, Request: LedgerQueryReq{},
+		Result: map[string]interface{}{"ledger": &api.Ledger{}}},
+	{Path: "/ledger/block", Summary: "Query blocks from a number.",
+		Handler: HandleBlockQuery, Request: BlockQueryReq{},
+		Result: map[string]interface{}{"blocks": []*api.Block{}}},
+	{Path: "/ledger/blockany", Summary: "Query a block by number, hash or transaction ID.",
+		Handler: HandleBlockQueryAny, Request: BlockQueryAnyReq{},
+		Result: map[string]interface{}{"block": &api.Block{}}},
+	{Path: "/ledger/mvcc", Summary: "Analyze MVCC conflicts and hot keys of a block range.",
+		Handler: HandleMVCCAnalyze, Request: MVCCAnalyzeReq{},
+		Result: map[string]interface{}{"analysis": &api.MVCCAnalysis{}}},
+	{Path: "/channel/create", Summary: "Create a channel by the channel transaction.",
+		Handler: HandleCreateChannel, Request: CreateChannelReq{},
+		Result: map[string]interface{}{"channelID": ""}},
+	{Path: "/channel/join", Summary: "Join peers to a channel.",
+		Handler: HandleJoinChannel, Request: JoinChannelReq{},
+		Result: map[string]interface{}{"channelID": ""}},
+	{Path: "/audit/query", Summary: "Query audit records, the latest first.",
+		Handler: HandleAuditQuery, Request: AuditQueryReq{},
+		Result: map[string]interface{}{"records": []*audit.Record{}}},
+	{Path: "/audit/export", Summary: "Export audit records as a file.",
+		Handler: HandleAuditExport, Request: AuditQueryReq{},
+		Produces: []string{"application/x-ndjson", "text/csv"}},
+	{Path: "/audit/verify", Summary: "Verify the hash chain of the audit log.",
+		Handler: HandleAuditVerify, Request: struct{}{},
+		Result: map[string]interface{}{"verification": &audit.Verification{}}},
+	{Path: "/tx/status", Summary: "Get the commit status of a transaction on each peer.",
+		Handler: HandleTxStatus, Request: TxStatusReq{},
+		Result: map[string]interface{}{"txStatus": &api.TxStatus{}}},
+	{Path: "/policy/parse", Summary: "Parse an endorsement policy.",
+		Handler: HandlePolicyParse, Request: PolicyParseReq{},
+		Result: map[string]interface{}{
+			"valid":       false,
+			"syntaxError": &api.PolicySyntaxError{},
+			"policy":      &api.PolicyNode{},
+			"expression":  "",
+			"tree":        "",
+			"principals":  []string{},
+		}},
+	{Path: "/policy/evaluate", Summary: "Evaluate if organizations or peers satisfy an endorsement policy.",
+		Handler: HandlePolicyEvaluate, Request: PolicyEvaluateReq{},
+		Result: map[string]interface{}{
+			"policy":     &api.PolicyNode{},
+			"expression": "",
+			"tree":       "",
+			"evaluation": &api.PolicyEvaluation{},
+		}},
+	{Path: "/benchmark/list", Summary: "List saved benchmark reports, the latest first.",
+		Handler: HandleBenchmarkList, Request: struct{}{},
+		Result: map[string]interface{}{"reports": []*api.BenchmarkReport{}}},
+	{Path: "/benchmark/result", Summary: "Get saved benchmark reports.",
+		Handler: HandleBenchmarkResult, Request: BenchmarkResultReq{},
+		Result: map[string]interface{}{"reports": []*api.BenchmarkReport{}}},
+	{Path: "/benchmark/compare", Summary: "Compare saved benchmark reports against the first one.",
+		Handler: HandleBenchmarkCompare, Request: BenchmarkResultReq{},
+		Result: map[string]interface{}{
+			"reports":     []*api.BenchmarkReport{},
+			"comparisons": []*api.BenchmarkComparison{},
+		}},
+	{Path: "/profile/validate", Summary: "Check the connection profile statically, the findings are with line numbers.",
+		Handler: HandleProfileValidate, Request: ProfileValidateReq{},
+		Result: map[string]interface{}{"validation": &api.ProfileValidation{}}},
+	{Path: "/profile/convert", Summary: "Convert a connection profile between yaml and json, paths of certificates can be inlined.",
+		Handler: HandleProfileConvert, Request: ProfileConvertReq{},
+		Result: map[string]interface{}{"connProfile": "", "format": ""}},
+	{Path: "/ca/list", Summary: "List Fabric CAs of the connection profile, registrars are not responded.",
+		Handler: HandleCAList, Request: BaseRequest{},
+		Result: map[string]interface{}{"CAs": []*api.CAConfig{}}},
+	{Path: "/ca/enroll", Summary: "Enroll an identity by the secret with a new key, it is stored in the workspace of the request.",
+		Handler: HandleCAEnroll, Request: CAEnrollReq{},
+		Result: map[string]interface{}{"identity": &WorkspaceIdentity{}, "workspace": &Workspace{}}},
+	{Path: "/ca/reenroll", Summary: "Renew the certificate of the identity of the request, it is updated in the workspace.",
+		Handler: HandleCAReenroll, Request: CAReq{},
+		Result: map[string]interface{}{"identity": &WorkspaceIdentity{}, "workspace": &Workspace{}}},
+	{Path: "/ca/register", Summary: "Register an identity by the identity of the request, or the registrar of the profile.",
+		Handler: HandleCARegister, Request: CARegisterReq{},
+		Result: map[string]interface{}{"secret": ""}},
+	{Path: "/ca/revoke", Summary: "Revoke certificates of an identity, or the certificate of the serial and AKI.",
+		Handler: HandleCARevoke, Request: CARevokeReq{},
+		Result: map[string]interface{}{"result": &api.CARevocationResult{}}},
+	{Path: "/ca/identities", Summary: "List identities of the CA which the identity of the request, or the registrar, is allowed to see.",
+		Handler: HandleCAIdentities, Request: CAReq{},
+		Result: map[string]interface{}{"identities": []*api.CAIdentity{}}},
+	{Path: "/workspace/list", Summary: "List workspaces without private keys, and the current one.",
+		Handler: HandleWorkspaceList, Request: struct{}{},
+		Result: map[string]interface{}{"workspaces": []*Workspace{}, "current": ""}},
+	{Path: "/workspace/save", Summary: "Create or update a workspace, private keys are kept if they are empty.",
+		Handler: HandleWorkspaceSave, Request: WorkspaceSaveReq{},
+		Result: map[string]interface{}{"workspace": &Workspace{}}},
+	{Path: "/workspace/delete", Summary: "Delete a workspace.",
+		Handler: HandleWorkspaceDelete, Request: WorkspaceNameReq{},
+		Result: map[string]interface{}{"workspaces": []*Workspace{}, "current": ""}},
+	{Path: "/workspace/switch", Summary: "Switch the current workspace, which clients select, requests still name their workspace.",
+		Handler: HandleWorkspaceSwitch, Request: WorkspaceNameReq{},
+		Result: map[string]interface{}{"workspaces": []*Workspace{}, "current": ""}},
+	{Path: "/workspace/compare", Summary: "Discover networks of workspaces side by side.",
+		Handler: HandleWorkspaceCompare, Request: WorkspaceCompareReq{},
+		Result: map[string]interface{}{"overviews": []*WorkspaceOverview{}}},
+	{Path: "/graphql", Summary: "Query the network and ledgers by GraphQL, the schema is at /graphql/schema.",
+		Handler: HandleGraphQL, Request: GraphQLReq{},
+		Result: map[string]interface{}{
+			"data":   map[string]interface{}{},
+			"errors": []gqlerrors.FormattedError{},
+		}},
+	{Path: "/event/blockevent", Summary: "Listen block events of a channel.",
+		WSHandler: HandleBlockEvent, Request: BlockEventReq{}, Message: BlockEventResult{}},
+	{Path: "/event/chaincodeevent", Summary: "Listen chaincode events, a new request replaces the former one.",
+		WSHandler: HandleChaincodeEvent, Request: ChaincodeEventReq{}, Message: ChaincodeEventResult{}},
+	{Path: "/event/benchmark", Summary: "Run a benchmark, send {\"action\": \"stop\"} to stop it.",
+		WSHandler: HandleBenchmark, Request: BenchmarkReq{}, Message: BenchmarkMessage{}},
+	{Path: "/event/txstatus", Summary: "Receive the status of a transaction whenever it changes.",
+		WSHandler: HandleTxStatusEvent, Request: TxStatusReq{}, Message: api.TxStatus{}},
+
+	// Read-only resources, the connection is identified by the ConnectionHeader.
+	{Path: "/channels", Summary: "List channels and their ledgers.", Method: http.MethodGet,
+		Handler: HandleChannelList,
+		Result: map[string]interface{}{
+			"channels":       []string{},
+			"channelLedgers": map[string]*api.Ledger{},
+		}},
+	{Path: "/channels/{channelID}", Summary: "Get the ledger of a channel.", Method: http.MethodGet,
+		Handler: HandleChannelGet, Query: []string{"targets"},
+		Result: map[string]interface{}{"channelID": "", "ledger": &api.Ledger{}}},
+	{Path: "/channels/{channelID}/blocks", Summary: "Get blocks from begin, 10 blocks by default.", Method: http.MethodGet,
+		Handler: HandleBlockList, Query: []string{"begin", "len", "targets"},
+		Result: map[string]interface{}{"blocks": []*api.Block{}}},
+	{Path: "/channels/{channelID}/blocks/{blockID}", Summary: "Get a block by number or hash.", Method: http.MethodGet,
+		Handler: HandleBlockGet, Query: []string{"targets"},
+		Result: map[string]interface{}{"block": &api.Block{}}},
+	{Path: "/channels/{channelID}/transactions/{txID}", Summary: "Get a transaction and the number of its block.", Method: http.MethodGet,
+		Handler: HandleTransactionGet, Query: []string{"targets"},
+		Result: map[string]interface{}{"transaction": &api.Transaction{}, "blockNumber": uint64(0)}},
+	{Path: "/channels/{channelID}/transactions/{txID}/status", Summary: "Get the commit status of a transaction on each peer.", Method: http.MethodGet,
+		Handler: HandleTransactionStatus,
+		Result:  map[string]interface{}{"txStatus": &api.TxStatus{}}},
+	{Path: "/channels/{channelID}/chaincodes", Summary: "List instantiated chaincodes of a channel.", Method: http.MethodGet,
+		Handler: HandleChaincodeList,
+		Result:  map[string]interface{}{"chaincodes": []*api.Chaincode{}}},
+	{Path: "/channels/{channelID}/chaincodes/{chaincodeID}", Summary: "Get an instantiated chaincode and its contract metadata.", Method: http.MethodGet,
+		Handler: HandleChaincodeGet,
+		Result:  map[string]interface{}{"chaincode": &api.Chaincode{}, "metadata": &api.ContractMetadata{}}},
+	{Path: "/peers", Summary: "List peers and their statuses.", Method: http.MethodGet,
+		Handler: HandlePeerList,
+		Result: map[string]interface{}{
+			"peers":        []*api.Peer{},
+			"peerStatuses": map[string]api.PeerStatus{},
+		}},
+	{Path: "/peers/{peerName}", Summary: "Get a peer with its joined channels and installed chaincodes.", Method: http.MethodGet,
+		Handler: HandlePeerGet,
+		Result: map[string]interface{}{
+			"peer":                &api.Peer{},
+			"channels":            []string{},
+			"installedChaincodes": []*api.Chaincode{},
+		}},
+}
+
+// GetHandlerMap to get handlers of all routes.
+func GetHandlerMap() map[string]HTTPHandler {
+	handlerMap := map[string]HTTPHandler{
+		"/openapi.json":   Get(HandleOpenAPI),
+		"/graphql/schema": Get(HandleGraphQLSchema),
+		"/metrics":        Get(HandleMetrics),
+	}
+	// Resource routes are dispatched by the first segment of the path.
+	resources := map[string][]*Route{}
+	for _, route := range Routes {
+		switch {
+		case route.WSHandler != nil:
+			handlerMap[route.Path] = WS(route.Path, route.WSHandler)
+		case route.Method == http.MethodGet:
+			root := "/" + strings.Split(strings.Trim(route.Path, "/"), "/")[0]
+			resources[root] = append(resources[root], route)
+		default:
+			handlerMap[route.Path] = Post(Validate(route.Path, route.Handler))
+		}
+	}
+	for root, routes := range resources {
+		handlerMap[root] = Resources(routes)
+		handlerMap[root+"/"] = Resources(routes)
+	}
+
+	return handlerMap
+}
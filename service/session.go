@@ -90,6 +90,17 @@ func GetConnection(connProfile *api.ConnectionProfile, participant *api.Particip
 	return NewConnection(connProfile, participant, useDiscovery)
 }
 
+// FindConnection find the connection in session by the identifier.
+func FindConnection(id string) (*api.NetworkConnection, bool) {
+	connSession.RLock()
+	defer connSession.RUnlock()
+	conn, ok := connSession.findConn(id)
+	if ok {
+		conn.ActiveTime = time.Now()
+	}
+	return conn, ok
+}
+
 // NewConnection create connection and then store it into session.
 func NewConnection(connProfile *api.ConnectionProfile, participant *api.Participant, useDiscovery bool) (*api.NetworkConnection, error) {
 	conn, err := api.NewConnection(connProfile, participant, useDiscovery)
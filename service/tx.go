@@ -0,0 +1,102 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/IBM/fablet/api"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+// TxStatusReq to get the commit status of a transaction.
+// The connection is optional, it is required only to track a transaction which is not submitted by Fablet.
+type TxStatusReq struct {
+	BaseRequest
+	ChannelID string `json:"channelID" openapi:"required"`
+	TxID      string `json:"txID" openapi:"required"`
+}
+
+// A global tracker of all submitted transactions.
+var txTracker = api.NewTxTracker()
+
+func getTxStatus(reqBody *TxStatusReq) (*api.TxStatus, error) {
+	if reqBody.TxID == "" {
+		return nil, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.New("transaction ID is empty"))
+	}
+	if status, ok := txTracker.GetStatus(reqBody.TxID); ok {
+		return status, nil
+	}
+	if reqBody.GetReqConn() == nil {
+		return nil, NewServiceError(ERR_CODE_NOT_FOUND, errors.Errorf("transaction %s is not tracked", reqBody.TxID))
+	}
+	conn, err := getConnOfReq(reqBody.GetReqConn(), true)
+	if err != nil {
+		return nil, err
+	}
+	return txTracker.Track(conn, reqBody.ChannelID, reqBody.TxID), nil
+}
+
+// HandleTxStatus to get the commit status of a transaction.
+func HandleTxStatus(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleTxStatus")
+
+	reqBody := &TxStatusReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+
+	status, err := getTxStatus(reqBody)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when getting the transaction status."))
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"txStatus": status,
+	})
+}
+
+// HandleTxStatusEvent to push the status of a transaction whenever it changes, until all peers committed or timeout.
+func HandleTxStatusEvent(wsConn *websocket.Conn, request []byte) error {
+	logger.Info("Service HandleTxStatusEvent")
+	reqBody := &TxStatusReq{}
+	if err := json.Unmarshal(request, reqBody); err != nil {
+		return err
+	}
+	if _, err := getTxStatus(reqBody); err != nil {
+		return writeWSError(wsConn, err)
+	}
+
+	statusChan, unsubscribe, err := txTracker.Subscribe(reqBody.TxID)
+	if err != nil {
+		return writeWSError(wsConn, err)
+	}
+	defer unsubscribe()
+
+	// Monitor the client connection.
+	errChan := make(chan error, 1)
+	go func() {
+		for {
+			if _, _, err := wsConn.ReadMessage(); err != nil {
+				errChan <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case status, ok := <-statusChan:
+			if !ok {
+				return nil
+			}
+			if err := wsConn.WriteJSON(status); err != nil {
+				return errors.WithMessage(err, "Error of write transaction status.")
+			}
+		case err := <-errChan:
+			return err
+		}
+	}
+}
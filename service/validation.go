@@ -0,0 +1,179 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FieldError an invalid field of the request.
+type FieldError struct {
+	Field   string `json:"field"` // Path of the field, e.g. chaincode.channelID or targets[0].
+	Message string `json:"message"`
+}
+
+// validateValue to validate a value decoded with json.Number against the schema.
+func (g *schemaGenerator) validateValue(schema *Schema, value interface{}, field string, fieldErrs []*FieldError) []*FieldError {
+	schema = g.resolve(schema)
+	// Null is the zero value in Go, the required check is done by the parent.
+	if schema == nil || value == nil {
+		return fieldErrs
+	}
+	addErr := func(format string, args ...interface{}) []*FieldError {
+		return append(fieldErrs, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return addErr("should be an object, but got %s", jsonTypeOf(value))
+		}
+		for _, name := range schema.Required {
+			// Only the presence, an empty value may be meaningful, such as the default.
+			if _, ok := obj[name]; !ok {
+				fieldErrs = append(fieldErrs, &FieldError{Field: joinField(field, name), Message: "is required"})
+			}
+		}
+		// Sorted for stable errors.
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propSchema := schema.Properties[name]
+			if propSchema == nil {
+				// Unknown fields are ignored as encoding/json does.
+				propSchema = schema.AdditionalProperties
+			}
+			fieldErrs = g.validateValue(propSchema, obj[name], joinField(field, name), fieldErrs)
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return addErr("should be an array, but got %s", jsonTypeOf(value))
+		}
+		for idx, item := range arr {
+			fieldErrs = g.validateValue(schema.Items, item, fmt.Sprintf("%s[%d]", field, idx), fieldErrs)
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return addErr("should be a string, but got %s", jsonTypeOf(value))
+		}
+		// Empty is the default value.
+		if len(schema.Enum) > 0 && !(str == "" && schema.Default != "") {
+			for _, e := range schema.Enum {
+				if str == e {
+					return fieldErrs
+				}
+			}
+			return addErr("should be one of %s, but got %q", strings.Join(schema.Enum, ", "), str)
+		}
+	case "integer", "number":
+		num, ok := value.(json.Number)
+		if !ok {
+			return addErr("should be a %s, but got %s", schema.Type, jsonTypeOf(value))
+		}
+		f, err := num.Float64()
+		if err != nil {
+			return addErr("%s is not a valid number", num)
+		}
+		if schema.Type == "integer" && strings.ContainsAny(num.String(), ".eE") {
+			return addErr("should be an integer, but got %s", num)
+		}
+		if schema.Minimum != nil && f < *schema.Minimum {
+			return addErr("should be at least %v, but got %s", *schema.Minimum, num)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return addErr("should be a boolean, but got %s", jsonTypeOf(value))
+		}
+	}
+	return fieldErrs
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func jsonTypeOf(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case json.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	}
+	return "null"
+}
+
+// ValidateRequest to validate the request body against the schema of the route.
+func ValidateRequest(path string, body []byte) ([]*FieldError, error) {
+	doc := getAPIDoc()
+	schema, ok := doc.reqSchemas[path]
+	if !ok {
+		return nil, nil
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		body = []byte("{}")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		if syntaxErr, ok := err.(*json.SyntaxError); ok {
+			return nil, errors.Errorf("The request is not valid JSON at offset %d: %s.", syntaxErr.Offset, syntaxErr.Error())
+		}
+		return nil, errors.WithMessage(err, "The request is not valid JSON.")
+	}
+	return doc.generator.validateValue(schema, value, "", nil), nil
+}
+
+// Validate to return HTTPHandler which validates the request body before the handler.
+func Validate(path string, hh HTTPHandler) HTTPHandler {
+	return func(res http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when reading the request."))
+			return
+		}
+		fieldErrs, err := ValidateRequest(path, body)
+		if err != nil {
+			ErrorOutput(res, req, RES_CODE_ERR_BAD_REQUEST, err)
+			return
+		}
+		if len(fieldErrs) > 0 {
+			msg := invalidRequestMessage(fieldErrs)
+			logger.Errorf("Invalid request of %s: %s", path, msg)
+			errorOutput(res, req, RES_CODE_ERR_BAD_REQUEST, ERR_CODE_INVALID_REQUEST, msg, map[string]interface{}{"fieldErrors": fieldErrs})
+			return
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		hh(res, req)
+	}
+}
+
+func invalidRequestMessage(fieldErrs []*FieldError) string {
+	msgs := []string{}
+	for _, fieldErr := range fieldErrs {
+		msgs = append(msgs, fieldErr.Field+" "+fieldErr.Message)
+	}
+	return "Invalid request: " + strings.Join(msgs, "; ") + "."
+}
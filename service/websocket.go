@@ -4,15 +4,17 @@ import (
 	"net/http"
 
 	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
 )
 
 var upgrader = websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
 
-// WSHandler To handle all incoming http request
-type WSHandler func(wsConn *websocket.Conn) error
+// WSHandler To handle all incoming http request, the request is the first message which has been validated.
+type WSHandler func(wsConn *websocket.Conn, request []byte) error
 
-// WS to return websocket handler
-func WS(wsh WSHandler) HTTPHandler {
+// WS to return websocket handler, the first message is validated against the request schema of the path.
+// An invalid request is answered with the field errors.
+func WS(path string, wsh WSHandler) HTTPHandler {
 	return func(res http.ResponseWriter, req *http.Request) {
 		logger.Infof("Websocket starts.")
 
@@ -26,7 +28,24 @@ func WS(wsh WSHandler) HTTPHandler {
 			wsConn.Close()
 		}()
 
-		if err := wsh(wsConn); err != nil {
+		_, request, err := wsConn.ReadMessage()
+		if err != nil {
+			logger.Errorf("Error in websocket: %s", err.Error())
+			return
+		}
+		fieldErrs, err := ValidateRequest(path, request)
+		if err == nil && len(fieldErrs) > 0 {
+			err = errors.New(invalidRequestMessage(fieldErrs))
+		}
+		if err != nil {
+			logger.Errorf("Invalid request of %s: %s", path, err.Error())
+			if wErr := wsConn.WriteJSON(&ErrorResult{Error: err.Error(), ErrCode: ERR_CODE_INVALID_REQUEST, FieldErrors: fieldErrs}); wErr != nil {
+				logger.Errorf("Error of write error result: %s", wErr.Error())
+			}
+			return
+		}
+
+		if err := wsh(wsConn, request); err != nil {
 			logger.Errorf("Error in websocket: %s", err.Error())
 		}
 	}
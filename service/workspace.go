@@ -0,0 +1,420 @@
+package service
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IBM/fablet/api"
+	"github.com/IBM/fablet/audit"
+	"github.com/pkg/errors"
+)
+
+// WorkspaceHeader the header of GET routes to use a workspace, if ConnectionHeader is absent.
+const WorkspaceHeader = "X-Fablet-Workspace"
+
+// Workspace a named network, with its connection profile, identities and settings.
+type Workspace struct {
+	Name        string `json:"name" openapi:"required"`
+	Description string `json:"description"`
+	ConnProfile string `json:"connProfile"`
+	// Identities to connect the network, the default identity is used if a request doesn't give one.
+	Identities      []*WorkspaceIdentity `json:"identities"`
+	DefaultIdentity string               `json:"defaultIdentity"`
+	// Settings of the clients, e.g. of the web UI, which are kept as they are.
+	Settings   map[string]interface{} `json:"settings"`
+	UpdateTime int64                  `json:"updateTime"`
+}
+
+// WorkspaceIdentity an identity of a workspace. The private key, the passphrase and the PIN are not responded, they are kept if they are empty when saving.
+type WorkspaceIdentity struct {
+	Label         string `json:"label" openapi:"required"`
+	MSPID         string `json:"MSPID"`
+	CertContent   string `json:"certContent"`
+	PrvKeyContent string `json:"prvKeyContent,omitempty"`
+	// Passphrase of prvKeyContent if it is encrypted.
+	Passphrase string `json:"passphrase,omitempty"`
+	// PKCS11 the private key in a PKCS#11 token instead of prvKeyContent.
+	PKCS11 *api.PKCS11Config `json:"PKCS11,omitempty"`
+}
+
+// WorkspaceSaveReq to create or update a workspace.
+type WorkspaceSaveReq struct {
+	Workspace Workspace `json:"workspace" openapi:"required"`
+}
+
+// WorkspaceNameReq to switch to or delete a workspace.
+type WorkspaceNameReq struct {
+	Name string `json:"name" openapi:"required"`
+}
+
+// WorkspaceCompareReq to discover networks of workspaces side by side.
+type WorkspaceCompareReq struct {
+	Names []string `json:"names" openapi:"required"`
+}
+
+// WorkspaceOverview the overview of the network of a workspace, or the error of it.
+type WorkspaceOverview struct {
+	Name           string                    `json:"name"`
+	Peers          []*api.Peer               `json:"peers"`
+	PeerStatuses   map[string]api.PeerStatus `json:"peerStatuses"`
+	Channels       []string                  `json:"channels"`
+	ChannelLedgers map[string]*api.Ledger    `json:"channelLedgers"`
+	ErrCode        ErrCode                   `json:"errCode,omitempty"`
+	Error          string                    `json:"error,omitempty"`
+}
+
+// WorkspaceStore all workspaces, persisted in the data folder, with the current one selected by clients.
+// Requests name the workspace they use, the current one is not used implicitly.
+type WorkspaceStore struct {
+	Current    string
+	Workspaces map[string]*Workspace
+	// salt of the key to encrypt secrets, which is persisted with them.
+	salt []byte
+	sync.RWMutex
+}
+
+var workspaceStore *WorkspaceStore
+var workspaceStoreErr error
+var workspaceOnce sync.Once
+
+func getWorkspaceFile() string {
+	return filepath.Join(GetDataFolder("workspace"), "workspaces.json")
+}
+
+func getWorkspaceStore() (*WorkspaceStore, error) {
+	workspaceOnce.Do(func() {
+		workspaceStore, workspaceStoreErr = &WorkspaceStore{Workspaces: map[string]*Workspace{}}, nil
+		content, err := ioutil.ReadFile(getWorkspaceFile())
+		if err != nil {
+			if !os.IsNotExist(err) {
+				workspaceStoreErr = errors.WithMessage(err, "Error occurred when reading workspaces.")
+			}
+			return
+		}
+		workspaceStoreErr = unmarshalStore(content, workspaceStore)
+	})
+	return workspaceStore, workspaceStoreErr
+}
+
+// save to persist the store with the lock held. Secrets are encrypted by WorkspaceKeyEnv, or not saved without it.
+// The file is only readable by the owner.
+func (store *WorkspaceStore) save() error {
+	content, err := marshalStore(store)
+	if err != nil {
+		return errors.WithMessage(err, "Error occurred when marshaling workspaces.")
+	}
+	if err := os.MkdirAll(GetDataFolder("workspace"), 0700); err != nil {
+		return errors.WithMessage(err, "Error occurred when creating the workspace folder.")
+	}
+	tmpFile := getWorkspaceFile() + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, content, 0600); err != nil {
+		return errors.WithMessage(err, "Error occurred when saving workspaces.")
+	}
+	return os.Rename(tmpFile, getWorkspaceFile())
+}
+
+func (store *WorkspaceStore) find(name string) (*Workspace, error) {
+	ws, ok := store.Workspaces[name]
+	if !ok {
+		return nil, NewServiceError(ERR_CODE_NOT_FOUND, errors.Errorf("workspace %s is not found", name))
+	}
+	return ws, nil
+}
+
+// redacted to copy the workspace without private keys.
+func (ws *Workspace) redacted() *Workspace {
+	copied := *ws
+	copied.Identities = []*WorkspaceIdentity{}
+	for _, identity := range ws.Identities {
+		copied.Identities = append(copied.Identities, identity.redacted())
+	}
+	return &copied
+}
+
+// redacted to copy the identity without the private key, passphrase and PIN.
+func (identity *WorkspaceIdentity) redacted() *WorkspaceIdentity {
+	copied := *identity
+	copied.PrvKeyContent = ""
+	copied.Passphrase = ""
+	if identity.PKCS11 != nil {
+		copiedPKCS11 := *identity.PKCS11
+		copiedPKCS11.PIN = ""
+		copied.PKCS11 = &copiedPKCS11
+	}
+	return &copied
+}
+
+// findIdentity to find the identity by label, nil if it doesn't exist.
+func (ws *Workspace) findIdentity(label string) *WorkspaceIdentity {
+	for _, identity := range ws.Identities {
+		if identity.Label == label {
+			return identity
+		}
+	}
+	return nil
+}
+
+// identity to get the identity by label, the default one if the label is empty, or the first one if there is no default.
+func (ws *Workspace) identity(label string) (*WorkspaceIdentity, error) {
+	if label == "" {
+		label = ws.DefaultIdentity
+	}
+	if label == "" {
+		if len(ws.Identities) == 0 {
+			return nil, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.Errorf("workspace %s has no identity", ws.Name))
+		}
+		return ws.Identities[0], nil
+	}
+	if identity := ws.findIdentity(label); identity != nil {
+		return identity, nil
+	}
+	return nil, NewServiceError(ERR_CODE_NOT_FOUND, errors.Errorf("identity %s is not found in workspace %s", label, ws.Name))
+}
+
+// withSecrets to copy the identity with the private key, the passphrase and the PIN of the request connection if they are given,
+// which are required if the secrets of the identity are not saved.
+func (identity *WorkspaceIdentity) withSecrets(reqConn *RequestConnection) *WorkspaceIdentity {
+	copied := *identity
+	if reqConn.PrvKeyContent != "" {
+		copied.PrvKeyContent = reqConn.PrvKeyContent
+	}
+	if reqConn.Passphrase != "" {
+		copied.Passphrase = reqConn.Passphrase
+	}
+	if identity.PKCS11 != nil && reqConn.PKCS11 != nil && reqConn.PKCS11.PIN != "" {
+		copiedPKCS11 := *identity.PKCS11
+		copiedPKCS11.PIN = reqConn.PKCS11.PIN
+		copied.PKCS11 = &copiedPKCS11
+	}
+	return &copied
+}
+
+// requestWorkspace to get the workspace named by the request connection, with the identity of the label.
+// The identity is nil if it is optional, and the request gives no label and the workspace has no identity.
+func requestWorkspace(reqConn *RequestConnection, identityRequired bool) (*Workspace, *WorkspaceIdentity, error) {
+	if reqConn == nil || reqConn.Workspace == "" {
+		return nil, nil, NewServiceError(ERR_CODE_INVALID_REQUEST, errors.New("the connection of the request is empty"))
+	}
+	store, err := getWorkspaceStore()
+	if err != nil {
+		return nil, nil, err
+	}
+	store.RLock()
+	defer store.RUnlock()
+
+	ws, err := store.find(reqConn.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !identityRequired && reqConn.Identity == "" && len(ws.Identities) == 0 {
+		return ws, nil, nil
+	}
+	identity, err := ws.identity(reqConn.Identity)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ws, identity.withSecrets(reqConn), nil
+}
+
+// resolveWorkspace to get the connection of the workspace of the request, the request connection is returned as it is if it doesn't use a workspace.
+func resolveWorkspace(reqConn *RequestConnection) (*RequestConnection, error) {
+	if reqConn != nil && reqConn.Workspace == "" {
+		return reqConn, nil
+	}
+	ws, identity, err := requestWorkspace(reqConn, true)
+	if err != nil {
+		return nil, err
+	}
+	return &RequestConnection{
+		Label:         identity.Label,
+		MSPID:         identity.MSPID,
+		CertContent:   identity.CertContent,
+		PrvKeyContent: identity.PrvKeyContent,
+		Passphrase:    identity.Passphrase,
+		PKCS11:        identity.PKCS11,
+		ConnProfile:   ws.ConnProfile,
+	}, nil
+}
+
+func listWorkspaces(store *WorkspaceStore) []*Workspace {
+	workspaces := []*Workspace{}
+	for _, ws := range store.Workspaces {
+		workspaces = append(workspaces, ws.redacted())
+	}
+	sort.Slice(workspaces, func(i, j int) bool { return workspaces[i].Name < workspaces[j].Name })
+	return workspaces
+}
+
+// HandleWorkspaceList to list workspaces without private keys.
+func HandleWorkspaceList(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleWorkspaceList")
+
+	store, err := getWorkspaceStore()
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	store.RLock()
+	defer store.RUnlock()
+	ResultOutput(res, req, map[string]interface{}{
+		"workspaces": listWorkspaces(store),
+		"current":    store.Current,
+	})
+}
+
+// HandleWorkspaceSave to create or update a workspace. Private keys of identities are kept if they are empty.
+func HandleWorkspaceSave(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleWorkspaceSave")
+
+	reqBody := &WorkspaceSaveReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	store, err := getWorkspaceStore()
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	ws := &reqBody.Workspace
+	begin := time.Now()
+	store.Lock()
+	defer store.Unlock()
+	if stored, ok := store.Workspaces[ws.Name]; ok {
+		for _, identity := range ws.Identities {
+			storedIdentity := stored.findIdentity(identity.Label)
+			if storedIdentity == nil {
+				continue
+			}
+			if identity.PrvKeyContent == "" {
+				identity.PrvKeyContent = storedIdentity.PrvKeyContent
+			}
+			if identity.Passphrase == "" {
+				identity.Passphrase = storedIdentity.Passphrase
+			}
+			if identity.PKCS11 != nil && identity.PKCS11.PIN == "" && storedIdentity.PKCS11 != nil {
+				identity.PKCS11.PIN = storedIdentity.PKCS11.PIN
+			}
+		}
+	}
+	if ws.DefaultIdentity != "" && ws.findIdentity(ws.DefaultIdentity) == nil {
+		ErrorOutput(res, req, RES_CODE_ERR_BAD_REQUEST, errors.Errorf("The default identity %s is not an identity of the workspace.", ws.DefaultIdentity))
+		return
+	}
+	ws.UpdateTime = time.Now().UnixNano() / 1000000
+	store.Workspaces[ws.Name] = ws
+	err = store.save()
+	recordAudit(req, nil, &audit.Record{Operation: AuditOperationSaveWorkspace, Resource: "workspace/" + ws.Name}, begin, err)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"workspace": ws.redacted(),
+	})
+}
+
+// HandleWorkspaceDelete to delete a workspace, there is no current workspace if it is deleted.
+func HandleWorkspaceDelete(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleWorkspaceDelete")
+	updateWorkspaceStore(res, req, AuditOperationDeleteWorkspace, func(store *WorkspaceStore, name string) {
+		delete(store.Workspaces, name)
+		if store.Current == name {
+			store.Current = ""
+		}
+	})
+}
+
+// HandleWorkspaceSwitch to switch the current workspace, which clients select, requests still name their workspace.
+func HandleWorkspaceSwitch(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleWorkspaceSwitch")
+	updateWorkspaceStore(res, req, AuditOperationSwitchWorkspace, func(store *WorkspaceStore, name string) {
+		store.Current = name
+	})
+}
+
+// updateWorkspaceStore to update the store by the workspace of the request, audit the operation and output the list.
+func updateWorkspaceStore(res http.ResponseWriter, req *http.Request, operation string, update func(store *WorkspaceStore, name string)) {
+	reqBody := &WorkspaceNameReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+	store, err := getWorkspaceStore()
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	begin := time.Now()
+	store.Lock()
+	defer store.Unlock()
+	if _, err := store.find(reqBody.Name); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+	update(store, reqBody.Name)
+	err = store.save()
+	recordAudit(req, nil, &audit.Record{Operation: operation, Resource: "workspace/" + reqBody.Name}, begin, err)
+	if err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, err)
+		return
+	}
+
+	ResultOutput(res, req, map[string]interface{}{
+		"workspaces": listWorkspaces(store),
+		"current":    store.Current,
+	})
+}
+
+// HandleWorkspaceCompare to discover networks of workspaces concurrently, a failure of a workspace doesn't fail the others.
+func HandleWorkspaceCompare(res http.ResponseWriter, req *http.Request) {
+	logger.Info("Service HandleWorkspaceCompare")
+
+	reqBody := &WorkspaceCompareReq{}
+	if err := ReadRequest(req, reqBody); err != nil {
+		ErrorOutput(res, req, RES_CODE_ERR_INTERNAL, errors.WithMessage(err, "Error occurred when parsing from request."))
+		return
+	}
+
+	overviews := make([]*WorkspaceOverview, len(reqBody.Names))
+	wg := sync.WaitGroup{}
+	for idx, name := range reqBody.Names {
+		wg.Add(1)
+		go func(idx int, name string) {
+			defer wg.Done()
+			overviews[idx] = overviewWorkspace(name)
+		}(idx, name)
+	}
+	wg.Wait()
+
+	ResultOutput(res, req, map[string]interface{}{
+		"overviews": overviews,
+	})
+}
+
+func overviewWorkspace(name string) *WorkspaceOverview {
+	overview := &WorkspaceOverview{Name: name}
+	conn, err := getConnOfReq(&RequestConnection{Workspace: name}, true)
+	var networkOverview *api.NetworkOverview
+	if err == nil {
+		networkOverview, err = api.DiscoverNetworkOverview(conn)
+	}
+	if err != nil {
+		overview.ErrCode = ClassifyError(err)
+		overview.Error = err.Error()
+		return overview
+	}
+	overview.Peers = networkOverview.Peers
+	overview.PeerStatuses = transPeerStatuses(networkOverview.EndpointStatuses)
+	overview.Channels = transChannels(networkOverview.Channels)
+	overview.ChannelLedgers = networkOverview.ChannelLedgers
+	return overview
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func postWorkspace(t *testing.T, path string, body string) (int, map[string]interface{}) {
+	res := httptest.NewRecorder()
+	GetHandlerMap()[path](res, httptest.NewRequest("POST", path, strings.NewReader(body)))
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(res.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	return res.Code, result
+}
+
+func TestWorkspace(t *testing.T) {
+	exeFolder := ExeFolder
+	tmpFolder, err := ioutil.TempDir("", "fablet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ExeFolder = tmpFolder
+	workspaceOnce = sync.Once{}
+	defer func() {
+		ExeFolder = exeFolder
+		workspaceOnce = sync.Once{}
+		os.RemoveAll(tmpFolder)
+	}()
+
+	os.Setenv(WorkspaceKeyEnv, "workspace key")
+	defer os.Unsetenv(WorkspaceKeyEnv)
+
+	code, result := postWorkspace(t, "/workspace/save", `{"workspace": {"name": "dev", "connProfile": "profile",
+		"identities": [{"label": "admin", "MSPID": "Org1MSP", "prvKeyContent": "key1", "passphrase": "secret1"}, {"label": "user", "prvKeyContent": "key2"},
+		{"label": "hsm", "PKCS11": {"library": "softhsm2.so", "PIN": "98765432", "keyLabel": "hsm"}}],
+		"defaultIdentity": "admin"}}`)
+	if body, _ := json.Marshal(result); code != 200 || strings.Contains(string(body), "prvKeyContent") || strings.Contains(string(body), "98765432") || strings.Contains(string(body), "secret1") {
+		t.Errorf("expected the workspace without private keys, passphrases and PINs, but got %d %v", code, result)
+	}
+	// Private keys, passphrases and PINs are kept if they are empty.
+	if code, result = postWorkspace(t, "/workspace/save", `{"workspace": {"name": "dev", "connProfile": "profile",
+		"identities": [{"label": "admin", "MSPID": "Org1MSP"}, {"label": "user", "prvKeyContent": "key3"},
+		{"label": "hsm", "PKCS11": {"library": "softhsm2.so", "keyLabel": "hsm"}}], "defaultIdentity": "admin"}}`); code != 200 {
+		t.Errorf("expected the workspace is updated, but got %d %v", code, result)
+	}
+	content, err := ioutil.ReadFile(getWorkspaceFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"key1", "secret1", "key3", "98765432"} {
+		if strings.Contains(string(content), secret) {
+			t.Errorf("expected %s is encrypted in the file:\n%s", secret, content)
+		}
+	}
+
+	if code, result = postWorkspace(t, "/workspace/switch", `{"name": "dev"}`); code != 200 || result["current"] != "dev" {
+		t.Errorf("expected switched to dev, but got %d %v", code, result)
+	}
+	if code, _ = postWorkspace(t, "/workspace/switch", `{"name": "prod"}`); code != 404 {
+		t.Errorf("expected 404 of an unknown workspace, but got %d", code)
+	}
+	// The current workspace is not used by a request without connection.
+	if _, err := resolveWorkspace(nil); ClassifyError(err) != ERR_CODE_INVALID_REQUEST {
+		t.Errorf("expected an error without connection, but got %v", err)
+	}
+
+	// Reloaded from the file.
+	workspaceOnce = sync.Once{}
+	reqConn, err := resolveWorkspace(&RequestConnection{Workspace: "dev"})
+	if err != nil || reqConn.Label != "admin" || reqConn.PrvKeyContent != "key1" || reqConn.Passphrase != "secret1" || reqConn.ConnProfile != "profile" {
+		t.Errorf("expected the default identity of the workspace, but got %+v %v", reqConn, err)
+	}
+	reqConn, err = resolveWorkspace(&RequestConnection{Workspace: "dev", Identity: "user"})
+	if err != nil || reqConn.Label != "user" || reqConn.PrvKeyContent != "key3" {
+		t.Errorf("expected the identity user, but got %+v %v", reqConn, err)
+	}
+	reqConn, err = resolveWorkspace(&RequestConnection{Workspace: "dev", Identity: "hsm"})
+	if err != nil || reqConn.PKCS11 == nil || reqConn.PKCS11.PIN != "98765432" {
+		t.Errorf("expected the identity of the token with the PIN, but got %+v %v", reqConn, err)
+	}
+	if _, err = resolveWorkspace(&RequestConnection{Workspace: "dev", Identity: "nobody"}); ClassifyError(err) != ERR_CODE_NOT_FOUND {
+		t.Errorf("expected an error of the unknown identity, but got %v", err)
+	}
+
+	os.Setenv(WorkspaceKeyEnv, "wrong key")
+	workspaceOnce = sync.Once{}
+	if _, err = getWorkspaceStore(); err == nil {
+		t.Error("expected an error of decrypting by a wrong key")
+	}
+
+	// Without the key, secrets are not saved and are given by requests.
+	os.Unsetenv(WorkspaceKeyEnv)
+	os.Remove(getWorkspaceFile())
+	workspaceOnce = sync.Once{}
+	if code, result = postWorkspace(t, "/workspace/save", `{"workspace": {"name": "dev", "connProfile": "profile",
+		"identities": [{"label": "admin", "prvKeyContent": "key1", "passphrase": "secret1"}]}}`); code != 200 {
+		t.Errorf("expected the workspace is saved, but got %d %v", code, result)
+	}
+	if content, err = ioutil.ReadFile(getWorkspaceFile()); err != nil || strings.Contains(string(content), "key1") || strings.Contains(string(content), "secret1") {
+		t.Errorf("expected no secrets in the file, but got %s %v", content, err)
+	}
+	workspaceOnce = sync.Once{}
+	reqConn, err = resolveWorkspace(&RequestConnection{Workspace: "dev", PrvKeyContent: "key4", Passphrase: "secret4"})
+	if err != nil || reqConn.Label != "admin" || reqConn.PrvKeyContent != "key4" || reqConn.Passphrase != "secret4" {
+		t.Errorf("expected the secrets of the request, but got %+v %v", reqConn, err)
+	}
+
+	if code, result = postWorkspace(t, "/workspace/delete", `{"name": "dev"}`); code != 200 || result["current"] != "" {
+		t.Errorf("expected no current workspace after deleted, but got %d %v", code, result)
+	}
+}
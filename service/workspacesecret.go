@@ -0,0 +1,167 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// WorkspaceKeyEnv the env of the passphrase to encrypt private keys, passphrases and PINs of workspace identities on disk.
+// Without it they are not written to disk, so they are lost when Fablet restarts and should be given by requests.
+const WorkspaceKeyEnv = "FABLET_WORKSPACE_KEY"
+
+const workspaceKeyIterations = 100000
+
+// workspaceSecrets the secrets of an identity, which are sealed in the workspace file.
+type workspaceSecrets struct {
+	PrvKeyContent string `json:"prvKeyContent,omitempty"`
+	Passphrase    string `json:"passphrase,omitempty"`
+	PIN           string `json:"PIN,omitempty"`
+}
+
+// workspaceFile the persisted store, identities of the workspaces are without secrets, which are sealed by workspace and label.
+type workspaceFile struct {
+	Current    string                       `json:"current"`
+	Workspaces map[string]*Workspace        `json:"workspaces"`
+	Salt       []byte                       `json:"salt,omitempty"`
+	Secrets    map[string]map[string][]byte `json:"secrets,omitempty"`
+}
+
+// workspaceCipher to get the cipher by the key of WorkspaceKeyEnv, nil if it is not set.
+func workspaceCipher(salt []byte) (cipher.AEAD, error) {
+	passphrase := os.Getenv(WorkspaceKeyEnv)
+	if passphrase == "" {
+		return nil, nil
+	}
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, workspaceKeyIterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealSecrets to encrypt the secrets of the identity, the nonce is prepended. The workspace and label are authenticated with it.
+func sealSecrets(aead cipher.AEAD, ws *Workspace, identity *WorkspaceIdentity) ([]byte, error) {
+	secrets := &workspaceSecrets{PrvKeyContent: identity.PrvKeyContent, Passphrase: identity.Passphrase}
+	if identity.PKCS11 != nil {
+		secrets.PIN = identity.PKCS11.PIN
+	}
+	if *secrets == (workspaceSecrets{}) {
+		return nil, nil
+	}
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plain, []byte(ws.Name+"/"+identity.Label)), nil
+}
+
+// openSecrets to decrypt the sealed secrets into the identity.
+func openSecrets(aead cipher.AEAD, ws *Workspace, identity *WorkspaceIdentity, sealed []byte) error {
+	if len(sealed) < aead.NonceSize() {
+		return errors.New("sealed secrets are too short")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(ws.Name+"/"+identity.Label))
+	if err != nil {
+		return err
+	}
+	secrets := &workspaceSecrets{}
+	if err := json.Unmarshal(plain, secrets); err != nil {
+		return err
+	}
+	identity.PrvKeyContent, identity.Passphrase = secrets.PrvKeyContent, secrets.Passphrase
+	if identity.PKCS11 != nil {
+		identity.PKCS11.PIN = secrets.PIN
+	}
+	return nil
+}
+
+// marshalStore to marshal the store with the secrets sealed, or without secrets if WorkspaceKeyEnv is not set.
+func marshalStore(store *WorkspaceStore) ([]byte, error) {
+	file := &workspaceFile{Current: store.Current, Workspaces: map[string]*Workspace{}, Salt: store.salt}
+	for name, ws := range store.Workspaces {
+		file.Workspaces[name] = ws.redacted()
+	}
+
+	if file.Salt == nil {
+		file.Salt = make([]byte, 16)
+		if _, err := rand.Read(file.Salt); err != nil {
+			return nil, err
+		}
+	}
+	aead, err := workspaceCipher(file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if aead == nil {
+		logger.Warnf("Secrets of workspace identities are not saved, since %s is not set.", WorkspaceKeyEnv)
+	} else {
+		file.Secrets = map[string]map[string][]byte{}
+		for name, ws := range store.Workspaces {
+			for _, identity := range ws.Identities {
+				sealed, err := sealSecrets(aead, ws, identity)
+				if err != nil {
+					return nil, errors.WithMessage(err, "Error occurred when encrypting secrets of workspaces.")
+				}
+				if sealed == nil {
+					continue
+				}
+				if file.Secrets[name] == nil {
+					file.Secrets[name] = map[string][]byte{}
+				}
+				file.Secrets[name][identity.Label] = sealed
+			}
+		}
+	}
+	store.salt = file.Salt
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// unmarshalStore to unmarshal the store and open the sealed secrets by WorkspaceKeyEnv.
+func unmarshalStore(content []byte, store *WorkspaceStore) error {
+	file := &workspaceFile{}
+	if err := json.Unmarshal(content, file); err != nil {
+		return errors.WithMessage(err, "Error occurred when unmarshaling workspaces.")
+	}
+	store.Current, store.salt = file.Current, file.Salt
+	if file.Workspaces != nil {
+		store.Workspaces = file.Workspaces
+	}
+	if len(file.Secrets) == 0 {
+		return nil
+	}
+
+	aead, err := workspaceCipher(file.Salt)
+	if err != nil {
+		return err
+	}
+	if aead == nil {
+		return errors.Errorf("Secrets of workspaces are encrypted, but %s is not set.", WorkspaceKeyEnv)
+	}
+	for name, sealedSecrets := range file.Secrets {
+		ws, ok := store.Workspaces[name]
+		if !ok {
+			continue
+		}
+		for label, sealed := range sealedSecrets {
+			identity := ws.findIdentity(label)
+			if identity == nil {
+				continue
+			}
+			if err := openSecrets(aead, ws, identity, sealed); err != nil {
+				return errors.WithMessagef(err, "Error occurred when decrypting secrets of identity %s of workspace %s, check %s.", label, name, WorkspaceKeyEnv)
+			}
+		}
+	}
+	return nil
+}
@@ -16,6 +16,23 @@ const (
 	EndPointStatus_NotFound
 )
 
+// String to have the readable status.
+func (status EndPointStatus) String() string {
+	switch status {
+	case EndPointStatus_Valid:
+		return "valid"
+	case EndPointStatus_Connectable:
+		return "connectable"
+	case EndPointStatus_Refused:
+		return "refused"
+	case EndPointStatus_Timeout:
+		return "timeout"
+	case EndPointStatus_NotFound:
+		return "not found"
+	}
+	return "unknown"
+}
+
 func resolveAddress(address string) error {
 	_, err := net.ResolveTCPAddr("tcp", address)
 	return err
@@ -49,3 +66,17 @@ func GetEndpointStatus(address string) EndPointStatus {
 	defer conn.Close()
 	return EndPointStatus_Connectable
 }
+
+// GetEndpointLatency to measure the latency of the endpoint, by the time of setting up a TCP connection.
+func GetEndpointLatency(address string) (time.Duration, error) {
+	if idx := strings.Index(address, "://"); idx >= 0 {
+		address = address[idx+3:]
+	}
+	begin := time.Now()
+	conn, err := net.DialTimeout("tcp", address, time.Second*2)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	return time.Since(begin), nil
+}
@@ -58,6 +58,8 @@ const refreshNetwork = async () => {
         headers: myHeaders,
         json: true,
         resolveWithFullResponse: true,
+        // Errors are responded with HTTP status, the result is still in the body.
+        simple: false,
         body: reqBody
     };
 
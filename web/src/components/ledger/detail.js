@@ -320,6 +320,8 @@ class LedgerDetail extends React.Component {
             headers: myHeaders,
             json: true,
             resolveWithFullResponse: true,
+            // Errors are responded with HTTP status, the result is still in the body.
+            simple: false,
             body: reqBody
         };
 
@@ -503,6 +505,8 @@ class LedgerDetail extends React.Component {
             body: reqBody,
             json: true,
             resolveWithFullResponse: true,
+            // Errors are responded with HTTP status, the result is still in the body.
+            simple: false,
         };
 
         return await requestPromise(option);